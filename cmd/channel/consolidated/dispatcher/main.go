@@ -19,11 +19,13 @@ package main
 import (
 	"os"
 
+	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/injection"
 	"knative.dev/pkg/injection/sharedmain"
 	"knative.dev/pkg/signals"
 
 	controller "knative.dev/eventing-kafka/pkg/channel/consolidated/reconciler/dispatcher"
+	"knative.dev/eventing-kafka/pkg/channel/consolidated/utils"
 )
 
 const component = "kafkachannel-dispatcher"
@@ -35,8 +37,25 @@ func main() {
 		ctx = injection.WithNamespaceScope(ctx, ns)
 	}
 
-	// Do not run the dispatcher in leader-election mode
-	ctx = sharedmain.WithHADisabled(ctx)
+	// Do not run the dispatcher in leader-election mode, unless it is sharded: each replica then
+	// only consumes the channels of the leader election buckets it leads.
+	if !dispatcherSharding() {
+		ctx = sharedmain.WithHADisabled(ctx)
+	}
 
 	sharedmain.MainWithContext(ctx, component, controller.NewController)
 }
+
+// dispatcherSharding returns true if config-kafka enables the sharding of the dispatcher. An invalid
+// configuration is left for the controller to report.
+func dispatcherSharding() bool {
+	configMap, err := configmap.Load("/etc/config-kafka")
+	if err != nil {
+		return false
+	}
+	kafkaConfig, err := utils.GetKafkaConfig(configMap)
+	if err != nil {
+		return false
+	}
+	return kafkaConfig.DispatcherSharding
+}
@@ -26,6 +26,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
 	commonconfig "knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
 	commonk8s "knative.dev/eventing-kafka/pkg/channel/distributed/common/k8s"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/sarama"
@@ -37,6 +38,7 @@ import (
 	dispatcherhealth "knative.dev/eventing-kafka/pkg/channel/distributed/dispatcher/health"
 	"knative.dev/eventing-kafka/pkg/client/clientset/versioned"
 	"knative.dev/eventing-kafka/pkg/client/informers/externalversions"
+	kafkainformers "knative.dev/eventing-kafka/pkg/client/informers/externalversions/messaging/v1beta1"
 	kncontroller "knative.dev/pkg/controller"
 	"knative.dev/pkg/logging"
 	eventingmetrics "knative.dev/pkg/metrics"
@@ -45,10 +47,13 @@ import (
 
 // Variables
 var (
-	logger     *zap.Logger
-	dispatcher dispatch.Dispatcher
-	serverURL  = flag.String("server", "", "The address of the Kubernetes API server. Overrides any value in kubeconfig. Only required if out-of-cluster.")
-	kubeconfig = flag.String("kubeconfig", "", "Path to a kubeconfig. Only required if out-of-cluster.")
+	logger                 *zap.Logger
+	dispatcher             dispatch.Dispatcher
+	pool                   *dispatch.Pool     // The Shared Dispatchers Of A Pool Member (Instead Of dispatcher)
+	kafkaChannelController *kncontroller.Impl // Resyncs The KafkaChannels After A ConfigMap Change
+	kafkaChannelInformer   kafkainformers.KafkaChannelInformer
+	serverURL              = flag.String("server", "", "The address of the Kubernetes API server. Overrides any value in kubeconfig. Only required if out-of-cluster.")
+	kubeconfig             = flag.String("kubeconfig", "", "Path to a kubeconfig. Only required if out-of-cluster.")
 )
 
 // The Main Function (Go Command)
@@ -111,13 +116,14 @@ func main() {
 		ChannelKey:    environment.ChannelKey,
 		StatsReporter: statsReporter,
 		SaramaConfig:  saramaConfig,
+		Concurrency:   ekConfig.Dispatcher.Concurrency,
 	}
-	dispatcher = dispatch.NewDispatcher(dispatcherConfig)
-
-	// Watch The Settings ConfigMap For Changes
-	err = commonconfig.InitializeConfigWatcher(ctx, logger.Sugar(), configMapObserver)
-	if err != nil {
-		logger.Fatal("Failed To Initialize ConfigMap Watcher", zap.Error(err))
+	if environment.PoolSize > 0 {
+		// A Member Of A Pool Of Shared Dispatchers Creates A Dispatcher For Each KafkaChannel Assigned To It
+		pool = dispatch.NewPool(dispatcherConfig, environment.PoolMember, environment.PoolSize, environment.PoolSecret)
+		logger.Info("Dispatching As A Shared Dispatcher Pool Member", zap.Int("PoolMember", environment.PoolMember), zap.Int("PoolSize", environment.PoolSize))
+	} else {
+		dispatcher = dispatch.NewDispatcher(dispatcherConfig)
 	}
 
 	config, err := clientcmd.BuildConfigFromFlags(*serverURL, *kubeconfig)
@@ -134,19 +140,43 @@ func main() {
 	kafkaInformerFactory := externalversions.NewSharedInformerFactory(kafkaClientSet, environment.ResyncPeriod)
 
 	// Create KafkaChannel Informer
-	kafkaChannelInformer := kafkaInformerFactory.Messaging().V1beta1().KafkaChannels()
+	kafkaChannelInformer = kafkaInformerFactory.Messaging().V1beta1().KafkaChannels()
+
+	// The Subscriber Health Thresholds Of The KafkaChannel Status
+	healthThresholds := kafkav1beta1.SubscriberHealthThresholds{
+		Lag:               ekConfig.Dispatcher.SubscriberLagThreshold,
+		FailurePercentage: ekConfig.Dispatcher.SubscriberFailureThreshold,
+	}
 
-	// Construct Array Of Controllers, In Our Case Just The One
-	controllers := [...]*kncontroller.Impl{
-		controller.NewController(
+	// Construct Array Of Controllers, In Our Case Just The One (Of The Single KafkaChannel Or Of The Pool Member)
+	if pool != nil {
+		kafkaChannelController = controller.NewSharedController(
+			logger,
+			pool,
+			kafkaChannelInformer,
+			kubeClient,
+			kafkaClientSet,
+			healthThresholds,
+			ctx.Done(),
+		)
+	} else {
+		kafkaChannelController = controller.NewController(
 			logger,
 			environment.ChannelKey,
 			dispatcher,
 			kafkaChannelInformer,
 			kubeClient,
 			kafkaClientSet,
+			healthThresholds,
 			ctx.Done(),
-		),
+		)
+	}
+	controllers := [...]*kncontroller.Impl{kafkaChannelController}
+
+	// Watch The Settings ConfigMap For Changes (Once The Controller Can Reconcile The Subscribers' Status After A Change)
+	err = commonconfig.InitializeConfigWatcher(ctx, logger.Sugar(), configMapObserver)
+	if err != nil {
+		logger.Fatal("Failed To Initialize ConfigMap Watcher", zap.Error(err))
 	}
 
 	// Start The Informers
@@ -168,8 +198,12 @@ func main() {
 	// Reset The Liveness and Readiness Flags In Preparation For Shutdown
 	healthServer.Shutdown()
 
-	// Shutdown The Dispatcher (Close ConsumerGroups)
-	dispatcher.Shutdown()
+	// Shutdown The Dispatcher(s) (Close ConsumerGroups)
+	if pool != nil {
+		pool.Shutdown()
+	} else {
+		dispatcher.Shutdown()
+	}
 
 	// Stop The Liveness And Readiness Servers
 	healthServer.Stop(logger)
@@ -187,6 +221,13 @@ func configMapObserver(configMap *v1.ConfigMap) {
 		return
 	}
 
+	if pool != nil {
+		// Each shared dispatcher of the pool member restarts its ConsumerGroups (in place)
+		pool.ConfigChanged(configMap)
+		resyncKafkaChannels()
+		return
+	}
+
 	if dispatcher == nil {
 		// This typically happens during startup
 		logger.Info("Dispatcher is nil during call to configMapObserver; ignoring changes")
@@ -196,7 +237,16 @@ func configMapObserver(configMap *v1.ConfigMap) {
 	// Toss the new config map to the dispatcher for inspection and action
 	newDispatcher := dispatcher.ConfigChanged(configMap)
 	if newDispatcher != nil {
-		// The configuration change caused a new dispatcher to be created, so switch to that one
+		// The configuration change restarted the dispatcher's ConsumerGroups (in place), so keep using the one returned
 		dispatcher = newDispatcher
 	}
+	resyncKafkaChannels()
+}
+
+// resyncKafkaChannels reconciles the KafkaChannels after a configuration change, so that the status of
+// any subscribers whose ConsumerGroups failed to restart is marked as not ready until they are restarted
+func resyncKafkaChannels() {
+	if kafkaChannelController != nil {
+		kafkaChannelController.GlobalResync(kafkaChannelInformer.Informer())
+	}
 }
@@ -22,6 +22,7 @@ import (
 	nethttp "net/http"
 	"strconv"
 	"strings"
+	"sync/atomic"
 
 	"github.com/cloudevents/sdk-go/v2/binding"
 	"github.com/google/uuid"
@@ -48,7 +49,7 @@ var (
 	logger        *zap.Logger
 	serverURL     = flag.String("server", "", "The address of the Kubernetes API server. Overrides any value in kubeconfig. Only required if out-of-cluster.")
 	kubeconfig    = flag.String("kubeconfig", "", "Path to a kubeconfig. Only required if out-of-cluster.")
-	kafkaProducer *producer.Producer
+	kafkaProducer atomic.Value // The Current *producer.Producer (Replaced When The Producer Settings Change)
 )
 
 // The Main Function (Go Command)
@@ -118,11 +119,12 @@ func main() {
 	}
 
 	// Initialize The Kafka Producer In Order To Start Processing Status Events
-	kafkaProducer, err = producer.NewProducer(logger, saramaConfig, strings.Split(environment.KafkaBrokers, ","), statsReporter, healthServer)
+	initialProducer, err := producer.NewProducer(logger, saramaConfig, strings.Split(environment.KafkaBrokers, ","), statsReporter, healthServer)
 	if err != nil {
 		logger.Fatal("Failed To Initialize Kafka Producer", zap.Error(err))
 	}
-	defer kafkaProducer.Close()
+	kafkaProducer.Store(initialProducer)
+	defer func() { currentProducer().Close() }()
 
 	channelReporter := eventingchannel.NewStatsReporter(environment.ContainerName, kmeta.ChildName(environment.PodName, uuid.New().String()))
 
@@ -174,8 +176,12 @@ func handleMessage(ctx context.Context, channelReference eventingchannel.Channel
 		return err
 	}
 
-	// Produce The CloudEvent Binding Message (Send To The Appropriate Kafka Topic)
-	err = kafkaProducer.ProduceKafkaMessage(ctx, channelReference, message, transformers...)
+	// Produce The CloudEvent Binding Message (Send To The Appropriate Kafka Topic) - A Producer Is Only Closed After
+	// Being Replaced, So A Message Rejected By A Closed Producer Is Produced Again With The Current One
+	err = currentProducer().ProduceKafkaMessage(ctx, channelReference, message, transformers...)
+	if err == producer.ErrProducerClosed {
+		err = currentProducer().ProduceKafkaMessage(ctx, channelReference, message, transformers...)
+	}
 	if err != nil {
 		logger.Error("Failed To Produce Kafka Message", zap.Error(err))
 		return err
@@ -191,17 +197,26 @@ func configMapObserver(configMap *v1.ConfigMap) {
 		logger.Warn("Nil ConfigMap passed to configMapObserver; ignoring")
 		return
 	}
-	if kafkaProducer == nil {
+	previousProducer := currentProducer()
+	if previousProducer == nil {
 		// This typically happens during startup
 		logger.Debug("Producer is nil during call to configMapObserver; ignoring changes")
 		return
 	}
 
 	// Toss the new config map to the producer for inspection and action
-	newProducer := kafkaProducer.ConfigChanged(configMap)
+	newProducer := previousProducer.ConfigChanged(configMap)
 	if newProducer != nil {
-		// The configuration change caused a new producer to be created, so switch to that one
+		// The configuration change caused a new producer to be created, so switch to that one before
+		// draining the previous one (which finishes producing the messages it was sent before the switch)
 		logger.Info("Producer Reconfigured; Switching To New Producer")
-		kafkaProducer = newProducer
+		kafkaProducer.Store(newProducer)
+		previousProducer.Drain()
 	}
 }
+
+// currentProducer returns the Producer which messages are currently produced with (nil until initialized)
+func currentProducer() *producer.Producer {
+	p, _ := kafkaProducer.Load().(*producer.Producer)
+	return p
+}
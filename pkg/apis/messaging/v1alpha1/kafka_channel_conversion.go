@@ -106,6 +106,31 @@ func (source *KafkaChannel) ConvertTo(ctx context.Context, obj apis.Convertible)
 				DeadLetterChannel: nil,
 			},
 		}
+		if len(source.Status.SubscriberHealth) > 0 {
+			sink.Status.SubscriberHealth = make([]v1beta1.SubscriberHealth, len(source.Status.SubscriberHealth))
+			for i, sh := range source.Status.SubscriberHealth {
+				sink.Status.SubscriberHealth[i] = v1beta1.SubscriberHealth{
+					UID:               sh.UID,
+					Lag:               sh.Lag,
+					FailurePercentage: sh.FailurePercentage,
+					LastFailureTime:   sh.LastFailureTime.DeepCopy(),
+					LastFailure:       sh.LastFailure,
+				}
+			}
+		}
+		sink.Status.Topic = source.Status.Topic
+		if source.Status.TopicMigration != nil {
+			sink.Status.TopicMigration = &v1beta1.TopicMigration{
+				FromTopic:          source.Status.TopicMigration.FromTopic,
+				ToTopic:            source.Status.TopicMigration.ToTopic,
+				Phase:              v1beta1.TopicMigrationPhase(source.Status.TopicMigration.Phase),
+				LastTransitionTime: source.Status.TopicMigration.LastTransitionTime,
+			}
+		}
+		if source.Status.KafkaCluster != nil {
+			kafkaCluster := *source.Status.KafkaCluster
+			sink.Status.KafkaCluster = &kafkaCluster
+		}
 
 		return nil
 	default:
@@ -184,6 +209,31 @@ func (sink *KafkaChannel) ConvertFrom(ctx context.Context, obj apis.Convertible)
 				SubscribableStatus: subscribableStatus,
 			},
 		}
+		if len(source.Status.SubscriberHealth) > 0 {
+			sink.Status.SubscriberHealth = make([]SubscriberHealth, len(source.Status.SubscriberHealth))
+			for i, sh := range source.Status.SubscriberHealth {
+				sink.Status.SubscriberHealth[i] = SubscriberHealth{
+					UID:               sh.UID,
+					Lag:               sh.Lag,
+					FailurePercentage: sh.FailurePercentage,
+					LastFailureTime:   sh.LastFailureTime.DeepCopy(),
+					LastFailure:       sh.LastFailure,
+				}
+			}
+		}
+		sink.Status.Topic = source.Status.Topic
+		if source.Status.TopicMigration != nil {
+			sink.Status.TopicMigration = &TopicMigration{
+				FromTopic:          source.Status.TopicMigration.FromTopic,
+				ToTopic:            source.Status.TopicMigration.ToTopic,
+				Phase:              TopicMigrationPhase(source.Status.TopicMigration.Phase),
+				LastTransitionTime: source.Status.TopicMigration.LastTransitionTime,
+			}
+		}
+		if source.Status.KafkaCluster != nil {
+			kafkaCluster := *source.Status.KafkaCluster
+			sink.Status.KafkaCluster = &kafkaCluster
+		}
 
 		return nil
 	default:
@@ -19,6 +19,7 @@ package v1alpha1
 import (
 	"context"
 	"testing"
+	"time"
 
 	v1 "knative.dev/eventing/pkg/apis/duck/v1"
 	duckv1alpha1 "knative.dev/pkg/apis/duck/v1alpha1"
@@ -149,6 +150,21 @@ func TestKafkaChannelConversionRoundTripV1alpha1(t *testing.T) {
 						},
 					},
 				},
+				SubscriberHealth: []SubscriberHealth{{
+					UID:               "status-subs-uid",
+					Lag:               44,
+					FailurePercentage: 45,
+					LastFailureTime:   &metav1.Time{Time: time.Unix(46, 0)},
+					LastFailure:       "status-subs-failure",
+				}},
+				Topic: "status-topic",
+				TopicMigration: &TopicMigration{
+					FromTopic:          "status-from-topic",
+					ToTopic:            "status-topic-2",
+					Phase:              TopicMigrationDraining,
+					LastTransitionTime: metav1.Time{Time: time.Unix(47, 0)},
+				},
+				KafkaCluster: pointer.StringPtr("status-cluster"),
 			},
 		},
 	}}
@@ -292,6 +308,21 @@ func TestKafkaChannelConversionRoundTripV1beta1(t *testing.T) {
 					//	APIVersion: "status-dl-channel-apiversion",
 					//},
 				},
+				SubscriberHealth: []v1beta1.SubscriberHealth{{
+					UID:               "status-subs-uid",
+					Lag:               44,
+					FailurePercentage: 45,
+					LastFailureTime:   &metav1.Time{Time: time.Unix(46, 0)},
+					LastFailure:       "status-subs-failure",
+				}},
+				Topic: "status-topic",
+				TopicMigration: &v1beta1.TopicMigration{
+					FromTopic:          "status-from-topic",
+					ToTopic:            "status-topic-2",
+					Phase:              v1beta1.TopicMigrationDraining,
+					LastTransitionTime: metav1.Time{Time: time.Unix(47, 0)},
+				},
+				KafkaCluster: pointer.StringPtr("status-cluster"),
 			},
 		},
 	}}
@@ -0,0 +1,77 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
+)
+
+// KafkaClusterAnnotation names the Kafka cluster configuration which the topic of a KafkaChannel lives in, taking
+// precedence over the cluster its namespace is mapped to.  The named clusters are configured by the channel
+// implementation, and the annotation can not be changed once set, as the events of the topic would be left behind.
+const KafkaClusterAnnotation = "kafka.eventing.knative.dev/cluster"
+
+// KafkaCluster returns the name of the Kafka cluster selected by the KafkaClusterAnnotation, empty if none is.
+func (c *KafkaChannel) KafkaCluster() string {
+	return c.Annotations[KafkaClusterAnnotation]
+}
+
+// PinnedKafkaCluster returns the Kafka cluster the channel is pinned to (empty for the default cluster), and
+// whether it has been pinned yet.
+func (cs *KafkaChannelStatus) PinnedKafkaCluster() (string, bool) {
+	if cs.KafkaCluster == nil {
+		return "", false
+	}
+	return *cs.KafkaCluster, true
+}
+
+// PinKafkaCluster pins the channel to the Kafka cluster its topic is created in, unless it is pinned already.
+func (cs *KafkaChannelStatus) PinKafkaCluster(name string) {
+	if cs.KafkaCluster == nil {
+		cs.KafkaCluster = &name
+	}
+}
+
+// MarkKafkaClusterMismatch sets the KafkaClusterMismatch condition while the Kafka cluster the channel is pinned
+// to differs from the configured one, and clears it otherwise.
+func (cs *KafkaChannelStatus) MarkKafkaClusterMismatch(configured string) {
+	manager := cs.GetConditionSet().Manage(cs)
+	pinned, ok := cs.PinnedKafkaCluster()
+	if !ok || pinned == configured {
+		_ = manager.ClearCondition(KafkaChannelConditionKafkaClusterMismatch)
+		return
+	}
+	manager.SetCondition(apis.Condition{
+		Type:     KafkaChannelConditionKafkaClusterMismatch,
+		Status:   corev1.ConditionTrue,
+		Reason:   "KafkaClusterMismatch",
+		Message:  fmt.Sprintf("the channel stays in the %s Kafka cluster it was created in, rather than the configured %s one", describeKafkaCluster(pinned), describeKafkaCluster(configured)),
+		Severity: apis.ConditionSeverityWarning,
+	})
+}
+
+// describeKafkaCluster quotes the name of a Kafka cluster for the messages of the conditions.
+func describeKafkaCluster(name string) string {
+	if name == "" {
+		return "default"
+	}
+	return strconv.Quote(name)
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// DeliveryOrderingAnnotation selects the DeliveryOrdering of all subscribers of a KafkaChannel.  A single
+	// subscriber may override it with an annotation whose key is suffixed with "." and the subscription UID.
+	DeliveryOrderingAnnotation = "kafka.eventing.knative.dev/delivery-ordering"
+
+	// DeliveryConcurrencyAnnotation sets the maximum number of in-flight events per partition for the
+	// unordered and ordered-by-key orderings.  It may be overridden per subscriber in the same way.
+	DeliveryConcurrencyAnnotation = "kafka.eventing.knative.dev/delivery-concurrency"
+
+	// DeliveryRetryTopicsAnnotation ("true" or "false") moves the retries of failed events to per-subscriber delay
+	// topics instead of retrying them in place, so that a failing event does not hold up the rest of its partition.
+	// It is ignored for the strictly-ordered ordering, and may be overridden per subscriber in the same way.
+	DeliveryRetryTopicsAnnotation = "kafka.eventing.knative.dev/delivery-retry-topics"
+
+	// DeliveryStartOffsetAnnotation selects where the consumer group of a new subscriber starts: "earliest", "latest"
+	// or an RFC3339 timestamp (skipping the events published before it).  It has no effect on subscribers which
+	// have already committed offsets, and may be overridden per subscriber in the same way.
+	DeliveryStartOffsetAnnotation = "kafka.eventing.knative.dev/delivery-start-offset"
+
+	// DeliveryRewindAnnotation replays the events of a subscriber from "earliest" or an RFC3339 timestamp.  The
+	// subscriber is moved to a new consumer group starting at that offset, and every change of the value rewinds it
+	// again.  It is normally set per subscriber, but applies to all subscribers when set on the channel.
+	DeliveryRewindAnnotation = "kafka.eventing.knative.dev/delivery-rewind"
+
+	// DeliveryOffsetEarliest and DeliveryOffsetLatest are the symbolic start offsets.
+	DeliveryOffsetEarliest = "earliest"
+	DeliveryOffsetLatest   = "latest"
+
+	// DefaultDeliveryConcurrency is the concurrency used when neither the channel nor the dispatcher specify one.
+	DefaultDeliveryConcurrency = 1
+)
+
+// DeliveryOrdering is the guarantee a dispatcher gives when delivering the events of a partition to a subscriber.
+type DeliveryOrdering string
+
+const (
+	// DeliveryUnordered dispatches up to the delivery concurrency of events at once and moves on after an event
+	// has exhausted its retries (and dead letter sink), regardless of the outcome.
+	DeliveryUnordered DeliveryOrdering = "unordered"
+
+	// DeliveryOrderedByKey behaves like DeliveryUnordered, except that events sharing a Kafka message key are
+	// always dispatched one after another in offset order.
+	DeliveryOrderedByKey DeliveryOrdering = "ordered-by-key"
+
+	// DeliveryStrictlyOrdered dispatches one event at a time and blocks the partition until the event has been
+	// delivered (either to the subscriber or to its dead letter sink).
+	DeliveryStrictlyOrdered DeliveryOrdering = "strictly-ordered"
+)
+
+// SubscriberDelivery holds the Kafka specific delivery settings of a single KafkaChannel subscriber.
+type SubscriberDelivery struct {
+	Ordering DeliveryOrdering
+	// Concurrency is zero when not specified, leaving the choice to the dispatcher.
+	Concurrency int
+	// RetryTopics is true if failed events are retried through delay topics rather than in place.
+	RetryTopics bool
+	// StartOffset is where a new subscriber starts, empty when not specified (leaving the choice to the dispatcher).
+	StartOffset string
+	// Rewind is the offset a rewound subscriber restarts from, empty unless the subscriber has been rewound.
+	Rewind string
+}
+
+// DefaultSubscriberDelivery returns the SubscriberDelivery used when a KafkaChannel has no delivery annotations.
+func DefaultSubscriberDelivery() SubscriberDelivery {
+	return SubscriberDelivery{Ordering: DeliveryUnordered}
+}
+
+// SubscriberDelivery returns the delivery settings of the subscriber with the specified UID, taking the
+// channel-wide annotations as defaults for any per-subscriber annotation which is not present.
+func (c *KafkaChannel) SubscriberDelivery(uid types.UID) (SubscriberDelivery, error) {
+	delivery := DefaultSubscriberDelivery()
+	if c.Annotations == nil {
+		return delivery, nil
+	}
+
+	if ordering, ok := lookupDeliveryAnnotation(c.Annotations, DeliveryOrderingAnnotation, uid); ok {
+		if !isValidDeliveryOrdering(DeliveryOrdering(ordering)) {
+			return delivery, fmt.Errorf("invalid delivery ordering %q", ordering)
+		}
+		delivery.Ordering = DeliveryOrdering(ordering)
+	}
+
+	if concurrency, ok := lookupDeliveryAnnotation(c.Annotations, DeliveryConcurrencyAnnotation, uid); ok {
+		value, err := parseDeliveryConcurrency(concurrency)
+		if err != nil {
+			return delivery, err
+		}
+		delivery.Concurrency = value
+	}
+
+	if retryTopics, ok := lookupDeliveryAnnotation(c.Annotations, DeliveryRetryTopicsAnnotation, uid); ok {
+		value, err := parseDeliveryRetryTopics(retryTopics)
+		if err != nil {
+			return delivery, err
+		}
+		delivery.RetryTopics = value
+	}
+
+	if startOffset, ok := lookupDeliveryAnnotation(c.Annotations, DeliveryStartOffsetAnnotation, uid); ok {
+		if _, err := ParseDeliveryOffset(startOffset); err != nil {
+			return delivery, err
+		}
+		delivery.StartOffset = startOffset
+	}
+
+	if rewind, ok := lookupDeliveryAnnotation(c.Annotations, DeliveryRewindAnnotation, uid); ok {
+		if offset, err := ParseDeliveryOffset(rewind); err != nil || offset.Latest {
+			return delivery, fmt.Errorf("invalid delivery rewind %q, expected 'earliest' or an RFC3339 timestamp", rewind)
+		}
+		delivery.Rewind = rewind
+	}
+
+	return delivery, nil
+}
+
+// UsesRetryTopics returns true if failed events are retried through delay topics, which requires an ordering
+// that allows the partition to move on while an event is waiting to be retried.
+func (d SubscriberDelivery) UsesRetryTopics() bool {
+	return d.RetryTopics && d.Ordering != DeliveryStrictlyOrdered
+}
+
+// DeliveryOffset is the parsed form of a start offset or rewind annotation value.
+type DeliveryOffset struct {
+	Earliest bool
+	Latest   bool
+	// Time is the timestamp of the offset, zero for the symbolic offsets.
+	Time time.Time
+}
+
+// ParseDeliveryOffset parses "earliest", "latest" or an RFC3339 timestamp.
+func ParseDeliveryOffset(value string) (DeliveryOffset, error) {
+	switch value {
+	case DeliveryOffsetEarliest:
+		return DeliveryOffset{Earliest: true}, nil
+	case DeliveryOffsetLatest:
+		return DeliveryOffset{Latest: true}, nil
+	}
+	timestamp, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return DeliveryOffset{}, fmt.Errorf("invalid delivery offset %q, expected '%s', '%s' or an RFC3339 timestamp", value, DeliveryOffsetEarliest, DeliveryOffsetLatest)
+	}
+	return DeliveryOffset{Time: timestamp}, nil
+}
+
+// SubscriberDeliveryAnnotation returns the per-subscriber variant of the specified delivery annotation.
+func SubscriberDeliveryAnnotation(annotation string, uid types.UID) string {
+	return annotation + "." + string(uid)
+}
+
+// lookupDeliveryAnnotation returns the per-subscriber value of an annotation, falling back to the channel-wide value.
+func lookupDeliveryAnnotation(annotations map[string]string, annotation string, uid types.UID) (string, bool) {
+	if value, ok := annotations[SubscriberDeliveryAnnotation(annotation, uid)]; ok {
+		return value, true
+	}
+	value, ok := annotations[annotation]
+	return value, ok
+}
+
+// isDeliveryAnnotation returns true if the key is the specified annotation or a per-subscriber variant of it.
+func isDeliveryAnnotation(key string, annotation string) bool {
+	return key == annotation || strings.HasPrefix(key, annotation+".")
+}
+
+func isValidDeliveryOrdering(ordering DeliveryOrdering) bool {
+	return ordering == DeliveryUnordered || ordering == DeliveryOrderedByKey || ordering == DeliveryStrictlyOrdered
+}
+
+func parseDeliveryConcurrency(value string) (int, error) {
+	concurrency, err := strconv.Atoi(value)
+	if err != nil || concurrency <= 0 {
+		return 0, fmt.Errorf("invalid delivery concurrency %q, expected a positive integer", value)
+	}
+	return concurrency, nil
+}
+
+func parseDeliveryRetryTopics(value string) (bool, error) {
+	retryTopics, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid delivery retry topics %q, expected 'true' or 'false'", value)
+	}
+	return retryTopics, nil
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestKafkaChannelSubscriberDelivery(t *testing.T) {
+	uid := types.UID("uid-1")
+
+	testCases := map[string]struct {
+		annotations map[string]string
+		want        SubscriberDelivery
+		wantErr     bool
+	}{
+		"no annotations": {
+			want: SubscriberDelivery{Ordering: DeliveryUnordered},
+		},
+		"channel annotations": {
+			annotations: map[string]string{
+				DeliveryOrderingAnnotation:    string(DeliveryOrderedByKey),
+				DeliveryConcurrencyAnnotation: "8",
+			},
+			want: SubscriberDelivery{Ordering: DeliveryOrderedByKey, Concurrency: 8},
+		},
+		"subscriber annotations override channel annotations": {
+			annotations: map[string]string{
+				DeliveryOrderingAnnotation:                                    string(DeliveryOrderedByKey),
+				DeliveryConcurrencyAnnotation:                                 "8",
+				SubscriberDeliveryAnnotation(DeliveryOrderingAnnotation, uid): string(DeliveryStrictlyOrdered),
+			},
+			want: SubscriberDelivery{Ordering: DeliveryStrictlyOrdered, Concurrency: 8},
+		},
+		"other subscriber annotations are ignored": {
+			annotations: map[string]string{
+				SubscriberDeliveryAnnotation(DeliveryOrderingAnnotation, "uid-2"): string(DeliveryStrictlyOrdered),
+			},
+			want: SubscriberDelivery{Ordering: DeliveryUnordered},
+		},
+		"retry topics": {
+			annotations: map[string]string{
+				DeliveryRetryTopicsAnnotation:                                        "true",
+				SubscriberDeliveryAnnotation(DeliveryRetryTopicsAnnotation, "uid-2"): "false",
+			},
+			want: SubscriberDelivery{Ordering: DeliveryUnordered, RetryTopics: true},
+		},
+		"invalid retry topics": {
+			annotations: map[string]string{DeliveryRetryTopicsAnnotation: "maybe"},
+			want:        SubscriberDelivery{Ordering: DeliveryUnordered},
+			wantErr:     true,
+		},
+		"start offset and rewind": {
+			annotations: map[string]string{
+				DeliveryStartOffsetAnnotation:                               DeliveryOffsetEarliest,
+				SubscriberDeliveryAnnotation(DeliveryRewindAnnotation, uid): "2021-01-02T03:04:05Z",
+			},
+			want: SubscriberDelivery{Ordering: DeliveryUnordered, StartOffset: DeliveryOffsetEarliest, Rewind: "2021-01-02T03:04:05Z"},
+		},
+		"invalid start offset": {
+			annotations: map[string]string{DeliveryStartOffsetAnnotation: "yesterday"},
+			want:        SubscriberDelivery{Ordering: DeliveryUnordered},
+			wantErr:     true,
+		},
+		"rewind to latest": {
+			annotations: map[string]string{DeliveryRewindAnnotation: DeliveryOffsetLatest},
+			want:        SubscriberDelivery{Ordering: DeliveryUnordered},
+			wantErr:     true,
+		},
+		"invalid ordering": {
+			annotations: map[string]string{DeliveryOrderingAnnotation: "random"},
+			want:        SubscriberDelivery{Ordering: DeliveryUnordered},
+			wantErr:     true,
+		},
+		"invalid concurrency": {
+			annotations: map[string]string{DeliveryConcurrencyAnnotation: "-1"},
+			want:        SubscriberDelivery{Ordering: DeliveryUnordered},
+			wantErr:     true,
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			channel := &KafkaChannel{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			got, err := channel.SubscriberDelivery(uid)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("SubscriberDelivery() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("SubscriberDelivery() (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
+
+func TestSubscriberDeliveryUsesRetryTopics(t *testing.T) {
+	if !(SubscriberDelivery{Ordering: DeliveryOrderedByKey, RetryTopics: true}).UsesRetryTopics() {
+		t.Error("expected ordered-by-key delivery to use retry topics")
+	}
+	if (SubscriberDelivery{Ordering: DeliveryStrictlyOrdered, RetryTopics: true}).UsesRetryTopics() {
+		t.Error("expected strictly-ordered delivery not to use retry topics")
+	}
+	if (SubscriberDelivery{Ordering: DeliveryUnordered}).UsesRetryTopics() {
+		t.Error("expected delivery without retry topics not to use them")
+	}
+}
+
+func TestParseDeliveryOffset(t *testing.T) {
+	testCases := map[string]struct {
+		value   string
+		want    DeliveryOffset
+		wantErr bool
+	}{
+		"earliest":  {value: "earliest", want: DeliveryOffset{Earliest: true}},
+		"latest":    {value: "latest", want: DeliveryOffset{Latest: true}},
+		"timestamp": {value: "2021-01-02T03:04:05Z", want: DeliveryOffset{Time: time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)}},
+		"invalid":   {value: "2021-01-02", wantErr: true},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			got, err := ParseDeliveryOffset(tc.value)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("ParseDeliveryOffset() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !got.Time.Equal(tc.want.Time) || got.Earliest != tc.want.Earliest || got.Latest != tc.want.Latest {
+				t.Errorf("ParseDeliveryOffset() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// DedicatedDispatcherAnnotation gives a distributed KafkaChannel a dispatcher of its own ("true") when the
+// dispatchers are otherwise shared by the KafkaChannels, for the channels which need to be isolated.
+const DedicatedDispatcherAnnotation = "kafka.eventing.knative.dev/dedicated-dispatcher"
+
+// DedicatedDispatcher returns whether the channel has a dispatcher of its own when the dispatchers are shared, which
+// is the case by default for the channels overriding the settings of their dispatcher Deployment.
+func (c *KafkaChannel) DedicatedDispatcher() (bool, error) {
+	value, ok := c.Annotations[DedicatedDispatcherAnnotation]
+	if !ok {
+		return c.HasDispatcherTemplate(), nil
+	}
+	return parseDedicatedDispatcher(value)
+}
+
+func parseDedicatedDispatcher(value string) (bool, error) {
+	dedicated, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid dedicated dispatcher %q, expected 'true' or 'false'", value)
+	}
+	return dedicated, nil
+}
+
+// The dispatcher template annotations override the dispatcher Deployment settings of the eventing-kafka config for
+// a single distributed KafkaChannel, which is then given a dispatcher of its own unless it is explicitly shared.
+const (
+	// DispatcherReplicasAnnotation sets the number of replicas of the dispatcher Deployment.
+	DispatcherReplicasAnnotation = "kafka.eventing.knative.dev/dispatcher-replicas"
+
+	// DispatcherCpuRequestAnnotation, DispatcherCpuLimitAnnotation, DispatcherMemoryRequestAnnotation and
+	// DispatcherMemoryLimitAnnotation set the resources of the dispatcher container as resource quantities.
+	DispatcherCpuRequestAnnotation    = "kafka.eventing.knative.dev/dispatcher-cpu-request"
+	DispatcherCpuLimitAnnotation      = "kafka.eventing.knative.dev/dispatcher-cpu-limit"
+	DispatcherMemoryRequestAnnotation = "kafka.eventing.knative.dev/dispatcher-memory-request"
+	DispatcherMemoryLimitAnnotation   = "kafka.eventing.knative.dev/dispatcher-memory-limit"
+
+	// DispatcherNodeSelectorAnnotation sets the node selector of the dispatcher pods as "key=value" pairs separated
+	// by commas.
+	DispatcherNodeSelectorAnnotation = "kafka.eventing.knative.dev/dispatcher-node-selector"
+
+	// DispatcherTolerationsAnnotation sets the tolerations of the dispatcher pods as a JSON array of tolerations.
+	DispatcherTolerationsAnnotation = "kafka.eventing.knative.dev/dispatcher-tolerations"
+
+	// DispatcherPriorityClassAnnotation sets the priority class name of the dispatcher pods.
+	DispatcherPriorityClassAnnotation = "kafka.eventing.knative.dev/dispatcher-priority-class"
+)
+
+// dispatcherTemplateAnnotations are the dispatcher template annotations, with a description of their expected values.
+var dispatcherTemplateAnnotations = []struct {
+	key      string
+	expected string
+}{
+	{DispatcherReplicasAnnotation, "a positive integer"},
+	{DispatcherCpuRequestAnnotation, "a positive resource quantity"},
+	{DispatcherCpuLimitAnnotation, "a positive resource quantity"},
+	{DispatcherMemoryRequestAnnotation, "a positive resource quantity"},
+	{DispatcherMemoryLimitAnnotation, "a positive resource quantity"},
+	{DispatcherNodeSelectorAnnotation, "comma separated 'key=value' node labels"},
+	{DispatcherTolerationsAnnotation, "a JSON array of tolerations"},
+	{DispatcherPriorityClassAnnotation, "a priority class name"},
+}
+
+// DispatcherTemplate holds the per-channel overrides of the dispatcher Deployment, which are empty when not specified.
+type DispatcherTemplate struct {
+	// Replicas is nil when not specified.
+	Replicas *int32
+	// Resources only holds the specified requests and limits.
+	Resources         corev1.ResourceRequirements
+	NodeSelector      map[string]string
+	Tolerations       []corev1.Toleration
+	PriorityClassName string
+}
+
+// HasDispatcherTemplate returns true if the channel overrides any setting of its dispatcher Deployment.
+func (c *KafkaChannel) HasDispatcherTemplate() bool {
+	for _, annotation := range dispatcherTemplateAnnotations {
+		if _, ok := c.Annotations[annotation.key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// DispatcherTemplate returns the overrides of the dispatcher Deployment of the channel.
+func (c *KafkaChannel) DispatcherTemplate() (DispatcherTemplate, error) {
+	var template DispatcherTemplate
+	for _, annotation := range dispatcherTemplateAnnotations {
+		if value, ok := c.Annotations[annotation.key]; ok {
+			if err := template.parseAnnotation(annotation.key, value, annotation.expected); err != nil {
+				return DispatcherTemplate{}, err
+			}
+		}
+	}
+	if err := template.validateResources(); err != nil {
+		return DispatcherTemplate{}, err
+	}
+	return template, nil
+}
+
+// parseAnnotation sets the setting of the specified dispatcher template annotation.
+func (t *DispatcherTemplate) parseAnnotation(annotation string, value string, expected string) error {
+	invalid := fmt.Errorf("invalid %s %q, expected %s", strings.TrimPrefix(annotation, "kafka.eventing.knative.dev/"), value, expected)
+	switch annotation {
+	case DispatcherReplicasAnnotation:
+		replicas, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || replicas <= 0 {
+			return invalid
+		}
+		r := int32(replicas)
+		t.Replicas = &r
+	case DispatcherCpuRequestAnnotation, DispatcherMemoryRequestAnnotation, DispatcherCpuLimitAnnotation, DispatcherMemoryLimitAnnotation:
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil || quantity.Sign() <= 0 {
+			return invalid
+		}
+		t.setResource(annotation, quantity)
+	case DispatcherNodeSelectorAnnotation:
+		nodeSelector, err := labels.ConvertSelectorToLabelsMap(value)
+		if err != nil || len(nodeSelector) == 0 {
+			return invalid
+		}
+		t.NodeSelector = nodeSelector
+	case DispatcherTolerationsAnnotation:
+		var tolerations []corev1.Toleration
+		if err := json.Unmarshal([]byte(value), &tolerations); err != nil {
+			return invalid
+		}
+		for _, toleration := range tolerations {
+			if !isValidToleration(toleration) {
+				return invalid
+			}
+		}
+		t.Tolerations = tolerations
+	case DispatcherPriorityClassAnnotation:
+		if len(validation.IsDNS1123Subdomain(value)) > 0 {
+			return invalid
+		}
+		t.PriorityClassName = value
+	}
+	return nil
+}
+
+// setResource sets the request or limit of the specified resource annotation.
+func (t *DispatcherTemplate) setResource(annotation string, quantity resource.Quantity) {
+	resources := &t.Resources.Requests
+	if annotation == DispatcherCpuLimitAnnotation || annotation == DispatcherMemoryLimitAnnotation {
+		resources = &t.Resources.Limits
+	}
+	if *resources == nil {
+		*resources = corev1.ResourceList{}
+	}
+	name := corev1.ResourceCPU
+	if annotation == DispatcherMemoryRequestAnnotation || annotation == DispatcherMemoryLimitAnnotation {
+		name = corev1.ResourceMemory
+	}
+	(*resources)[name] = quantity
+}
+
+// validateResources returns an error if a request exceeds the limit of the same resource.
+func (t *DispatcherTemplate) validateResources() error {
+	for name, request := range t.Resources.Requests {
+		if limit, ok := t.Resources.Limits[name]; ok && request.Cmp(limit) > 0 {
+			return fmt.Errorf("invalid dispatcher %s request %s, expected no more than the limit %s", name, request.String(), limit.String())
+		}
+	}
+	return nil
+}
+
+func isValidToleration(toleration corev1.Toleration) bool {
+	switch toleration.Operator {
+	case corev1.TolerationOpEqual, "":
+	case corev1.TolerationOpExists:
+		if toleration.Value != "" {
+			return false
+		}
+	default:
+		return false
+	}
+	switch toleration.Effect {
+	case corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute, "":
+		return true
+	}
+	return false
+}
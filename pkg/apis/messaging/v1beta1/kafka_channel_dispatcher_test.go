@@ -0,0 +1,154 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestKafkaChannelDedicatedDispatcher(t *testing.T) {
+	testCases := map[string]struct {
+		annotations map[string]string
+		want        bool
+		wantErr     bool
+	}{
+		"no annotations": {},
+		"dedicated": {
+			annotations: map[string]string{DedicatedDispatcherAnnotation: "true"},
+			want:        true,
+		},
+		"dispatcher template": {
+			annotations: map[string]string{DispatcherReplicasAnnotation: "2"},
+			want:        true,
+		},
+		"shared dispatcher template": {
+			annotations: map[string]string{DedicatedDispatcherAnnotation: "false", DispatcherReplicasAnnotation: "2"},
+		},
+		"invalid": {
+			annotations: map[string]string{DedicatedDispatcherAnnotation: "always"},
+			wantErr:     true,
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			kc := &KafkaChannel{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			got, err := kc.DedicatedDispatcher()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("DedicatedDispatcher() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("DedicatedDispatcher() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestKafkaChannelDispatcherTemplate(t *testing.T) {
+	replicas := int32(4)
+
+	testCases := map[string]struct {
+		annotations map[string]string
+		want        DispatcherTemplate
+		wantErr     bool
+	}{
+		"no annotations": {},
+		"all annotations": {
+			annotations: map[string]string{
+				DispatcherReplicasAnnotation:      "4",
+				DispatcherCpuRequestAnnotation:    "500m",
+				DispatcherCpuLimitAnnotation:      "2",
+				DispatcherMemoryRequestAnnotation: "128Mi",
+				DispatcherMemoryLimitAnnotation:   "512Mi",
+				DispatcherNodeSelectorAnnotation:  "pool=kafka, zone=a",
+				DispatcherTolerationsAnnotation:   `[{"key":"dedicated","operator":"Exists","effect":"NoExecute"}]`,
+				DispatcherPriorityClassAnnotation: "high-priority",
+			},
+			want: DispatcherTemplate{
+				Replicas: &replicas,
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("500m"),
+						corev1.ResourceMemory: resource.MustParse("128Mi"),
+					},
+					Limits: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("2"),
+						corev1.ResourceMemory: resource.MustParse("512Mi"),
+					},
+				},
+				NodeSelector:      map[string]string{"pool": "kafka", "zone": "a"},
+				Tolerations:       []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoExecute}},
+				PriorityClassName: "high-priority",
+			},
+		},
+		"only a limit": {
+			annotations: map[string]string{DispatcherCpuLimitAnnotation: "4"},
+			want: DispatcherTemplate{
+				Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")}},
+			},
+		},
+		"invalid replicas": {
+			annotations: map[string]string{DispatcherReplicasAnnotation: "-1"},
+			wantErr:     true,
+		},
+		"invalid quantity": {
+			annotations: map[string]string{DispatcherMemoryRequestAnnotation: "lots"},
+			wantErr:     true,
+		},
+		"invalid node selector": {
+			annotations: map[string]string{DispatcherNodeSelectorAnnotation: "pool"},
+			wantErr:     true,
+		},
+		"invalid tolerations": {
+			annotations: map[string]string{DispatcherTolerationsAnnotation: `{"key":"dedicated"}`},
+			wantErr:     true,
+		},
+		"invalid toleration effect": {
+			annotations: map[string]string{DispatcherTolerationsAnnotation: `[{"key":"dedicated","effect":"Sometimes"}]`},
+			wantErr:     true,
+		},
+		"invalid priority class": {
+			annotations: map[string]string{DispatcherPriorityClassAnnotation: "High Priority"},
+			wantErr:     true,
+		},
+		"request above limit": {
+			annotations: map[string]string{DispatcherCpuRequestAnnotation: "2", DispatcherCpuLimitAnnotation: "1"},
+			wantErr:     true,
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			kc := &KafkaChannel{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			if got := kc.HasDispatcherTemplate(); got != (len(tc.annotations) > 0) {
+				t.Errorf("HasDispatcherTemplate() = %v", got)
+			}
+			got, err := kc.DispatcherTemplate()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("DispatcherTemplate() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("DispatcherTemplate() (-want, +got) = %v", diff)
+			}
+		})
+	}
+}
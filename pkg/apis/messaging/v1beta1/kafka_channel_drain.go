@@ -0,0 +1,45 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"time"
+)
+
+// DrainTimeoutAnnotation drains a KafkaChannel before deleting it: its ingress is stopped, and its topic is only
+// deleted once every subscriber has consumed the remaining events, or once the timeout (a duration such as "10m")
+// has elapsed since the deletion.  Without the annotation the topic is deleted right away, as it always is by the
+// distributed channel implementation, which ignores the annotation.
+const DrainTimeoutAnnotation = "kafka.eventing.knative.dev/drain-timeout"
+
+// DrainTimeout returns the timeout of the DrainTimeoutAnnotation, zero if the channel is not drained.
+func (c *KafkaChannel) DrainTimeout() (time.Duration, error) {
+	value, ok := c.Annotations[DrainTimeoutAnnotation]
+	if !ok {
+		return 0, nil
+	}
+	return parseDrainTimeout(value)
+}
+
+func parseDrainTimeout(value string) (time.Duration, error) {
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf("invalid drain timeout %q, expected a positive duration", value)
+	}
+	return timeout, nil
+}
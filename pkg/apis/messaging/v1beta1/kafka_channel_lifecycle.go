@@ -17,10 +17,15 @@ limitations under the License.
 package v1beta1
 
 import (
+	"fmt"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 )
@@ -67,8 +72,33 @@ const (
 	// KafkaChannelConditionConfigReady has status True when the Kafka configuration to use by the channel exists and is valid
 	// (ie. the connection has been established).
 	KafkaChannelConditionConfigReady apis.ConditionType = "ConfigurationReady"
+
+	// KafkaChannelConditionSubscriberLagging has status True when the lag of a subscriber exceeds the
+	// threshold configured in config-kafka. It is informational and does not affect the readiness.
+	KafkaChannelConditionSubscriberLagging apis.ConditionType = "SubscriberLagging"
+
+	// KafkaChannelConditionSubscriberFailing has status True when the percentage of the events a subscriber
+	// recently failed to accept exceeds the threshold configured in config-kafka. It is informational and
+	// does not affect the readiness.
+	KafkaChannelConditionSubscriberFailing apis.ConditionType = "SubscriberFailing"
+
+	// KafkaChannelConditionKafkaClusterMismatch has status True when the Kafka cluster the channel is pinned to
+	// differs from the one its namespace is now mapped to in config-kafka. It is informational and does not affect
+	// the readiness, the channel staying in the cluster it is pinned to.
+	KafkaChannelConditionKafkaClusterMismatch apis.ConditionType = "KafkaClusterMismatch"
+
+	// KafkaChannelReasonDraining is the reason of the Addressable condition of a channel which is drained before
+	// its deletion (see DrainTimeoutAnnotation).
+	KafkaChannelReasonDraining = "Draining"
 )
 
+// SubscriberHealthThresholds are the lag and the failure percentage above which a subscriber is reported as
+// lagging or failing. A zero threshold disables the corresponding condition.
+type SubscriberHealthThresholds struct {
+	Lag               int64
+	FailurePercentage int32
+}
+
 // RegisterAlternateKafkaChannelConditionSet register a different apis.ConditionSet.
 func RegisterAlternateKafkaChannelConditionSet(conditionSet apis.ConditionSet) {
 	channelCondSetLock.Lock()
@@ -181,6 +211,21 @@ func (cs *KafkaChannelStatus) MarkTopicFailed(reason, messageFormat string, mess
 	cs.GetConditionSet().Manage(cs).MarkFalse(KafkaChannelConditionTopicReady, reason, messageFormat, messageA...)
 }
 
+// MarkDraining removes the address of a channel which is drained before its deletion, for its ingress to stop
+// while its subscribers consume the remaining events.
+func (cs *KafkaChannelStatus) MarkDraining(messageFormat string, messageA ...interface{}) {
+	if cs.Address != nil {
+		cs.Address.URL = nil
+	}
+	cs.GetConditionSet().Manage(cs).MarkFalse(KafkaChannelConditionAddressable, KafkaChannelReasonDraining, messageFormat, messageA...)
+}
+
+// IsDraining returns true if the channel is drained before its deletion (see MarkDraining).
+func (cs *KafkaChannelStatus) IsDraining() bool {
+	condition := cs.GetCondition(KafkaChannelConditionAddressable)
+	return condition != nil && condition.Status == corev1.ConditionFalse && condition.Reason == KafkaChannelReasonDraining
+}
+
 func (cs *KafkaChannelStatus) MarkConfigTrue() {
 	cs.GetConditionSet().Manage(cs).MarkTrue(KafkaChannelConditionConfigReady)
 }
@@ -188,3 +233,102 @@ func (cs *KafkaChannelStatus) MarkConfigTrue() {
 func (cs *KafkaChannelStatus) MarkConfigFailed(reason, messageFormat string, messageA ...interface{}) {
 	cs.GetConditionSet().Manage(cs).MarkFalse(KafkaChannelConditionConfigReady, reason, messageFormat, messageA...)
 }
+
+// SetSubscriberLag records the lag of the subscribers, as measured by the controller from the offsets committed by
+// their consumer groups, and sets the SubscriberLagging condition per the threshold.  The health of the subscribers
+// without a lag (i.e. which no longer exist) is removed.
+func (cs *KafkaChannelStatus) SetSubscriberLag(lags map[types.UID]int64, thresholds SubscriberHealthThresholds) {
+	health := make([]SubscriberHealth, 0, len(lags))
+	known := make(map[types.UID]bool, len(cs.SubscriberHealth))
+	for _, subscriber := range cs.SubscriberHealth {
+		if lag, ok := lags[subscriber.UID]; ok {
+			subscriber.Lag = lag
+			health = append(health, subscriber)
+			known[subscriber.UID] = true
+		}
+	}
+	uids := make([]string, 0, len(lags))
+	for uid := range lags {
+		if !known[uid] {
+			uids = append(uids, string(uid))
+		}
+	}
+	sort.Strings(uids)
+	for _, uid := range uids {
+		health = append(health, SubscriberHealth{UID: types.UID(uid), Lag: lags[types.UID(uid)]})
+	}
+	cs.SubscriberHealth = health
+
+	var lagging []string
+	for _, subscriber := range health {
+		if thresholds.Lag > 0 && subscriber.Lag > thresholds.Lag {
+			lagging = append(lagging, fmt.Sprintf("%s (lag %d)", subscriber.UID, subscriber.Lag))
+		}
+	}
+	cs.setSubscriberHealthCondition(KafkaChannelConditionSubscriberLagging, thresholds.Lag > 0, lagging,
+		"SubscribersLagging", "subscribers lagging by more than %d events: %s", thresholds.Lag)
+}
+
+// MergeSubscriberFailures merges the failures of the subscribers seen by a replica of the dispatcher (within the
+// window) into their health, and sets the SubscriberFailing condition per the threshold.  Each replica only sees the
+// events it dispatched, so rather than overwriting each other the replicas keep the highest failure percentage,
+// which is only lowered once the last failure of the subscriber is older than the window (i.e. once none of the
+// replicas has seen it fail since).
+func (cs *KafkaChannelStatus) MergeSubscriberFailures(failures []SubscriberHealth, thresholds SubscriberHealthThresholds, window time.Duration, now time.Time) {
+	for _, failure := range failures {
+		index := -1
+		for i := range cs.SubscriberHealth {
+			if cs.SubscriberHealth[i].UID == failure.UID {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			cs.SubscriberHealth = append(cs.SubscriberHealth, SubscriberHealth{UID: failure.UID})
+			index = len(cs.SubscriberHealth) - 1
+		}
+		health := &cs.SubscriberHealth[index]
+		recentFailure := health.LastFailureTime != nil && now.Sub(health.LastFailureTime.Time) <= window
+		if failure.FailurePercentage >= health.FailurePercentage || !recentFailure {
+			health.FailurePercentage = failure.FailurePercentage
+		}
+		if failure.LastFailureTime != nil && (health.LastFailureTime == nil || failure.LastFailureTime.After(health.LastFailureTime.Time)) {
+			health.LastFailureTime = failure.LastFailureTime.DeepCopy()
+			health.LastFailure = failure.LastFailure
+		}
+	}
+
+	var failing []string
+	for _, subscriber := range cs.SubscriberHealth {
+		if thresholds.FailurePercentage > 0 && subscriber.FailurePercentage > thresholds.FailurePercentage {
+			failing = append(failing, fmt.Sprintf("%s (%d%% failed)", subscriber.UID, subscriber.FailurePercentage))
+		}
+	}
+	cs.setSubscriberHealthCondition(KafkaChannelConditionSubscriberFailing, thresholds.FailurePercentage > 0, failing,
+		"SubscribersFailing", "subscribers failing more than %d%% of events: %s", thresholds.FailurePercentage)
+}
+
+// setSubscriberHealthCondition sets an informational condition to True if any subscriber exceeds its threshold,
+// which is described by the reason and message, and removes the condition if it is disabled.
+func (cs *KafkaChannelStatus) setSubscriberHealthCondition(t apis.ConditionType, enabled bool, subscribers []string, reason, messageFormat string, threshold interface{}) {
+	manager := cs.GetConditionSet().Manage(cs)
+	if !enabled {
+		_ = manager.ClearCondition(t)
+		return
+	}
+	if len(subscribers) == 0 {
+		manager.SetCondition(apis.Condition{
+			Type:     t,
+			Status:   corev1.ConditionFalse,
+			Severity: apis.ConditionSeverityInfo,
+		})
+		return
+	}
+	manager.SetCondition(apis.Condition{
+		Type:     t,
+		Status:   corev1.ConditionTrue,
+		Reason:   reason,
+		Message:  fmt.Sprintf(messageFormat, threshold, strings.Join(subscribers, ", ")),
+		Severity: apis.ConditionSeverityWarning,
+	})
+}
@@ -18,6 +18,7 @@ package v1beta1
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"knative.dev/pkg/apis"
@@ -26,6 +27,8 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 )
@@ -436,6 +439,151 @@ func TestKafkaChannelStatus_SetAddressable(t *testing.T) {
 	}
 }
 
+func TestKafkaChannelStatus_SetSubscriberLag(t *testing.T) {
+	cs := &KafkaChannelStatus{}
+	cs.InitializeConditions()
+	cs.MarkTopicTrue()
+	cs.SubscriberHealth = []SubscriberHealth{{UID: "uid-2", FailurePercentage: 80, LastFailure: "subscriber failed"}, {UID: "uid-4", Lag: 7}}
+
+	// Subscribers Exceeding The Threshold Are Reported In The Condition, Without Affecting The Readiness Or The Failures
+	lags := map[types.UID]int64{"uid-1": 5000, "uid-2": 10, "uid-3": 2000}
+	cs.SetSubscriberLag(lags, SubscriberHealthThresholds{Lag: 1000, FailurePercentage: 50})
+	assert.Equal(t, []SubscriberHealth{
+		{UID: "uid-2", Lag: 10, FailurePercentage: 80, LastFailure: "subscriber failed"},
+		{UID: "uid-1", Lag: 5000},
+		{UID: "uid-3", Lag: 2000},
+	}, cs.SubscriberHealth)
+	assert.Len(t, lags, 3)
+	lagging := cs.GetCondition(KafkaChannelConditionSubscriberLagging)
+	assert.Equal(t, corev1.ConditionTrue, lagging.Status)
+	assert.Equal(t, "SubscribersLagging", lagging.Reason)
+	assert.Equal(t, "subscribers lagging by more than 1000 events: uid-1 (lag 5000), uid-3 (lag 2000)", lagging.Message)
+	assert.Equal(t, apis.ConditionSeverityWarning, lagging.Severity)
+	assert.Nil(t, cs.GetCondition(KafkaChannelConditionSubscriberFailing))
+	assert.Equal(t, corev1.ConditionUnknown, cs.GetCondition(KafkaChannelConditionReady).Status)
+
+	// The Condition Is False Once The Subscribers Catch Up
+	cs.SetSubscriberLag(map[types.UID]int64{"uid-1": 0}, SubscriberHealthThresholds{Lag: 1000})
+	assert.Equal(t, []SubscriberHealth{{UID: "uid-1"}}, cs.SubscriberHealth)
+	assert.Equal(t, corev1.ConditionFalse, cs.GetCondition(KafkaChannelConditionSubscriberLagging).Status)
+	assert.Equal(t, apis.ConditionSeverityInfo, cs.GetCondition(KafkaChannelConditionSubscriberLagging).Severity)
+
+	// A Disabled Threshold Removes The Condition
+	cs.SetSubscriberLag(map[types.UID]int64{"uid-1": 5000}, SubscriberHealthThresholds{})
+	assert.Nil(t, cs.GetCondition(KafkaChannelConditionSubscriberLagging))
+}
+
+func TestKafkaChannelStatus_MergeSubscriberFailures(t *testing.T) {
+	now := time.Now()
+	recently := metav1.NewTime(now.Add(-time.Minute))
+	earlier := metav1.NewTime(now.Add(-2 * time.Minute))
+	longAgo := metav1.NewTime(now.Add(-time.Hour))
+	thresholds := SubscriberHealthThresholds{Lag: 1000, FailurePercentage: 50}
+	cs := &KafkaChannelStatus{}
+	cs.InitializeConditions()
+	cs.MarkTopicTrue()
+	cs.SubscriberHealth = []SubscriberHealth{{UID: "uid-1", Lag: 5000}}
+
+	// The Failures Of A Replica Are Added To The Health, Without Affecting The Lag
+	cs.MergeSubscriberFailures([]SubscriberHealth{
+		{UID: "uid-1", FailurePercentage: 80, LastFailureTime: &recently, LastFailure: "subscriber failed"},
+		{UID: "uid-2", FailurePercentage: 10, LastFailureTime: &earlier, LastFailure: "subscriber timed out"},
+	}, thresholds, 5*time.Minute, now)
+	assert.Equal(t, []SubscriberHealth{
+		{UID: "uid-1", Lag: 5000, FailurePercentage: 80, LastFailureTime: &recently, LastFailure: "subscriber failed"},
+		{UID: "uid-2", FailurePercentage: 10, LastFailureTime: &earlier, LastFailure: "subscriber timed out"},
+	}, cs.SubscriberHealth)
+	failing := cs.GetCondition(KafkaChannelConditionSubscriberFailing)
+	assert.Equal(t, corev1.ConditionTrue, failing.Status)
+	assert.Equal(t, "subscribers failing more than 50% of events: uid-1 (80% failed)", failing.Message)
+	assert.Equal(t, apis.ConditionSeverityWarning, failing.Severity)
+	assert.Nil(t, cs.GetCondition(KafkaChannelConditionSubscriberLagging))
+	assert.Equal(t, corev1.ConditionUnknown, cs.GetCondition(KafkaChannelConditionReady).Status)
+
+	// Another Replica Which Saw Fewer Failures Does Not Lower The Percentage While The Last Failure Is Recent, But A
+	// Higher Percentage Is Kept
+	cs.MergeSubscriberFailures([]SubscriberHealth{
+		{UID: "uid-1", FailurePercentage: 0},
+		{UID: "uid-2", FailurePercentage: 30, LastFailureTime: &longAgo, LastFailure: "subscriber refused"},
+	}, thresholds, 5*time.Minute, now)
+	assert.Equal(t, []SubscriberHealth{
+		{UID: "uid-1", Lag: 5000, FailurePercentage: 80, LastFailureTime: &recently, LastFailure: "subscriber failed"},
+		{UID: "uid-2", FailurePercentage: 30, LastFailureTime: &earlier, LastFailure: "subscriber timed out"},
+	}, cs.SubscriberHealth)
+	assert.Equal(t, corev1.ConditionTrue, cs.GetCondition(KafkaChannelConditionSubscriberFailing).Status)
+
+	// The Percentage Is Lowered Once The Last Failure Is Older Than The Window
+	cs.MergeSubscriberFailures([]SubscriberHealth{{UID: "uid-1", FailurePercentage: 0}}, thresholds, 5*time.Minute, now.Add(10*time.Minute))
+	assert.Equal(t, int32(0), cs.SubscriberHealth[0].FailurePercentage)
+	assert.Equal(t, corev1.ConditionFalse, cs.GetCondition(KafkaChannelConditionSubscriberFailing).Status)
+	assert.Equal(t, apis.ConditionSeverityInfo, cs.GetCondition(KafkaChannelConditionSubscriberFailing).Severity)
+
+	// A Disabled Threshold Removes The Condition
+	cs.MergeSubscriberFailures(nil, SubscriberHealthThresholds{}, 5*time.Minute, now)
+	assert.Nil(t, cs.GetCondition(KafkaChannelConditionSubscriberFailing))
+}
+
+func TestKafkaChannelStatus_MarkDraining(t *testing.T) {
+	cs := &KafkaChannelStatus{}
+	cs.InitializeConditions()
+	cs.SetAddress(apis.HTTP("example.com"))
+	assert.False(t, cs.IsDraining())
+
+	cs.MarkDraining("waiting for %d subscribers", 2)
+	assert.True(t, cs.IsDraining())
+	assert.Nil(t, cs.Address.URL)
+	addressable := cs.GetCondition(KafkaChannelConditionAddressable)
+	assert.Equal(t, corev1.ConditionFalse, addressable.Status)
+	assert.Equal(t, KafkaChannelReasonDraining, addressable.Reason)
+	assert.Equal(t, "waiting for 2 subscribers", addressable.Message)
+	assert.Equal(t, corev1.ConditionFalse, cs.GetCondition(KafkaChannelConditionReady).Status)
+
+	cs.SetAddress(apis.HTTP("example.com"))
+	assert.False(t, cs.IsDraining())
+}
+
+func TestKafkaChannel_DrainTimeout(t *testing.T) {
+	kc := &KafkaChannel{}
+	timeout, err := kc.DrainTimeout()
+	assert.Nil(t, err)
+	assert.Zero(t, timeout)
+
+	kc.Annotations = map[string]string{DrainTimeoutAnnotation: "90s"}
+	timeout, err = kc.DrainTimeout()
+	assert.Nil(t, err)
+	assert.Equal(t, 90*time.Second, timeout)
+
+	kc.Annotations[DrainTimeoutAnnotation] = "soon"
+	_, err = kc.DrainTimeout()
+	assert.NotNil(t, err)
+}
+
+func TestKafkaChannelStatus_TopicMigration(t *testing.T) {
+	cs := &KafkaChannelStatus{}
+	assert.Equal(t, "topic", cs.TopicName("topic"))
+	assert.Equal(t, "topic", cs.IngressTopicName("topic"))
+
+	// The Events Are Produced To The New Topic While The Subscribers Drain The Previous One
+	start := time.Unix(100, 0)
+	cs.StartTopicMigration("topic", "topic.G2", start)
+	assert.Equal(t, "topic", cs.TopicName("topic"))
+	assert.Equal(t, "topic.G2", cs.IngressTopicName("topic"))
+	assert.Equal(t, TopicMigrationDraining, cs.TopicMigration.Phase)
+	assert.Equal(t, start, cs.TopicMigration.LastTransitionTime.Time)
+
+	// The Subscribers Are Then Cut Over To The New Topic
+	cutOver := time.Unix(200, 0)
+	cs.CutOverTopicMigration(cutOver)
+	assert.Equal(t, "topic.G2", cs.TopicName("topic"))
+	assert.Equal(t, "topic.G2", cs.IngressTopicName("topic"))
+	assert.Equal(t, TopicMigrationCutOver, cs.TopicMigration.Phase)
+	assert.Equal(t, cutOver, cs.TopicMigration.LastTransitionTime.Time)
+
+	cs.CompleteTopicMigration()
+	assert.Nil(t, cs.TopicMigration)
+	assert.Equal(t, "topic.G2", cs.IngressTopicName("topic"))
+}
+
 func TestRegisterAlternateKafkaChannelConditionSet(t *testing.T) {
 
 	cs := apis.NewLivingConditionSet(apis.ConditionReady, "hello")
@@ -447,3 +595,29 @@ func TestRegisterAlternateKafkaChannelConditionSet(t *testing.T) {
 	assert.Equal(t, cs, kc.GetConditionSet())
 	assert.Equal(t, cs, kc.Status.GetConditionSet())
 }
+
+func TestKafkaChannelStatus_PinKafkaCluster(t *testing.T) {
+	cs := &KafkaChannelStatus{}
+	cs.InitializeConditions()
+
+	// A Channel Is Not Pinned Until Its First Reconciliation, Which Pins It Once
+	_, pinned := cs.PinnedKafkaCluster()
+	assert.False(t, pinned)
+	cs.PinKafkaCluster("")
+	cs.PinKafkaCluster("tenant-a")
+	cluster, pinned := cs.PinnedKafkaCluster()
+	assert.True(t, pinned)
+	assert.Equal(t, "", cluster)
+
+	// A Configured Cluster Differing From The Pinned One Is Reported, Without Affecting The Readiness
+	cs.MarkKafkaClusterMismatch("tenant-a")
+	mismatch := cs.GetCondition(KafkaChannelConditionKafkaClusterMismatch)
+	assert.Equal(t, corev1.ConditionTrue, mismatch.Status)
+	assert.Equal(t, `the channel stays in the default Kafka cluster it was created in, rather than the configured "tenant-a" one`, mismatch.Message)
+	assert.Equal(t, apis.ConditionSeverityWarning, mismatch.Severity)
+	assert.Equal(t, corev1.ConditionUnknown, cs.GetCondition(KafkaChannelConditionReady).Status)
+
+	// The Condition Is Removed Once The Configuration Agrees Again
+	cs.MarkKafkaClusterMismatch("")
+	assert.Nil(t, cs.GetCondition(KafkaChannelConditionKafkaClusterMismatch))
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TopicName returns the topic which the subscribers of the channel consume, which is the specified topic the
+// channel was created with unless it has been migrated to a new topic.
+func (cs *KafkaChannelStatus) TopicName(createdTopic string) string {
+	if cs.Topic != "" {
+		return cs.Topic
+	}
+	return createdTopic
+}
+
+// IngressTopicName returns the topic which the events of the channel are produced to, which is the topic the
+// channel is migrating to while its subscribers are draining the previous one.
+func (cs *KafkaChannelStatus) IngressTopicName(createdTopic string) string {
+	if cs.TopicMigration != nil && cs.TopicMigration.Phase == TopicMigrationDraining {
+		return cs.TopicMigration.ToTopic
+	}
+	return cs.TopicName(createdTopic)
+}
+
+// StartTopicMigration starts the migration of the channel from its current topic to a new topic.
+func (cs *KafkaChannelStatus) StartTopicMigration(fromTopic string, toTopic string, now time.Time) {
+	cs.TopicMigration = &TopicMigration{
+		FromTopic:          fromTopic,
+		ToTopic:            toTopic,
+		Phase:              TopicMigrationDraining,
+		LastTransitionTime: metav1.NewTime(now),
+	}
+}
+
+// CutOverTopicMigration makes the subscribers of a migrating channel consume the topic it is migrating to.
+func (cs *KafkaChannelStatus) CutOverTopicMigration(now time.Time) {
+	if cs.TopicMigration == nil {
+		return
+	}
+	cs.Topic = cs.TopicMigration.ToTopic
+	cs.TopicMigration.Phase = TopicMigrationCutOver
+	cs.TopicMigration.LastTransitionTime = metav1.NewTime(now)
+}
+
+// CompleteTopicMigration ends the migration of the channel, once the topic it migrated from has been deleted.
+func (cs *KafkaChannelStatus) CompleteTopicMigration() {
+	cs.TopicMigration = nil
+}
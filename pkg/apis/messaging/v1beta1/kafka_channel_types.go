@@ -20,6 +20,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
 	"knative.dev/pkg/apis"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
@@ -75,6 +76,76 @@ type KafkaChannelSpec struct {
 type KafkaChannelStatus struct {
 	// Channel conforms to Duck type Channelable.
 	eventingduck.ChannelableStatus `json:",inline"`
+
+	// SubscriberHealth is the delivery health of the subscribers, whose lag is measured by the controller and whose
+	// failures are reported by the replicas of the dispatcher.
+	// +optional
+	SubscriberHealth []SubscriberHealth `json:"subscriberHealth,omitempty"`
+
+	// Topic is the Kafka topic of the channel once it has been migrated to a new topic, empty while the channel
+	// uses the topic it was created with.
+	// +optional
+	Topic string `json:"topic,omitempty"`
+
+	// TopicMigration is the migration of the channel to a new topic in progress, if any.
+	// +optional
+	TopicMigration *TopicMigration `json:"topicMigration,omitempty"`
+
+	// KafkaCluster is the Kafka cluster the topic of the channel was created in, pinned by its first reconciliation
+	// so that a later change of the namespaces mapped to the clusters does not move the channel.  An empty name is
+	// the default cluster, while a nil KafkaCluster is not pinned yet.
+	// +optional
+	KafkaCluster *string `json:"kafkaCluster,omitempty"`
+}
+
+// TopicMigration is the migration of a KafkaChannel to a new topic, which is created with the number of partitions
+// and the replication factor of its spec when they no longer match those of its topic.
+type TopicMigration struct {
+	// FromTopic is the topic the channel is migrating from.
+	FromTopic string `json:"fromTopic"`
+
+	// ToTopic is the topic the channel is migrating to.
+	ToTopic string `json:"toTopic"`
+
+	// Phase is the phase of the migration.
+	Phase TopicMigrationPhase `json:"phase"`
+
+	// LastTransitionTime is when the migration entered its phase.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+}
+
+// TopicMigrationPhase is the phase of a TopicMigration.
+type TopicMigrationPhase string
+
+const (
+	// TopicMigrationDraining is the phase in which the events are produced to the ToTopic, while the subscribers
+	// consume the events left in the FromTopic.
+	TopicMigrationDraining TopicMigrationPhase = "Draining"
+
+	// TopicMigrationCutOver is the phase in which the subscribers consume the ToTopic, from its oldest events, until
+	// the FromTopic is deleted.
+	TopicMigrationCutOver TopicMigrationPhase = "CutOver"
+)
+
+// SubscriberHealth is the delivery health of a subscriber of the KafkaChannel, which is refreshed periodically.
+type SubscriberHealth struct {
+	// UID of the subscriber, matching its SubscriberStatus.
+	UID types.UID `json:"uid,omitempty"`
+
+	// Lag is the number of events of the channel which the subscriber has yet to be sent.
+	Lag int64 `json:"lag"`
+
+	// FailurePercentage is the highest percentage of the events recently dispatched by a replica of the dispatcher
+	// which the subscriber failed to accept.
+	FailurePercentage int32 `json:"failurePercentage"`
+
+	// LastFailureTime is when the subscriber last failed to accept an event.
+	// +optional
+	LastFailureTime *metav1.Time `json:"lastFailureTime,omitempty"`
+
+	// LastFailure is the error the subscriber last failed to accept an event with.
+	// +optional
+	LastFailure string `json:"lastFailure,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -36,6 +36,77 @@ func (c *KafkaChannel) Validate(ctx context.Context) *apis.FieldError {
 				errs = errs.Also(iv.ViaFieldKey("annotations", eventing.ScopeAnnotationKey).ViaField("metadata"))
 			}
 		}
+		if value, ok := c.Annotations[DrainTimeoutAnnotation]; ok {
+			if _, err := parseDrainTimeout(value); err != nil {
+				iv := apis.ErrInvalidValue(value, "")
+				iv.Details = "expected a positive duration"
+				errs = errs.Also(iv.ViaFieldKey("annotations", DrainTimeoutAnnotation).ViaField("metadata"))
+			}
+		}
+		if value, ok := c.Annotations[DedicatedDispatcherAnnotation]; ok {
+			if _, err := parseDedicatedDispatcher(value); err != nil {
+				iv := apis.ErrInvalidValue(value, "")
+				iv.Details = "expected 'true' or 'false'"
+				errs = errs.Also(iv.ViaFieldKey("annotations", DedicatedDispatcherAnnotation).ViaField("metadata"))
+			}
+		}
+		var template DispatcherTemplate
+		for _, annotation := range dispatcherTemplateAnnotations {
+			if value, ok := c.Annotations[annotation.key]; ok {
+				if err := template.parseAnnotation(annotation.key, value, annotation.expected); err != nil {
+					iv := apis.ErrInvalidValue(value, "")
+					iv.Details = "expected " + annotation.expected
+					errs = errs.Also(iv.ViaFieldKey("annotations", annotation.key).ViaField("metadata"))
+				}
+			}
+		}
+		if err := template.validateResources(); err != nil {
+			fe := &apis.FieldError{Message: "Invalid dispatcher resources", Paths: []string{apis.CurrentField}, Details: err.Error()}
+			errs = errs.Also(fe.ViaField("annotations").ViaField("metadata"))
+		}
+		for key, value := range c.Annotations {
+			if isDeliveryAnnotation(key, DeliveryOrderingAnnotation) && !isValidDeliveryOrdering(DeliveryOrdering(value)) {
+				iv := apis.ErrInvalidValue(value, "")
+				iv.Details = fmt.Sprintf("expected one of '%s', '%s' or '%s'", DeliveryUnordered, DeliveryOrderedByKey, DeliveryStrictlyOrdered)
+				errs = errs.Also(iv.ViaFieldKey("annotations", key).ViaField("metadata"))
+			} else if isDeliveryAnnotation(key, DeliveryConcurrencyAnnotation) {
+				if _, err := parseDeliveryConcurrency(value); err != nil {
+					iv := apis.ErrInvalidValue(value, "")
+					iv.Details = "expected a positive integer"
+					errs = errs.Also(iv.ViaFieldKey("annotations", key).ViaField("metadata"))
+				}
+			} else if isDeliveryAnnotation(key, DeliveryRetryTopicsAnnotation) {
+				if _, err := parseDeliveryRetryTopics(value); err != nil {
+					iv := apis.ErrInvalidValue(value, "")
+					iv.Details = "expected 'true' or 'false'"
+					errs = errs.Also(iv.ViaFieldKey("annotations", key).ViaField("metadata"))
+				}
+			} else if isDeliveryAnnotation(key, DeliveryStartOffsetAnnotation) {
+				if _, err := ParseDeliveryOffset(value); err != nil {
+					iv := apis.ErrInvalidValue(value, "")
+					iv.Details = fmt.Sprintf("expected '%s', '%s' or an RFC3339 timestamp", DeliveryOffsetEarliest, DeliveryOffsetLatest)
+					errs = errs.Also(iv.ViaFieldKey("annotations", key).ViaField("metadata"))
+				}
+			} else if isDeliveryAnnotation(key, DeliveryRewindAnnotation) {
+				if offset, err := ParseDeliveryOffset(value); err != nil || offset.Latest {
+					iv := apis.ErrInvalidValue(value, "")
+					iv.Details = fmt.Sprintf("expected '%s' or an RFC3339 timestamp", DeliveryOffsetEarliest)
+					errs = errs.Also(iv.ViaFieldKey("annotations", key).ViaField("metadata"))
+				}
+			}
+		}
+	}
+
+	// The topic of the channel stays in the Kafka cluster it was created in.
+	if apis.IsInUpdate(ctx) {
+		if original, ok := apis.GetBaseline(ctx).(*KafkaChannel); ok && original.KafkaCluster() != c.KafkaCluster() {
+			fe := &apis.FieldError{
+				Message: "Immutable annotation changed",
+				Paths:   []string{apis.CurrentField},
+				Details: fmt.Sprintf("the Kafka cluster can not be changed from %q to %q", original.KafkaCluster(), c.KafkaCluster()),
+			}
+			errs = errs.Also(fe.ViaFieldKey("annotations", KafkaClusterAnnotation).ViaField("metadata"))
+		}
 	}
 
 	return errs
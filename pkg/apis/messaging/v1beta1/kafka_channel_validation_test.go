@@ -149,6 +149,200 @@ func TestKafkaChannelValidation(t *testing.T) {
 				return fe
 			}(),
 		},
+		"valid delivery annotations": {
+			cr: &KafkaChannel{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						DeliveryOrderingAnnotation:            string(DeliveryOrderedByKey),
+						DeliveryConcurrencyAnnotation:         "10",
+						DeliveryOrderingAnnotation + ".uid-1": string(DeliveryStrictlyOrdered),
+					},
+				},
+				Spec: KafkaChannelSpec{
+					NumPartitions:     1,
+					ReplicationFactor: 1,
+				},
+			},
+			want: nil,
+		},
+		"invalid delivery ordering annotation": {
+			cr: &KafkaChannel{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						DeliveryOrderingAnnotation + ".uid-1": "notvalid",
+					},
+				},
+				Spec: KafkaChannelSpec{
+					NumPartitions:     1,
+					ReplicationFactor: 1,
+				},
+			},
+			want: func() *apis.FieldError {
+				fe := apis.ErrInvalidValue("notvalid", "metadata.annotations.[kafka.eventing.knative.dev/delivery-ordering.uid-1]")
+				fe.Details = "expected one of 'unordered', 'ordered-by-key' or 'strictly-ordered'"
+				return fe
+			}(),
+		},
+		"invalid delivery concurrency annotation": {
+			cr: &KafkaChannel{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						DeliveryConcurrencyAnnotation: "0",
+					},
+				},
+				Spec: KafkaChannelSpec{
+					NumPartitions:     1,
+					ReplicationFactor: 1,
+				},
+			},
+			want: func() *apis.FieldError {
+				fe := apis.ErrInvalidValue("0", "metadata.annotations.[kafka.eventing.knative.dev/delivery-concurrency]")
+				fe.Details = "expected a positive integer"
+				return fe
+			}(),
+		},
+		"invalid delivery retry topics annotation": {
+			cr: &KafkaChannel{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						DeliveryRetryTopicsAnnotation: "sometimes",
+					},
+				},
+				Spec: KafkaChannelSpec{
+					NumPartitions:     1,
+					ReplicationFactor: 1,
+				},
+			},
+			want: func() *apis.FieldError {
+				fe := apis.ErrInvalidValue("sometimes", "metadata.annotations.[kafka.eventing.knative.dev/delivery-retry-topics]")
+				fe.Details = "expected 'true' or 'false'"
+				return fe
+			}(),
+		},
+		"valid drain timeout annotation": {
+			cr: &KafkaChannel{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						DrainTimeoutAnnotation: "10m",
+					},
+				},
+				Spec: KafkaChannelSpec{
+					NumPartitions:     1,
+					ReplicationFactor: 1,
+				},
+			},
+			want: nil,
+		},
+		"invalid drain timeout annotation": {
+			cr: &KafkaChannel{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						DrainTimeoutAnnotation: "-1m",
+					},
+				},
+				Spec: KafkaChannelSpec{
+					NumPartitions:     1,
+					ReplicationFactor: 1,
+				},
+			},
+			want: func() *apis.FieldError {
+				fe := apis.ErrInvalidValue("-1m", "metadata.annotations.[kafka.eventing.knative.dev/drain-timeout]")
+				fe.Details = "expected a positive duration"
+				return fe
+			}(),
+		},
+		"valid dedicated dispatcher annotation": {
+			cr: &KafkaChannel{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						DedicatedDispatcherAnnotation: "true",
+					},
+				},
+				Spec: KafkaChannelSpec{
+					NumPartitions:     1,
+					ReplicationFactor: 1,
+				},
+			},
+			want: nil,
+		},
+		"invalid dedicated dispatcher annotation": {
+			cr: &KafkaChannel{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						DedicatedDispatcherAnnotation: "always",
+					},
+				},
+				Spec: KafkaChannelSpec{
+					NumPartitions:     1,
+					ReplicationFactor: 1,
+				},
+			},
+			want: func() *apis.FieldError {
+				fe := apis.ErrInvalidValue("always", "metadata.annotations.[kafka.eventing.knative.dev/dedicated-dispatcher]")
+				fe.Details = "expected 'true' or 'false'"
+				return fe
+			}(),
+		},
+		"valid dispatcher template annotations": {
+			cr: &KafkaChannel{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						DispatcherReplicasAnnotation:      "3",
+						DispatcherCpuRequestAnnotation:    "1",
+						DispatcherCpuLimitAnnotation:      "2",
+						DispatcherMemoryRequestAnnotation: "256Mi",
+						DispatcherNodeSelectorAnnotation:  "pool=kafka,zone=a",
+						DispatcherTolerationsAnnotation:   `[{"key":"dedicated","operator":"Equal","value":"kafka","effect":"NoSchedule"}]`,
+						DispatcherPriorityClassAnnotation: "high-priority",
+					},
+				},
+				Spec: KafkaChannelSpec{
+					NumPartitions:     1,
+					ReplicationFactor: 1,
+				},
+			},
+			want: nil,
+		},
+		"invalid dispatcher template annotations": {
+			cr: &KafkaChannel{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						DispatcherReplicasAnnotation:    "0",
+						DispatcherTolerationsAnnotation: `[{"key":"dedicated","operator":"Always"}]`,
+					},
+				},
+				Spec: KafkaChannelSpec{
+					NumPartitions:     1,
+					ReplicationFactor: 1,
+				},
+			},
+			want: func() *apis.FieldError {
+				fe := apis.ErrInvalidValue("0", "metadata.annotations.[kafka.eventing.knative.dev/dispatcher-replicas]")
+				fe.Details = "expected a positive integer"
+				tolerations := apis.ErrInvalidValue(`[{"key":"dedicated","operator":"Always"}]`, "metadata.annotations.[kafka.eventing.knative.dev/dispatcher-tolerations]")
+				tolerations.Details = "expected a JSON array of tolerations"
+				return fe.Also(tolerations)
+			}(),
+		},
+		"dispatcher resource request above limit": {
+			cr: &KafkaChannel{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						DispatcherMemoryRequestAnnotation: "1Gi",
+						DispatcherMemoryLimitAnnotation:   "512Mi",
+					},
+				},
+				Spec: KafkaChannelSpec{
+					NumPartitions:     1,
+					ReplicationFactor: 1,
+				},
+			},
+			want: &apis.FieldError{
+				Message: "Invalid dispatcher resources",
+				Paths:   []string{"metadata.annotations"},
+				Details: "invalid dispatcher memory request 1Gi, expected no more than the limit 512Mi",
+			},
+		},
 	}
 
 	for n, test := range testCases {
@@ -160,3 +354,56 @@ func TestKafkaChannelValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestKafkaChannelClusterImmutable(t *testing.T) {
+	channel := func(cluster string) *KafkaChannel {
+		kc := &KafkaChannel{Spec: KafkaChannelSpec{NumPartitions: 1, ReplicationFactor: 1}}
+		if cluster != "" {
+			kc.Annotations = map[string]string{KafkaClusterAnnotation: cluster}
+		}
+		return kc
+	}
+
+	testCases := map[string]struct {
+		original *KafkaChannel
+		updated  *KafkaChannel
+		want     *apis.FieldError
+	}{
+		"unchanged cluster": {
+			original: channel("tenant-a"),
+			updated:  channel("tenant-a"),
+		},
+		"unchanged default cluster": {
+			original: channel(""),
+			updated:  channel(""),
+		},
+		"changed cluster": {
+			original: channel("tenant-a"),
+			updated:  channel("tenant-b"),
+			want: &apis.FieldError{
+				Message: "Immutable annotation changed",
+				Paths:   []string{"metadata.annotations.[kafka.eventing.knative.dev/cluster]"},
+				Details: `the Kafka cluster can not be changed from "tenant-a" to "tenant-b"`,
+			},
+		},
+		"cluster added": {
+			original: channel(""),
+			updated:  channel("tenant-a"),
+			want: &apis.FieldError{
+				Message: "Immutable annotation changed",
+				Paths:   []string{"metadata.annotations.[kafka.eventing.knative.dev/cluster]"},
+				Details: `the Kafka cluster can not be changed from "" to "tenant-a"`,
+			},
+		},
+	}
+
+	for n, test := range testCases {
+		t.Run(n, func(t *testing.T) {
+			ctx := apis.WithinUpdate(context.Background(), test.original)
+			got := test.updated.Validate(ctx)
+			if diff := cmp.Diff(test.want.Error(), got.Error()); diff != "" {
+				t.Errorf("%s: validate (-want, +got) = %v", n, diff)
+			}
+		})
+	}
+}
@@ -106,6 +106,23 @@ func (in *KafkaChannelSpec) DeepCopy() *KafkaChannelSpec {
 func (in *KafkaChannelStatus) DeepCopyInto(out *KafkaChannelStatus) {
 	*out = *in
 	in.ChannelableStatus.DeepCopyInto(&out.ChannelableStatus)
+	if in.SubscriberHealth != nil {
+		in, out := &in.SubscriberHealth, &out.SubscriberHealth
+		*out = make([]SubscriberHealth, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TopicMigration != nil {
+		in, out := &in.TopicMigration, &out.TopicMigration
+		*out = new(TopicMigration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KafkaCluster != nil {
+		in, out := &in.KafkaCluster, &out.KafkaCluster
+		*out = new(string)
+		**out = **in
+	}
 	return
 }
 
@@ -118,3 +135,40 @@ func (in *KafkaChannelStatus) DeepCopy() *KafkaChannelStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubscriberHealth) DeepCopyInto(out *SubscriberHealth) {
+	*out = *in
+	if in.LastFailureTime != nil {
+		in, out := &in.LastFailureTime, &out.LastFailureTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubscriberHealth.
+func (in *SubscriberHealth) DeepCopy() *SubscriberHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(SubscriberHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TopicMigration) DeepCopyInto(out *TopicMigration) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TopicMigration.
+func (in *TopicMigration) DeepCopy() *TopicMigration {
+	if in == nil {
+		return nil
+	}
+	out := new(TopicMigration)
+	in.DeepCopyInto(out)
+	return out
+}
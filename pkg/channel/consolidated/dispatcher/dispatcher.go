@@ -5,7 +5,7 @@ Licensed under the Apache License, Version 2.0 (the "License");
 you may not use this file except in compliance with the License.
 You may obtain a copy of the License at
 
-    http://www.apache.org/licenses/LICENSE-2.0
+	http://www.apache.org/licenses/LICENSE-2.0
 
 Unless required by applicable law or agreed to in writing, software
 distributed under the License is distributed on an "AS IS" BASIS,
@@ -23,6 +23,7 @@ import (
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"knative.dev/eventing-kafka/pkg/source"
 
@@ -35,9 +36,16 @@ import (
 	"go.opencensus.io/trace"
 	"go.uber.org/zap"
 	"k8s.io/apimachinery/pkg/types"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/channel/consolidated/dispatcher/health"
 	"knative.dev/eventing-kafka/pkg/channel/consolidated/utils"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/common/env"
+	kafkasarama "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/sarama"
 	"knative.dev/eventing-kafka/pkg/common/consumer"
+	"knative.dev/eventing-kafka/pkg/common/deadletter"
+	dispatchmetrics "knative.dev/eventing-kafka/pkg/common/metrics"
+	commonproducer "knative.dev/eventing-kafka/pkg/common/producer"
+	"knative.dev/eventing-kafka/pkg/common/retrytopic"
 	eventingchannels "knative.dev/eventing/pkg/channel"
 	"knative.dev/eventing/pkg/channel/fanout"
 	"knative.dev/eventing/pkg/kncloudevents"
@@ -52,21 +60,50 @@ type KafkaDispatcher struct {
 	receiver   *eventingchannels.MessageReceiver
 	dispatcher *eventingchannels.MessageDispatcherImpl
 
-	kafkaSyncProducer    sarama.SyncProducer
+	// kafkaSyncProducer holds the current producerRef, which is replaced when the sarama settings change
+	kafkaSyncProducer    atomic.Value
 	channelSubscriptions map[eventingchannels.ChannelReference][]types.UID
 	subsConsumerGroups   map[types.UID]sarama.ConsumerGroup
+	subsHandlers         map[types.UID]*consumerMessageHandler
 	subscriptions        map[types.UID]Subscription
 	// consumerUpdateLock must be used to update kafkaConsumers
 	consumerUpdateLock   sync.Mutex
 	kafkaConsumerFactory consumer.KafkaConsumerGroupFactory
+	// args and saramaConfig (the sarama config built from args, without the auth config) are those the producer
+	// and consumer groups were created with, and are used to detect changes to the sarama settings
+	args         KafkaDispatcherArgs
+	saramaConfig *sarama.Config
+	// healthServer (optional) reports the producer and the consumer groups
+	healthServer *health.Server
+	// clusters are the producers and consumer group factories of the named Kafka clusters of the args, created
+	// when first used (the channels of the default cluster use kafkaSyncProducer and kafkaConsumerFactory)
+	clusters     map[string]*kafkaCluster
+	clustersLock sync.Mutex
+	// channelClusters holds the map of the channels to the name of their Kafka cluster, empty for the default one
+	channelClusters atomic.Value
+	// channelTopics holds the map of the channels to the topic their events are produced to, when it is not the
+	// topic of the topicFunc (i.e. for the channels which have been migrated to a new topic)
+	channelTopics atomic.Value
 
 	topicFunc TopicFunc
 	logger    *zap.SugaredLogger
 }
 
+// kafkaCluster is the producer and the consumer group factory of a named Kafka cluster.
+type kafkaCluster struct {
+	producer        sarama.SyncProducer
+	consumerFactory consumer.KafkaConsumerGroupFactory
+}
+
+// producerRef wraps the producer stored in the kafkaSyncProducer atomic.Value, which requires a consistent type.
+type producerRef struct {
+	sarama.SyncProducer
+}
+
 type Subscription struct {
 	UID types.UID
 	fanout.Subscription
+	Delivery kafkav1beta1.SubscriberDelivery
 }
 
 func (sub Subscription) String() string {
@@ -85,37 +122,41 @@ func (sub Subscription) String() string {
 		s.WriteString("DeadLetter: " + sub.DeadLetter.String())
 		s.WriteRune('\n')
 	}
+	if sub.Delivery.Ordering != "" {
+		s.WriteString(fmt.Sprintf("Delivery: %s (concurrency %d)", sub.Delivery.Ordering, sub.Delivery.Concurrency))
+		s.WriteRune('\n')
+	}
 	return s.String()
 }
 
 func NewDispatcher(ctx context.Context, args *KafkaDispatcherArgs) (*KafkaDispatcher, error) {
-	conf := sarama.NewConfig()
-	conf.Version = sarama.V2_0_0_0
-	conf.ClientID = args.ClientID
-	conf.Consumer.Return.Errors = true    // Returns the errors in ConsumerGroup#Errors() https://godoc.org/github.com/Shopify/sarama#ConsumerGroup
-	conf.Producer.Return.Successes = true // Must be enabled for sync producer
-
-	// append the auth info:
-	err := source.UpdateSaramaConfigWithKafkaAuthConfig(conf, args.KafkaAuthConfig)
+	saramaConfig, err := newSaramaConfig(args)
 	if err != nil {
-		return nil, fmt.Errorf("Error updating the Sarama Auth config: %w", err)
+		return nil, err
 	}
-
-	producer, err := sarama.NewSyncProducer(args.Brokers, conf)
+	conf, producer, err := newProducer(args.Brokers, args.KafkaAuthConfig, saramaConfig)
 	if err != nil {
-		return nil, fmt.Errorf("unable to create kafka producer against Kafka bootstrap servers %v : %v", args.Brokers, err)
+		return nil, err
 	}
 
 	dispatcher := &KafkaDispatcher{
 		dispatcher:           eventingchannels.NewMessageDispatcher(args.Logger.Desugar()),
-		kafkaConsumerFactory: consumer.NewConsumerGroupFactory(args.Brokers, conf),
+		kafkaConsumerFactory: newConsumerGroupFactory(args.Brokers, conf),
 		channelSubscriptions: make(map[eventingchannels.ChannelReference][]types.UID),
 		subsConsumerGroups:   make(map[types.UID]sarama.ConsumerGroup),
+		subsHandlers:         make(map[types.UID]*consumerMessageHandler),
 		subscriptions:        make(map[types.UID]Subscription),
-		kafkaSyncProducer:    producer,
+		args:                 *args,
+		saramaConfig:         saramaConfig,
+		healthServer:         args.HealthServer,
+		clusters:             make(map[string]*kafkaCluster),
 		logger:               args.Logger,
 		topicFunc:            args.TopicFunc,
 	}
+	dispatcher.setProducer(producer)
+	if dispatcher.healthServer != nil {
+		dispatcher.healthServer.SetProducerReady(true)
+	}
 
 	podName, err := env.GetRequiredConfigValue(args.Logger.Desugar(), env.PodNameEnvVarKey)
 	if err != nil {
@@ -129,7 +170,7 @@ func NewDispatcher(ctx context.Context, args *KafkaDispatcherArgs) (*KafkaDispat
 	receiverFunc, err := eventingchannels.NewMessageReceiver(
 		func(ctx context.Context, channel eventingchannels.ChannelReference, message binding.Message, transformers []binding.Transformer, _ nethttp.Header) error {
 			kafkaProducerMessage := sarama.ProducerMessage{
-				Topic: dispatcher.topicFunc(utils.KafkaChannelSeparator, channel.Namespace, channel.Name),
+				Topic: dispatcher.ingressTopic(channel),
 			}
 
 			dispatcher.logger.Debugw("Received a new message from MessageReceiver, dispatching to Kafka", zap.Any("channel", channel))
@@ -140,7 +181,11 @@ func NewDispatcher(ctx context.Context, args *KafkaDispatcherArgs) (*KafkaDispat
 
 			kafkaProducerMessage.Headers = append(kafkaProducerMessage.Headers, tracing.SerializeTrace(trace.FromContext(ctx).SpanContext())...)
 
-			partition, offset, err := dispatcher.kafkaSyncProducer.SendMessage(&kafkaProducerMessage)
+			producer, err := dispatcher.channelProducer(channel)
+			if err != nil {
+				return err
+			}
+			partition, offset, err := producer.SendMessage(&kafkaProducerMessage)
 
 			if err == nil {
 				dispatcher.logger.Debugw("message sent", zap.Int32("partition", partition), zap.Int64("offset", offset))
@@ -162,6 +207,174 @@ func NewDispatcher(ctx context.Context, args *KafkaDispatcherArgs) (*KafkaDispat
 	return dispatcher, nil
 }
 
+// newSaramaConfig returns the sarama config of the specified args, without their auth config (see newProducer).
+func newSaramaConfig(args *KafkaDispatcherArgs) (*sarama.Config, error) {
+	conf, err := utils.NewSaramaConfig(args.SaramaSettings)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sarama settings: %w", err)
+	}
+	conf.ClientID = args.ClientID
+	conf.Consumer.Return.Errors = true    // Returns the errors in ConsumerGroup#Errors() https://godoc.org/github.com/Shopify/sarama#ConsumerGroup
+	conf.Producer.Return.Successes = true // Must be enabled for sync producer
+	commonproducer.SetBatching(conf, args.ProducerLinger, args.ProducerBatchSize)
+	return conf, nil
+}
+
+// newProducer adds the auth config of a Kafka cluster to a copy of the sarama config, returning that copy and a
+// producer created with it.  The ingress events received concurrently are batched together by the sync producer,
+// each request still waiting for its own event.
+func newProducer(brokers []string, authConfig *utils.KafkaAuthConfig, saramaConfig *sarama.Config) (*sarama.Config, sarama.SyncProducer, error) {
+	conf := *saramaConfig
+	err := source.UpdateSaramaConfigWithKafkaAuthConfig(&conf, authConfig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error updating the Sarama Auth config: %w", err)
+	}
+
+	producer, err := newSyncProducer(brokers, &conf)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to create kafka producer against Kafka bootstrap servers %v : %v", brokers, err)
+	}
+	return &conf, producer, nil
+}
+
+// newSyncProducer and newConsumerGroupFactory are variables so that tests can replace them.
+var newSyncProducer = sarama.NewSyncProducer
+
+var newConsumerGroupFactory = consumer.NewConsumerGroupFactory
+
+// UpdateKafkaConfig applies the sarama settings and the producer batching of a reloaded config-kafka.  When they
+// change the sarama config, the producers are replaced and every consumer group restarted with the new config (the
+// brokers and auth configs of the dispatcher, including those of the named clusters, are not reloaded).
+func (d *KafkaDispatcher) UpdateKafkaConfig(kafkaConfig *utils.KafkaConfig) error {
+	args := d.args
+	args.SaramaSettings = kafkaConfig.SaramaSettings
+	args.ProducerLinger = kafkaConfig.ProducerLinger
+	args.ProducerBatchSize = kafkaConfig.ProducerBatchSize
+	saramaConfig, err := newSaramaConfig(&args)
+	if err != nil {
+		return err
+	}
+
+	d.consumerUpdateLock.Lock()
+	defer d.consumerUpdateLock.Unlock()
+
+	if d.saramaConfig != nil && saramaConfigEqual(saramaConfig, d.saramaConfig) {
+		d.logger.Debug("No sarama config changes, keeping the producer and consumer groups")
+		return nil
+	}
+
+	conf, producer, err := newProducer(args.Brokers, args.KafkaAuthConfig, saramaConfig)
+	if err != nil {
+		return err
+	}
+	d.logger.Info("Sarama config changed, replacing the producers and restarting the consumer groups")
+	previousProducers := []sarama.SyncProducer{d.producer()}
+	d.setProducer(producer)
+	d.kafkaConsumerFactory = newConsumerGroupFactory(args.Brokers, conf)
+
+	// the named clusters are created again with the new config when next used
+	d.clustersLock.Lock()
+	for _, cluster := range d.clusters {
+		previousProducers = append(previousProducers, cluster.producer)
+	}
+	d.clusters = make(map[string]*kafkaCluster)
+	d.args = args
+	d.saramaConfig = saramaConfig
+	d.clustersLock.Unlock()
+
+	err = d.restartConsumerGroups()
+
+	// the consumer groups using the previous producers (for retry topics and dead letter sinks) are closed by now
+	for _, previousProducer := range previousProducers {
+		if previousProducer == nil {
+			continue
+		}
+		if closeErr := previousProducer.Close(); closeErr != nil {
+			d.logger.Warnw("Failed to close a previous producer", zap.Error(closeErr))
+		}
+	}
+	return err
+}
+
+// saramaConfigEqual compares sarama configs like kafkasarama.ConfigEqual, which ignores the rebalance strategy.
+func saramaConfigEqual(config1 *sarama.Config, config2 *sarama.Config) bool {
+	return kafkasarama.ConfigEqual(config1, config2) &&
+		config1.Consumer.Group.Rebalance.Strategy.Name() == config2.Consumer.Group.Rebalance.Strategy.Name()
+}
+
+// restartConsumerGroups replaces the consumer group of every subscription with one created by the current consumer
+// group factory.  The subscriptions which could not be restarted are left unsubscribed, to be subscribed again by
+// the next update of the consumers.
+// restartConsumerGroups must be called under updateLock.
+func (d *KafkaDispatcher) restartConsumerGroups() error {
+	var failed []types.UID
+	for channelRef, uids := range d.channelSubscriptions {
+		for _, uid := range append([]types.UID{}, uids...) {
+			sub := d.subscriptions[uid]
+			clusterName, topicName, fromOldest := d.subscribedCluster(uid), d.subscribedTopic(uid), d.subscribedFromOldest(uid)
+			if err := d.unsubscribe(channelRef, sub); err != nil {
+				d.logger.Warnw("Failed to close a consumer group", zap.Any("subscription", uid), zap.Error(err))
+			}
+			if err := d.subscribe(channelRef, clusterName, topicName, fromOldest, sub); err != nil {
+				failed = append(failed, uid)
+			}
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("unable to restart the consumer groups of the subscriptions %v", failed)
+	}
+	return nil
+}
+
+func (d *KafkaDispatcher) producer() sarama.SyncProducer {
+	if ref, ok := d.kafkaSyncProducer.Load().(producerRef); ok {
+		return ref.SyncProducer
+	}
+	return nil
+}
+
+func (d *KafkaDispatcher) setProducer(producer sarama.SyncProducer) {
+	d.kafkaSyncProducer.Store(producerRef{producer})
+}
+
+// cluster returns the named Kafka cluster, creating its producer and consumer group factory when first used.  A
+// cluster which can not be created is attempted again when next used, without affecting the other clusters.
+func (d *KafkaDispatcher) cluster(name string) (*kafkaCluster, error) {
+	d.clustersLock.Lock()
+	defer d.clustersLock.Unlock()
+	if cluster, ok := d.clusters[name]; ok {
+		return cluster, nil
+	}
+	clusterArgs, ok := d.args.Clusters[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown Kafka cluster %q, the dispatcher must be restarted to use new clusters", name)
+	}
+	conf, producer, err := newProducer(clusterArgs.Brokers, clusterArgs.KafkaAuthConfig, d.saramaConfig)
+	if err != nil {
+		return nil, fmt.Errorf("Kafka cluster %q: %w", name, err)
+	}
+	d.logger.Infow("Created the producer of a Kafka cluster", zap.String("cluster", name), zap.Strings("brokers", clusterArgs.Brokers))
+	cluster := &kafkaCluster{producer: producer, consumerFactory: newConsumerGroupFactory(clusterArgs.Brokers, conf)}
+	if d.clusters == nil {
+		d.clusters = make(map[string]*kafkaCluster)
+	}
+	d.clusters[name] = cluster
+	return cluster, nil
+}
+
+// channelProducer returns the producer of the Kafka cluster of a channel.
+func (d *KafkaDispatcher) channelProducer(channel eventingchannels.ChannelReference) (sarama.SyncProducer, error) {
+	clusterName := d.getChannelClusters()[channel]
+	if clusterName == "" {
+		return d.producer(), nil
+	}
+	cluster, err := d.cluster(clusterName)
+	if err != nil {
+		return nil, err
+	}
+	return cluster.producer, nil
+}
+
 type TopicFunc func(separator, namespace, name string) string
 
 type KafkaDispatcherArgs struct {
@@ -171,15 +384,64 @@ type KafkaDispatcherArgs struct {
 	KafkaAuthConfig    *utils.KafkaAuthConfig
 	TopicFunc          TopicFunc
 	Logger             *zap.SugaredLogger
+	// ProducerLinger and ProducerBatchSize configure the batching of the produced events (see utils.KafkaConfig)
+	ProducerLinger    time.Duration
+	ProducerBatchSize int
+	// SaramaSettings is the sarama section of config-kafka (see utils.NewSaramaConfig)
+	SaramaSettings string
+	// HealthServer (optional) is updated with the state of the producer and of the consumer groups
+	HealthServer *health.Server
+	// Clusters are the named Kafka clusters, which the channels not using the default cluster of the Brokers use
+	Clusters map[string]KafkaClusterArgs
+}
+
+// KafkaClusterArgs are the brokers and the auth config of a named Kafka cluster (see utils.KafkaConfig.Clusters).
+type KafkaClusterArgs struct {
+	Brokers         []string
+	KafkaAuthConfig *utils.KafkaAuthConfig
 }
 
 type consumerMessageHandler struct {
 	logger     *zap.SugaredLogger
-	sub        Subscription
 	dispatcher *eventingchannels.MessageDispatcherImpl
+	// producer is used for retry topics and for dead letter sinks which are Kafka topics
+	producer sarama.SyncProducer
+	// cluster is the name of the Kafka cluster the subscription is consumed from, empty for the default one
+	cluster string
+	// topic is the channel topic the subscription is consumed from
+	topic string
+	// fromOldest starts the new consumer groups of the subscription at the oldest offset of the topic, which is
+	// the case of the topic a channel is cut over to (see ChannelConfig.MigrationCutOver)
+	fromOldest bool
+	// reporter records the dispatch metrics and the consumer lag of the subscription
+	reporter *dispatchmetrics.SubscriptionReporter
+	// subscription holds the current *handlerSubscription, which is replaced as a whole when the subscription is
+	// updated so that every message is dispatched with a consistent set of settings
+	subscription atomic.Value
 }
 
-func (c consumerMessageHandler) Handle(ctx context.Context, consumerMessage *sarama.ConsumerMessage) (bool, error) {
+// handlerSubscription is the part of a consumerMessageHandler which can be updated without restarting its consumer group.
+type handlerSubscription struct {
+	sub         Subscription
+	retryTopics retrytopic.Topics
+}
+
+func newConsumerMessageHandler(logger *zap.SugaredLogger, sub Subscription, dispatcher *eventingchannels.MessageDispatcherImpl, producer sarama.SyncProducer, cluster string, topic string, retryTopics retrytopic.Topics, reporter *dispatchmetrics.SubscriptionReporter) *consumerMessageHandler {
+	handler := &consumerMessageHandler{logger: logger, dispatcher: dispatcher, producer: producer, cluster: cluster, topic: topic, reporter: reporter}
+	handler.update(sub, retryTopics)
+	return handler
+}
+
+// update replaces the subscription of the handler, messages being dispatched keep using the previous one.
+func (c *consumerMessageHandler) update(sub Subscription, retryTopics retrytopic.Topics) {
+	c.subscription.Store(&handlerSubscription{sub: sub, retryTopics: retryTopics})
+}
+
+func (c *consumerMessageHandler) current() *handlerSubscription {
+	return c.subscription.Load().(*handlerSubscription)
+}
+
+func (c *consumerMessageHandler) Handle(ctx context.Context, consumerMessage *sarama.ConsumerMessage) (bool, error) {
 	defer func() {
 		if r := recover(); r != nil {
 			c.logger.Warn("Panic happened while handling a message",
@@ -190,32 +452,80 @@ func (c consumerMessageHandler) Handle(ctx context.Context, consumerMessage *sar
 	}()
 	message := protocolkafka.NewMessageFromConsumerMessage(consumerMessage)
 	if message.ReadEncoding() == binding.EncodingUnknown {
-		return false, errors.New("received a message with unknown encoding")
+		// The message can never be delivered, so don't block a strictly-ordered partition on it.
+		return true, errors.New("received a message with unknown encoding")
 	}
 
+	current := c.current()
 	c.logger.Debug("Going to dispatch the message",
 		zap.String("topic", consumerMessage.Topic),
-		zap.String("subscription", c.sub.String()),
+		zap.String("subscription", current.sub.String()),
 	)
 
 	ctx, span := tracing.StartTraceFromMessage(c.logger, ctx, message, consumerMessage.Topic)
 	defer span.End()
 
-	_, err := c.dispatcher.DispatchMessageWithRetries(
+	start := time.Now()
+	result, err := retrytopic.DispatchMessage(
 		ctx,
+		current.retryTopics,
+		c.dispatcher,
+		c.producer,
 		message,
-		nil,
-		c.sub.Subscriber,
-		c.sub.Reply,
-		c.sub.DeadLetter,
-		c.sub.RetryConfig,
+		consumerMessage,
+		current.sub.Subscriber,
+		current.sub.Reply,
+		current.sub.DeadLetter,
+		current.sub.RetryConfig,
 	)
+	c.reportDispatch(result, time.Since(start))
 
 	// NOTE: only return `true` here if DispatchMessage actually delivered the message.
 	return err == nil, err
 }
 
+func (c *consumerMessageHandler) reportDispatch(result *deadletter.Result, latency time.Duration) {
+	if c.reporter == nil || result == nil {
+		return
+	}
+	err := c.reporter.ReportDispatch(dispatchmetrics.DispatchResult{
+		ResponseCode: result.ResponseCode(),
+		Latency:      latency,
+		Retries:      result.Retries,
+		DeadLettered: result.DeadLettered,
+		Error:        result.Error,
+	})
+	if err != nil {
+		c.logger.Warnw("Failed to report the dispatch metrics", zap.Error(err))
+	}
+}
+
+// ReportLag records the number of messages of a partition which the consumer group has yet to consume.
+func (c *consumerMessageHandler) ReportLag(topic string, partition int32, lag int64) {
+	if c.reporter == nil {
+		return
+	}
+	if err := c.reporter.ReportLag(topic, partition, lag); err != nil {
+		c.logger.Warnw("Failed to report the consumer lag", zap.Error(err))
+	}
+}
+
+// SubscriberDelivery returns the delivery ordering of the subscription, defaulting to unordered.
+func (c *consumerMessageHandler) SubscriberDelivery() kafkav1beta1.SubscriberDelivery {
+	delivery := c.current().sub.Delivery
+	if delivery.Ordering == "" {
+		delivery = kafkav1beta1.DefaultSubscriberDelivery()
+	}
+	// the events of a migrated topic were produced while the subscription consumed the previous topic
+	if c.fromOldest && delivery.Rewind == "" {
+		delivery.StartOffset = kafkav1beta1.DeliveryOffsetEarliest
+	}
+	return delivery
+}
+
 var _ consumer.KafkaConsumerHandler = (*consumerMessageHandler)(nil)
+var _ consumer.KafkaConsumerDeliveryHandler = (*consumerMessageHandler)(nil)
+var _ consumer.KafkaConsumerLagHandler = (*consumerMessageHandler)(nil)
 
 type Config struct {
 	// The configuration of each channel in this handler.
@@ -227,6 +537,17 @@ type ChannelConfig struct {
 	Name          string
 	HostName      string
 	Subscriptions []Subscription
+	// KafkaCluster is the name of the Kafka cluster of the channel, empty for the default cluster.
+	KafkaCluster string
+	// Topic is the topic the subscriptions of the channel consume, empty for the topic of the TopicFunc.
+	Topic string
+	// MigrationCutOver is set while the subscriptions of the channel are cut over to the Topic it has been migrated
+	// to, whose events were produced while they consumed the previous topic.  The subscriptions consuming another
+	// topic, as well as the subscriptions added meanwhile, then start consuming the Topic from its oldest offset.
+	MigrationCutOver bool
+	// IngressTopic is the topic the events of the channel are produced to, empty for the consumed Topic. It
+	// differs from the Topic while the channel is migrating to a new topic.
+	IngressTopic string
 }
 
 // UpdateKafkaConsumers will be called by new CRD based kafka channel dispatcher controller.
@@ -239,12 +560,17 @@ func (d *KafkaDispatcher) UpdateKafkaConsumers(config *Config) (map[types.UID]er
 	defer d.consumerUpdateLock.Unlock()
 
 	var newSubs []types.UID
+	channelNewSubs := make(map[eventingchannels.ChannelReference][]types.UID)
 	failedToSubscribe := make(map[types.UID]error)
 	for _, cc := range config.ChannelConfigs {
 		channelRef := eventingchannels.ChannelReference{
 			Name:      cc.Name,
 			Namespace: cc.Namespace,
 		}
+		topicName := cc.Topic
+		if topicName == "" {
+			topicName = d.topicFunc(utils.KafkaChannelSeparator, cc.Namespace, cc.Name)
+		}
 		for _, subSpec := range cc.Subscriptions {
 			newSubs = append(newSubs, subSpec.UID)
 
@@ -259,9 +585,26 @@ func (d *KafkaDispatcher) UpdateKafkaConsumers(config *Config) (map[types.UID]er
 			if !exists {
 				// only subscribe when not exists in channel-subscriptions map
 				// do not need to resubscribe every time channel fanout config is updated
-				if err := d.subscribe(channelRef, subSpec); err != nil {
+				if err := d.subscribe(channelRef, cc.KafkaCluster, topicName, cc.MigrationCutOver, subSpec); err != nil {
 					failedToSubscribe[subSpec.UID] = err
 				}
+			} else if d.requiresNewSession(channelRef, d.subscriptions[subSpec.UID], subSpec) || d.subscribedCluster(subSpec.UID) != cc.KafkaCluster || d.subscribedTopic(subSpec.UID) != topicName {
+				// the subscription is consumed differently (or rewound), which requires a new consumer group session,
+				// consuming the topic the channel has been migrated to (if it changed) from its oldest offset
+				fromOldest := cc.MigrationCutOver || d.subscribedTopic(subSpec.UID) != topicName
+				if err := d.unsubscribe(channelRef, d.subscriptions[subSpec.UID]); err != nil {
+					failedToSubscribe[subSpec.UID] = err
+				} else if err := d.subscribe(channelRef, cc.KafkaCluster, topicName, fromOldest, subSpec); err != nil {
+					failedToSubscribe[subSpec.UID] = err
+				}
+			} else {
+				// everything else is updated in place, without rebalancing the consumer group
+				d.updateSubscription(channelRef, subSpec)
+			}
+
+			// the subscriptions which failed are subscribed again by the next update
+			if _, failed := failedToSubscribe[subSpec.UID]; !failed {
+				channelNewSubs[channelRef] = append(channelNewSubs[channelRef], subSpec.UID)
 			}
 		}
 	}
@@ -285,11 +628,54 @@ func (d *KafkaDispatcher) UpdateKafkaConsumers(config *Config) (map[types.UID]er
 				}
 			}
 		}
-		d.channelSubscriptions[channelRef] = newSubs
+		d.channelSubscriptions[channelRef] = channelNewSubs[channelRef]
+	}
+
+	// forget the failed consumer groups of the subscriptions which are gone
+	if d.healthServer != nil {
+		d.healthServer.RetainConsumerGroups(newSubs)
 	}
 	return failedToSubscribe, nil
 }
 
+// subscribedCluster returns the name of the Kafka cluster a subscription is consumed from, empty for the default one.
+// subscribedCluster must be called under updateLock.
+func (d *KafkaDispatcher) subscribedCluster(uid types.UID) string {
+	if handler, ok := d.subsHandlers[uid]; ok {
+		return handler.cluster
+	}
+	return ""
+}
+
+// subscribedFromOldest returns true if the new consumer groups of a subscription start at the oldest offset.
+// subscribedFromOldest must be called under updateLock.
+func (d *KafkaDispatcher) subscribedFromOldest(uid types.UID) bool {
+	if handler, ok := d.subsHandlers[uid]; ok {
+		return handler.fromOldest
+	}
+	return false
+}
+
+// subscribedTopic returns the channel topic a subscription is consumed from.
+// subscribedTopic must be called under updateLock.
+func (d *KafkaDispatcher) subscribedTopic(uid types.UID) string {
+	if handler, ok := d.subsHandlers[uid]; ok {
+		return handler.topic
+	}
+	return ""
+}
+
+// SubscriberHealth returns the health of the subscriptions consumed by this dispatcher.
+func (d *KafkaDispatcher) SubscriberHealth() map[types.UID]dispatchmetrics.SubscriptionHealth {
+	d.consumerUpdateLock.Lock()
+	defer d.consumerUpdateLock.Unlock()
+	subscriberHealth := make(map[types.UID]dispatchmetrics.SubscriptionHealth, len(d.subsHandlers))
+	for uid, handler := range d.subsHandlers {
+		subscriberHealth[uid] = handler.reporter.Health()
+	}
+	return subscriberHealth
+}
+
 // UpdateHostToChannelMap will be called by new CRD based kafka channel dispatcher controller.
 func (d *KafkaDispatcher) UpdateHostToChannelMap(config *Config) error {
 	if config == nil {
@@ -305,9 +691,108 @@ func (d *KafkaDispatcher) UpdateHostToChannelMap(config *Config) error {
 	}
 
 	d.setHostToChannelMap(hcMap)
+	d.setChannelClusters(createChannelClusters(config))
+	d.setChannelTopics(createChannelTopics(config))
 	return nil
 }
 
+// UpdateChannelMapping maps the host, cluster and topic of a single channel, leaving the other channels as they are.
+func (d *KafkaDispatcher) UpdateChannelMapping(channelConfig *ChannelConfig) error {
+	if channelConfig == nil {
+		return errors.New("nil channel config")
+	}
+
+	d.hostToChannelMapLock.Lock()
+	defer d.hostToChannelMapLock.Unlock()
+
+	channelRef := eventingchannels.ChannelReference{Name: channelConfig.Name, Namespace: channelConfig.Namespace}
+	if cr, ok := d.getHostToChannelMap()[channelConfig.HostName]; ok && cr != channelRef {
+		return fmt.Errorf(
+			"duplicate hostName found. Each channel must have a unique host header. HostName:%s, channel:%s.%s, channel:%s.%s",
+			channelConfig.HostName,
+			channelConfig.Namespace,
+			channelConfig.Name,
+			cr.Namespace,
+			cr.Name)
+	}
+
+	hcMap, channelClusters, channelTopics := d.copyChannelMappings(channelRef)
+	hcMap[channelConfig.HostName] = channelRef
+	for ref, cluster := range createChannelClusters(&Config{ChannelConfigs: []ChannelConfig{*channelConfig}}) {
+		channelClusters[ref] = cluster
+	}
+	for ref, topic := range createChannelTopics(&Config{ChannelConfigs: []ChannelConfig{*channelConfig}}) {
+		channelTopics[ref] = topic
+	}
+
+	d.setHostToChannelMap(hcMap)
+	d.setChannelClusters(channelClusters)
+	d.setChannelTopics(channelTopics)
+	return nil
+}
+
+// RemoveChannelMapping unmaps the host, cluster and topic of a single channel, leaving the other channels as they are.
+func (d *KafkaDispatcher) RemoveChannelMapping(channelRef eventingchannels.ChannelReference) {
+	d.hostToChannelMapLock.Lock()
+	defer d.hostToChannelMapLock.Unlock()
+
+	hcMap, channelClusters, channelTopics := d.copyChannelMappings(channelRef)
+	d.setHostToChannelMap(hcMap)
+	d.setChannelClusters(channelClusters)
+	d.setChannelTopics(channelTopics)
+}
+
+// copyChannelMappings returns copies of the host, cluster and topic maps without the entries of the channel, as the
+// current maps are read without locking.
+func (d *KafkaDispatcher) copyChannelMappings(without eventingchannels.ChannelReference) (map[string]eventingchannels.ChannelReference, map[eventingchannels.ChannelReference]string, map[eventingchannels.ChannelReference]string) {
+	hcMap := make(map[string]eventingchannels.ChannelReference)
+	for host, cr := range d.getHostToChannelMap() {
+		if cr != without {
+			hcMap[host] = cr
+		}
+	}
+	channelClusters := make(map[eventingchannels.ChannelReference]string)
+	for cr, cluster := range d.getChannelClusters() {
+		if cr != without {
+			channelClusters[cr] = cluster
+		}
+	}
+	channelTopics := make(map[eventingchannels.ChannelReference]string)
+	currentTopics, _ := d.channelTopics.Load().(map[eventingchannels.ChannelReference]string)
+	for cr, topic := range currentTopics {
+		if cr != without {
+			channelTopics[cr] = topic
+		}
+	}
+	return hcMap, channelClusters, channelTopics
+}
+
+// createChannelTopics maps the channels whose events are not produced to the topic of the TopicFunc to their topic.
+func createChannelTopics(config *Config) map[eventingchannels.ChannelReference]string {
+	channelTopics := make(map[eventingchannels.ChannelReference]string)
+	for _, cConfig := range config.ChannelConfigs {
+		topic := cConfig.IngressTopic
+		if topic == "" {
+			topic = cConfig.Topic
+		}
+		if topic != "" {
+			channelTopics[eventingchannels.ChannelReference{Name: cConfig.Name, Namespace: cConfig.Namespace}] = topic
+		}
+	}
+	return channelTopics
+}
+
+// createChannelClusters maps the channels which do not use the default Kafka cluster to the name of their cluster.
+func createChannelClusters(config *Config) map[eventingchannels.ChannelReference]string {
+	channelClusters := make(map[eventingchannels.ChannelReference]string)
+	for _, cConfig := range config.ChannelConfigs {
+		if cConfig.KafkaCluster != "" {
+			channelClusters[eventingchannels.ChannelReference{Name: cConfig.Name, Namespace: cConfig.Namespace}] = cConfig.KafkaCluster
+		}
+	}
+	return channelClusters
+}
+
 func createHostToChannelMap(config *Config) (map[string]eventingchannels.ChannelReference, error) {
 	hcMap := make(map[string]eventingchannels.ChannelReference, len(config.ChannelConfigs))
 	for _, cConfig := range config.ChannelConfigs {
@@ -336,37 +821,98 @@ func (d *KafkaDispatcher) Start(ctx context.Context) error {
 
 // subscribe reads kafkaConsumers which gets updated in UpdateConfig in a separate go-routine.
 // subscribe must be called under updateLock.
-func (d *KafkaDispatcher) subscribe(channelRef eventingchannels.ChannelReference, sub Subscription) error {
+func (d *KafkaDispatcher) subscribe(channelRef eventingchannels.ChannelReference, clusterName string, topicName string, fromOldest bool, sub Subscription) error {
 	d.logger.Info("Subscribing", zap.Any("channelRef", channelRef), zap.Any("subscription", sub.UID))
 
-	topicName := d.topicFunc(utils.KafkaChannelSeparator, channelRef.Namespace, channelRef.Name)
-	groupID := fmt.Sprintf("kafka.%s.%s.%s", channelRef.Namespace, channelRef.Name, string(sub.UID))
+	groupID := consumer.GroupID(fmt.Sprintf("kafka.%s.%s.%s", channelRef.Namespace, channelRef.Name, string(sub.UID)), sub.Delivery)
 
-	handler := &consumerMessageHandler{d.logger, sub, d.dispatcher}
+	// the subscription is consumed from the Kafka cluster of its channel
+	producer, consumerFactory := d.producer(), d.kafkaConsumerFactory
+	if clusterName != "" {
+		cluster, err := d.cluster(clusterName)
+		if err != nil {
+			d.logger.Infow("Could not create proper consumer", zap.Error(err))
+			if d.healthServer != nil {
+				d.healthServer.SetConsumerGroupFailed(channelRef.String(), sub.UID, groupID, err)
+			}
+			return err
+		}
+		producer, consumerFactory = cluster.producer, cluster.consumerFactory
+	}
+
+	// the consumer group also consumes the subscription's retry topics (if any)
+	retryTopics := d.retryTopics(channelRef, sub)
+	reporter := dispatchmetrics.NewSubscriptionReporter(channelRef.Namespace, channelRef.Name, sub.UID)
+	handler := newConsumerMessageHandler(d.logger, sub, d.dispatcher, producer, clusterName, topicName, retryTopics, reporter)
+	handler.fromOldest = fromOldest
 
-	consumerGroup, err := d.kafkaConsumerFactory.StartConsumerGroup(groupID, []string{topicName}, d.logger, handler)
+	consumerGroup, err := consumerFactory.StartConsumerGroup(groupID, append([]string{topicName}, retryTopics.Names()...), d.logger, handler)
 
 	if err != nil {
 		// we can not create a consumer - logging that, with reason
 		d.logger.Infow("Could not create proper consumer", zap.Error(err))
+		if d.healthServer != nil {
+			d.healthServer.SetConsumerGroupFailed(channelRef.String(), sub.UID, groupID, err)
+		}
 		return err
 	}
+	if d.healthServer != nil {
+		d.healthServer.SetConsumerGroupStarted(channelRef.String(), sub.UID, groupID)
+	}
 
 	// sarama reports error in consumerGroup.Error() channel
 	// this goroutine logs errors incoming
 	go func() {
-		for err = range consumerGroup.Errors() {
+		for err := range consumerGroup.Errors() {
 			d.logger.Warnw("Error in consumer group", zap.Error(err))
+			if d.healthServer != nil {
+				d.healthServer.SetConsumerGroupError(sub.UID, err)
+			}
 		}
 	}()
 
 	d.channelSubscriptions[channelRef] = append(d.channelSubscriptions[channelRef], sub.UID)
 	d.subscriptions[sub.UID] = sub
 	d.subsConsumerGroups[sub.UID] = consumerGroup
+	d.subsHandlers[sub.UID] = handler
 
 	return nil
 }
 
+// updateSubscription applies the new settings of a subscribed subscription to its handler.
+// updateSubscription must be called under updateLock.
+func (d *KafkaDispatcher) updateSubscription(channelRef eventingchannels.ChannelReference, sub Subscription) {
+	d.subscriptions[sub.UID] = sub
+	if handler, ok := d.subsHandlers[sub.UID]; ok {
+		handler.update(sub, d.retryTopics(channelRef, sub))
+	}
+}
+
+// requiresNewSession returns true if the new settings of a subscription can not be applied to its running consumer
+// group, because they change the way its messages are consumed or the topics it consumes.
+func (d *KafkaDispatcher) requiresNewSession(channelRef eventingchannels.ChannelReference, previous Subscription, sub Subscription) bool {
+	if consumer.RequiresNewSession(previous.Delivery, sub.Delivery) {
+		return true
+	}
+	previousTopics, topics := d.retryTopics(channelRef, previous).Names(), d.retryTopics(channelRef, sub).Names()
+	if len(previousTopics) != len(topics) {
+		return true
+	}
+	for i := range topics {
+		if previousTopics[i] != topics[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// retryTopics returns the retry topics of the subscription, which are named after the topic of the TopicFunc even once
+// the channel has been migrated to a new topic, for them to be kept by the migration.
+func (d *KafkaDispatcher) retryTopics(channelRef eventingchannels.ChannelReference, sub Subscription) retrytopic.Topics {
+	topicName := d.topicFunc(utils.KafkaChannelSeparator, channelRef.Namespace, channelRef.Name)
+	return retrytopic.NewTopics(topicName, sub.UID, sub.Delivery, sub.RetryConfig)
+}
+
 // unsubscribe reads kafkaConsumers which gets updated in UpdateConfig in a separate go-routine.
 // unsubscribe must be called under updateLock.
 func (d *KafkaDispatcher) unsubscribe(channel eventingchannels.ChannelReference, sub Subscription) error {
@@ -381,6 +927,10 @@ func (d *KafkaDispatcher) unsubscribe(channel eventingchannels.ChannelReference,
 		}
 		d.channelSubscriptions[channel] = newSlice
 	}
+	delete(d.subsHandlers, sub.UID)
+	if d.healthServer != nil {
+		d.healthServer.RemoveConsumerGroup(sub.UID)
+	}
 	if consumer, ok := d.subsConsumerGroups[sub.UID]; ok {
 		delete(d.subsConsumerGroups, sub.UID)
 		return consumer.Close()
@@ -396,6 +946,28 @@ func (d *KafkaDispatcher) setHostToChannelMap(hcMap map[string]eventingchannels.
 	d.hostToChannelMap.Store(hcMap)
 }
 
+func (d *KafkaDispatcher) getChannelClusters() map[eventingchannels.ChannelReference]string {
+	channelClusters, _ := d.channelClusters.Load().(map[eventingchannels.ChannelReference]string)
+	return channelClusters
+}
+
+func (d *KafkaDispatcher) setChannelClusters(channelClusters map[eventingchannels.ChannelReference]string) {
+	d.channelClusters.Store(channelClusters)
+}
+
+func (d *KafkaDispatcher) setChannelTopics(channelTopics map[eventingchannels.ChannelReference]string) {
+	d.channelTopics.Store(channelTopics)
+}
+
+// ingressTopic returns the topic which the events of a channel are produced to.
+func (d *KafkaDispatcher) ingressTopic(channel eventingchannels.ChannelReference) string {
+	channelTopics, _ := d.channelTopics.Load().(map[eventingchannels.ChannelReference]string)
+	if topic, ok := channelTopics[channel]; ok {
+		return topic
+	}
+	return d.topicFunc(utils.KafkaChannelSeparator, channel.Namespace, channel.Name)
+}
+
 func (d *KafkaDispatcher) getChannelReferenceFromHost(host string) (eventingchannels.ChannelReference, error) {
 	chMap := d.getHostToChannelMap()
 	cr, ok := chMap[host]
@@ -21,16 +21,21 @@ import (
 	"errors"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/Shopify/sarama"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/channel/consolidated/dispatcher/health"
 	"knative.dev/eventing-kafka/pkg/channel/consolidated/utils"
 	"knative.dev/eventing-kafka/pkg/common/consumer"
+	"knative.dev/eventing-kafka/pkg/common/deadletter"
 	eventingchannels "knative.dev/eventing/pkg/channel"
 	"knative.dev/eventing/pkg/channel/fanout"
 	_ "knative.dev/pkg/system/testing"
@@ -41,12 +46,17 @@ import (
 type mockKafkaConsumerFactory struct {
 	// createErr will return an error when creating a consumer
 	createErr bool
+	// groupIDs records the ids of the consumer groups started (if not nil)
+	groupIDs *[]string
 }
 
 func (c mockKafkaConsumerFactory) StartConsumerGroup(groupID string, topics []string, logger *zap.SugaredLogger, handler consumer.KafkaConsumerHandler) (sarama.ConsumerGroup, error) {
 	if c.createErr {
 		return nil, errors.New("error creating consumer")
 	}
+	if c.groupIDs != nil {
+		*c.groupIDs = append(*c.groupIDs, groupID)
+	}
 
 	return mockConsumerGroup{}, nil
 }
@@ -69,6 +79,25 @@ func (m mockConsumerGroup) Close() error {
 
 var _ sarama.ConsumerGroup = (*mockConsumerGroup)(nil)
 
+type mockSyncProducer struct {
+	closed bool
+}
+
+func (p *mockSyncProducer) SendMessage(msg *sarama.ProducerMessage) (int32, int64, error) {
+	return 0, 0, nil
+}
+
+func (p *mockSyncProducer) SendMessages(msgs []*sarama.ProducerMessage) error {
+	return nil
+}
+
+func (p *mockSyncProducer) Close() error {
+	p.closed = true
+	return nil
+}
+
+var _ sarama.SyncProducer = (*mockSyncProducer)(nil)
+
 // ----- Tests
 
 // test util for various config checks
@@ -322,6 +351,7 @@ func TestDispatcher_UpdateConfig(t *testing.T) {
 				kafkaConsumerFactory: &mockKafkaConsumerFactory{},
 				channelSubscriptions: make(map[eventingchannels.ChannelReference][]types.UID),
 				subsConsumerGroups:   make(map[types.UID]sarama.ConsumerGroup),
+				subsHandlers:         make(map[types.UID]*consumerMessageHandler),
 				subscriptions:        make(map[types.UID]Subscription),
 				topicFunc:            utils.TopicName,
 				logger:               zaptest.NewLogger(t).Sugar(),
@@ -374,6 +404,372 @@ func TestDispatcher_UpdateConfig(t *testing.T) {
 	}
 }
 
+func TestRewindSubscription(t *testing.T) {
+	var groupIDs []string
+	d := &KafkaDispatcher{
+		kafkaConsumerFactory: &mockKafkaConsumerFactory{groupIDs: &groupIDs},
+		channelSubscriptions: make(map[eventingchannels.ChannelReference][]types.UID),
+		subsConsumerGroups:   make(map[types.UID]sarama.ConsumerGroup),
+		subsHandlers:         make(map[types.UID]*consumerMessageHandler),
+		subscriptions:        make(map[types.UID]Subscription),
+		topicFunc:            utils.TopicName,
+		logger:               zaptest.NewLogger(t).Sugar(),
+	}
+	config := func(rewind string) *Config {
+		return &Config{ChannelConfigs: []ChannelConfig{{
+			Namespace:     "default",
+			Name:          "test-channel",
+			Subscriptions: []Subscription{{UID: "subscription-1", Delivery: kafkav1beta1.SubscriberDelivery{Rewind: rewind}}},
+		}}}
+	}
+
+	// Unchanged Subscriptions Keep Their Consumer Group, Rewound Ones Move To A New One
+	for _, rewind := range []string{"", "", "earliest", "earliest", "2021-01-02T03:04:05Z"} {
+		failed, err := d.UpdateKafkaConsumers(config(rewind))
+		assert.Nil(t, err)
+		assert.Empty(t, failed)
+	}
+	assert.Len(t, groupIDs, 3)
+	assert.Equal(t, "kafka.default.test-channel.subscription-1", groupIDs[0])
+	assert.Equal(t, "2021-01-02T03:04:05Z", d.subscriptions["subscription-1"].Delivery.Rewind)
+	assert.Len(t, d.subscriptions, 1)
+	assert.Len(t, d.subsConsumerGroups, 1)
+	assert.Equal(t, []types.UID{"subscription-1"}, d.channelSubscriptions[eventingchannels.ChannelReference{Namespace: "default", Name: "test-channel"}])
+}
+
+func TestUpdateSubscriptionInPlace(t *testing.T) {
+	var groupIDs []string
+	d := &KafkaDispatcher{
+		kafkaConsumerFactory: &mockKafkaConsumerFactory{groupIDs: &groupIDs},
+		channelSubscriptions: make(map[eventingchannels.ChannelReference][]types.UID),
+		subsConsumerGroups:   make(map[types.UID]sarama.ConsumerGroup),
+		subsHandlers:         make(map[types.UID]*consumerMessageHandler),
+		subscriptions:        make(map[types.UID]Subscription),
+		topicFunc:            utils.TopicName,
+		logger:               zaptest.NewLogger(t).Sugar(),
+	}
+	config := func(subscriber string, delivery kafkav1beta1.SubscriberDelivery) *Config {
+		subscriberURL, _ := url.Parse(subscriber)
+		return &Config{ChannelConfigs: []ChannelConfig{{
+			Namespace: "default",
+			Name:      "test-channel",
+			Subscriptions: []Subscription{{
+				UID:          "subscription-1",
+				Subscription: fanout.Subscription{Subscriber: subscriberURL},
+				Delivery:     delivery,
+			}},
+		}}}
+	}
+	update := func(subscriber string, delivery kafkav1beta1.SubscriberDelivery) {
+		failed, err := d.UpdateKafkaConsumers(config(subscriber, delivery))
+		assert.Nil(t, err)
+		assert.Empty(t, failed)
+	}
+
+	// A Changed Subscriber Is Picked Up By The Existing Handler Without A New Consumer Group
+	update("http://test/subscriber-1", kafkav1beta1.DefaultSubscriberDelivery())
+	handler := d.subsHandlers["subscription-1"]
+	update("http://test/subscriber-2", kafkav1beta1.DefaultSubscriberDelivery())
+	assert.Len(t, groupIDs, 1)
+	assert.Same(t, handler, d.subsHandlers["subscription-1"])
+	assert.Equal(t, "http://test/subscriber-2", handler.current().sub.Subscriber.String())
+	assert.Equal(t, "http://test/subscriber-2", d.subscriptions["subscription-1"].Subscriber.String())
+
+	// A Changed Ordering Requires A New Consumer Group Session
+	update("http://test/subscriber-2", kafkav1beta1.SubscriberDelivery{Ordering: kafkav1beta1.DeliveryStrictlyOrdered})
+	assert.Len(t, groupIDs, 2)
+	assert.NotSame(t, handler, d.subsHandlers["subscription-1"])
+	assert.Equal(t, kafkav1beta1.DeliveryStrictlyOrdered, d.subsHandlers["subscription-1"].SubscriberDelivery().Ordering)
+}
+
+func TestUpdateKafkaConfig(t *testing.T) {
+	defer func(producer func([]string, *sarama.Config) (sarama.SyncProducer, error), factory func([]string, *sarama.Config) consumer.KafkaConsumerGroupFactory) {
+		newSyncProducer, newConsumerGroupFactory = producer, factory
+	}(newSyncProducer, newConsumerGroupFactory)
+
+	var groupIDs []string
+	var producers []*mockSyncProducer
+	var configs []*sarama.Config
+	newSyncProducer = func(brokers []string, conf *sarama.Config) (sarama.SyncProducer, error) {
+		producer := &mockSyncProducer{}
+		producers = append(producers, producer)
+		return producer, nil
+	}
+	newConsumerGroupFactory = func(addrs []string, conf *sarama.Config) consumer.KafkaConsumerGroupFactory {
+		configs = append(configs, conf)
+		return &mockKafkaConsumerFactory{groupIDs: &groupIDs}
+	}
+
+	args := &KafkaDispatcherArgs{ClientID: "kafka-ch-dispatcher", Brokers: []string{"broker"}, TopicFunc: utils.TopicName, Logger: zaptest.NewLogger(t).Sugar()}
+	saramaConfig, err := newSaramaConfig(args)
+	assert.Nil(t, err)
+	d := &KafkaDispatcher{
+		kafkaConsumerFactory: &mockKafkaConsumerFactory{groupIDs: &groupIDs},
+		channelSubscriptions: make(map[eventingchannels.ChannelReference][]types.UID),
+		subsConsumerGroups:   make(map[types.UID]sarama.ConsumerGroup),
+		subsHandlers:         make(map[types.UID]*consumerMessageHandler),
+		subscriptions:        make(map[types.UID]Subscription),
+		args:                 *args,
+		saramaConfig:         saramaConfig,
+		topicFunc:            utils.TopicName,
+		logger:               args.Logger,
+	}
+	initialProducer := &mockSyncProducer{}
+	d.setProducer(initialProducer)
+	failed, err := d.UpdateKafkaConsumers(&Config{ChannelConfigs: []ChannelConfig{{
+		Namespace:     "default",
+		Name:          "test-channel",
+		Subscriptions: []Subscription{{UID: "subscription-1"}, {UID: "subscription-2"}},
+	}}})
+	assert.Nil(t, err)
+	assert.Empty(t, failed)
+	assert.Len(t, groupIDs, 2)
+
+	// Unchanged Settings Keep The Producer And Consumer Groups
+	assert.Nil(t, d.UpdateKafkaConfig(&utils.KafkaConfig{}))
+	assert.Empty(t, producers)
+	assert.Len(t, groupIDs, 2)
+
+	// Invalid Settings Are Rejected
+	assert.NotNil(t, d.UpdateKafkaConfig(&utils.KafkaConfig{SaramaSettings: "Version: INVALID"}))
+	assert.Empty(t, producers)
+
+	// Changed Settings Replace The Producer And Restart Every Consumer Group With The New Config
+	handler := d.subsHandlers["subscription-1"]
+	settings := "Producer:\n  RequiredAcks: -1\nConsumer:\n  Fetch:\n    Default: 2097152\n"
+	assert.Nil(t, d.UpdateKafkaConfig(&utils.KafkaConfig{SaramaSettings: settings}))
+	assert.Len(t, producers, 1)
+	assert.Same(t, producers[0], d.producer())
+	assert.True(t, initialProducer.closed)
+	assert.Len(t, configs, 1)
+	assert.Equal(t, sarama.WaitForAll, configs[0].Producer.RequiredAcks)
+	assert.Equal(t, int32(2097152), configs[0].Consumer.Fetch.Default)
+	assert.Equal(t, "kafka-ch-dispatcher", configs[0].ClientID)
+	assert.Len(t, groupIDs, 4)
+	assert.Len(t, d.subsConsumerGroups, 2)
+	assert.NotSame(t, handler, d.subsHandlers["subscription-1"])
+	assert.Same(t, producers[0], d.subsHandlers["subscription-1"].producer)
+	assert.ElementsMatch(t, []types.UID{"subscription-1", "subscription-2"}, d.channelSubscriptions[eventingchannels.ChannelReference{Namespace: "default", Name: "test-channel"}])
+
+	// A Changed Rebalance Strategy Is A Change Too
+	assert.Nil(t, d.UpdateKafkaConfig(&utils.KafkaConfig{SaramaSettings: settings + "  Group:\n    Rebalance:\n      Strategy: roundrobin\n"}))
+	assert.Len(t, producers, 2)
+	assert.Len(t, groupIDs, 6)
+}
+
+func TestKafkaClusters(t *testing.T) {
+	defer func(producer func([]string, *sarama.Config) (sarama.SyncProducer, error), factory func([]string, *sarama.Config) consumer.KafkaConsumerGroupFactory) {
+		newSyncProducer, newConsumerGroupFactory = producer, factory
+	}(newSyncProducer, newConsumerGroupFactory)
+
+	var defaultGroupIDs, clusterGroupIDs []string
+	var producerBrokers [][]string
+	var producers []*mockSyncProducer
+	newSyncProducer = func(brokers []string, conf *sarama.Config) (sarama.SyncProducer, error) {
+		if brokers[0] == "unreachable" {
+			return nil, errors.New("unreachable brokers")
+		}
+		producerBrokers = append(producerBrokers, brokers)
+		producer := &mockSyncProducer{}
+		producers = append(producers, producer)
+		return producer, nil
+	}
+	newConsumerGroupFactory = func(addrs []string, conf *sarama.Config) consumer.KafkaConsumerGroupFactory {
+		return &mockKafkaConsumerFactory{groupIDs: &clusterGroupIDs}
+	}
+
+	args := &KafkaDispatcherArgs{
+		ClientID:  "kafka-ch-dispatcher",
+		Brokers:   []string{"broker"},
+		TopicFunc: utils.TopicName,
+		Logger:    zaptest.NewLogger(t).Sugar(),
+		Clusters: map[string]KafkaClusterArgs{
+			"tenant-a":    {Brokers: []string{"broker.tenant-a"}},
+			"unreachable": {Brokers: []string{"unreachable"}},
+		},
+	}
+	saramaConfig, err := newSaramaConfig(args)
+	assert.Nil(t, err)
+	d := &KafkaDispatcher{
+		kafkaConsumerFactory: &mockKafkaConsumerFactory{groupIDs: &defaultGroupIDs},
+		channelSubscriptions: make(map[eventingchannels.ChannelReference][]types.UID),
+		subsConsumerGroups:   make(map[types.UID]sarama.ConsumerGroup),
+		subsHandlers:         make(map[types.UID]*consumerMessageHandler),
+		subscriptions:        make(map[types.UID]Subscription),
+		clusters:             make(map[string]*kafkaCluster),
+		args:                 *args,
+		saramaConfig:         saramaConfig,
+		topicFunc:            utils.TopicName,
+		logger:               args.Logger,
+	}
+	defaultProducer := &mockSyncProducer{}
+	d.setProducer(defaultProducer)
+	d.setHostToChannelMap(map[string]eventingchannels.ChannelReference{})
+
+	config := &Config{ChannelConfigs: []ChannelConfig{
+		{Namespace: "default", Name: "default-channel", HostName: "default-channel", Subscriptions: []Subscription{{UID: "subscription-1"}}},
+		{Namespace: "a", Name: "a-channel", HostName: "a-channel", KafkaCluster: "tenant-a", Subscriptions: []Subscription{{UID: "subscription-2"}}},
+		{Namespace: "u", Name: "u-channel", HostName: "u-channel", KafkaCluster: "unreachable", Subscriptions: []Subscription{{UID: "subscription-3"}}},
+		{Namespace: "x", Name: "x-channel", HostName: "x-channel", KafkaCluster: "unknown", Subscriptions: []Subscription{{UID: "subscription-4"}}},
+	}}
+	assert.Nil(t, d.UpdateHostToChannelMap(config))
+	failed, err := d.UpdateKafkaConsumers(config)
+	assert.Nil(t, err)
+
+	// The Subscriptions Are Consumed From The Kafka Cluster Of Their Channel, Which Is Created When First Used
+	assert.Equal(t, []string{"kafka.default.default-channel.subscription-1"}, defaultGroupIDs)
+	assert.Equal(t, []string{"kafka.a.a-channel.subscription-2"}, clusterGroupIDs)
+	assert.Equal(t, [][]string{{"broker.tenant-a"}}, producerBrokers)
+	assert.Same(t, defaultProducer, d.subsHandlers["subscription-1"].producer)
+	assert.Same(t, producers[0], d.subsHandlers["subscription-2"].producer)
+	assert.Equal(t, "tenant-a", d.subsHandlers["subscription-2"].cluster)
+	assert.Equal(t, []types.UID{"subscription-1"}, d.channelSubscriptions[eventingchannels.ChannelReference{Namespace: "default", Name: "default-channel"}])
+	assert.Equal(t, []types.UID{"subscription-2"}, d.channelSubscriptions[eventingchannels.ChannelReference{Namespace: "a", Name: "a-channel"}])
+
+	// The Clusters Which Can Not Be Used Only Fail Their Own Subscriptions
+	assert.Len(t, failed, 2)
+	assert.EqualError(t, failed["subscription-3"], `Kafka cluster "unreachable": unable to create kafka producer against Kafka bootstrap servers [unreachable] : unreachable brokers`)
+	assert.EqualError(t, failed["subscription-4"], `unknown Kafka cluster "unknown", the dispatcher must be restarted to use new clusters`)
+	assert.Empty(t, d.channelSubscriptions[eventingchannels.ChannelReference{Namespace: "u", Name: "u-channel"}])
+
+	// The Ingress Events Are Produced To The Kafka Cluster Of Their Channel
+	producer, err := d.channelProducer(eventingchannels.ChannelReference{Namespace: "default", Name: "default-channel"})
+	assert.Nil(t, err)
+	assert.Same(t, defaultProducer, producer)
+	producer, err = d.channelProducer(eventingchannels.ChannelReference{Namespace: "a", Name: "a-channel"})
+	assert.Nil(t, err)
+	assert.Same(t, producers[0], producer)
+	_, err = d.channelProducer(eventingchannels.ChannelReference{Namespace: "x", Name: "x-channel"})
+	assert.NotNil(t, err)
+
+	// Changed Sarama Settings Replace The Producers Of The Clusters Too
+	assert.Nil(t, d.UpdateKafkaConfig(&utils.KafkaConfig{SaramaSettings: "Producer:\n  RequiredAcks: -1\n"}))
+	assert.True(t, producers[0].closed)
+	assert.Len(t, clusterGroupIDs, 3) // the default cluster's new factory, then the tenant's
+	assert.Same(t, producers[len(producers)-1], d.subsHandlers["subscription-2"].producer)
+	assert.Equal(t, "tenant-a", d.subsHandlers["subscription-2"].cluster)
+}
+
+func TestChannelMapping(t *testing.T) {
+	d := &KafkaDispatcher{topicFunc: utils.TopicName, logger: zaptest.NewLogger(t).Sugar()}
+	d.setHostToChannelMap(map[string]eventingchannels.ChannelReference{})
+
+	channelA := eventingchannels.ChannelReference{Namespace: "a", Name: "a-channel"}
+	channelB := eventingchannels.ChannelReference{Namespace: "b", Name: "b-channel"}
+	assert.Nil(t, d.UpdateHostToChannelMap(&Config{ChannelConfigs: []ChannelConfig{
+		{Namespace: "a", Name: "a-channel", HostName: "a-channel", KafkaCluster: "tenant-a"},
+	}}))
+
+	// A Single Channel Is Mapped Without Changing The Others
+	assert.Nil(t, d.UpdateChannelMapping(&ChannelConfig{Namespace: "b", Name: "b-channel", HostName: "b-channel", IngressTopic: "b-topic"}))
+	assert.Equal(t, map[string]eventingchannels.ChannelReference{"a-channel": channelA, "b-channel": channelB}, d.getHostToChannelMap())
+	assert.Equal(t, map[eventingchannels.ChannelReference]string{channelA: "tenant-a"}, d.getChannelClusters())
+	assert.Equal(t, "b-topic", d.ingressTopic(channelB))
+
+	// Its Host Can Change, But Not To The Host Of Another Channel
+	assert.Nil(t, d.UpdateChannelMapping(&ChannelConfig{Namespace: "b", Name: "b-channel", HostName: "b-channel-2", KafkaCluster: "tenant-b"}))
+	assert.Equal(t, map[string]eventingchannels.ChannelReference{"a-channel": channelA, "b-channel-2": channelB}, d.getHostToChannelMap())
+	assert.Equal(t, map[eventingchannels.ChannelReference]string{channelA: "tenant-a", channelB: "tenant-b"}, d.getChannelClusters())
+	assert.Equal(t, utils.TopicName(utils.KafkaChannelSeparator, "b", "b-channel"), d.ingressTopic(channelB))
+	assert.NotNil(t, d.UpdateChannelMapping(&ChannelConfig{Namespace: "b", Name: "b-channel", HostName: "a-channel"}))
+	assert.Equal(t, map[string]eventingchannels.ChannelReference{"a-channel": channelA, "b-channel-2": channelB}, d.getHostToChannelMap())
+	assert.NotNil(t, d.UpdateChannelMapping(nil))
+
+	// A Single Channel Is Unmapped Without Changing The Others
+	d.RemoveChannelMapping(channelA)
+	assert.Equal(t, map[string]eventingchannels.ChannelReference{"b-channel-2": channelB}, d.getHostToChannelMap())
+	assert.Equal(t, map[eventingchannels.ChannelReference]string{channelB: "tenant-b"}, d.getChannelClusters())
+}
+
+func TestTopicMigration(t *testing.T) {
+	var groupIDs []string
+	d := &KafkaDispatcher{
+		kafkaConsumerFactory: &mockKafkaConsumerFactory{groupIDs: &groupIDs},
+		channelSubscriptions: make(map[eventingchannels.ChannelReference][]types.UID),
+		subsConsumerGroups:   make(map[types.UID]sarama.ConsumerGroup),
+		subsHandlers:         make(map[types.UID]*consumerMessageHandler),
+		subscriptions:        make(map[types.UID]Subscription),
+		topicFunc:            utils.TopicName,
+		logger:               zaptest.NewLogger(t).Sugar(),
+	}
+	d.setProducer(&mockSyncProducer{})
+	channelRef := eventingchannels.ChannelReference{Namespace: "ns", Name: "channel"}
+	topicName := utils.TopicName(utils.KafkaChannelSeparator, "ns", "channel")
+	migratedTopicName := utils.MigrationTopicName(topicName, 2)
+	subscriptions := []Subscription{{UID: "subscription-1"}}
+	update := func(topic string, ingressTopic string, cutOver bool) {
+		config := &Config{ChannelConfigs: []ChannelConfig{{
+			Namespace:        "ns",
+			Name:             "channel",
+			HostName:         "channel",
+			Topic:            topic,
+			IngressTopic:     ingressTopic,
+			MigrationCutOver: cutOver,
+			Subscriptions:    subscriptions,
+		}}}
+		assert.Nil(t, d.UpdateHostToChannelMap(config))
+		failed, err := d.UpdateKafkaConsumers(config)
+		assert.Nil(t, err)
+		assert.Empty(t, failed)
+	}
+
+	update("", "", false)
+	assert.Equal(t, topicName, d.ingressTopic(channelRef))
+	assert.Equal(t, topicName, d.subsHandlers["subscription-1"].topic)
+	assert.Equal(t, "", d.subsHandlers["subscription-1"].SubscriberDelivery().StartOffset)
+
+	// The Events Are Produced To The New Topic While The Subscription Drains The Previous One
+	update("", migratedTopicName, false)
+	assert.Equal(t, migratedTopicName, d.ingressTopic(channelRef))
+	assert.Equal(t, topicName, d.subsHandlers["subscription-1"].topic)
+	assert.Len(t, groupIDs, 1)
+
+	// The Cut Over Subscription Consumes The New Topic From Its Oldest Events, With The Same Consumer Group
+	update(migratedTopicName, "", true)
+	assert.Equal(t, migratedTopicName, d.ingressTopic(channelRef))
+	assert.Equal(t, migratedTopicName, d.subsHandlers["subscription-1"].topic)
+	assert.Equal(t, kafkav1beta1.DeliveryOffsetEarliest, d.subsHandlers["subscription-1"].SubscriberDelivery().StartOffset)
+	assert.Equal(t, []string{"kafka.ns.channel.subscription-1", "kafka.ns.channel.subscription-1"}, groupIDs)
+
+	// The Subscriptions Added Once The Migration Completed Start At The Usual Offset
+	subscriptions = append(subscriptions, Subscription{UID: "subscription-2"})
+	update(migratedTopicName, "", false)
+	assert.Equal(t, migratedTopicName, d.subsHandlers["subscription-2"].topic)
+	assert.Equal(t, "", d.subsHandlers["subscription-2"].SubscriberDelivery().StartOffset)
+	assert.Len(t, groupIDs, 3)
+}
+
+func TestUpdateKafkaConsumersMissedCutOver(t *testing.T) {
+	d := &KafkaDispatcher{
+		kafkaConsumerFactory: &mockKafkaConsumerFactory{},
+		channelSubscriptions: make(map[eventingchannels.ChannelReference][]types.UID),
+		subsConsumerGroups:   make(map[types.UID]sarama.ConsumerGroup),
+		subsHandlers:         make(map[types.UID]*consumerMessageHandler),
+		subscriptions:        make(map[types.UID]Subscription),
+		topicFunc:            utils.TopicName,
+		logger:               zaptest.NewLogger(t).Sugar(),
+	}
+	d.setProducer(&mockSyncProducer{})
+	migratedTopicName := utils.MigrationTopicName(utils.TopicName(utils.KafkaChannelSeparator, "ns", "channel"), 2)
+	update := func(topic string) {
+		config := &Config{ChannelConfigs: []ChannelConfig{{
+			Namespace:     "ns",
+			Name:          "channel",
+			HostName:      "channel",
+			Topic:         topic,
+			Subscriptions: []Subscription{{UID: "subscription-1"}},
+		}}}
+		failed, err := d.UpdateKafkaConsumers(config)
+		assert.Nil(t, err)
+		assert.Empty(t, failed)
+	}
+
+	// A Subscription Moved To The Migrated Topic Consumes It From Its Oldest Events, Even If The Cut Over Was Missed
+	update("")
+	update(migratedTopicName)
+	assert.Equal(t, kafkav1beta1.DeliveryOffsetEarliest, d.subsHandlers["subscription-1"].SubscriberDelivery().StartOffset)
+}
+
 func TestSubscribeError(t *testing.T) {
 	cf := &mockKafkaConsumerFactory{createErr: true}
 	d := &KafkaDispatcher{
@@ -391,7 +787,7 @@ func TestSubscribeError(t *testing.T) {
 		UID:          "test-sub",
 		Subscription: fanout.Subscription{},
 	}
-	err := d.subscribe(channelRef, subRef)
+	err := d.subscribe(channelRef, "", "test-topic", false, subRef)
 	if err == nil {
 		t.Errorf("Expected error want %s, got %s", "error creating consumer", err)
 	}
@@ -418,6 +814,72 @@ func TestUnsubscribeUnknownSub(t *testing.T) {
 	}
 }
 
+func TestConsumerGroupsHealth(t *testing.T) {
+	factory := &mockKafkaConsumerFactory{}
+	d := &KafkaDispatcher{
+		kafkaConsumerFactory: factory,
+		channelSubscriptions: make(map[eventingchannels.ChannelReference][]types.UID),
+		subsConsumerGroups:   make(map[types.UID]sarama.ConsumerGroup),
+		subsHandlers:         make(map[types.UID]*consumerMessageHandler),
+		subscriptions:        make(map[types.UID]Subscription),
+		healthServer:         health.NewDispatcherHealthServer("0"),
+		topicFunc:            utils.TopicName,
+		logger:               zaptest.NewLogger(t).Sugar(),
+	}
+	config := func(uids ...types.UID) *Config {
+		channelConfig := ChannelConfig{Namespace: "default", Name: "test-channel"}
+		for _, uid := range uids {
+			channelConfig.Subscriptions = append(channelConfig.Subscriptions, Subscription{UID: uid})
+		}
+		return &Config{ChannelConfigs: []ChannelConfig{channelConfig}}
+	}
+
+	_, err := d.UpdateKafkaConsumers(config("subscription-1"))
+	assert.Nil(t, err)
+	assert.Equal(t, []health.ConsumerGroupState{
+		{Channel: "default/test-channel", Subscription: "subscription-1", GroupID: "kafka.default.test-channel.subscription-1", Running: true},
+	}, d.healthServer.Status().ConsumerGroups)
+
+	// Failed Consumer Groups Are Reported Until Their Subscription Is Removed
+	factory.createErr = true
+	failed, err := d.UpdateKafkaConsumers(config("subscription-1", "subscription-2"))
+	assert.Nil(t, err)
+	assert.Len(t, failed, 1)
+	states := d.healthServer.Status().ConsumerGroups
+	assert.Len(t, states, 2)
+	assert.False(t, states[1].Running)
+	assert.Equal(t, "error creating consumer", states[1].Error)
+
+	_, err = d.UpdateKafkaConsumers(config())
+	assert.Nil(t, err)
+	assert.Empty(t, d.healthServer.Status().ConsumerGroups)
+}
+
+func TestSubscriberHealth(t *testing.T) {
+	d := &KafkaDispatcher{
+		kafkaConsumerFactory: &mockKafkaConsumerFactory{},
+		channelSubscriptions: make(map[eventingchannels.ChannelReference][]types.UID),
+		subsConsumerGroups:   make(map[types.UID]sarama.ConsumerGroup),
+		subsHandlers:         make(map[types.UID]*consumerMessageHandler),
+		subscriptions:        make(map[types.UID]Subscription),
+		topicFunc:            utils.TopicName,
+		logger:               zaptest.NewLogger(t).Sugar(),
+	}
+	_, err := d.UpdateKafkaConsumers(&Config{ChannelConfigs: []ChannelConfig{{
+		Namespace:     "default",
+		Name:          "test-channel",
+		Subscriptions: []Subscription{{UID: "subscription-1"}},
+	}}})
+	assert.Nil(t, err)
+
+	// the health is that tracked by the reporter of the subscription's handler
+	d.subsHandlers["subscription-1"].reportDispatch(&deadletter.Result{Error: errors.New("subscriber failed")}, time.Millisecond)
+	subscriberHealth := d.SubscriberHealth()
+	assert.Len(t, subscriberHealth, 1)
+	assert.Equal(t, int32(100), subscriberHealth["subscription-1"].FailurePercentage())
+	assert.Equal(t, "subscriber failed", subscriberHealth["subscription-1"].LastFailure)
+}
+
 func TestKafkaDispatcher_Start(t *testing.T) {
 	d := &KafkaDispatcher{}
 	err := d.Start(context.TODO())
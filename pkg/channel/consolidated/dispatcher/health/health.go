@@ -0,0 +1,246 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package health implements the health server of the consolidated KafkaChannel dispatcher.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/types"
+
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/health"
+)
+
+const (
+	// Port is the port the health server of the dispatcher listens on.
+	Port = 8082
+	// LivenessPath and ReadinessPath are the endpoints of the liveness and readiness probes.
+	LivenessPath  = health.LivenessPath
+	ReadinessPath = health.ReadinessPath
+	// StatusPath is the endpoint reporting the details of the dispatcher's health as JSON.
+	StatusPath = "/status"
+
+	// brokerDialTimeout is the timeout of each connection attempt to a broker.
+	brokerDialTimeout = 5 * time.Second
+)
+
+// ConsumerGroupState is the state of the consumer group of a subscription.
+type ConsumerGroupState struct {
+	Channel      string    `json:"channel"`
+	Subscription types.UID `json:"subscription"`
+	GroupID      string    `json:"groupId,omitempty"`
+	Running      bool      `json:"running"`
+	// Error is the reason the consumer group failed to start or, while running, its last error.
+	Error string `json:"error,omitempty"`
+}
+
+// Status is the JSON document served on the StatusPath.
+type Status struct {
+	Alive            bool                 `json:"alive"`
+	Ready            bool                 `json:"ready"`
+	ProducerReady    bool                 `json:"producerReady"`
+	BrokersReachable bool                 `json:"brokersReachable"`
+	ConsumerGroups   []ConsumerGroupState `json:"consumerGroups"`
+}
+
+// Server is the health server of the dispatcher. The dispatcher is ready to receive events once its producer is
+// created and the brokers are reachable. The consumer groups are reported on the StatusPath but do not affect the
+// readiness, as a single failing subscription must not stop the ingress of every channel.
+type Server struct {
+	*health.Server
+
+	lock             sync.RWMutex
+	producerReady    bool
+	brokersReachable bool
+	consumerGroups   map[types.UID]ConsumerGroupState
+}
+
+// NewDispatcherHealthServer creates the health server of the dispatcher, listening on the specified port.
+func NewDispatcherHealthServer(httpPort string) *Server {
+	s := &Server{consumerGroups: make(map[types.UID]ConsumerGroupState)}
+	s.Server = health.NewHealthServer(httpPort, s)
+	s.HandleFunc(StatusPath, s.HandleStatus)
+	return s
+}
+
+// SetProducerReady sets whether the dispatcher has a producer to send the events it receives with.
+func (s *Server) SetProducerReady(ready bool) {
+	s.lock.Lock()
+	s.producerReady = ready
+	s.lock.Unlock()
+}
+
+// SetBrokersReachable sets whether at least one of the brokers accepts connections.
+func (s *Server) SetBrokersReachable(reachable bool) {
+	s.lock.Lock()
+	s.brokersReachable = reachable
+	s.lock.Unlock()
+}
+
+// SetConsumerGroupStarted records the consumer group of a subscription as running.
+func (s *Server) SetConsumerGroupStarted(channel string, uid types.UID, groupID string) {
+	s.lock.Lock()
+	s.consumerGroups[uid] = ConsumerGroupState{Channel: channel, Subscription: uid, GroupID: groupID, Running: true}
+	s.lock.Unlock()
+}
+
+// SetConsumerGroupFailed records that the consumer group of a subscription could not be started.
+func (s *Server) SetConsumerGroupFailed(channel string, uid types.UID, groupID string, err error) {
+	s.lock.Lock()
+	s.consumerGroups[uid] = ConsumerGroupState{Channel: channel, Subscription: uid, GroupID: groupID, Error: err.Error()}
+	s.lock.Unlock()
+}
+
+// SetConsumerGroupError records the last error of the running consumer group of a subscription.
+func (s *Server) SetConsumerGroupError(uid types.UID, err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if state, ok := s.consumerGroups[uid]; ok && state.Running {
+		state.Error = err.Error()
+		s.consumerGroups[uid] = state
+	}
+}
+
+// RemoveConsumerGroup forgets the consumer group of a subscription which was unsubscribed.
+func (s *Server) RemoveConsumerGroup(uid types.UID) {
+	s.lock.Lock()
+	delete(s.consumerGroups, uid)
+	s.lock.Unlock()
+}
+
+// RetainConsumerGroups forgets the consumer groups of the subscriptions which are not in the specified ones.
+func (s *Server) RetainConsumerGroups(uids []types.UID) {
+	retained := make(map[types.UID]bool, len(uids))
+	for _, uid := range uids {
+		retained[uid] = true
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for uid := range s.consumerGroups {
+		if !retained[uid] {
+			delete(s.consumerGroups, uid)
+		}
+	}
+}
+
+// Shutdown sets the dispatcher as neither alive nor ready.
+func (s *Server) Shutdown() {
+	s.Server.Shutdown()
+	s.SetProducerReady(false)
+}
+
+// Ready returns true if the dispatcher has a producer and the brokers are reachable.
+func (s *Server) Ready() bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.producerReady && s.brokersReachable
+}
+
+// Alive returns true once the dispatcher is started, until it is shut down.
+func (s *Server) Alive() bool {
+	return s.Server.Alive()
+}
+
+// Status returns the details of the health of the dispatcher, the consumer groups sorted by channel.
+func (s *Server) Status() Status {
+	s.lock.RLock()
+	status := Status{
+		ProducerReady:    s.producerReady,
+		BrokersReachable: s.brokersReachable,
+		ConsumerGroups:   make([]ConsumerGroupState, 0, len(s.consumerGroups)),
+	}
+	for _, state := range s.consumerGroups {
+		status.ConsumerGroups = append(status.ConsumerGroups, state)
+	}
+	s.lock.RUnlock()
+
+	status.Alive = s.Alive()
+	status.Ready = status.ProducerReady && status.BrokersReachable
+	sort.Slice(status.ConsumerGroups, func(i, j int) bool {
+		if status.ConsumerGroups[i].Channel != status.ConsumerGroups[j].Channel {
+			return status.ConsumerGroups[i].Channel < status.ConsumerGroups[j].Channel
+		}
+		return status.ConsumerGroups[i].Subscription < status.ConsumerGroups[j].Subscription
+	})
+	return status
+}
+
+// HandleStatus serves the Status of the dispatcher, with a 503 status code while it is not ready.
+func (s *Server) HandleStatus(responseWriter http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodGet {
+		responseWriter.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	status := s.Status()
+	responseWriter.Header().Set("Content-Type", "application/json")
+	if status.Ready {
+		responseWriter.WriteHeader(http.StatusOK)
+	} else {
+		responseWriter.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(responseWriter).Encode(status)
+}
+
+// WatchBrokers checks whether the brokers are reachable every interval, until the context is done.
+func (s *Server) WatchBrokers(ctx context.Context, logger *zap.SugaredLogger, brokers []string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		reachable := anyBrokerReachable(brokers)
+		if reachable != s.brokersReachableFlag() {
+			logger.Infow("Kafka brokers reachability changed", zap.Strings("brokers", brokers), zap.Bool("reachable", reachable))
+		}
+		s.SetBrokersReachable(reachable)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Server) brokersReachableFlag() bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.brokersReachable
+}
+
+// anyBrokerReachable returns true if a connection can be opened to at least one of the brokers.
+func anyBrokerReachable(brokers []string) bool {
+	for _, broker := range brokers {
+		if err := dialBroker(broker, brokerDialTimeout); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// dialBroker is a variable so that tests can replace it.
+var dialBroker = func(address string, timeout time.Duration) error {
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+func TestReadiness(t *testing.T) {
+	s := NewDispatcherHealthServer("0")
+	assert.False(t, s.Alive())
+	assert.False(t, s.Ready())
+
+	s.SetAlive(true)
+	s.SetProducerReady(true)
+	assert.True(t, s.Alive())
+	assert.False(t, s.Ready())
+
+	s.SetBrokersReachable(true)
+	assert.True(t, s.Ready())
+
+	// failing consumer groups do not affect the readiness
+	s.SetConsumerGroupFailed("ns/name", "uid", "kafka.ns.name.uid", errors.New("failed"))
+	assert.True(t, s.Ready())
+
+	s.Shutdown()
+	assert.False(t, s.Alive())
+	assert.False(t, s.Ready())
+}
+
+func TestConsumerGroups(t *testing.T) {
+	s := NewDispatcherHealthServer("0")
+	s.SetConsumerGroupStarted("ns/b", "uid-3", "group-3")
+	s.SetConsumerGroupStarted("ns/a", "uid-2", "group-2")
+	s.SetConsumerGroupFailed("ns/a", "uid-1", "group-1", errors.New("no brokers"))
+
+	// the errors of a consumer group are only recorded while it runs
+	s.SetConsumerGroupError("uid-2", errors.New("rebalance failed"))
+	s.SetConsumerGroupError("uid-1", errors.New("ignored"))
+	s.SetConsumerGroupError("unknown", errors.New("ignored"))
+
+	assert.Equal(t, []ConsumerGroupState{
+		{Channel: "ns/a", Subscription: "uid-1", GroupID: "group-1", Error: "no brokers"},
+		{Channel: "ns/a", Subscription: "uid-2", GroupID: "group-2", Running: true, Error: "rebalance failed"},
+		{Channel: "ns/b", Subscription: "uid-3", GroupID: "group-3", Running: true},
+	}, s.Status().ConsumerGroups)
+
+	s.RemoveConsumerGroup("uid-2")
+	s.RetainConsumerGroups(nil)
+	assert.Empty(t, s.Status().ConsumerGroups)
+
+	s.SetConsumerGroupStarted("ns/a", "uid-1", "group-1")
+	s.SetConsumerGroupStarted("ns/a", "uid-2", "group-2")
+	s.RetainConsumerGroups([]types.UID{"uid-2"})
+	assert.Equal(t, []ConsumerGroupState{
+		{Channel: "ns/a", Subscription: "uid-2", GroupID: "group-2", Running: true},
+	}, s.Status().ConsumerGroups)
+}
+
+func TestHandleStatus(t *testing.T) {
+	s := NewDispatcherHealthServer("0")
+	s.SetConsumerGroupStarted("ns/a", "uid-1", "group-1")
+
+	recorder := httptest.NewRecorder()
+	s.HandleStatus(recorder, httptest.NewRequest(http.MethodGet, StatusPath, nil))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+
+	s.SetProducerReady(true)
+	s.SetBrokersReachable(true)
+	recorder = httptest.NewRecorder()
+	s.HandleStatus(recorder, httptest.NewRequest(http.MethodGet, StatusPath, nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+
+	var status Status
+	assert.Nil(t, json.NewDecoder(recorder.Body).Decode(&status))
+	assert.True(t, status.Ready)
+	assert.Equal(t, []ConsumerGroupState{{Channel: "ns/a", Subscription: "uid-1", GroupID: "group-1", Running: true}}, status.ConsumerGroups)
+
+	recorder = httptest.NewRecorder()
+	s.HandleStatus(recorder, httptest.NewRequest(http.MethodPost, StatusPath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
+
+func TestStatusServed(t *testing.T) {
+	logger := logtesting.TestLogger(t).Desugar()
+	s := NewDispatcherHealthServer("0")
+	s.Start(logger)
+	defer s.Stop(logger)
+
+	var response *http.Response
+	var err error
+	for start := time.Now(); time.Since(start) < 3*time.Second; time.Sleep(100 * time.Millisecond) {
+		if response, err = http.Get(fmt.Sprintf("http://localhost:%s%s", s.HttpPort, StatusPath)); err == nil {
+			break
+		}
+	}
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, response.StatusCode)
+	_ = response.Body.Close()
+
+	// the served probes reflect the state set on the dispatcher's health server
+	s.SetAlive(true)
+	response, err = http.Get(fmt.Sprintf("http://localhost:%s%s", s.HttpPort, LivenessPath))
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	_ = response.Body.Close()
+}
+
+func TestWatchBrokers(t *testing.T) {
+	defer func(dial func(string, time.Duration) error) { dialBroker = dial }(dialBroker)
+	reachable := make(chan string, 10)
+	dialBroker = func(address string, _ time.Duration) error {
+		reachable <- address
+		if address == "up:9092" {
+			return nil
+		}
+		return errors.New("connection refused")
+	}
+
+	s := NewDispatcherHealthServer("0")
+	s.SetProducerReady(true)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.WatchBrokers(ctx, logtesting.TestLogger(t), []string{"down:9092", "up:9092"}, time.Hour)
+		close(done)
+	}()
+
+	// the brokers are checked in turn until one is reachable
+	assert.Equal(t, "down:9092", <-reachable)
+	assert.Equal(t, "up:9092", <-reachable)
+	cancel()
+	<-done
+	assert.True(t, s.Ready())
+
+	assert.False(t, anyBrokerReachable([]string{"down:9092"}))
+	assert.False(t, anyBrokerReachable(nil))
+}
@@ -42,6 +42,8 @@ import (
 	kafkaChannelClient "knative.dev/eventing-kafka/pkg/client/injection/client"
 	"knative.dev/eventing-kafka/pkg/client/injection/informers/messaging/v1beta1/kafkachannel"
 	kafkaChannelReconciler "knative.dev/eventing-kafka/pkg/client/injection/reconciler/messaging/v1beta1/kafkachannel"
+	dispatchmetrics "knative.dev/eventing-kafka/pkg/common/metrics"
+	"knative.dev/eventing-kafka/pkg/common/orphans"
 	eventingClient "knative.dev/eventing/pkg/client/injection/client"
 )
 
@@ -71,6 +73,7 @@ func NewController(
 		endpointsLister:      endpointsInformer.Lister(),
 		serviceAccountLister: serviceAccountInformer.Lister(),
 		roleBindingLister:    roleBindingInformer.Lister(),
+		orphansSeen:          orphans.NewSeen(),
 	}
 
 	env := &envConfig{}
@@ -85,6 +88,7 @@ func NewController(
 	r.dispatcherImage = env.Image
 
 	impl := kafkaChannelReconciler.NewImpl(ctx, r)
+	r.enqueueAfter = impl.EnqueueAfter
 
 	// Get and Watch the Kakfa config map and dynamically update Kafka configuration.
 	if _, err := kubeclient.Get(ctx).CoreV1().ConfigMaps(system.Namespace()).Get(ctx, "config-kafka", metav1.GetOptions{}); err == nil {
@@ -95,6 +99,12 @@ func NewController(
 		logging.FromContext(ctx).With(zap.Error(err)).Fatal("Error reading ConfigMap 'config-kafka'")
 	}
 
+	// Periodically collect the topics and consumer groups left behind by deleted channels and subscribers.
+	go orphans.Run(ctx, r.orphanCollectionInterval, func() { r.collectOrphans(ctx) })
+
+	// Periodically measure the lag of the subscribers, for the status of their channels to report it.
+	go r.refreshSubscriberLag(ctx, dispatchmetrics.HealthRefreshInterval)
+
 	logging.FromContext(ctx).Info("Setting up event handlers")
 	kafkaChannelInformer.Informer().AddEventHandler(controller.HandleAll(impl.Enqueue))
 
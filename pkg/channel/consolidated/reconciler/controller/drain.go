@@ -0,0 +1,188 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"go.uber.org/zap"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"knative.dev/pkg/logging"
+	pkgreconciler "knative.dev/pkg/reconciler"
+
+	"knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/channel/consolidated/reconciler/controller/resources"
+	"knative.dev/eventing-kafka/pkg/channel/consolidated/utils"
+	"knative.dev/eventing-kafka/pkg/common/consumer"
+	"knative.dev/eventing-kafka/pkg/common/retrytopic"
+)
+
+// drainCheckInterval is the interval at which the lag of the subscribers of a draining channel is measured.
+const drainCheckInterval = 10 * time.Second
+
+// offsetsClient is the part of sarama.Client which reads the offsets of the topics of a draining or migrating channel.
+type offsetsClient interface {
+	consumer.OffsetsReader
+	Close() error
+}
+
+var _ offsetsClient = (sarama.Client)(nil)
+
+// newDrainingEvent keeps the finalizer of a draining channel, which requeueDraining requeues.  The progress of the
+// drain is reported by the Addressable condition of the channel rather than by the event, whose message stays the
+// same for the recorder to aggregate the events of a drain into a single one.  The knative.dev/pkg version of this
+// module has no controller.NewRequeueAfter, which the generated reconciler would handle without any event, and any
+// other event than a warning would let the finalizer of the channel be removed.
+func newDrainingEvent(kc *v1beta1.KafkaChannel) pkgreconciler.Event {
+	return pkgreconciler.NewEvent(corev1.EventTypeWarning, "KafkaChannelDraining", "KafkaChannel draining its subscribers before deleting its topic: \"%s/%s\"", kc.Namespace, kc.Name)
+}
+
+// drain stops the ingress of a channel deleted with a drain timeout, and keeps its finalizer while its subscribers
+// have events left to consume, for its topic to be deleted once they are done or once the timeout has elapsed.
+func (r *Reconciler) drain(ctx context.Context, kc *v1beta1.KafkaChannel, timeout time.Duration) pkgreconciler.Event {
+	logger := logging.FromContext(ctx)
+
+	// the channel loses its address and its service, and the dispatcher unmaps its host while it keeps consuming
+	// its topic, so the lag is measured once the ingress is stopped
+	if !kc.Status.IsDraining() {
+		if err := r.deleteChannelService(ctx, kc); err != nil {
+			return err
+		}
+		kc.Status.MarkDraining("Draining the subscribers before deleting the topic")
+		r.requeueDraining(kc, timeout)
+		return newDrainingEvent(kc)
+	}
+
+	if time.Since(kc.DeletionTimestamp.Time) >= timeout {
+		logger.Warnw("Timed out draining the subscribers, deleting the topic", zap.Duration("timeout", timeout))
+		return nil
+	}
+
+	lag, err := r.subscribersLag(ctx, kc, true)
+	if err != nil {
+		logger.Warnw("Unable to measure the lag of the subscribers", zap.Error(err))
+		kc.Status.MarkDraining("Unable to measure the lag of the subscribers: %v", err)
+	} else if lag > 0 {
+		kc.Status.MarkDraining("Waiting for the subscribers to consume %d events", lag)
+	} else {
+		logger.Info("Drained the subscribers, deleting the topic")
+		return nil
+	}
+	r.requeueDraining(kc, timeout)
+	return newDrainingEvent(kc)
+}
+
+// requeueDraining requeues the channel for the next measure of the lag, or at the latest for its drain timeout.
+func (r *Reconciler) requeueDraining(kc *v1beta1.KafkaChannel, timeout time.Duration) {
+	after := drainCheckInterval
+	if remaining := time.Until(kc.DeletionTimestamp.Add(timeout)); remaining < after {
+		after = remaining
+	}
+	r.requeueAfter(kc, after)
+}
+
+// requeueAfter reconciles the channel again after the duration.
+func (r *Reconciler) requeueAfter(kc *v1beta1.KafkaChannel, after time.Duration) {
+	if r.enqueueAfter == nil {
+		return
+	}
+	if after < 0 {
+		after = 0
+	}
+	r.enqueueAfter(kc, after)
+}
+
+// deleteChannelService deletes the service of the channel, for its senders to stop resolving the dispatcher.
+func (r *Reconciler) deleteChannelService(ctx context.Context, kc *v1beta1.KafkaChannel) error {
+	name := resources.MakeChannelServiceName(kc.Name)
+	svc, err := r.serviceLister.Services(kc.Namespace).Get(name)
+	if apierrs.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if !metav1.IsControlledBy(svc, kc) {
+		return nil
+	}
+	err = r.KubeClientSet.CoreV1().Services(kc.Namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !apierrs.IsNotFound(err) {
+		logging.FromContext(ctx).Errorw("Failed to delete the channel service", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// subscribersLag returns the number of events of the topic the subscribers consume (and of their retry topics if
+// requested) left to consume by the subscribers.
+func (r *Reconciler) subscribersLag(ctx context.Context, kc *v1beta1.KafkaChannel, withRetryTopics bool) (int64, error) {
+	lags, err := r.subscriberLags(ctx, kc, withRetryTopics)
+	if err != nil {
+		return 0, err
+	}
+	var lag int64
+	for _, subscriberLag := range lags {
+		lag += subscriberLag
+	}
+	return lag, nil
+}
+
+// subscriberLags returns the number of events of the topic the subscribers consume (and of their retry topics if
+// requested) left to consume by each subscriber.
+func (r *Reconciler) subscriberLags(ctx context.Context, kc *v1beta1.KafkaChannel, withRetryTopics bool) (map[types.UID]int64, error) {
+	kafkaClusterAdmin, err := r.createClient(ctx, kc)
+	if err != nil {
+		return nil, err
+	}
+	defer kafkaClusterAdmin.Close()
+	offsets, err := r.createOffsetsClient(ctx, kc)
+	if err != nil {
+		return nil, err
+	}
+	defer offsets.Close()
+
+	// the retry topics keep the name of the topic the channel was created with
+	topicName := utils.TopicName(utils.KafkaChannelSeparator, kc.Namespace, kc.Name)
+	consumedTopicName := kc.Status.TopicName(topicName)
+	lags := make(map[types.UID]int64, len(kc.Spec.Subscribers))
+	for _, subscriber := range kc.Spec.Subscribers {
+		// the delivery annotations have already been validated, and the consumer groups are those of the dispatcher
+		delivery, _ := kc.SubscriberDelivery(subscriber.UID)
+		topics := []string{consumedTopicName}
+		if withRetryTopics {
+			retryTopics, err := retrytopic.SubscriberTopics(topicName, subscriber, delivery)
+			if err != nil {
+				return nil, err
+			}
+			topics = append(topics, retryTopics.Names()...)
+		}
+		groupID := consumer.GroupID(fmt.Sprintf("kafka.%s.%s.%s", kc.Namespace, kc.Name, string(subscriber.UID)), delivery)
+		groupLag, err := consumer.GroupLag(kafkaClusterAdmin, offsets, groupID, topics)
+		if err != nil {
+			return nil, fmt.Errorf("consumer group %s: %w", groupID, err)
+		}
+		lags[subscriber.UID] = groupLag
+	}
+	return lags, nil
+}
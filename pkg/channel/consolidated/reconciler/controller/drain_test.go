@@ -0,0 +1,202 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakekubeclientset "k8s.io/client-go/kubernetes/fake"
+
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis"
+	pkgreconciler "knative.dev/pkg/reconciler"
+
+	"knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	reconcilertesting "knative.dev/eventing-kafka/pkg/channel/consolidated/reconciler/testing"
+	. "knative.dev/eventing-kafka/pkg/channel/consolidated/utils"
+)
+
+type mockOffsetsClient struct {
+	// newest and oldest offsets of the single partition of each topic
+	newest map[string]int64
+	oldest map[string]int64
+}
+
+func (c *mockOffsetsClient) Partitions(topic string) ([]int32, error) {
+	if _, ok := c.newest[topic]; !ok {
+		return nil, sarama.ErrUnknownTopicOrPartition
+	}
+	return []int32{0}, nil
+}
+
+func (c *mockOffsetsClient) GetOffset(topic string, partitionID int32, time int64) (int64, error) {
+	if time == sarama.OffsetOldest {
+		return c.oldest[topic], nil
+	}
+	return c.newest[topic], nil
+}
+
+func (c *mockOffsetsClient) Close() error {
+	return nil
+}
+
+func TestFinalizeKindDrain(t *testing.T) {
+	topicName := TopicName(KafkaChannelSeparator, testNS, kcName)
+	groupID := "kafka." + testNS + "." + kcName + ".sub-1"
+
+	testCases := map[string]struct {
+		drainTimeout string
+		deletedSince time.Duration
+		draining     bool
+		committed    int64
+		wantEvent    string
+		wantMessage  string
+		wantDeleted  bool
+		wantRequeue  bool
+	}{
+		"not drained": {
+			deletedSince: time.Minute,
+			committed:    2,
+			wantEvent:    corev1.EventTypeNormal,
+			wantDeleted:  true,
+		},
+		"ingress stopped": {
+			drainTimeout: "1h",
+			deletedSince: time.Second,
+			committed:    2,
+			wantEvent:    corev1.EventTypeWarning,
+			wantMessage:  "Draining the subscribers before deleting the topic",
+			wantRequeue:  true,
+		},
+		"subscribers lagging": {
+			drainTimeout: "1h",
+			deletedSince: time.Minute,
+			draining:     true,
+			committed:    2,
+			wantEvent:    corev1.EventTypeWarning,
+			wantMessage:  "Waiting for the subscribers to consume 8 events",
+			wantRequeue:  true,
+		},
+		"subscribers without committed offsets": {
+			drainTimeout: "1h",
+			deletedSince: time.Minute,
+			draining:     true,
+			committed:    -1,
+			wantEvent:    corev1.EventTypeWarning,
+			wantMessage:  "Waiting for the subscribers to consume 9 events",
+			wantRequeue:  true,
+		},
+		"subscribers drained": {
+			drainTimeout: "1h",
+			deletedSince: time.Minute,
+			draining:     true,
+			committed:    10,
+			wantEvent:    corev1.EventTypeNormal,
+			wantDeleted:  true,
+		},
+		"drain timed out": {
+			drainTimeout: "1h",
+			deletedSince: 2 * time.Hour,
+			draining:     true,
+			committed:    2,
+			wantEvent:    corev1.EventTypeNormal,
+			wantDeleted:  true,
+		},
+	}
+
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			kc := reconcilertesting.NewKafkaChannel(kcName, testNS)
+			deletionTimestamp := metav1.NewTime(time.Now().Add(-tc.deletedSince))
+			kc.DeletionTimestamp = &deletionTimestamp
+			if tc.drainTimeout != "" {
+				kc.Annotations = map[string]string{v1beta1.DrainTimeoutAnnotation: tc.drainTimeout}
+			}
+			kc.Spec.Subscribers = []eventingduckv1.SubscriberSpec{{UID: "sub-1"}}
+			kc.Status.InitializeConditions()
+			kc.Status.SetAddress(apis.HTTP(channelServiceAddress))
+			if tc.draining {
+				kc.Status.MarkDraining("Draining the subscribers before deleting the topic")
+			}
+
+			var deleted []string
+			clusterAdmin := &mockClusterAdmin{
+				mockDeleteTopicFunc: func(topic string) error {
+					deleted = append(deleted, topic)
+					return nil
+				},
+				mockListConsumerGroupOffsetsFunc: func(group string, topicPartitions map[string][]int32) (*sarama.OffsetFetchResponse, error) {
+					assert.Equal(t, groupID, group)
+					assert.Equal(t, map[string][]int32{topicName: {0}}, topicPartitions)
+					response := &sarama.OffsetFetchResponse{}
+					response.AddBlock(topicName, 0, &sarama.OffsetFetchResponseBlock{Offset: tc.committed})
+					return response, nil
+				},
+			}
+
+			var requeued []time.Duration
+			objects := []runtime.Object{makeChannelService(kc)}
+			listers := reconcilertesting.NewListers(objects)
+			r := &Reconciler{
+				KubeClientSet:      fakekubeclientset.NewSimpleClientset(objects...),
+				kafkaConfig:        &KafkaConfig{Brokers: []string{brokerName}},
+				kafkaClusterAdmin:  clusterAdmin,
+				kafkaOffsetsClient: &mockOffsetsClient{newest: map[string]int64{topicName: 10}, oldest: map[string]int64{topicName: 1}},
+				serviceLister:      listers.GetServiceLister(),
+				enqueueAfter: func(obj interface{}, after time.Duration) {
+					requeued = append(requeued, after)
+				},
+			}
+
+			event := r.FinalizeKind(context.TODO(), kc)
+			var reconcilerEvent *pkgreconciler.ReconcilerEvent
+			assert.True(t, pkgreconciler.EventAs(event, &reconcilerEvent))
+			assert.Equal(t, tc.wantEvent, reconcilerEvent.EventType)
+			if tc.wantEvent == corev1.EventTypeWarning {
+				// the progress is reported by the status, for the events of a drain to be aggregated
+				assert.Equal(t, newDrainingEvent(kc), event)
+			}
+
+			if tc.wantDeleted {
+				assert.Equal(t, []string{topicName}, deleted)
+			} else {
+				assert.Empty(t, deleted)
+				assert.True(t, kc.Status.IsDraining())
+				assert.Nil(t, kc.Status.Address.URL)
+				assert.Equal(t, tc.wantMessage, kc.Status.GetCondition(v1beta1.KafkaChannelConditionAddressable).Message)
+			}
+			if tc.wantRequeue {
+				assert.Equal(t, []time.Duration{drainCheckInterval}, requeued)
+			} else {
+				assert.Empty(t, requeued)
+			}
+
+			// the service of the channel is deleted as soon as it is drained
+			_, err := r.KubeClientSet.CoreV1().Services(testNS).Get(context.TODO(), kcName+"-kn-channel", metav1.GetOptions{})
+			assert.Equal(t, tc.drainTimeout != "" && !tc.draining, apierrs.IsNotFound(err))
+		})
+	}
+}
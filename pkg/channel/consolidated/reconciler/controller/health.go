@@ -0,0 +1,79 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"knative.dev/pkg/logging"
+)
+
+// refreshSubscriberLag updates the lag of the subscribers of the channels every interval, until the context is done.
+func (r *Reconciler) refreshSubscriberLag(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.updateSubscriberLag(ctx)
+		}
+	}
+}
+
+// updateSubscriberLag measures the lag of the subscribers of the ready channels, from the offsets committed by their
+// consumer groups, and writes it to the status of the channels.  The lag is measured by the controller, rather than
+// by the replicas of the dispatcher which each only consume some of the partitions, so that a stalled consumer is
+// reported as lagging too.  The status is updated directly, without reconciling the channels, and a conflicting
+// update is retried at the next interval.
+func (r *Reconciler) updateSubscriberLag(ctx context.Context) {
+	logger := logging.FromContext(ctx)
+	kafkaConfig := r.kafkaConfig
+	if kafkaConfig == nil || !r.kafkachannelInformer.HasSynced() {
+		return
+	}
+	channels, err := r.kafkachannelLister.List(labels.Everything())
+	if err != nil {
+		logger.Errorw("Failed to list the channels to update the lag of their subscribers", zap.Error(err))
+		return
+	}
+	for _, kc := range channels {
+		if kc.DeletionTimestamp != nil || !kc.Status.IsReady() {
+			continue
+		}
+		lags, err := r.subscriberLags(ctx, kc, false)
+		if err != nil {
+			logger.Warnw("Unable to measure the lag of the subscribers", zap.String("channel", kc.Namespace+"/"+kc.Name), zap.Error(err))
+			continue
+		}
+		desired := kc.DeepCopy()
+		desired.Status.SetSubscriberLag(lags, kafkaConfig.SubscriberHealthThresholds)
+		if equality.Semantic.DeepEqual(kc.Status, desired.Status) {
+			continue
+		}
+		if _, err := r.kafkaClientSet.MessagingV1beta1().KafkaChannels(kc.Namespace).UpdateStatus(ctx, desired, metav1.UpdateOptions{}); err != nil {
+			logger.Warnw("Failed to update the lag of the subscribers", zap.String("channel", kc.Namespace+"/"+kc.Name), zap.Error(err))
+		}
+	}
+}
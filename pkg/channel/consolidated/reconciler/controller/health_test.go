@@ -0,0 +1,90 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+
+	"knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	reconcilertesting "knative.dev/eventing-kafka/pkg/channel/consolidated/reconciler/testing"
+	. "knative.dev/eventing-kafka/pkg/channel/consolidated/utils"
+	fakekafkaclientset "knative.dev/eventing-kafka/pkg/client/clientset/versioned/fake"
+)
+
+func TestUpdateSubscriberLag(t *testing.T) {
+	topicName := TopicName(KafkaChannelSeparator, testNS, kcName)
+	kc := reconcilertesting.NewKafkaChannel(kcName, testNS,
+		reconcilertesting.WithInitKafkaChannelConditions,
+		reconcilertesting.WithKafkaChannelConfigReady(),
+		reconcilertesting.WithKafkaChannelTopicReady(),
+		reconcilertesting.WithKafkaChannelDeploymentReady(),
+		reconcilertesting.WithKafkaChannelServiceReady(),
+		reconcilertesting.WithKafkaChannelEndpointsReady(),
+		reconcilertesting.WithKafkaChannelChannelServiceReady(),
+		reconcilertesting.WithKafkaChannelAddress(channelServiceAddress))
+	kc.Spec.Subscribers = []eventingduckv1.SubscriberSpec{{UID: "sub-1"}, {UID: "sub-2"}}
+	// the failures reported by the dispatcher are kept, and the health of the removed subscribers is dropped
+	kc.Status.SubscriberHealth = []v1beta1.SubscriberHealth{{UID: "sub-1", FailurePercentage: 20}, {UID: "sub-3", Lag: 4}}
+	unready := reconcilertesting.NewKafkaChannel("unready", testNS)
+	unready.Spec.Subscribers = []eventingduckv1.SubscriberSpec{{UID: "sub-4"}}
+
+	committed := map[string]int64{
+		"kafka." + testNS + "." + kcName + ".sub-1": 8,
+		"kafka." + testNS + "." + kcName + ".sub-2": 2,
+	}
+	clusterAdmin := &mockClusterAdmin{
+		mockListConsumerGroupOffsetsFunc: func(group string, topicPartitions map[string][]int32) (*sarama.OffsetFetchResponse, error) {
+			response := &sarama.OffsetFetchResponse{}
+			response.AddBlock(topicName, 0, &sarama.OffsetFetchResponseBlock{Offset: committed[group]})
+			return response, nil
+		},
+	}
+	kafkaClientSet := fakekafkaclientset.NewSimpleClientset(kc, unready)
+	listers := reconcilertesting.NewListers([]runtime.Object{kc, unready})
+	r := &Reconciler{
+		kafkaConfig:          &KafkaConfig{Brokers: []string{brokerName}, SubscriberHealthThresholds: v1beta1.SubscriberHealthThresholds{Lag: 5}},
+		kafkaClientSet:       kafkaClientSet,
+		kafkaClusterAdmin:    clusterAdmin,
+		kafkaOffsetsClient:   &mockOffsetsClient{newest: map[string]int64{topicName: 10}, oldest: map[string]int64{topicName: 1}},
+		kafkachannelLister:   listers.GetKafkaChannelLister(),
+		kafkachannelInformer: &syncedInformer{synced: true},
+	}
+
+	r.updateSubscriberLag(context.TODO())
+
+	updated, err := kafkaClientSet.MessagingV1beta1().KafkaChannels(testNS).Get(context.TODO(), kcName, metav1.GetOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, []v1beta1.SubscriberHealth{{UID: "sub-1", Lag: 2, FailurePercentage: 20}, {UID: "sub-2", Lag: 8}}, updated.Status.SubscriberHealth)
+	lagging := updated.Status.GetCondition(v1beta1.KafkaChannelConditionSubscriberLagging)
+	assert.Equal(t, corev1.ConditionTrue, lagging.Status)
+	assert.Equal(t, "subscribers lagging by more than 5 events: sub-2 (lag 8)", lagging.Message)
+
+	// the channels which are not ready are left alone
+	updated, err = kafkaClientSet.MessagingV1beta1().KafkaChannels(testNS).Get(context.TODO(), "unready", metav1.GetOptions{})
+	assert.Nil(t, err)
+	assert.Empty(t, updated.Status.SubscriberHealth)
+}
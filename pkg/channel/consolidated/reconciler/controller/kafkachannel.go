@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"knative.dev/eventing-kafka/pkg/source"
 
@@ -58,6 +59,8 @@ import (
 	kafkaScheme "knative.dev/eventing-kafka/pkg/client/clientset/versioned/scheme"
 	kafkaChannelReconciler "knative.dev/eventing-kafka/pkg/client/injection/reconciler/messaging/v1beta1/kafkachannel"
 	listers "knative.dev/eventing-kafka/pkg/client/listers/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/common/orphans"
+	"knative.dev/eventing-kafka/pkg/common/retrytopic"
 )
 
 const (
@@ -116,10 +119,18 @@ type Reconciler struct {
 	kafkaAuthConfig  *utils.KafkaAuthConfig
 	kafkaConfigError error
 	kafkaClientSet   kafkaclientset.Interface
+	// kafkaClusterAuthConfigs are the auth configs of the named clusters of kafkaConfig which have an auth secret
+	kafkaClusterAuthConfigs map[string]*utils.KafkaAuthConfig
 
 	// Using a shared kafkaClusterAdmin does not work currently because of an issue with
 	// Shopify/sarama, see https://github.com/Shopify/sarama/issues/1162.
 	kafkaClusterAdmin sarama.ClusterAdmin
+	// kafkaOffsetsClient is used to pass a fake offsets client in the tests (see createOffsetsClient)
+	kafkaOffsetsClient offsetsClient
+	// enqueueAfter requeues the channels which are draining, to measure the lag of their subscribers again
+	enqueueAfter func(obj interface{}, after time.Duration)
+	// orphansSeen are the topics of the channels seen by the orphan collection, the only ones it may delete
+	orphansSeen *orphans.Seen
 
 	kafkachannelLister   listers.KafkaChannelLister
 	kafkachannelInformer cache.SharedIndexInformer
@@ -162,6 +173,17 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, kc *v1beta1.KafkaChannel
 		return r.kafkaConfigError
 	}
 
+	// the channel stays in the cluster its topic was created in, even if its namespace is mapped to another one later
+	clusterName, err := r.kafkaConfig.ClusterName(kc)
+	if err != nil {
+		kc.Status.MarkConfigFailed("UnknownKafkaCluster", "%v", err)
+		return err
+	}
+	kc.Status.PinKafkaCluster(clusterName)
+	if configuredClusterName, err := r.kafkaConfig.ConfiguredClusterName(kc); err == nil {
+		kc.Status.MarkKafkaClusterMismatch(configuredClusterName)
+	}
+
 	kafkaClusterAdmin, err := r.createClient(ctx, kc)
 	if err != nil {
 		kc.Status.MarkConfigFailed("InvalidConfiguration", "Unable to build Kafka admin client for channel %s: %v", kc.Name, err)
@@ -181,6 +203,16 @@ func (r *Reconciler) ReconcileKind(ctx context.Context, kc *v1beta1.KafkaChannel
 		kc.Status.MarkTopicFailed("TopicCreateFailed", "error while creating topic: %s", err)
 		return err
 	}
+
+	if err := r.reconcileRetryTopics(ctx, kc, kafkaClusterAdmin); err != nil {
+		kc.Status.MarkTopicFailed("RetryTopicsFailed", "error while reconciling retry topics: %s", err)
+		return err
+	}
+
+	if err := r.reconcileTopicMigration(ctx, kc, kafkaClusterAdmin); err != nil {
+		kc.Status.MarkTopicFailed("TopicMigrationFailed", "error while migrating the topic: %s", err)
+		return err
+	}
 	kc.Status.MarkTopicTrue()
 
 	scope, ok := kc.Annotations[eventing.ScopeAnnotationKey]
@@ -465,14 +497,31 @@ func (r *Reconciler) reconcileChannelService(ctx context.Context, dispatcherName
 }
 
 func (r *Reconciler) createClient(ctx context.Context, kc *v1beta1.KafkaChannel) (sarama.ClusterAdmin, error) {
+	if r.kafkaClusterAdmin != nil {
+		return r.kafkaClusterAdmin, nil
+	}
+	// the topic of the channel lives in its named cluster, if any, else in the default cluster
+	clusterName, err := r.kafkaConfig.ClusterName(kc)
+	if err != nil {
+		return nil, err
+	}
+	return r.createClusterClient(ctx, clusterName)
+}
+
+// createClusterClient creates a cluster admin client of the named Kafka cluster, or of the default cluster when the
+// name is empty.
+func (r *Reconciler) createClusterClient(ctx context.Context, clusterName string) (sarama.ClusterAdmin, error) {
 	// We don't currently initialize r.kafkaClusterAdmin, hence we end up creating the cluster admin client every time.
 	// This is because of an issue with Shopify/sarama. See https://github.com/Shopify/sarama/issues/1162.
 	// Once the issue is fixed we should use a shared cluster admin client. Also, r.kafkaClusterAdmin is currently
 	// used to pass a fake admin client in the tests.
 	kafkaClusterAdmin := r.kafkaClusterAdmin
 	if kafkaClusterAdmin == nil {
-		var err error
-		kafkaClusterAdmin, err = source.MakeAdminClient(controllerAgentName, r.kafkaAuthConfig, r.kafkaConfig.Brokers)
+		brokers, kafkaAuthConfig, saramaConf, err := r.namedClusterConfig(clusterName)
+		if err != nil {
+			return nil, err
+		}
+		kafkaClusterAdmin, err = source.MakeAdminClientWithConfig(controllerAgentName, saramaConf, kafkaAuthConfig, brokers)
 		if err != nil {
 			return nil, err
 		}
@@ -480,10 +529,53 @@ func (r *Reconciler) createClient(ctx context.Context, kc *v1beta1.KafkaChannel)
 	return kafkaClusterAdmin, nil
 }
 
+// createOffsetsClient creates a client reading the offsets of the topics of the channel, r.kafkaOffsetsClient is
+// used to pass a fake client in the tests.
+func (r *Reconciler) createOffsetsClient(ctx context.Context, kc *v1beta1.KafkaChannel) (offsetsClient, error) {
+	if r.kafkaOffsetsClient != nil {
+		return r.kafkaOffsetsClient, nil
+	}
+	brokers, kafkaAuthConfig, saramaConf, err := r.clusterConfig(kc)
+	if err != nil {
+		return nil, err
+	}
+	saramaConf.ClientID = controllerAgentName
+	if err := source.UpdateSaramaConfigWithKafkaAuthConfig(saramaConf, kafkaAuthConfig); err != nil {
+		return nil, err
+	}
+	return sarama.NewClient(brokers, saramaConf)
+}
+
+// clusterConfig returns the brokers, the auth config and the sarama config of the Kafka cluster which the topic of
+// the channel lives in.
+func (r *Reconciler) clusterConfig(kc *v1beta1.KafkaChannel) ([]string, *utils.KafkaAuthConfig, *sarama.Config, error) {
+	// the topic of the channel lives in its named cluster, if any, else in the default cluster
+	clusterName, err := r.kafkaConfig.ClusterName(kc)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return r.namedClusterConfig(clusterName)
+}
+
+// namedClusterConfig returns the brokers, the auth config and the sarama config of the named Kafka cluster, or of
+// the default cluster when the name is empty.
+func (r *Reconciler) namedClusterConfig(clusterName string) ([]string, *utils.KafkaAuthConfig, *sarama.Config, error) {
+	// the sarama settings of config-kafka are reloaded by updateKafkaConfig, so they apply to the next client
+	saramaConf, err := utils.NewSaramaConfig(r.kafkaConfig.SaramaSettings)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if clusterName != "" {
+		return r.kafkaConfig.Clusters[clusterName].Brokers, r.kafkaClusterAuthConfigs[clusterName], saramaConf, nil
+	}
+	return r.kafkaConfig.Brokers, r.kafkaAuthConfig, saramaConf, nil
+}
+
 func (r *Reconciler) createTopic(ctx context.Context, channel *v1beta1.KafkaChannel, kafkaClusterAdmin sarama.ClusterAdmin) error {
 	logger := logging.FromContext(ctx)
 
-	topicName := utils.TopicName(utils.KafkaChannelSeparator, channel.Namespace, channel.Name)
+	// the topic the channel was created with, unless it has been migrated to a new topic
+	topicName := channel.Status.TopicName(utils.TopicName(utils.KafkaChannelSeparator, channel.Namespace, channel.Name))
 	logger.Infow("Creating topic on Kafka cluster", zap.String("topic", topicName))
 	err := kafkaClusterAdmin.CreateTopic(topicName, &sarama.TopicDetail{
 		ReplicationFactor: channel.Spec.ReplicationFactor,
@@ -499,10 +591,9 @@ func (r *Reconciler) createTopic(ctx context.Context, channel *v1beta1.KafkaChan
 	return err
 }
 
-func (r *Reconciler) deleteTopic(ctx context.Context, channel *v1beta1.KafkaChannel, kafkaClusterAdmin sarama.ClusterAdmin) error {
+func (r *Reconciler) deleteTopic(ctx context.Context, topicName string, kafkaClusterAdmin sarama.ClusterAdmin) error {
 	logger := logging.FromContext(ctx)
 
-	topicName := utils.TopicName(utils.KafkaChannelSeparator, channel.Namespace, channel.Name)
 	logger.Infow("Deleting topic on Kafka Cluster", zap.String("topic", topicName))
 	err := kafkaClusterAdmin.DeleteTopic(topicName)
 	if err == sarama.ErrUnknownTopicOrPartition {
@@ -515,6 +606,59 @@ func (r *Reconciler) deleteTopic(ctx context.Context, channel *v1beta1.KafkaChan
 	return err
 }
 
+// reconcileRetryTopics creates the retry topics of the subscribers which use them, and deletes any other retry
+// topics of the channel (e.g. those of removed subscribers, or left over from a higher retry count).
+func (r *Reconciler) reconcileRetryTopics(ctx context.Context, channel *v1beta1.KafkaChannel, kafkaClusterAdmin sarama.ClusterAdmin) error {
+	logger := logging.FromContext(ctx)
+
+	topicName := utils.TopicName(utils.KafkaChannelSeparator, channel.Namespace, channel.Name)
+	retryTopicNames := make(map[string]bool)
+	for _, subscriber := range channel.Spec.Subscribers {
+		// The delivery annotations have already been validated.
+		delivery, _ := channel.SubscriberDelivery(subscriber.UID)
+		retryTopics, err := retrytopic.SubscriberTopics(topicName, subscriber, delivery)
+		if err != nil {
+			return err
+		}
+		for _, retryTopicName := range retryTopics.Names() {
+			retryTopicNames[retryTopicName] = true
+			err := kafkaClusterAdmin.CreateTopic(retryTopicName, &sarama.TopicDetail{
+				ReplicationFactor: channel.Spec.ReplicationFactor,
+				NumPartitions:     channel.Spec.NumPartitions,
+			}, false)
+			if e, ok := err.(*sarama.TopicError); ok && e.Err == sarama.ErrTopicAlreadyExists {
+				continue
+			} else if err != nil {
+				logger.Errorw("Error creating retry topic", zap.String("topic", retryTopicName), zap.Error(err))
+				return err
+			}
+			logger.Infow("Successfully created retry topic", zap.String("topic", retryTopicName))
+		}
+	}
+	return r.deleteRetryTopics(ctx, topicName, retryTopicNames, kafkaClusterAdmin)
+}
+
+// deleteRetryTopics deletes the retry topics of the channel topic, except for the ones to keep.
+func (r *Reconciler) deleteRetryTopics(ctx context.Context, topicName string, keep map[string]bool, kafkaClusterAdmin sarama.ClusterAdmin) error {
+	logger := logging.FromContext(ctx)
+
+	topics, err := kafkaClusterAdmin.ListTopics()
+	if err != nil {
+		return err
+	}
+	for name := range topics {
+		if !retrytopic.IsName(topicName, name) || keep[name] {
+			continue
+		}
+		logger.Infow("Deleting retry topic on Kafka Cluster", zap.String("topic", name))
+		if err := kafkaClusterAdmin.DeleteTopic(name); err != nil && err != sarama.ErrUnknownTopicOrPartition {
+			logger.Errorw("Error deleting retry topic", zap.String("topic", name), zap.Error(err))
+			return err
+		}
+	}
+	return nil
+}
+
 func (r *Reconciler) updateKafkaConfig(ctx context.Context, configMap *corev1.ConfigMap) {
 	logging.FromContext(ctx).Info("Reloading Kafka configuration")
 	kafkaConfig, err := utils.GetKafkaConfig(configMap.Data)
@@ -522,10 +666,19 @@ func (r *Reconciler) updateKafkaConfig(ctx context.Context, configMap *corev1.Co
 		logging.FromContext(ctx).Errorw("Error reading Kafka configuration", zap.Error(err))
 	}
 
-	if kafkaConfig.AuthSecretName != "" {
+	if kafkaConfig != nil && kafkaConfig.AuthSecretName != "" {
 		kafkaAuthConfig := utils.GetKafkaAuthData(ctx, kafkaConfig.AuthSecretName, kafkaConfig.AuthSecretNamespace)
 		r.kafkaAuthConfig = kafkaAuthConfig
 	}
+	if kafkaConfig != nil {
+		kafkaClusterAuthConfigs := make(map[string]*utils.KafkaAuthConfig)
+		for name, cluster := range kafkaConfig.Clusters {
+			if cluster.AuthSecretName != "" {
+				kafkaClusterAuthConfigs[name] = utils.GetKafkaAuthData(ctx, cluster.AuthSecretName, cluster.AuthSecretNamespace)
+			}
+		}
+		r.kafkaClusterAuthConfigs = kafkaClusterAuthConfigs
+	}
 	// For now just override the previous config.
 	// Eventually the previous config should be snapshotted to delete Kafka topics
 	r.kafkaConfig = kafkaConfig
@@ -533,10 +686,22 @@ func (r *Reconciler) updateKafkaConfig(ctx context.Context, configMap *corev1.Co
 }
 
 func (r *Reconciler) FinalizeKind(ctx context.Context, kc *v1beta1.KafkaChannel) pkgreconciler.Event {
+	// the topic of a channel deleted with a drain timeout is kept until its subscribers have consumed it
+	if drainTimeout, err := kc.DrainTimeout(); err == nil && drainTimeout > 0 && r.kafkaConfig != nil {
+		if event := r.drain(ctx, kc, drainTimeout); event != nil {
+			return event
+		}
+	}
 	// Do not attempt retrying creating the client because it might be a permanent error
 	// in which case the finalizer will never get removed.
 	if kafkaClusterAdmin, err := r.createClient(ctx, kc); err == nil && r.kafkaConfig != nil {
-		if err := r.deleteTopic(ctx, kc, kafkaClusterAdmin); err != nil {
+		topicName := utils.TopicName(utils.KafkaChannelSeparator, kc.Namespace, kc.Name)
+		for _, name := range channelTopicNames(topicName, &kc.Status) {
+			if err := r.deleteTopic(ctx, name, kafkaClusterAdmin); err != nil {
+				return err
+			}
+		}
+		if err := r.deleteRetryTopics(ctx, topicName, nil, kafkaClusterAdmin); err != nil {
 			return err
 		}
 	}
@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
 
 	"go.uber.org/zap"
 
@@ -29,11 +30,13 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	clientgotesting "k8s.io/client-go/testing"
 
 	eventingClient "knative.dev/eventing/pkg/client/injection/client"
 
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	duckv1 "knative.dev/pkg/apis/duck/v1"
 	kubeclient "knative.dev/pkg/client/injection/kube/client"
 	"knative.dev/pkg/configmap"
@@ -41,6 +44,7 @@ import (
 	"knative.dev/pkg/kmeta"
 	"knative.dev/pkg/logging"
 	"knative.dev/pkg/network"
+	"knative.dev/pkg/ptr"
 	. "knative.dev/pkg/reconciler/testing"
 
 	"knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
@@ -109,6 +113,7 @@ func TestAllCases(t *testing.T) {
 				Object: reconcilertesting.NewKafkaChannel(kcName, testNS,
 					reconcilertesting.WithInitKafkaChannelConditions,
 					reconcilertesting.WithKafkaChannelConfigReady(),
+					reconcilertesting.WithKafkaChannelClusterPinned(""),
 					reconcilertesting.WithKafkaChannelTopicReady(),
 					reconcilertesting.WithKafkaChannelServiceReady(),
 					reconcilertesting.WithKafkaChannelEndpointsNotReady("DispatcherEndpointsDoesNotExist", "Dispatcher Endpoints does not exist")),
@@ -122,6 +127,25 @@ func TestAllCases(t *testing.T) {
 				Eventf(corev1.EventTypeNormal, dispatcherServiceCreated, "Dispatcher service created"),
 				Eventf(corev1.EventTypeWarning, "InternalError", `endpoints "kafka-ch-dispatcher" not found`),
 			},
+		}, {
+			Name: "unknown Kafka cluster",
+			Key:  kcKey,
+			Objects: []runtime.Object{
+				reconcilertesting.NewKafkaChannel(kcName, testNS,
+					reconcilertesting.WithKafkaFinalizer(finalizerName),
+					reconcilertesting.WithKafkaChannelCluster("tenant-a")),
+			},
+			WantErr: true,
+			WantStatusUpdates: []clientgotesting.UpdateActionImpl{{
+				Object: reconcilertesting.NewKafkaChannel(kcName, testNS,
+					reconcilertesting.WithInitKafkaChannelConditions,
+					reconcilertesting.WithKafkaFinalizer(finalizerName),
+					reconcilertesting.WithKafkaChannelCluster("tenant-a"),
+					reconcilertesting.WithKafkaChannelConfigNotReady("UnknownKafkaCluster", `unknown Kafka cluster "tenant-a" in annotation kafka.eventing.knative.dev/cluster`)),
+			}},
+			WantEvents: []string{
+				Eventf(corev1.EventTypeWarning, "InternalError", `unknown Kafka cluster "tenant-a" in annotation kafka.eventing.knative.dev/cluster`),
+			},
 		}, {
 			Name: "Service does not exist, automatically created",
 			Key:  kcKey,
@@ -139,6 +163,7 @@ func TestAllCases(t *testing.T) {
 					reconcilertesting.WithInitKafkaChannelConditions,
 					reconcilertesting.WithKafkaFinalizer(finalizerName),
 					reconcilertesting.WithKafkaChannelConfigReady(),
+					reconcilertesting.WithKafkaChannelClusterPinned(""),
 					reconcilertesting.WithKafkaChannelTopicReady(),
 					reconcilertesting.WithKafkaChannelDeploymentReady(),
 					reconcilertesting.WithKafkaChannelServiceReady(),
@@ -163,6 +188,7 @@ func TestAllCases(t *testing.T) {
 					reconcilertesting.WithInitKafkaChannelConditions,
 					reconcilertesting.WithKafkaFinalizer(finalizerName),
 					reconcilertesting.WithKafkaChannelConfigReady(),
+					reconcilertesting.WithKafkaChannelClusterPinned(""),
 					reconcilertesting.WithKafkaChannelTopicReady(),
 					reconcilertesting.WithKafkaChannelDeploymentReady(),
 					reconcilertesting.WithKafkaChannelServiceReady(),
@@ -188,6 +214,7 @@ func TestAllCases(t *testing.T) {
 					reconcilertesting.WithInitKafkaChannelConditions,
 					reconcilertesting.WithKafkaFinalizer(finalizerName),
 					reconcilertesting.WithKafkaChannelConfigReady(),
+					reconcilertesting.WithKafkaChannelClusterPinned(""),
 					reconcilertesting.WithKafkaChannelTopicReady(),
 					reconcilertesting.WithKafkaChannelDeploymentReady(),
 					reconcilertesting.WithKafkaChannelServiceReady(),
@@ -216,6 +243,7 @@ func TestAllCases(t *testing.T) {
 					reconcilertesting.WithInitKafkaChannelConditions,
 					reconcilertesting.WithKafkaFinalizer(finalizerName),
 					reconcilertesting.WithKafkaChannelConfigReady(),
+					reconcilertesting.WithKafkaChannelClusterPinned(""),
 					reconcilertesting.WithKafkaChannelTopicReady(),
 					reconcilertesting.WithKafkaChannelDeploymentReady(),
 					reconcilertesting.WithKafkaChannelServiceReady(),
@@ -244,6 +272,7 @@ func TestAllCases(t *testing.T) {
 					reconcilertesting.WithInitKafkaChannelConditions,
 					reconcilertesting.WithKafkaFinalizer(finalizerName),
 					reconcilertesting.WithKafkaChannelConfigReady(),
+					reconcilertesting.WithKafkaChannelClusterPinned(""),
 					reconcilertesting.WithKafkaChannelTopicReady(),
 					reconcilertesting.WithKafkaChannelDeploymentReady(),
 					reconcilertesting.WithKafkaChannelServiceReady(),
@@ -272,6 +301,7 @@ func TestAllCases(t *testing.T) {
 					reconcilertesting.WithInitKafkaChannelConditions,
 					reconcilertesting.WithKafkaFinalizer(finalizerName),
 					reconcilertesting.WithKafkaChannelConfigReady(),
+					reconcilertesting.WithKafkaChannelClusterPinned(""),
 					reconcilertesting.WithKafkaChannelTopicReady(),
 					reconcilertesting.WithKafkaChannelDeploymentReady(),
 					reconcilertesting.WithKafkaChannelServiceReady(),
@@ -301,6 +331,7 @@ func TestAllCases(t *testing.T) {
 					reconcilertesting.WithInitKafkaChannelConditions,
 					reconcilertesting.WithKafkaFinalizer(finalizerName),
 					reconcilertesting.WithKafkaChannelConfigReady(),
+					reconcilertesting.WithKafkaChannelClusterPinned(""),
 					reconcilertesting.WithKafkaChannelTopicReady(),
 					reconcilertesting.WithKafkaChannelDeploymentReady(),
 					reconcilertesting.WithKafkaChannelServiceReady(),
@@ -362,6 +393,7 @@ func TestTopicExists(t *testing.T) {
 				reconcilertesting.WithInitKafkaChannelConditions,
 				reconcilertesting.WithKafkaFinalizer(finalizerName),
 				reconcilertesting.WithKafkaChannelConfigReady(),
+				reconcilertesting.WithKafkaChannelClusterPinned(""),
 				reconcilertesting.WithKafkaChannelTopicReady(),
 				reconcilertesting.WithKafkaChannelDeploymentReady(),
 				reconcilertesting.WithKafkaChannelServiceReady(),
@@ -430,6 +462,7 @@ func TestDeploymentUpdatedOnImageChange(t *testing.T) {
 				reconcilertesting.WithInitKafkaChannelConditions,
 				reconcilertesting.WithKafkaFinalizer(finalizerName),
 				reconcilertesting.WithKafkaChannelConfigReady(),
+				reconcilertesting.WithKafkaChannelClusterPinned(""),
 				reconcilertesting.WithKafkaChannelTopicReady(),
 				//				reconcilekafkatesting.WithKafkaChannelDeploymentReady(),
 				reconcilertesting.WithKafkaChannelServiceReady(),
@@ -499,6 +532,7 @@ func TestDeploymentZeroReplicas(t *testing.T) {
 				reconcilertesting.WithInitKafkaChannelConditions,
 				reconcilertesting.WithKafkaFinalizer(finalizerName),
 				reconcilertesting.WithKafkaChannelConfigReady(),
+				reconcilertesting.WithKafkaChannelClusterPinned(""),
 				reconcilertesting.WithKafkaChannelTopicReady(),
 				//				reconcilekafkatesting.WithKafkaChannelDeploymentReady(),
 				reconcilertesting.WithKafkaChannelServiceReady(),
@@ -566,6 +600,7 @@ func TestDeploymentMoreThanOneReplicas(t *testing.T) {
 				reconcilertesting.WithInitKafkaChannelConditions,
 				reconcilertesting.WithKafkaFinalizer(finalizerName),
 				reconcilertesting.WithKafkaChannelConfigReady(),
+				reconcilertesting.WithKafkaChannelClusterPinned(""),
 				reconcilertesting.WithKafkaChannelTopicReady(),
 				//				reconcilekafkatesting.WithKafkaChannelDeploymentReady(),
 				reconcilertesting.WithKafkaChannelServiceReady(),
@@ -610,9 +645,62 @@ func TestDeploymentMoreThanOneReplicas(t *testing.T) {
 	}, zap.L()))
 }
 
+func TestReconcileRetryTopics(t *testing.T) {
+	subscriberUID := types.UID("6f8a4c7e-7b1a-4b59-9a54-8a3e2f7c1d20")
+	removedUID := "0c1d2e3f-7b1a-4b59-9a54-8a3e2f7c1d20"
+	topicName := TopicName(KafkaChannelSeparator, testNS, kcName)
+
+	kc := reconcilertesting.NewKafkaChannel(kcName, testNS)
+	kc.Annotations = map[string]string{v1beta1.DeliveryRetryTopicsAnnotation: "true"}
+	kc.Spec.Subscribers = []eventingduckv1.SubscriberSpec{{
+		UID:      subscriberUID,
+		Delivery: &eventingduckv1.DeliverySpec{Retry: ptr.Int32(2)},
+	}}
+
+	var created, deleted []string
+	r := &Reconciler{}
+	clusterAdmin := &mockClusterAdmin{
+		mockCreateTopicFunc: func(topic string, detail *sarama.TopicDetail, validateOnly bool) error {
+			created = append(created, topic)
+			return nil
+		},
+		mockDeleteTopicFunc: func(topic string) error {
+			deleted = append(deleted, topic)
+			return nil
+		},
+		mockListTopicsFunc: func() (map[string]sarama.TopicDetail, error) {
+			return map[string]sarama.TopicDetail{
+				topicName: {},
+				topicName + ".retry." + string(subscriberUID) + ".1": {},
+				topicName + ".retry." + string(subscriberUID) + ".3": {},
+				topicName + ".retry." + removedUID + ".1":            {},
+				topicName + ".retry.other-channel":                   {},
+			}, nil
+		},
+	}
+
+	err := r.reconcileRetryTopics(context.TODO(), kc, clusterAdmin)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		topicName + ".retry." + string(subscriberUID) + ".1",
+		topicName + ".retry." + string(subscriberUID) + ".2",
+	}, created)
+	assert.ElementsMatch(t, []string{
+		topicName + ".retry." + string(subscriberUID) + ".3",
+		topicName + ".retry." + removedUID + ".1",
+	}, deleted)
+}
+
 type mockClusterAdmin struct {
 	mockCreateTopicFunc func(topic string, detail *sarama.TopicDetail, validateOnly bool) error
 	mockDeleteTopicFunc func(topic string) error
+	mockListTopicsFunc  func() (map[string]sarama.TopicDetail, error)
+
+	mockListConsumerGroupsFunc  func() (map[string]string, error)
+	mockDeleteConsumerGroupFunc func(group string) error
+
+	mockListConsumerGroupOffsetsFunc func(group string, topicPartitions map[string][]int32) (*sarama.OffsetFetchResponse, error)
+	mockDescribeTopicsFunc           func(topics []string) ([]*sarama.TopicMetadata, error)
 }
 
 func (ca *mockClusterAdmin) AlterPartitionReassignments(topic string, assignment [][]int32) error {
@@ -646,10 +734,16 @@ func (ca *mockClusterAdmin) DeleteTopic(topic string) error {
 }
 
 func (ca *mockClusterAdmin) DescribeTopics(topics []string) (metadata []*sarama.TopicMetadata, err error) {
+	if ca.mockDescribeTopicsFunc != nil {
+		return ca.mockDescribeTopicsFunc(topics)
+	}
 	return nil, nil
 }
 
 func (ca *mockClusterAdmin) ListTopics() (map[string]sarama.TopicDetail, error) {
+	if ca.mockListTopicsFunc != nil {
+		return ca.mockListTopicsFunc()
+	}
 	return nil, nil
 }
 
@@ -682,6 +776,9 @@ func (ca *mockClusterAdmin) DeleteACL(filter sarama.AclFilter, validateOnly bool
 }
 
 func (ca *mockClusterAdmin) ListConsumerGroups() (map[string]string, error) {
+	if ca.mockListConsumerGroupsFunc != nil {
+		return ca.mockListConsumerGroupsFunc()
+	}
 	return nil, nil
 }
 
@@ -690,6 +787,9 @@ func (ca *mockClusterAdmin) DescribeConsumerGroups(groups []string) ([]*sarama.G
 }
 
 func (ca *mockClusterAdmin) ListConsumerGroupOffsets(group string, topicPartitions map[string][]int32) (*sarama.OffsetFetchResponse, error) {
+	if ca.mockListConsumerGroupOffsetsFunc != nil {
+		return ca.mockListConsumerGroupOffsetsFunc(group, topicPartitions)
+	}
 	return &sarama.OffsetFetchResponse{}, nil
 }
 
@@ -699,6 +799,9 @@ func (ca *mockClusterAdmin) DescribeCluster() (brokers []*sarama.Broker, control
 
 // Delete a consumer group.
 func (ca *mockClusterAdmin) DeleteConsumerGroup(group string) error {
+	if ca.mockDeleteConsumerGroupFunc != nil {
+		return ca.mockDeleteConsumerGroupFunc(group)
+	}
 	return nil
 }
 
@@ -0,0 +1,176 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"go.uber.org/zap"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+
+	"knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/channel/consolidated/utils"
+)
+
+// migrationCheckInterval is the interval at which a migrating channel is checked, which leaves the dispatchers
+// the time to apply each phase of the migration before the next one.
+const migrationCheckInterval = 10 * time.Second
+
+// migrationDrainTimeout bounds the time the subscribers are given to consume the previous topic of a migrating
+// channel, after which they are cut over regardless (var to allow testing).
+var migrationDrainTimeout = time.Hour
+
+// reconcileTopicMigration migrates the channel to a new topic when the number of partitions or the replication
+// factor of its spec no longer match those of its topic, as they can not be changed without breaking the ordering
+// of its events:
+//  1. the new topic is created and the events are produced to it, while the subscribers keep consuming the
+//     previous topic (TopicMigrationDraining)
+//  2. once the subscribers have consumed the previous topic (or after the migrationDrainTimeout), they consume the
+//     new one from its oldest events, with the same consumer groups (TopicMigrationCutOver)
+//  3. once the dispatchers have cut the subscribers over, the previous topic is deleted
+func (r *Reconciler) reconcileTopicMigration(ctx context.Context, kc *v1beta1.KafkaChannel, kafkaClusterAdmin sarama.ClusterAdmin) error {
+	logger := logging.FromContext(ctx)
+
+	migration := kc.Status.TopicMigration
+	if migration == nil {
+		return r.startTopicMigration(ctx, kc, kafkaClusterAdmin)
+	}
+	if elapsed := time.Since(migration.LastTransitionTime.Time); elapsed < migrationCheckInterval {
+		r.requeueAfter(kc, migrationCheckInterval-elapsed)
+		return nil
+	}
+
+	switch migration.Phase {
+	case v1beta1.TopicMigrationDraining:
+		if err := r.createTopicIfNotExists(migration.ToTopic, kc, kafkaClusterAdmin); err != nil {
+			return err
+		}
+		lag, err := r.subscribersLag(ctx, kc, false)
+		if err != nil {
+			return err
+		}
+		if lag > 0 {
+			if time.Since(migration.LastTransitionTime.Time) < migrationDrainTimeout {
+				logger.Infow("Waiting for the subscribers to drain the topic", zap.String("topic", migration.FromTopic), zap.Int64("lag", lag))
+				r.requeueAfter(kc, migrationCheckInterval)
+				return nil
+			}
+			logger.Warnw("Timed out draining the topic, cutting the subscribers over", zap.String("topic", migration.FromTopic),
+				zap.Int64("lag", lag), zap.Duration("timeout", migrationDrainTimeout))
+			recordMigrationWarning(ctx, kc, "TopicMigrationDrainTimedOut", "Subscribers cut over from topic %s to topic %s with %d events left to consume",
+				migration.FromTopic, migration.ToTopic, lag)
+		} else {
+			recordMigrationEvent(ctx, kc, "TopicMigrationCutOver", "Subscribers cut over from topic %s to topic %s", migration.FromTopic, migration.ToTopic)
+		}
+		kc.Status.CutOverTopicMigration(time.Now())
+		r.requeueAfter(kc, migrationCheckInterval)
+
+	case v1beta1.TopicMigrationCutOver:
+		if err := r.deleteTopic(ctx, migration.FromTopic, kafkaClusterAdmin); err != nil {
+			return err
+		}
+		kc.Status.CompleteTopicMigration()
+		recordMigrationEvent(ctx, kc, "TopicMigrationCompleted", "Migrated from topic %s to topic %s", migration.FromTopic, migration.ToTopic)
+	}
+	return nil
+}
+
+// startTopicMigration starts the migration of the channel to a new topic if its topic no longer matches its spec.
+func (r *Reconciler) startTopicMigration(ctx context.Context, kc *v1beta1.KafkaChannel, kafkaClusterAdmin sarama.ClusterAdmin) error {
+	logger := logging.FromContext(ctx)
+
+	createdTopicName := utils.TopicName(utils.KafkaChannelSeparator, kc.Namespace, kc.Name)
+	topicName := kc.Status.TopicName(createdTopicName)
+	metadata, err := kafkaClusterAdmin.DescribeTopics([]string{topicName})
+	if err != nil {
+		return err
+	}
+	// the topic is left as is when its metadata is not known yet
+	if len(metadata) != 1 || metadata[0].Err != sarama.ErrNoError || len(metadata[0].Partitions) == 0 {
+		return nil
+	}
+	numPartitions, replicationFactor := int32(len(metadata[0].Partitions)), int16(len(metadata[0].Partitions[0].Replicas))
+	if numPartitions == kc.Spec.NumPartitions && replicationFactor == kc.Spec.ReplicationFactor {
+		return nil
+	}
+
+	toTopicName := utils.MigrationTopicName(createdTopicName, kc.Generation)
+	if toTopicName == topicName {
+		// the topic was already created for this generation of the spec, the cluster did not honour it
+		logger.Warnw("The topic does not match the spec of the channel", zap.String("topic", topicName),
+			zap.Int32("numPartitions", numPartitions), zap.Int16("replicationFactor", replicationFactor))
+		return nil
+	}
+	logger.Infow("Migrating the channel to a new topic", zap.String("topic", topicName), zap.String("newTopic", toTopicName),
+		zap.Int32("numPartitions", kc.Spec.NumPartitions), zap.Int16("replicationFactor", kc.Spec.ReplicationFactor))
+	if err := r.createTopicIfNotExists(toTopicName, kc, kafkaClusterAdmin); err != nil {
+		return err
+	}
+	kc.Status.StartTopicMigration(topicName, toTopicName, time.Now())
+	recordMigrationEvent(ctx, kc, "TopicMigrationStarted", "Migrating from topic %s to topic %s", topicName, toTopicName)
+	r.requeueAfter(kc, migrationCheckInterval)
+	return nil
+}
+
+// createTopicIfNotExists creates a topic with the partitions and replication factor of the spec of the channel.
+func (r *Reconciler) createTopicIfNotExists(topicName string, kc *v1beta1.KafkaChannel, kafkaClusterAdmin sarama.ClusterAdmin) error {
+	err := kafkaClusterAdmin.CreateTopic(topicName, &sarama.TopicDetail{
+		ReplicationFactor: kc.Spec.ReplicationFactor,
+		NumPartitions:     kc.Spec.NumPartitions,
+	}, false)
+	if e, ok := err.(*sarama.TopicError); ok && e.Err == sarama.ErrTopicAlreadyExists {
+		return nil
+	}
+	return err
+}
+
+// channelTopicNames returns the topics of a channel, including those of its migration in progress (if any).
+func channelTopicNames(createdTopicName string, status *v1beta1.KafkaChannelStatus) []string {
+	names := []string{createdTopicName}
+	add := func(name string) {
+		for _, n := range names {
+			if n == name {
+				return
+			}
+		}
+		names = append(names, name)
+	}
+	add(status.TopicName(createdTopicName))
+	if status.TopicMigration != nil {
+		add(status.TopicMigration.FromTopic)
+		add(status.TopicMigration.ToTopic)
+	}
+	return names
+}
+
+func recordMigrationEvent(ctx context.Context, kc *v1beta1.KafkaChannel, reason string, messageFmt string, args ...interface{}) {
+	if recorder := controller.GetEventRecorder(ctx); recorder != nil {
+		recorder.Eventf(kc, corev1.EventTypeNormal, reason, messageFmt, args...)
+	}
+}
+
+func recordMigrationWarning(ctx context.Context, kc *v1beta1.KafkaChannel, reason string, messageFmt string, args ...interface{}) {
+	if recorder := controller.GetEventRecorder(ctx); recorder != nil {
+		recorder.Eventf(kc, corev1.EventTypeWarning, reason, messageFmt, args...)
+	}
+}
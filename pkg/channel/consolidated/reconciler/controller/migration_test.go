@@ -0,0 +1,174 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/controller"
+
+	"knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	reconcilertesting "knative.dev/eventing-kafka/pkg/channel/consolidated/reconciler/testing"
+	. "knative.dev/eventing-kafka/pkg/channel/consolidated/utils"
+)
+
+func TestReconcileTopicMigration(t *testing.T) {
+	topicName := TopicName(KafkaChannelSeparator, testNS, kcName)
+	migratedTopicName := MigrationTopicName(topicName, 3)
+
+	kc := reconcilertesting.NewKafkaChannel(kcName, testNS)
+	kc.Generation = 3
+	kc.Spec.NumPartitions = 4
+	kc.Spec.ReplicationFactor = 1
+	kc.Spec.Subscribers = []eventingduckv1.SubscriberSpec{{UID: "sub-1"}}
+
+	// the topic of the channel was created with a single partition
+	partitions := map[string]int{topicName: 1}
+	var created, deleted []string
+	committed := int64(2)
+	clusterAdmin := &mockClusterAdmin{
+		mockCreateTopicFunc: func(topic string, detail *sarama.TopicDetail, validateOnly bool) error {
+			assert.Equal(t, int32(4), detail.NumPartitions)
+			created = append(created, topic)
+			partitions[topic] = int(detail.NumPartitions)
+			return nil
+		},
+		mockDeleteTopicFunc: func(topic string) error {
+			deleted = append(deleted, topic)
+			return nil
+		},
+		mockDescribeTopicsFunc: func(topics []string) ([]*sarama.TopicMetadata, error) {
+			metadata := &sarama.TopicMetadata{Name: topics[0]}
+			for i := 0; i < partitions[topics[0]]; i++ {
+				metadata.Partitions = append(metadata.Partitions, &sarama.PartitionMetadata{ID: int32(i), Replicas: []int32{1}})
+			}
+			return []*sarama.TopicMetadata{metadata}, nil
+		},
+		mockListConsumerGroupOffsetsFunc: func(group string, topicPartitions map[string][]int32) (*sarama.OffsetFetchResponse, error) {
+			// only the topic being drained is measured, the retry topics are kept by the migration
+			assert.Equal(t, map[string][]int32{topicName: {0}}, topicPartitions)
+			response := &sarama.OffsetFetchResponse{}
+			response.AddBlock(topicName, 0, &sarama.OffsetFetchResponseBlock{Offset: committed})
+			return response, nil
+		},
+	}
+	var requeued []time.Duration
+	r := &Reconciler{
+		kafkaConfig:        &KafkaConfig{Brokers: []string{brokerName}},
+		kafkaClusterAdmin:  clusterAdmin,
+		kafkaOffsetsClient: &mockOffsetsClient{newest: map[string]int64{topicName: 10}},
+		enqueueAfter: func(obj interface{}, after time.Duration) {
+			requeued = append(requeued, after)
+		},
+	}
+	// elapse moves the last transition of the migration back, as if the check interval had elapsed
+	elapse := func() {
+		kc.Status.TopicMigration.LastTransitionTime = metav1.NewTime(kc.Status.TopicMigration.LastTransitionTime.Add(-migrationCheckInterval))
+	}
+
+	// The New Topic Is Created, And The Events Are Produced To It
+	assert.Nil(t, r.reconcileTopicMigration(context.TODO(), kc, clusterAdmin))
+	assert.Equal(t, []string{migratedTopicName}, created)
+	assert.Equal(t, v1beta1.TopicMigrationDraining, kc.Status.TopicMigration.Phase)
+	assert.Equal(t, topicName, kc.Status.TopicName(topicName))
+	assert.Equal(t, migratedTopicName, kc.Status.IngressTopicName(topicName))
+	assert.Equal(t, []time.Duration{migrationCheckInterval}, requeued)
+
+	// The Dispatchers Are Left The Time To Produce To The New Topic Before The Lag Is Measured
+	assert.Nil(t, r.reconcileTopicMigration(context.TODO(), kc, clusterAdmin))
+	assert.Equal(t, v1beta1.TopicMigrationDraining, kc.Status.TopicMigration.Phase)
+	assert.Len(t, requeued, 2)
+
+	// The Subscribers Keep Consuming The Previous Topic Until They Have Drained It
+	elapse()
+	assert.Nil(t, r.reconcileTopicMigration(context.TODO(), kc, clusterAdmin))
+	assert.Equal(t, v1beta1.TopicMigrationDraining, kc.Status.TopicMigration.Phase)
+
+	committed = 10
+	assert.Nil(t, r.reconcileTopicMigration(context.TODO(), kc, clusterAdmin))
+	assert.Equal(t, v1beta1.TopicMigrationCutOver, kc.Status.TopicMigration.Phase)
+	assert.Equal(t, migratedTopicName, kc.Status.TopicName(topicName))
+	assert.Empty(t, deleted)
+
+	// The Previous Topic Is Deleted Once The Dispatchers Have Cut The Subscribers Over
+	elapse()
+	assert.Nil(t, r.reconcileTopicMigration(context.TODO(), kc, clusterAdmin))
+	assert.Equal(t, []string{topicName}, deleted)
+	assert.Nil(t, kc.Status.TopicMigration)
+	assert.Equal(t, migratedTopicName, kc.Status.Topic)
+
+	// The Migrated Topic Matches The Spec (The Draining Checks Only Made Sure It Still Existed)
+	assert.Nil(t, r.reconcileTopicMigration(context.TODO(), kc, clusterAdmin))
+	assert.Nil(t, kc.Status.TopicMigration)
+	for _, topic := range created {
+		assert.Equal(t, migratedTopicName, topic)
+	}
+}
+
+func TestChannelTopicNames(t *testing.T) {
+	status := &v1beta1.KafkaChannelStatus{}
+	assert.Equal(t, []string{"topic"}, channelTopicNames("topic", status))
+
+	status.StartTopicMigration("topic", "topic.G2", time.Now())
+	assert.Equal(t, []string{"topic", "topic.G2"}, channelTopicNames("topic", status))
+
+	status.CutOverTopicMigration(time.Now())
+	status.CompleteTopicMigration()
+	status.StartTopicMigration("topic.G2", "topic.G3", time.Now())
+	assert.Equal(t, []string{"topic", "topic.G2", "topic.G3"}, channelTopicNames("topic", status))
+}
+
+func TestReconcileTopicMigrationDrainTimeout(t *testing.T) {
+	topicName := TopicName(KafkaChannelSeparator, testNS, kcName)
+	migratedTopicName := MigrationTopicName(topicName, 3)
+
+	kc := reconcilertesting.NewKafkaChannel(kcName, testNS)
+	kc.Spec.Subscribers = []eventingduckv1.SubscriberSpec{{UID: "sub-1"}}
+	kc.Status.StartTopicMigration(topicName, migratedTopicName, time.Now().Add(-migrationDrainTimeout))
+
+	// the subscriber never catches up on the previous topic
+	clusterAdmin := &mockClusterAdmin{
+		mockListConsumerGroupOffsetsFunc: func(group string, topicPartitions map[string][]int32) (*sarama.OffsetFetchResponse, error) {
+			response := &sarama.OffsetFetchResponse{}
+			response.AddBlock(topicName, 0, &sarama.OffsetFetchResponseBlock{Offset: 2})
+			return response, nil
+		},
+	}
+	r := &Reconciler{
+		kafkaConfig:        &KafkaConfig{Brokers: []string{brokerName}},
+		kafkaClusterAdmin:  clusterAdmin,
+		kafkaOffsetsClient: &mockOffsetsClient{newest: map[string]int64{topicName: 10}},
+	}
+	recorder := record.NewFakeRecorder(10)
+	ctx := controller.WithEventRecorder(context.TODO(), recorder)
+
+	// The Subscribers Are Cut Over Once The Drain Timeout Has Elapsed, With A Warning
+	assert.Nil(t, r.reconcileTopicMigration(ctx, kc, clusterAdmin))
+	assert.Equal(t, v1beta1.TopicMigrationCutOver, kc.Status.TopicMigration.Phase)
+	assert.Equal(t, migratedTopicName, kc.Status.TopicName(topicName))
+	assert.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "Warning TopicMigrationDrainTimedOut")
+}
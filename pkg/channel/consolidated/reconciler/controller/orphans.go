@@ -0,0 +1,101 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"knative.dev/pkg/logging"
+
+	"knative.dev/eventing-kafka/pkg/channel/consolidated/utils"
+	"knative.dev/eventing-kafka/pkg/common/orphans"
+)
+
+// orphanCollectionInterval returns the interval of the orphan collection of config-kafka, zero while it is disabled.
+func (r *Reconciler) orphanCollectionInterval() time.Duration {
+	if kafkaConfig := r.kafkaConfig; kafkaConfig != nil {
+		return kafkaConfig.OrphanCollectionInterval
+	}
+	return 0
+}
+
+// collectOrphans finds the topics and consumer groups of the default and the named Kafka clusters which no longer
+// belong to any channel or subscriber, and deletes them if config-kafka enables it (else they are only reported).
+// Only the topics of the channels seen since the controller started are collected, and never dead letter topics.
+func (r *Reconciler) collectOrphans(ctx context.Context) {
+	logger := logging.FromContext(ctx).With(zap.String("component", "orphan-collector"))
+	kafkaConfig := r.kafkaConfig
+	if kafkaConfig == nil {
+		return
+	}
+
+	// the channels of a cache which has not synced yet would make every topic look orphaned
+	if !r.kafkachannelInformer.HasSynced() {
+		logger.Info("Skipping the orphan collection until the KafkaChannels have synced")
+		return
+	}
+
+	// every channel owns its topics and groups in all the clusters, as a channel may have moved to another one
+	clusterNames := []string{""}
+	for name := range kafkaConfig.Clusters {
+		clusterNames = append(clusterNames, name)
+	}
+	sort.Strings(clusterNames)
+	for _, clusterName := range clusterNames {
+		clusterLogger := logger.With(zap.String("cluster", clusterName))
+		kafkaClusterAdmin, err := r.createClusterClient(ctx, clusterName)
+		if err != nil {
+			clusterLogger.Errorw("Failed to create the cluster admin client for the orphan collection", zap.Error(err))
+			continue
+		}
+		collector := &orphans.Collector{
+			IsChannelTopic: func(topic string) bool { return utils.IsTopicName(utils.KafkaChannelSeparator, topic) },
+			Seen:           r.orphansSeen,
+			DryRun:         !kafkaConfig.OrphanCollectionDelete,
+			Logger:         clusterLogger,
+		}
+		result, err := collector.Collect(kafkaClusterAdmin, kafkaClusterAdmin, r.orphanOwners)
+		if err != nil {
+			clusterLogger.Errorw("Failed to collect the orphaned topics and consumer groups", zap.Error(err))
+		} else {
+			clusterLogger.Infow("Collected the orphaned topics and consumer groups", zap.Int("topics", len(result.Topics)),
+				zap.Int("groups", len(result.Groups)), zap.Bool("dryRun", collector.DryRun))
+		}
+		if err := kafkaClusterAdmin.Close(); err != nil {
+			clusterLogger.Errorw("Failed to close the cluster admin client", zap.Error(err))
+		}
+	}
+}
+
+// orphanOwners returns the owners of the topics and groups of the KafkaChannels currently known to the lister.
+func (r *Reconciler) orphanOwners() (*orphans.Owners, error) {
+	channels, err := r.kafkachannelLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	owners := orphans.NewOwners()
+	for _, kc := range channels {
+		topicName := utils.TopicName(utils.KafkaChannelSeparator, kc.Namespace, kc.Name)
+		owners.AddChannel(kc, channelTopicNames(topicName, &kc.Status)...)
+	}
+	return owners, nil
+}
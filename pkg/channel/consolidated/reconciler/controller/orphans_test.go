@@ -0,0 +1,123 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+
+	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
+
+	"knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	reconcilertesting "knative.dev/eventing-kafka/pkg/channel/consolidated/reconciler/testing"
+	. "knative.dev/eventing-kafka/pkg/channel/consolidated/utils"
+	"knative.dev/eventing-kafka/pkg/common/orphans"
+)
+
+const (
+	ownedSubscriberUID    = "6f8a4c7e-7b1a-4b59-9a54-8a3e2f7c1d20"
+	orphanedSubscriberUID = "0c9b0e1c-3d55-4c36-8f44-5b8f2d0a7e11"
+)
+
+// syncedInformer is a SharedIndexInformer whose only implemented method reports whether it has synced.
+type syncedInformer struct {
+	cache.SharedIndexInformer
+	synced bool
+}
+
+func (i *syncedInformer) HasSynced() bool {
+	return i.synced
+}
+
+func TestCollectOrphans(t *testing.T) {
+	kc := reconcilertesting.NewKafkaChannel(kcName, testNS)
+	kc.Spec.Subscribers = []eventingduckv1.SubscriberSpec{{UID: ownedSubscriberUID}}
+	topicName := TopicName(KafkaChannelSeparator, testNS, kcName)
+	orphanedTopicName := TopicName(KafkaChannelSeparator, testNS, "deleted")
+
+	testCases := map[string]struct {
+		synced      bool
+		kafkaConfig *KafkaConfig
+		wantDeleted []string
+	}{
+		"not synced": {
+			kafkaConfig: &KafkaConfig{Brokers: []string{brokerName}, OrphanCollectionInterval: time.Hour, OrphanCollectionDelete: true},
+		},
+		"dry run": {
+			synced:      true,
+			kafkaConfig: &KafkaConfig{Brokers: []string{brokerName}, OrphanCollectionInterval: time.Hour},
+		},
+		"delete": {
+			synced:      true,
+			kafkaConfig: &KafkaConfig{Brokers: []string{brokerName}, OrphanCollectionInterval: time.Hour, OrphanCollectionDelete: true},
+			wantDeleted: []string{orphanedTopicName, "kafka." + testNS + ".deleted." + orphanedSubscriberUID},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var deleted []string
+			clusterAdmin := &mockClusterAdmin{
+				mockListTopicsFunc: func() (map[string]sarama.TopicDetail, error) {
+					return map[string]sarama.TopicDetail{
+						topicName:         {NumPartitions: 1},
+						orphanedTopicName: {NumPartitions: 1},
+						TopicName(KafkaChannelSeparator, testNS, "never-seen"): {NumPartitions: 1},
+						"other-application": {NumPartitions: 1},
+					}, nil
+				},
+				mockDeleteTopicFunc: func(topic string) error {
+					deleted = append(deleted, topic)
+					return nil
+				},
+				mockListConsumerGroupsFunc: func() (map[string]string, error) {
+					return map[string]string{
+						"kafka." + testNS + "." + kcName + "." + ownedSubscriberUID: "consumer",
+						"kafka." + testNS + ".deleted." + orphanedSubscriberUID:     "consumer",
+						"other-application": "consumer",
+					}, nil
+				},
+				mockDeleteConsumerGroupFunc: func(group string) error {
+					deleted = append(deleted, group)
+					return nil
+				},
+			}
+			listers := reconcilertesting.NewListers([]runtime.Object{kc})
+			seen := orphans.NewSeen() // the deleted channel was seen by an earlier collection
+			seenOwners := orphans.NewOwners()
+			seenOwners.AddChannel(&v1beta1.KafkaChannel{}, orphanedTopicName)
+			seen.Add(seenOwners)
+			r := &Reconciler{
+				kafkaConfig:          tc.kafkaConfig,
+				kafkaClusterAdmin:    clusterAdmin,
+				kafkachannelLister:   listers.GetKafkaChannelLister(),
+				kafkachannelInformer: &syncedInformer{synced: tc.synced},
+				orphansSeen:          seen,
+			}
+
+			assert.Equal(t, time.Hour, r.orphanCollectionInterval())
+			r.collectOrphans(context.TODO())
+			assert.Equal(t, tc.wantDeleted, deleted)
+		})
+	}
+}
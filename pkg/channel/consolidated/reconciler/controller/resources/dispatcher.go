@@ -20,10 +20,19 @@ import (
 	v1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"knative.dev/pkg/system"
+
+	"knative.dev/eventing-kafka/pkg/channel/consolidated/dispatcher/health"
 )
 
-const DispatcherContainerName = "dispatcher"
+const (
+	DispatcherContainerName = "dispatcher"
+
+	// the dispatcher is created before it connects to kafka, which the probes give it time for
+	probeInitialDelaySeconds = 10
+	probePeriodSeconds       = 5
+)
 
 var (
 	serviceAccountName = "kafka-ch-dispatcher"
@@ -73,7 +82,12 @@ func MakeDispatcher(args DispatcherArgs) *v1.Deployment {
 							Ports: []corev1.ContainerPort{{
 								Name:          "metrics",
 								ContainerPort: 9090,
+							}, {
+								Name:          "health",
+								ContainerPort: health.Port,
 							}},
+							LivenessProbe:  makeProbe(health.LivenessPath),
+							ReadinessProbe: makeProbe(health.ReadinessPath),
 							VolumeMounts: []corev1.VolumeMount{
 								{
 									Name:      "config-kafka",
@@ -100,6 +114,19 @@ func MakeDispatcher(args DispatcherArgs) *v1.Deployment {
 	}
 }
 
+func makeProbe(path string) *corev1.Probe {
+	return &corev1.Probe{
+		Handler: corev1.Handler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Port: intstr.FromInt(health.Port),
+				Path: path,
+			},
+		},
+		InitialDelaySeconds: probeInitialDelaySeconds,
+		PeriodSeconds:       probePeriodSeconds,
+	}
+}
+
 func makeEnv(args DispatcherArgs) []corev1.EnvVar {
 	vars := []corev1.EnvVar{{
 		Name:  system.NamespaceEnvKey,
@@ -24,6 +24,7 @@ import (
 	v1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"knative.dev/pkg/system"
 )
 
@@ -82,7 +83,30 @@ func TestNewDispatcher(t *testing.T) {
 							Ports: []corev1.ContainerPort{{
 								Name:          "metrics",
 								ContainerPort: 9090,
+							}, {
+								Name:          "health",
+								ContainerPort: 8082,
 							}},
+							LivenessProbe: &corev1.Probe{
+								Handler: corev1.Handler{
+									HTTPGet: &corev1.HTTPGetAction{
+										Port: intstr.FromInt(8082),
+										Path: "/healthz",
+									},
+								},
+								InitialDelaySeconds: 10,
+								PeriodSeconds:       5,
+							},
+							ReadinessProbe: &corev1.Probe{
+								Handler: corev1.Handler{
+									HTTPGet: &corev1.HTTPGetAction{
+										Port: intstr.FromInt(8082),
+										Path: "/healthy",
+									},
+								},
+								InitialDelaySeconds: 10,
+								PeriodSeconds:       5,
+							},
 							VolumeMounts: []corev1.VolumeMount{
 								{
 									Name:      "config-kafka",
@@ -181,7 +205,30 @@ func TestNewNamespaceDispatcher(t *testing.T) {
 							Ports: []corev1.ContainerPort{{
 								Name:          "metrics",
 								ContainerPort: 9090,
+							}, {
+								Name:          "health",
+								ContainerPort: 8082,
 							}},
+							LivenessProbe: &corev1.Probe{
+								Handler: corev1.Handler{
+									HTTPGet: &corev1.HTTPGetAction{
+										Port: intstr.FromInt(8082),
+										Path: "/healthz",
+									},
+								},
+								InitialDelaySeconds: 10,
+								PeriodSeconds:       5,
+							},
+							ReadinessProbe: &corev1.Probe{
+								Handler: corev1.Handler{
+									HTTPGet: &corev1.HTTPGetAction{
+										Port: intstr.FromInt(8082),
+										Path: "/healthy",
+									},
+								},
+								InitialDelaySeconds: 10,
+								PeriodSeconds:       5,
+							},
 							VolumeMounts: []corev1.VolumeMount{
 								{
 									Name:      "config-kafka",
@@ -19,9 +19,15 @@ package controller
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
 
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -29,17 +35,21 @@ import (
 
 	eventingduckv1 "knative.dev/eventing/pkg/apis/duck/v1"
 	"knative.dev/eventing/pkg/apis/eventing"
+	eventingchannels "knative.dev/eventing/pkg/channel"
 	"knative.dev/eventing/pkg/channel/fanout"
 	"knative.dev/eventing/pkg/kncloudevents"
+	kubeclient "knative.dev/pkg/client/injection/kube/client"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/injection"
 	"knative.dev/pkg/logging"
 	pkgreconciler "knative.dev/pkg/reconciler"
+	"knative.dev/pkg/system"
 	"knative.dev/pkg/tracing"
 
 	"knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
 	"knative.dev/eventing-kafka/pkg/channel/consolidated/dispatcher"
+	"knative.dev/eventing-kafka/pkg/channel/consolidated/dispatcher/health"
 	"knative.dev/eventing-kafka/pkg/channel/consolidated/utils"
 	kafkaclientset "knative.dev/eventing-kafka/pkg/client/clientset/versioned"
 	kafkaScheme "knative.dev/eventing-kafka/pkg/client/clientset/versioned/scheme"
@@ -47,8 +57,12 @@ import (
 	"knative.dev/eventing-kafka/pkg/client/injection/informers/messaging/v1beta1/kafkachannel"
 	kafkachannelreconciler "knative.dev/eventing-kafka/pkg/client/injection/reconciler/messaging/v1beta1/kafkachannel"
 	listers "knative.dev/eventing-kafka/pkg/client/listers/messaging/v1beta1"
+	dispatchmetrics "knative.dev/eventing-kafka/pkg/common/metrics"
 )
 
+// brokersCheckInterval is the interval between the checks of the reachability of the brokers.
+const brokersCheckInterval = 10 * time.Second
+
 func init() {
 	// Add run types to the default Kubernetes Scheme so Events can be
 	// logged for run types.
@@ -63,10 +77,16 @@ type Reconciler struct {
 	kafkachannelLister   listers.KafkaChannelLister
 	kafkachannelInformer cache.SharedIndexInformer
 	impl                 *controller.Impl
+
+	// healthThresholds holds the v1beta1.SubscriberHealthThresholds of config-kafka, which may be reloaded
+	healthThresholds atomic.Value
+	// kafkaConfig holds the current *utils.KafkaConfig, which maps the channels to their Kafka cluster
+	kafkaConfig atomic.Value
 }
 
 // Check that our Reconciler implements controller.Reconciler.
 var _ kafkachannelreconciler.Interface = (*Reconciler)(nil)
+var _ kafkachannelreconciler.ReadOnlyInterface = (*Reconciler)(nil)
 
 // NewController initializes the controller and is called by the generated code.
 // Registers event handlers to enqueue events.
@@ -90,11 +110,27 @@ func NewController(ctx context.Context, cmw configmap.Watcher) *controller.Impl
 
 	kafkaAuthCfg := utils.GetKafkaAuthData(ctx, kafkaConfig.AuthSecretName, kafkaConfig.AuthSecretNamespace)
 
+	// the named clusters are only connected to when the channels using them are dispatched
+	clusters := make(map[string]dispatcher.KafkaClusterArgs, len(kafkaConfig.Clusters))
+	for name, cluster := range kafkaConfig.Clusters {
+		clusterArgs := dispatcher.KafkaClusterArgs{Brokers: cluster.Brokers}
+		if cluster.AuthSecretName != "" {
+			clusterArgs.KafkaAuthConfig = utils.GetKafkaAuthData(ctx, cluster.AuthSecretName, cluster.AuthSecretNamespace)
+		}
+		clusters[name] = clusterArgs
+	}
+
 	connectionArgs := &kncloudevents.ConnectionArgs{
 		MaxIdleConns:        int(kafkaConfig.MaxIdleConns),
 		MaxIdleConnsPerHost: int(kafkaConfig.MaxIdleConnsPerHost),
 	}
 
+	// The health server is started first, so that the probes fail rather than time out while the
+	// dispatcher is being created.
+	healthServer := health.NewDispatcherHealthServer(strconv.Itoa(health.Port))
+	healthServer.Start(logger.Desugar())
+	go healthServer.WatchBrokers(ctx, logger, kafkaConfig.Brokers, brokersCheckInterval)
+
 	kafkaChannelInformer := kafkachannel.Get(ctx)
 	args := &dispatcher.KafkaDispatcherArgs{
 		KnCEConnectionArgs: connectionArgs,
@@ -103,6 +139,11 @@ func NewController(ctx context.Context, cmw configmap.Watcher) *controller.Impl
 		KafkaAuthConfig:    kafkaAuthCfg,
 		TopicFunc:          utils.TopicName,
 		Logger:             logger,
+		ProducerLinger:     kafkaConfig.ProducerLinger,
+		ProducerBatchSize:  kafkaConfig.ProducerBatchSize,
+		SaramaSettings:     kafkaConfig.SaramaSettings,
+		HealthServer:       healthServer,
+		Clusters:           clusters,
 	}
 	kafkaDispatcher, err := dispatcher.NewDispatcher(ctx, args)
 	if err != nil {
@@ -117,27 +158,137 @@ func NewController(ctx context.Context, cmw configmap.Watcher) *controller.Impl
 		kafkachannelLister:   kafkaChannelInformer.Lister(),
 		kafkachannelInformer: kafkaChannelInformer.Informer(),
 	}
+	r.healthThresholds.Store(kafkaConfig.SubscriberHealthThresholds)
+	r.kafkaConfig.Store(kafkaConfig)
 	r.impl = kafkachannelreconciler.NewImpl(ctx, r)
 
+	// The channels of a bucket lost to another replica are not reconciled again until they change, so their
+	// consumers are torn down as soon as this replica is demoted.
+	if leaderAware, ok := r.impl.Reconciler.(leaderAwareReconciler); ok {
+		r.impl.Reconciler = &demotionAwareReconciler{
+			leaderAwareReconciler: leaderAware,
+			demoted: func(bkt pkgreconciler.Bucket) {
+				logger.Infow("Lost the leadership of a bucket, updating the kafka consumers", zap.String("bucket", bkt.Name()))
+				if err := r.updateConsumers(ctx); err != nil {
+					logger.Errorw("Error updating the kafka consumers after a demotion", zap.Error(err))
+				}
+			},
+		}
+	}
+
+	// Watch the config-kafka config map to reload the sarama settings. Namespace dispatchers mount the
+	// config-kafka of their own namespace instead, which is not watched.
+	if !injection.HasNamespaceScope(ctx) {
+		if _, err := kubeclient.Get(ctx).CoreV1().ConfigMaps(system.Namespace()).Get(ctx, "config-kafka", metav1.GetOptions{}); err == nil {
+			cmw.Watch("config-kafka", func(configMap *corev1.ConfigMap) {
+				r.updateKafkaConfig(ctx, configMap)
+			})
+		} else if !apierrors.IsNotFound(err) {
+			logger.Fatalw("Error reading ConfigMap 'config-kafka'", zap.Error(err))
+		}
+	}
+
 	logger.Info("Setting up event handlers")
 
-	// Watch for kafka channels.
+	// Watch for kafka channels. The deleted channels are not reconciled by the dispatcher, so they are
+	// unmapped here, and the other channels are resynced for the consumers of the deleted ones to be updated.
 	kafkaChannelInformer.Informer().AddEventHandler(
 		cache.FilteringResourceEventHandler{
 			FilterFunc: filterWithAnnotation(injection.HasNamespaceScope(ctx)),
-			Handler:    controller.HandleAll(r.impl.Enqueue),
+			Handler: controller.HandleAll(func(obj interface{}) {
+				if kc, ok := obj.(*v1beta1.KafkaChannel); ok && kc.DeletionTimestamp != nil {
+					kafkaDispatcher.RemoveChannelMapping(eventingchannels.ChannelReference{Namespace: kc.Namespace, Name: kc.Name})
+					r.impl.GlobalResync(kafkaChannelInformer.Informer())
+					return
+				}
+				r.impl.Enqueue(obj)
+			}),
 		})
 
+	// Periodically merge the failures of the subscribers into the status of their channels.
+	go r.refreshSubscriberHealth(ctx, dispatchmetrics.HealthRefreshInterval)
+
 	logger.Info("Starting dispatcher.")
 	go func() {
+		healthServer.SetAlive(true)
 		if err := kafkaDispatcher.Start(ctx); err != nil {
 			logger.Errorw("Cannot start dispatcher", zap.Error(err))
 		}
+		healthServer.Shutdown()
+		healthServer.Stop(logger.Desugar())
 	}()
 
 	return r.impl
 }
 
+// updateKafkaConfig applies the sarama settings of a reloaded config-kafka to the dispatcher. The channels
+// are resynced afterwards, as the subscriptions which could not be restarted must be subscribed again.
+func (r *Reconciler) updateKafkaConfig(ctx context.Context, configMap *corev1.ConfigMap) {
+	logger := logging.FromContext(ctx)
+	kafkaConfig, err := utils.GetKafkaConfig(configMap.Data)
+	if err != nil {
+		logger.Errorw("Error reading Kafka configuration, keeping the current sarama settings", zap.Error(err))
+		return
+	}
+	if err := r.kafkaDispatcher.UpdateKafkaConfig(kafkaConfig); err != nil {
+		logger.Errorw("Error applying the Kafka configuration", zap.Error(err))
+	}
+	r.healthThresholds.Store(kafkaConfig.SubscriberHealthThresholds)
+	r.kafkaConfig.Store(kafkaConfig)
+	r.impl.GlobalResync(r.kafkachannelInformer)
+}
+
+// refreshSubscriberHealth merges the failures of the subscribers consumed by this replica into the status of their
+// channels every interval, until the context is done.  The failures change with every event, so they are written
+// directly to the status on a timer rather than by the reconciliation (which would otherwise have to run for every
+// channel on each tick).
+func (r *Reconciler) refreshSubscriberHealth(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.updateSubscriberFailures(ctx)
+		}
+	}
+}
+
+// updateSubscriberFailures merges the failures of the subscribers consumed by this replica into the status of their
+// channels.  Each replica only sees the events it dispatched, so the failures of all the replicas are merged (see
+// MergeSubscriberFailures) rather than overwritten, while the lag of the subscribers is left to the controller which
+// measures it from the offsets committed by their consumer groups.  A conflicting update is retried at the next
+// interval.
+func (r *Reconciler) updateSubscriberFailures(ctx context.Context) {
+	logger := logging.FromContext(ctx)
+	channels, err := r.kafkachannelLister.List(labels.Everything())
+	if err != nil {
+		logger.Errorw("Error listing kafka channels to update the failures of their subscribers", zap.Error(err))
+		return
+	}
+	health := r.kafkaDispatcher.SubscriberHealth()
+	for _, kc := range channels {
+		if !r.isConsumed(kc) {
+			continue
+		}
+		failures := make([]v1beta1.SubscriberHealth, 0, len(kc.Spec.Subscribers))
+		for _, sub := range kc.Spec.Subscribers {
+			if h, ok := health[sub.UID]; ok {
+				failures = append(failures, h.SubscriberHealth(sub.UID))
+			}
+		}
+		desired := kc.DeepCopy()
+		desired.Status.MergeSubscriberFailures(failures, r.subscriberHealthThresholds(), dispatchmetrics.HealthWindow, time.Now())
+		if equality.Semantic.DeepEqual(kc.Status, desired.Status) {
+			continue
+		}
+		if _, err := r.kafkaClientSet.MessagingV1beta1().KafkaChannels(kc.Namespace).UpdateStatus(ctx, desired, metav1.UpdateOptions{}); err != nil {
+			logger.Warnw("Error updating the failures of the subscribers", zap.String("channel", kc.Namespace+"/"+kc.Name), zap.Error(err))
+		}
+	}
+}
+
 func filterWithAnnotation(namespaced bool) func(obj interface{}) bool {
 	if namespaced {
 		return pkgreconciler.AnnotationFilterFunc(eventing.ScopeAnnotationKey, "namespace", false)
@@ -146,38 +297,120 @@ func filterWithAnnotation(namespaced bool) func(obj interface{}) bool {
 }
 
 func (r *Reconciler) ReconcileKind(ctx context.Context, kc *v1beta1.KafkaChannel) pkgreconciler.Event {
+	failedSubscriptions, err := r.updateDispatcher(ctx)
+	if err != nil {
+		return err
+	}
+	kc.Status.SubscribableStatus = r.createSubscribableStatus(&kc.Spec.SubscribableSpec, failedSubscriptions)
+	if len(failedSubscriptions) > 0 {
+		logging.FromContext(ctx).Error("Some kafka subscriptions failed to subscribe")
+		return fmt.Errorf("Some kafka subscriptions failed to subscribe")
+	}
+	return nil
+}
+
+// ObserveKind is called instead of ReconcileKind for the channels of the buckets led by other
+// replicas of a sharded dispatcher. Their hosts are still mapped, as any replica may receive
+// their events, but their subscriptions are left to the leader of the bucket. Only the observed
+// channel is updated, the consumers of the buckets lost by this replica being updated on demotion.
+func (r *Reconciler) ObserveKind(ctx context.Context, kc *v1beta1.KafkaChannel) pkgreconciler.Event {
+	if kc.Status.IsReady() {
+		if err := r.kafkaDispatcher.UpdateChannelMapping(r.newChannelConfigFromKafkaChannel(kc)); err != nil {
+			logging.FromContext(ctx).Errorw("Error updating the mapping of the channel in dispatcher", zap.Error(err))
+			return err
+		}
+		return nil
+	}
+	r.kafkaDispatcher.RemoveChannelMapping(eventingchannels.ChannelReference{Namespace: kc.Namespace, Name: kc.Name})
+	return nil
+}
+
+// updateDispatcher updates the host to channel map of the dispatcher with all the ready channels,
+// and its consumers with the ready channels led by this replica. The deleted channels which are
+// draining are still consumed, but no longer mapped, for their ingress to stop.
+func (r *Reconciler) updateDispatcher(ctx context.Context) (map[types.UID]error, error) {
 	channels, err := r.kafkachannelLister.List(labels.Everything())
 	if err != nil {
 		logging.FromContext(ctx).Error("Error listing kafka channels")
-		return err
+		return nil, err
 	}
 
 	// TODO: revisit this code. Instead of reading all channels and updating consumers and hostToChannel map for all
 	// why not just reconcile the current channel. With this the UpdateKafkaConsumers can now return SubscribableStatus
 	// for the subscriptions on the channel that is being reconciled.
 	kafkaChannels := make([]*v1beta1.KafkaChannel, 0)
+	ledChannels := make([]*v1beta1.KafkaChannel, 0)
 	for _, channel := range channels {
 		if channel.Status.IsReady() {
 			kafkaChannels = append(kafkaChannels, channel)
 		}
+		if r.isConsumed(channel) {
+			ledChannels = append(ledChannels, channel)
+		}
 	}
-	config := r.newConfigFromKafkaChannels(kafkaChannels)
-	if err := r.kafkaDispatcher.UpdateHostToChannelMap(config); err != nil {
+	if err := r.kafkaDispatcher.UpdateHostToChannelMap(r.newConfigFromKafkaChannels(kafkaChannels)); err != nil {
 		logging.FromContext(ctx).Error("Error updating host to channel map in dispatcher")
-		return err
+		return nil, err
 	}
 
-	failedSubscriptions, err := r.kafkaDispatcher.UpdateKafkaConsumers(config)
+	failedSubscriptions, err := r.kafkaDispatcher.UpdateKafkaConsumers(r.newConfigFromKafkaChannels(ledChannels))
 	if err != nil {
 		logging.FromContext(ctx).Error("Error updating kafka consumers in dispatcher")
+		return nil, err
+	}
+	return failedSubscriptions, nil
+}
+
+// updateConsumers updates the consumers of the dispatcher with the channels led by this replica.
+func (r *Reconciler) updateConsumers(ctx context.Context) error {
+	channels, err := r.kafkachannelLister.List(labels.Everything())
+	if err != nil {
 		return err
 	}
-	kc.Status.SubscribableStatus = r.createSubscribableStatus(&kc.Spec.SubscribableSpec, failedSubscriptions)
-	if len(failedSubscriptions) > 0 {
-		logging.FromContext(ctx).Error("Some kafka subscriptions failed to subscribe")
-		return fmt.Errorf("Some kafka subscriptions failed to subscribe")
+	ledChannels := make([]*v1beta1.KafkaChannel, 0)
+	for _, channel := range channels {
+		if r.isConsumed(channel) {
+			ledChannels = append(ledChannels, channel)
+		}
 	}
-	return nil
+	_, err = r.kafkaDispatcher.UpdateKafkaConsumers(r.newConfigFromKafkaChannels(ledChannels))
+	return err
+}
+
+// isConsumed returns true if the channel is ready, or deleted but still draining, and led by this replica.
+func (r *Reconciler) isConsumed(kc *v1beta1.KafkaChannel) bool {
+	return (kc.Status.IsReady() || (kc.DeletionTimestamp != nil && kc.Status.IsDraining())) && r.isLeaderFor(kc)
+}
+
+// leaderAwareReconciler is implemented by the generated reconciler, which tracks the buckets led by this replica.
+type leaderAwareReconciler interface {
+	controller.Reconciler
+	pkgreconciler.LeaderAware
+	IsLeaderFor(key types.NamespacedName) bool
+}
+
+// demotionAwareReconciler wraps the generated reconciler, for this replica to be notified of the buckets it loses.
+type demotionAwareReconciler struct {
+	leaderAwareReconciler
+	demoted func(bkt pkgreconciler.Bucket)
+}
+
+// Demote implements reconciler.LeaderAware, notifying the demotion once the bucket is no longer led.
+func (d *demotionAwareReconciler) Demote(bkt pkgreconciler.Bucket) {
+	d.leaderAwareReconciler.Demote(bkt)
+	d.demoted(bkt)
+}
+
+// isLeaderFor returns true if this replica consumes the channel, which is always the case unless
+// the dispatcher is sharded (see utils.KafkaConfig.DispatcherSharding).
+func (r *Reconciler) isLeaderFor(kc *v1beta1.KafkaChannel) bool {
+	if r.impl == nil {
+		return true
+	}
+	leader, ok := r.impl.Reconciler.(interface {
+		IsLeaderFor(key types.NamespacedName) bool
+	})
+	return !ok || leader.IsLeaderFor(types.NamespacedName{Namespace: kc.Namespace, Name: kc.Name})
 }
 
 func (r *Reconciler) createSubscribableStatus(subscribable *eventingduckv1.SubscribableSpec, failedSubscriptions map[types.UID]error) eventingduckv1.SubscribableStatus {
@@ -202,21 +435,48 @@ func (r *Reconciler) createSubscribableStatus(subscribable *eventingduckv1.Subsc
 	}
 }
 
+// subscriberHealthThresholds returns the thresholds of the current config-kafka.
+func (r *Reconciler) subscriberHealthThresholds() v1beta1.SubscriberHealthThresholds {
+	thresholds, _ := r.healthThresholds.Load().(v1beta1.SubscriberHealthThresholds)
+	return thresholds
+}
+
 // newConfigFromKafkaChannels creates a new Config from the list of kafka channels.
 func (r *Reconciler) newChannelConfigFromKafkaChannel(c *v1beta1.KafkaChannel) *dispatcher.ChannelConfig {
 	channelConfig := dispatcher.ChannelConfig{
 		Namespace: c.Namespace,
 		Name:      c.Name,
-		HostName:  c.Status.Address.URL.Host,
+	}
+	// draining channels have no address, they are only consumed
+	if c.Status.Address != nil && c.Status.Address.URL != nil {
+		channelConfig.HostName = c.Status.Address.URL.Host
+	}
+	// the topics are those of the topic func (empty) unless the channel has been migrated, or is migrating
+	channelConfig.Topic, channelConfig.IngressTopic = c.Status.TopicName(""), c.Status.IngressTopicName("")
+	channelConfig.MigrationCutOver = c.Status.TopicMigration != nil && c.Status.TopicMigration.Phase == v1beta1.TopicMigrationCutOver
+	// a cluster which is no longer configured is kept, for the dispatcher to fail its subscriptions rather
+	// than to consume them from the default cluster
+	if kafkaConfig, ok := r.kafkaConfig.Load().(*utils.KafkaConfig); ok {
+		var err error
+		if channelConfig.KafkaCluster, err = kafkaConfig.ClusterName(c); err != nil {
+			channelConfig.KafkaCluster = c.KafkaCluster()
+			if pinned, ok := c.Status.PinnedKafkaCluster(); ok {
+				channelConfig.KafkaCluster = pinned
+			}
+		}
 	}
 	if c.Spec.SubscribableSpec.Subscribers != nil {
 		newSubs := make([]dispatcher.Subscription, 0, len(c.Spec.SubscribableSpec.Subscribers))
 		for _, source := range c.Spec.SubscribableSpec.Subscribers {
 			innerSub, _ := fanout.SubscriberSpecToFanoutConfig(source)
 
+			// Invalid delivery annotations are rejected by the webhook, any remaining ones fall back to the defaults.
+			delivery, _ := c.SubscriberDelivery(source.UID)
+
 			newSubs = append(newSubs, dispatcher.Subscription{
 				Subscription: *innerSub,
 				UID:          source.UID,
+				Delivery:     delivery,
 			})
 		}
 		channelConfig.Subscriptions = newSubs
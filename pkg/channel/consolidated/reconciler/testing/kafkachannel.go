@@ -69,6 +69,27 @@ func WithKafkaChannelConfigReady() KafkaChannelOption {
 	}
 }
 
+func WithKafkaChannelClusterPinned(cluster string) KafkaChannelOption {
+	return func(nc *v1beta1.KafkaChannel) {
+		nc.Status.PinKafkaCluster(cluster)
+	}
+}
+
+func WithKafkaChannelConfigNotReady(reason, message string) KafkaChannelOption {
+	return func(nc *v1beta1.KafkaChannel) {
+		nc.Status.MarkConfigFailed(reason, message)
+	}
+}
+
+func WithKafkaChannelCluster(cluster string) KafkaChannelOption {
+	return func(nc *v1beta1.KafkaChannel) {
+		if nc.Annotations == nil {
+			nc.Annotations = make(map[string]string)
+		}
+		nc.Annotations[v1beta1.KafkaClusterAnnotation] = cluster
+	}
+}
+
 func WithKafkaChannelDeploymentNotReady(reason, message string) KafkaChannelOption {
 	return func(nc *v1beta1.KafkaChannel) {
 		nc.Status.MarkDispatcherFailed(reason, message)
@@ -0,0 +1,50 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"github.com/Shopify/sarama"
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+
+	kafkasarama "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/sarama"
+)
+
+// DefaultKafkaVersion is the Kafka version used when the sarama settings do not specify one.
+var DefaultKafkaVersion = sarama.V2_0_0_0
+
+// NewSaramaConfig returns the sarama.Config of the specified sarama settings, which are the YAML of the fields of
+// sarama.Config overriding Sarama's defaults, parsed like the sarama settings of the distributed channel (the
+// Kafka version is a "major.minor.patch" string and the rebalance strategy a strategy name, for example).  The
+// config always returns the errors of consumers and the successes of producers.
+func NewSaramaConfig(settings string) (*sarama.Config, error) {
+	config := sarama.NewConfig()
+	config.Consumer.Return.Errors = true
+	config.Producer.Return.Successes = true
+
+	config, err := kafkasarama.MergeSaramaSettings(config, &corev1.ConfigMap{Data: map[string]string{SaramaSettingsKey: settings}})
+	if err != nil {
+		return nil, err
+	}
+
+	// unlike the distributed channel, the consolidated channel defaults to Kafka 2.0.0
+	var shell struct{ Version string }
+	if err := yaml.Unmarshal([]byte(settings), &shell); err == nil && shell.Version == "" {
+		config.Version = DefaultKafkaVersion
+	}
+	return config, nil
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSaramaConfig(t *testing.T) {
+	// no settings means sarama's defaults, with kafka 2.0.0
+	config, err := NewSaramaConfig("")
+	assert.Nil(t, err)
+	assert.Equal(t, sarama.V2_0_0_0, config.Version)
+	assert.True(t, config.Consumer.Return.Errors)
+	assert.True(t, config.Producer.Return.Successes)
+	assert.Equal(t, sarama.NewConfig().Producer.RequiredAcks, config.Producer.RequiredAcks)
+
+	config, err = NewSaramaConfig(`
+Version: 2.3.0
+Net:
+  DialTimeout: 10000000000
+Producer:
+  Idempotent: true
+  RequiredAcks: -1
+Consumer:
+  Fetch:
+    Min: 1024
+    Default: 2097152
+  Group:
+    Rebalance:
+      Strategy: roundrobin
+`)
+	assert.Nil(t, err)
+	assert.Equal(t, sarama.V2_3_0_0, config.Version)
+	assert.Equal(t, 10*time.Second, config.Net.DialTimeout)
+	assert.True(t, config.Producer.Idempotent)
+	assert.Equal(t, sarama.WaitForAll, config.Producer.RequiredAcks)
+	assert.Equal(t, int32(1024), config.Consumer.Fetch.Min)
+	assert.Equal(t, int32(2097152), config.Consumer.Fetch.Default)
+	assert.Equal(t, sarama.RoundRobinBalanceStrategyName, config.Consumer.Group.Rebalance.Strategy.Name())
+
+	_, err = NewSaramaConfig("Producer: [")
+	assert.NotNil(t, err)
+}
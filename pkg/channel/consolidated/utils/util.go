@@ -20,8 +20,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/ghodss/yaml"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kubeclient "knative.dev/pkg/client/injection/kube/client"
 	"knative.dev/pkg/logging"
@@ -30,14 +33,25 @@ import (
 	corev1 "k8s.io/api/core/v1"
 
 	"knative.dev/pkg/configmap"
+
+	"knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
 )
 
 const (
-	BrokerConfigMapKey           = "bootstrapServers"
-	AuthSecretName               = "authSecretName"
-	AuthSecretNamespace          = "authSecretNamespace"
-	MaxIdleConnectionsKey        = "maxIdleConns"
-	MaxIdleConnectionsPerHostKey = "maxIdleConnsPerHost"
+	BrokerConfigMapKey            = "bootstrapServers"
+	AuthSecretName                = "authSecretName"
+	AuthSecretNamespace           = "authSecretNamespace"
+	MaxIdleConnectionsKey         = "maxIdleConns"
+	MaxIdleConnectionsPerHostKey  = "maxIdleConnsPerHost"
+	DispatcherShardingKey         = "dispatcherSharding"
+	ProducerLingerKey             = "producerLinger"
+	ProducerBatchSizeKey          = "producerBatchSize"
+	SaramaSettingsKey             = "sarama"
+	SubscriberLagThresholdKey     = "subscriberLagThreshold"
+	SubscriberFailureThresholdKey = "subscriberFailureThreshold"
+	KafkaClustersKey              = "clusters"
+	OrphanCollectionIntervalKey   = "orphanCollectionInterval"
+	OrphanCollectionDeleteKey     = "orphanCollectionDelete"
 
 	TlsCacert    = "ca.crt"
 	TlsUsercert  = "user.crt"
@@ -60,6 +74,115 @@ type KafkaConfig struct {
 	MaxIdleConnsPerHost int32
 	AuthSecretName      string
 	AuthSecretNamespace string
+	// DispatcherSharding spreads the channels over the dispatcher replicas, each replica only consuming
+	// the channels of the leader election buckets it leads.
+	DispatcherSharding bool
+	// ProducerLinger is how long the ingress producer waits for more events before sending a batch to a broker,
+	// and ProducerBatchSize the number of events it sends a batch at.  Zero values send as soon as possible.
+	ProducerLinger    time.Duration
+	ProducerBatchSize int
+	// SaramaSettings is the YAML of the sarama.Config settings overriding the defaults (see NewSaramaConfig).
+	SaramaSettings string
+	// SubscriberHealthThresholds are the lag and the failure percentage above which the subscribers of a
+	// channel are reported as lagging or failing.  Zero thresholds disable the conditions.
+	SubscriberHealthThresholds v1beta1.SubscriberHealthThresholds
+	// Clusters are the named Kafka clusters which channels may use instead of the default cluster of the
+	// Brokers, selected by the v1beta1.KafkaClusterAnnotation or by the namespace of the channel.
+	Clusters map[string]*KafkaClusterConfig
+	// OrphanCollectionInterval is the interval at which the controller looks for the topics and consumer groups
+	// left behind by deleted channels and subscribers (zero disables it), which are only reported unless
+	// OrphanCollectionDelete is true.
+	OrphanCollectionInterval time.Duration
+	OrphanCollectionDelete   bool
+}
+
+// KafkaClusterConfig is the configuration of a named Kafka cluster of config-kafka's clusters.
+type KafkaClusterConfig struct {
+	Brokers             []string `json:"-"`
+	BootstrapServers    string   `json:"bootstrapServers"`
+	AuthSecretName      string   `json:"authSecretName,omitempty"`
+	AuthSecretNamespace string   `json:"authSecretNamespace,omitempty"`
+	// Namespaces are the namespaces whose channels use the cluster, unless they are annotated with another one.
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// ClusterName returns the name of the Kafka cluster of a channel, which is the cluster pinned in its status by its
+// first reconciliation, else the configured one (see ConfiguredClusterName).  An empty name is the default cluster.
+func (c *KafkaConfig) ClusterName(kc *v1beta1.KafkaChannel) (string, error) {
+	if name, ok := kc.Status.PinnedKafkaCluster(); ok {
+		if _, configured := c.Clusters[name]; name != "" && !configured {
+			return "", fmt.Errorf("unknown Kafka cluster %q the channel is pinned to", name)
+		}
+		return name, nil
+	}
+	return c.ConfiguredClusterName(kc)
+}
+
+// ConfiguredClusterName returns the name of the Kafka cluster configured for a channel: the cluster of its
+// v1beta1.KafkaClusterAnnotation, else the cluster its namespace is mapped to, else an empty name for the default
+// cluster.
+func (c *KafkaConfig) ConfiguredClusterName(kc *v1beta1.KafkaChannel) (string, error) {
+	if name := kc.KafkaCluster(); name != "" {
+		if _, ok := c.Clusters[name]; !ok {
+			return "", fmt.Errorf("unknown Kafka cluster %q in annotation %s", name, v1beta1.KafkaClusterAnnotation)
+		}
+		return name, nil
+	}
+	for name, cluster := range c.Clusters {
+		for _, namespace := range cluster.Namespaces {
+			if namespace == kc.Namespace {
+				return name, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// parseClusters parses the YAML of the named clusters, each of which must have its own bootstrap servers and may
+// be mapped to namespaces which are not mapped to another cluster.
+func parseClusters(settings string) (map[string]*KafkaClusterConfig, error) {
+	if settings == "" {
+		return nil, nil
+	}
+	clusters := make(map[string]*KafkaClusterConfig)
+	if err := yaml.Unmarshal([]byte(settings), &clusters); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(clusters))
+	for name := range clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	namespaces := make(map[string]string)
+	for _, name := range names {
+		cluster := clusters[name]
+		if cluster == nil || cluster.BootstrapServers == "" {
+			return nil, fmt.Errorf("missing or empty %s of cluster %q", BrokerConfigMapKey, name)
+		}
+		brokers, err := splitBootstrapServers(cluster.BootstrapServers)
+		if err != nil {
+			return nil, err
+		}
+		cluster.Brokers = brokers
+		for _, namespace := range cluster.Namespaces {
+			if other, ok := namespaces[namespace]; ok {
+				return nil, fmt.Errorf("namespace %q is mapped to both clusters %q and %q", namespace, other, name)
+			}
+			namespaces[namespace] = name
+		}
+	}
+	return clusters, nil
+}
+
+// splitBootstrapServers returns the brokers of a comma separated list of bootstrap servers.
+func splitBootstrapServers(bootstrapServers string) ([]string, error) {
+	brokers := strings.Split(bootstrapServers, ",")
+	for _, s := range brokers {
+		if len(s) == 0 {
+			return nil, fmt.Errorf("empty %s value in configuration", BrokerConfigMapKey)
+		}
+	}
+	return brokers, nil
 }
 
 type KafkaAuthConfig struct {
@@ -125,6 +248,7 @@ func GetKafkaConfig(configMap map[string]string) (*KafkaConfig, error) {
 	var bootstrapServers string
 	var authSecretNamespace string
 	var authSecretName string
+	var clusters string
 
 	err := configmap.Parse(configMap,
 		configmap.AsString(BrokerConfigMapKey, &bootstrapServers),
@@ -132,19 +256,46 @@ func GetKafkaConfig(configMap map[string]string) (*KafkaConfig, error) {
 		configmap.AsString(AuthSecretNamespace, &authSecretNamespace),
 		configmap.AsInt32(MaxIdleConnectionsKey, &config.MaxIdleConns),
 		configmap.AsInt32(MaxIdleConnectionsPerHostKey, &config.MaxIdleConnsPerHost),
+		configmap.AsBool(DispatcherShardingKey, &config.DispatcherSharding),
+		configmap.AsDuration(ProducerLingerKey, &config.ProducerLinger),
+		configmap.AsInt(ProducerBatchSizeKey, &config.ProducerBatchSize),
+		configmap.AsString(SaramaSettingsKey, &config.SaramaSettings),
+		configmap.AsInt64(SubscriberLagThresholdKey, &config.SubscriberHealthThresholds.Lag),
+		configmap.AsInt32(SubscriberFailureThresholdKey, &config.SubscriberHealthThresholds.FailurePercentage),
+		configmap.AsString(KafkaClustersKey, &clusters),
+		configmap.AsDuration(OrphanCollectionIntervalKey, &config.OrphanCollectionInterval),
+		configmap.AsBool(OrphanCollectionDeleteKey, &config.OrphanCollectionDelete),
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	if _, err := NewSaramaConfig(config.SaramaSettings); err != nil {
+		return nil, fmt.Errorf("invalid %s in configuration: %w", SaramaSettingsKey, err)
+	}
+
+	if config.ProducerLinger < 0 || config.ProducerBatchSize < 0 {
+		return nil, fmt.Errorf("negative %s or %s in configuration", ProducerLingerKey, ProducerBatchSizeKey)
+	}
+
+	if config.SubscriberHealthThresholds.Lag < 0 || config.SubscriberHealthThresholds.FailurePercentage < 0 {
+		return nil, fmt.Errorf("negative %s or %s in configuration", SubscriberLagThresholdKey, SubscriberFailureThresholdKey)
+	}
+
+	if config.OrphanCollectionInterval < 0 {
+		return nil, fmt.Errorf("negative %s in configuration", OrphanCollectionIntervalKey)
+	}
+
+	if config.Clusters, err = parseClusters(clusters); err != nil {
+		return nil, fmt.Errorf("invalid %s in configuration: %w", KafkaClustersKey, err)
+	}
+
 	if bootstrapServers == "" {
 		return nil, errors.New("missing or empty key bootstrapServers in configuration")
 	}
-	bootstrapServersSplitted := strings.Split(bootstrapServers, ",")
-	for _, s := range bootstrapServersSplitted {
-		if len(s) == 0 {
-			return nil, fmt.Errorf("empty %s value in configuration", BrokerConfigMapKey)
-		}
+	bootstrapServersSplitted, err := splitBootstrapServers(bootstrapServers)
+	if err != nil {
+		return nil, err
 	}
 	config.Brokers = bootstrapServersSplitted
 	config.AuthSecretName = authSecretName
@@ -153,11 +304,23 @@ func GetKafkaConfig(configMap map[string]string) (*KafkaConfig, error) {
 	return config, nil
 }
 
+// IsTopicName returns true if the specified topic is named like the topic of a channel (or one of its retry or
+// migration topics), whether or not the channel exists.
+func IsTopicName(separator, topic string) bool {
+	return strings.HasPrefix(topic, knativeKafkaTopicPrefix+separator)
+}
+
 func TopicName(separator, namespace, name string) string {
 	topic := []string{knativeKafkaTopicPrefix, namespace, name}
 	return strings.Join(topic, separator)
 }
 
+// MigrationTopicName returns the name of the topic a channel is migrated to when its spec reaches the generation.
+// Kubernetes names have no uppercase letters, so it can not be the topic of another channel.
+func MigrationTopicName(topicName string, generation int64) string {
+	return fmt.Sprintf("%s.G%d", topicName, generation)
+}
+
 func FindContainer(d *appsv1.Deployment, containerName string) *corev1.Container {
 	for i := range d.Spec.Template.Spec.Containers {
 		if d.Spec.Template.Spec.Containers[i].Name == containerName {
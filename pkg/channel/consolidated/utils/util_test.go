@@ -25,6 +25,7 @@ import (
 	injectionclient "knative.dev/pkg/client/injection/kube/client"
 
 	"testing"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -32,6 +33,8 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	_ "knative.dev/pkg/system/testing"
+
+	"knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
 )
 
 type KubernetesAPI struct {
@@ -59,6 +62,15 @@ func TestGenerateTopicNameWithHyphen(t *testing.T) {
 	}
 }
 
+func TestIsTopicName(t *testing.T) {
+	if !IsTopicName(".", TopicName(".", "channel-namespace", "channel-name")) {
+		t.Errorf("Expected the topic of a channel to be a topic name")
+	}
+	if IsTopicName(".", "knative-messaging-kafka-other") {
+		t.Errorf("Expected a topic without the channel topic prefix not to be a topic name")
+	}
+}
+
 func TestGetKafkaAuthData(t *testing.T) {
 
 	api := &KubernetesAPI{
@@ -289,6 +301,110 @@ func TestGetKafkaConfig(t *testing.T) {
 				MaxIdleConnsPerHost: 600,
 			},
 		},
+		{
+			name: "sharded dispatcher",
+			data: map[string]string{"bootstrapServers": "kafkabroker.kafka:9092", "dispatcherSharding": "true"},
+			expected: &KafkaConfig{
+				Brokers:             []string{"kafkabroker.kafka:9092"},
+				MaxIdleConns:        1000,
+				MaxIdleConnsPerHost: 100,
+				DispatcherSharding:  true,
+			},
+		},
+		{
+			name: "batching producer",
+			data: map[string]string{"bootstrapServers": "kafkabroker.kafka:9092", "producerLinger": "5ms", "producerBatchSize": "500"},
+			expected: &KafkaConfig{
+				Brokers:             []string{"kafkabroker.kafka:9092"},
+				MaxIdleConns:        1000,
+				MaxIdleConnsPerHost: 100,
+				ProducerLinger:      5 * time.Millisecond,
+				ProducerBatchSize:   500,
+			},
+		},
+		{
+			name: "sarama settings",
+			data: map[string]string{"bootstrapServers": "kafkabroker.kafka:9092", "sarama": "Producer:\n  RequiredAcks: -1\n"},
+			expected: &KafkaConfig{
+				Brokers:             []string{"kafkabroker.kafka:9092"},
+				MaxIdleConns:        1000,
+				MaxIdleConnsPerHost: 100,
+				SaramaSettings:      "Producer:\n  RequiredAcks: -1\n",
+			},
+		},
+		{
+			name:     "invalid sarama settings",
+			data:     map[string]string{"bootstrapServers": "kafkabroker.kafka:9092", "sarama": "Version: INVALID"},
+			getError: "invalid sarama in configuration: failed to extract KafkaVersion from Sarama Config YAML: err=invalid version `INVALID` : config=Version: INVALID",
+		},
+		{
+			name:     "negative producer batch size",
+			data:     map[string]string{"bootstrapServers": "kafkabroker.kafka:9092", "producerBatchSize": "-1"},
+			getError: "negative producerLinger or producerBatchSize in configuration",
+		},
+		{
+			name: "subscriber health thresholds",
+			data: map[string]string{"bootstrapServers": "kafkabroker.kafka:9092", "subscriberLagThreshold": "1000", "subscriberFailureThreshold": "50"},
+			expected: &KafkaConfig{
+				Brokers:                    []string{"kafkabroker.kafka:9092"},
+				MaxIdleConns:               1000,
+				MaxIdleConnsPerHost:        100,
+				SubscriberHealthThresholds: v1beta1.SubscriberHealthThresholds{Lag: 1000, FailurePercentage: 50},
+			},
+		},
+		{
+			name:     "negative subscriber lag threshold",
+			data:     map[string]string{"bootstrapServers": "kafkabroker.kafka:9092", "subscriberLagThreshold": "-1"},
+			getError: "negative subscriberLagThreshold or subscriberFailureThreshold in configuration",
+		},
+		{
+			name: "orphan collection",
+			data: map[string]string{"bootstrapServers": "kafkabroker.kafka:9092", "orphanCollectionInterval": "1h", "orphanCollectionDelete": "true"},
+			expected: &KafkaConfig{
+				Brokers:                  []string{"kafkabroker.kafka:9092"},
+				MaxIdleConns:             1000,
+				MaxIdleConnsPerHost:      100,
+				OrphanCollectionInterval: time.Hour,
+				OrphanCollectionDelete:   true,
+			},
+		},
+		{
+			name:     "negative orphan collection interval",
+			data:     map[string]string{"bootstrapServers": "kafkabroker.kafka:9092", "orphanCollectionInterval": "-1h"},
+			getError: "negative orphanCollectionInterval in configuration",
+		},
+		{
+			name: "named clusters",
+			data: map[string]string{"bootstrapServers": "kafkabroker.kafka:9092", "clusters": "tenant-a:\n  bootstrapServers: broker1.tenant-a:9092,broker2.tenant-a:9092\n  authSecretName: tenant-a-auth\n  authSecretNamespace: knative-eventing\n  namespaces: [a1, a2]\ntenant-b:\n  bootstrapServers: broker.tenant-b:9092\n"},
+			expected: &KafkaConfig{
+				Brokers:             []string{"kafkabroker.kafka:9092"},
+				MaxIdleConns:        1000,
+				MaxIdleConnsPerHost: 100,
+				Clusters: map[string]*KafkaClusterConfig{
+					"tenant-a": {
+						Brokers:             []string{"broker1.tenant-a:9092", "broker2.tenant-a:9092"},
+						BootstrapServers:    "broker1.tenant-a:9092,broker2.tenant-a:9092",
+						AuthSecretName:      "tenant-a-auth",
+						AuthSecretNamespace: "knative-eventing",
+						Namespaces:          []string{"a1", "a2"},
+					},
+					"tenant-b": {
+						Brokers:          []string{"broker.tenant-b:9092"},
+						BootstrapServers: "broker.tenant-b:9092",
+					},
+				},
+			},
+		},
+		{
+			name:     "named cluster without bootstrapServers",
+			data:     map[string]string{"bootstrapServers": "kafkabroker.kafka:9092", "clusters": "tenant-a:\n  authSecretName: tenant-a-auth\n"},
+			getError: `invalid clusters in configuration: missing or empty bootstrapServers of cluster "tenant-a"`,
+		},
+		{
+			name:     "namespace mapped to two named clusters",
+			data:     map[string]string{"bootstrapServers": "kafkabroker.kafka:9092", "clusters": "tenant-a:\n  bootstrapServers: a:9092\n  namespaces: [shared]\ntenant-b:\n  bootstrapServers: b:9092\n  namespaces: [shared]\n"},
+			getError: `invalid clusters in configuration: namespace "shared" is mapped to both clusters "tenant-a" and "tenant-b"`,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -316,6 +432,86 @@ func TestGetKafkaConfig(t *testing.T) {
 	}
 }
 
+func TestKafkaConfigClusterName(t *testing.T) {
+	config := &KafkaConfig{
+		Brokers: []string{"kafkabroker.kafka:9092"},
+		Clusters: map[string]*KafkaClusterConfig{
+			"tenant-a": {Brokers: []string{"broker.tenant-a:9092"}, Namespaces: []string{"a1"}},
+			"tenant-b": {Brokers: []string{"broker.tenant-b:9092"}},
+		},
+	}
+	channel := func(namespace string, annotations map[string]string) *v1beta1.KafkaChannel {
+		return &v1beta1.KafkaChannel{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "channel", Annotations: annotations}}
+	}
+	pinned := func(kc *v1beta1.KafkaChannel, clusterName string) *v1beta1.KafkaChannel {
+		kc.Status.PinKafkaCluster(clusterName)
+		return kc
+	}
+
+	testCases := []struct {
+		name     string
+		channel  *v1beta1.KafkaChannel
+		expected string
+		getError string
+	}{
+		{
+			name:    "default cluster",
+			channel: channel("other", nil),
+		},
+		{
+			name:     "namespace mapped to a cluster",
+			channel:  channel("a1", nil),
+			expected: "tenant-a",
+		},
+		{
+			name:     "annotated cluster",
+			channel:  channel("other", map[string]string{v1beta1.KafkaClusterAnnotation: "tenant-b"}),
+			expected: "tenant-b",
+		},
+		{
+			name:     "annotation overriding the namespace cluster",
+			channel:  channel("a1", map[string]string{v1beta1.KafkaClusterAnnotation: "tenant-b"}),
+			expected: "tenant-b",
+		},
+		{
+			name:     "unknown annotated cluster",
+			channel:  channel("a1", map[string]string{v1beta1.KafkaClusterAnnotation: "tenant-c"}),
+			getError: `unknown Kafka cluster "tenant-c" in annotation kafka.eventing.knative.dev/cluster`,
+		},
+		{
+			name:     "pinned cluster overriding the namespace cluster",
+			channel:  pinned(channel("a1", nil), "tenant-b"),
+			expected: "tenant-b",
+		},
+		{
+			name:    "pinned default cluster overriding the namespace cluster",
+			channel: pinned(channel("a1", nil), ""),
+		},
+		{
+			name:     "unknown pinned cluster",
+			channel:  pinned(channel("a1", nil), "tenant-c"),
+			getError: `unknown Kafka cluster "tenant-c" the channel is pinned to`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := config.ClusterName(tc.channel)
+			if tc.getError != "" {
+				if err == nil || err.Error() != tc.getError {
+					t.Errorf("Unexpected ClusterName error. Expected '%v'. Actual '%v'", tc.getError, err)
+				}
+				return
+			} else if err != nil {
+				t.Errorf("Unexpected ClusterName error. Expected nil. Actual '%v'", err)
+			}
+			if got != tc.expected {
+				t.Errorf("Unexpected ClusterName. Expected '%s'. Actual '%s'", tc.expected, got)
+			}
+		})
+	}
+}
+
 func TestFindContainer(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -18,6 +18,7 @@ package config
 
 import (
 	"context"
+	"time"
 
 	"go.uber.org/zap"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -33,11 +34,47 @@ import (
 // stored in the config-kafka configmap.  The sub-structs are explicitly declared so that they
 // can have their own JSON tags in the overall EventingKafkaConfig
 type EKKubernetesConfig struct {
-	CpuLimit      resource.Quantity `json:"cpuLimit,omitempty"`
-	CpuRequest    resource.Quantity `json:"cpuRequest,omitempty"`
-	MemoryLimit   resource.Quantity `json:"memoryLimit,omitempty"`
-	MemoryRequest resource.Quantity `json:"memoryRequest,omitempty"`
-	Replicas      int               `json:"replicas,omitempty"`
+	CpuLimit      resource.Quantity   `json:"cpuLimit,omitempty"`
+	CpuRequest    resource.Quantity   `json:"cpuRequest,omitempty"`
+	MemoryLimit   resource.Quantity   `json:"memoryLimit,omitempty"`
+	MemoryRequest resource.Quantity   `json:"memoryRequest,omitempty"`
+	Replicas      int                 `json:"replicas,omitempty"`
+	Autoscaling   EKAutoscalingConfig `json:"autoscaling,omitempty"`
+}
+
+// The Autoscaling config replaces the static Replicas with a HorizontalPodAutoscaler (when MaxReplicas is
+// nonzero) scaling between MinReplicas & MaxReplicas on the average CPU utilization (percentage of the CPU
+// request) and either the request rate of the Receiver or the consumer lag of the Dispatcher, as exposed by
+// the custom/external metrics API under the configured metric names
+type EKAutoscalingConfig struct {
+	MinReplicas          int32             `json:"minReplicas,omitempty"`
+	MaxReplicas          int32             `json:"maxReplicas,omitempty"`
+	TargetCpuUtilization int32             `json:"targetCpuUtilization,omitempty"`
+	TargetRequestRate    resource.Quantity `json:"targetRequestRate,omitempty"`
+	RequestRateMetric    string            `json:"requestRateMetric,omitempty"`
+	TargetLag            int64             `json:"targetLag,omitempty"`
+	LagMetric            string            `json:"lagMetric,omitempty"`
+}
+
+// Whether The Deployment Is Scaled By A HorizontalPodAutoscaler
+func (c EKAutoscalingConfig) Enabled() bool {
+	return c.MaxReplicas > 0
+}
+
+// The Minimum Number Of Replicas (Defaulted When Not Specified)
+func (c EKAutoscalingConfig) MinimumReplicas() int32 {
+	if c.MinReplicas > 0 {
+		return c.MinReplicas
+	}
+	return DefaultAutoscalingMinReplicas
+}
+
+// The Name Of The External Metric Providing The Consumer Lag (Defaulted When Not Specified)
+func (c EKAutoscalingConfig) ConsumerLagMetric() string {
+	if len(c.LagMetric) > 0 {
+		return c.LagMetric
+	}
+	return DefaultAutoscalingLagMetric
 }
 
 // The Receiver config has the base Kubernetes fields (Cpu, Memory, Replicas) only
@@ -45,9 +82,31 @@ type EKReceiverConfig struct {
 	EKKubernetesConfig
 }
 
-// The Dispatcher config has the base Kubernetes fields (Cpu, Memory, Replicas) only
+// The Dispatcher config has the base Kubernetes fields (Cpu, Memory, Replicas), the default number of
+// messages dispatched concurrently per partition (overridden by the KafkaChannel delivery annotations),
+// the lag & failure percentage above which subscribers are reported as lagging or failing (zero to disable)
+// and the dispatcher mode ("shared" for a pool of PoolSize dispatchers shared by the KafkaChannels, or a
+// dispatcher per KafkaChannel by default)
 type EKDispatcherConfig struct {
 	EKKubernetesConfig
+	Concurrency                int    `json:"concurrency,omitempty"`
+	SubscriberLagThreshold     int64  `json:"subscriberLagThreshold,omitempty"`
+	SubscriberFailureThreshold int32  `json:"subscriberFailureThreshold,omitempty"`
+	Mode                       string `json:"mode,omitempty"`
+	PoolSize                   int    `json:"poolSize,omitempty"`
+}
+
+// Whether The KafkaChannels Share A Pool Of Dispatchers (Unless Annotated With A Dedicated Dispatcher)
+func (c EKDispatcherConfig) Shared() bool {
+	return c.Mode == DispatcherModeShared
+}
+
+// The Number Of Dispatchers Shared By The KafkaChannels (Defaulted When Not Specified)
+func (c EKDispatcherConfig) SharedPoolSize() int {
+	if c.PoolSize > 0 {
+		return c.PoolSize
+	}
+	return DefaultDispatcherPoolSize
 }
 
 // EKKafkaTopicConfig contains some defaults that are only used if not provided by the channel spec
@@ -57,11 +116,73 @@ type EKKafkaTopicConfig struct {
 	DefaultRetentionMillis   int64 `json:"defaultRetentionMillis,omitempty"`
 }
 
+// The KafkaSecrets config routes the KafkaChannels to one of several Kafka Secrets (clusters) - the Kafka Secret
+// of the KafkaChannel's cluster annotation, else the Kafka Secret whose Namespaces include the KafkaChannel's
+// namespace, else the Default Kafka Secret (a single Kafka Secret needs no routing)
+type EKKafkaSecretsConfig struct {
+	Default    string              `json:"default,omitempty"`
+	Namespaces map[string][]string `json:"namespaces,omitempty"`
+}
+
+// Select The Kafka Secret, Among Those Specified, Of A KafkaChannel With The Specified Cluster Annotation & Namespace
+// (Empty If None Applies)
+func (c EKKafkaSecretsConfig) KafkaSecretName(kafkaCluster string, namespace string, kafkaSecretNames []string) string {
+
+	// Only The Specified Kafka Secrets Can Be Selected
+	exists := func(kafkaSecretName string) bool {
+		for _, name := range kafkaSecretNames {
+			if name == kafkaSecretName {
+				return true
+			}
+		}
+		return false
+	}
+
+	// The Kafka Secret Of The Cluster Annotation (Without Failover, An Unknown Cluster Being An Error)
+	if len(kafkaCluster) > 0 {
+		if exists(kafkaCluster) {
+			return kafkaCluster
+		}
+		return ""
+	}
+
+	// The Kafka Secret Mapped To The Namespace (In Kafka Secret Name Order For Determinism)
+	for _, kafkaSecretName := range kafkaSecretNames {
+		for _, mappedNamespace := range c.Namespaces[kafkaSecretName] {
+			if mappedNamespace == namespace {
+				return kafkaSecretName
+			}
+		}
+	}
+
+	// The Default Kafka Secret, Or The Only One
+	if len(c.Default) > 0 && exists(c.Default) {
+		return c.Default
+	} else if len(kafkaSecretNames) == 1 {
+		return kafkaSecretNames[0]
+	}
+	return ""
+}
+
+// EKOrphanCollectionConfig controls the periodic collection of the topics and consumer groups left behind
+// by deleted KafkaChannels and subscribers (disabled while IntervalMinutes is zero, and only logged unless Delete)
+type EKOrphanCollectionConfig struct {
+	IntervalMinutes int  `json:"intervalMinutes,omitempty"`
+	Delete          bool `json:"delete,omitempty"`
+}
+
+// Interval returns the interval of the orphan collection, zero while it is disabled
+func (c EKOrphanCollectionConfig) Interval() time.Duration {
+	return time.Duration(c.IntervalMinutes) * time.Minute
+}
+
 // EKKafkaConfig contains items relevant to Kafka specifically, and the Sarama logging flag
 type EKKafkaConfig struct {
-	EnableSaramaLogging bool               `json:"enableSaramaLogging,omitempty"`
-	Topic               EKKafkaTopicConfig `json:"topic,omitempty"`
-	AdminType           string             `json:"adminType,omitempty"`
+	EnableSaramaLogging bool                     `json:"enableSaramaLogging,omitempty"`
+	Topic               EKKafkaTopicConfig       `json:"topic,omitempty"`
+	AdminType           string                   `json:"adminType,omitempty"`
+	Secrets             EKKafkaSecretsConfig     `json:"secrets,omitempty"`
+	OrphanCollection    EKOrphanCollectionConfig `json:"orphanCollection,omitempty"`
 }
 
 // EventingKafkaConfig is the main struct that holds the Receiver, Dispatcher, and Kafka sub-items
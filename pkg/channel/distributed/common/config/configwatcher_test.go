@@ -96,6 +96,43 @@ func TestInitializeConfigWatcher(t *testing.T) {
 	assert.Equal(t, getWatchedMap().Data["sarama"], commontesting.NewSaramaConfig)
 }
 
+// Test The EKKafkaSecretsConfig KafkaSecretName() Routing Of KafkaChannels To Kafka Secrets
+func TestEKKafkaSecretsConfigKafkaSecretName(t *testing.T) {
+	secrets := EKKafkaSecretsConfig{
+		Default:    "default-secret",
+		Namespaces: map[string][]string{"secret-1": {"namespace-1"}, "secret-2": {"namespace-2", "namespace-3"}},
+	}
+	names := []string{"default-secret", "secret-1", "secret-2"}
+
+	tests := []struct {
+		name         string
+		secrets      EKKafkaSecretsConfig
+		kafkaCluster string
+		namespace    string
+		names        []string
+		expected     string
+	}{
+		{name: "Annotated Cluster", secrets: secrets, kafkaCluster: "secret-2", namespace: "namespace-1", names: names, expected: "secret-2"},
+		{name: "Unknown Annotated Cluster", secrets: secrets, kafkaCluster: "unknown", namespace: "namespace-1", names: names, expected: ""},
+		{name: "Mapped Namespace", secrets: secrets, namespace: "namespace-3", names: names, expected: "secret-2"},
+		{name: "Unmapped Namespace", secrets: secrets, namespace: "namespace-4", names: names, expected: "default-secret"},
+		{name: "Missing Default", secrets: secrets, namespace: "namespace-4", names: []string{"secret-1", "secret-2"}, expected: ""},
+		{name: "Single Secret", secrets: EKKafkaSecretsConfig{}, namespace: "namespace-4", names: []string{"secret-1"}, expected: "secret-1"},
+		{name: "No Secrets", secrets: secrets, namespace: "namespace-1", names: nil, expected: ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.secrets.KafkaSecretName(test.kafkaCluster, test.namespace, test.names))
+		})
+	}
+}
+
+// Test The EKOrphanCollectionConfig Interval() Conversion
+func TestEKOrphanCollectionConfigInterval(t *testing.T) {
+	assert.Equal(t, time.Duration(0), EKOrphanCollectionConfig{}.Interval())
+	assert.Equal(t, 90*time.Minute, EKOrphanCollectionConfig{IntervalMinutes: 90}.Interval())
+}
+
 func getWatchedMap() *corev1.ConfigMap {
 	configMapMutex.Lock()
 	defer configMapMutex.Unlock()
@@ -23,4 +23,13 @@ const (
 	// The name of the keys in the Data section of the eventing-kafka configmap that holds Sarama and Eventing-Kafka configuration YAML
 	SaramaSettingsConfigKey        = "sarama"
 	EventingKafkaSettingsConfigKey = "eventing-kafka"
+
+	// The dispatcher mode in which the KafkaChannels share a pool of dispatchers, and its default pool size
+	DispatcherModeShared      = "shared"
+	DefaultDispatcherPoolSize = 3
+
+	// The default minimum number of replicas of autoscaled deployments, and the default external metric
+	// providing the consumer lag of the dispatchers (as exported by the Prometheus kafka-exporter)
+	DefaultAutoscalingMinReplicas = 1
+	DefaultAutoscalingLagMetric   = "kafka_consumergroup_lag"
 )
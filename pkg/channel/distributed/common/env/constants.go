@@ -42,4 +42,9 @@ const (
 	// Dispatcher Configuration
 	ChannelKeyEnvVarKey  = "CHANNEL_KEY"
 	ServiceNameEnvVarKey = "SERVICE_NAME"
+
+	// Shared Dispatcher Configuration (Replaces The ChannelKey & Topic Of A Dispatcher Of A Single Channel)
+	DispatcherPoolMemberEnvVarKey = "DISPATCHER_POOL_MEMBER"
+	DispatcherPoolSizeEnvVarKey   = "DISPATCHER_POOL_SIZE"
+	DispatcherPoolSecretEnvVarKey = "DISPATCHER_POOL_SECRET"
 )
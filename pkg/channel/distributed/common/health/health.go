@@ -34,7 +34,8 @@ type Status interface {
 
 // Structure Containing Basic Liveness Information For Health Server
 type Server struct {
-	server   *http.Server // The Golang HTTP Server Instance
+	server   *http.Server   // The Golang HTTP Server Instance
+	serveMux *http.ServeMux // The Mux Routing The Requests Of The HTTP Server
 	status   Status
 	HttpPort string // The HTTP Port The Dispatcher Server Listens On
 
@@ -82,9 +83,15 @@ func (hs *Server) initializeServer(httpPort string) {
 	server := &http.Server{Addr: ":" + httpPort, Handler: serveMux}
 
 	// Set The Initialized HTTP Server
+	hs.serveMux = serveMux
 	hs.server = server
 }
 
+// Register An Additional Handler (Must Be Called Before Start)
+func (hs *Server) HandleFunc(pattern string, handler http.HandlerFunc) {
+	hs.serveMux.HandleFunc(pattern, handler)
+}
+
 // Start The HTTP Server (Blocking Call)
 func (hs *Server) Start(logger *zap.Logger) {
 	listener, err := net.Listen("tcp", ":"+hs.HttpPort)
@@ -138,8 +138,13 @@ func TestHealthServer(t *testing.T) {
 	logger := logtesting.TestLogger(t).Desugar()
 
 	health := getTestHealthServer()
+	health.HandleFunc("/teapot", func(responseWriter http.ResponseWriter, request *http.Request) {
+		responseWriter.WriteHeader(http.StatusTeapot)
+	})
 	health.Start(logger)
 
+	teapotUri, err := url.Parse(fmt.Sprintf("http://%s:%s/teapot", testHttpHost, health.HttpPort))
+	assert.Nil(t, err)
 	livenessUri, err := url.Parse(fmt.Sprintf("http://%s:%s%s", testHttpHost, health.HttpPort, livenessPath))
 	assert.Nil(t, err)
 	readinessUri, err := url.Parse(fmt.Sprintf("http://%s:%s%s", testHttpHost, health.HttpPort, readinessPath))
@@ -152,6 +157,9 @@ func TestHealthServer(t *testing.T) {
 	health.SetAlive(true)
 	getEventToServer(t, livenessUri, http.StatusOK)
 
+	// Verify The Additional Handler Is Served Too
+	getEventToServer(t, teapotUri, http.StatusTeapot)
+
 	health.Stop(logger)
 
 	// Pause to let async go process finish logging :(
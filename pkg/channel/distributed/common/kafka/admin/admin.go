@@ -33,6 +33,30 @@ type AdminClientInterface interface {
 	GetKafkaSecretName(topicName string) string
 }
 
+// Optional AdminClientInterface Extension For Implementations Able To List The Existing Topics
+type TopicLister interface {
+	ListTopics(context.Context) (map[string]sarama.TopicDetail, error)
+}
+
+// Optional AdminClientInterface Extension For Implementations Able To List & Delete The Consumer Groups
+type ConsumerGroupAdmin interface {
+	ListConsumerGroups(context.Context) (map[string]string, error)
+	DeleteConsumerGroup(context.Context, string) error
+}
+
+// Optional AdminClientInterface Extension For Implementations Able To Measure The Lag Of The Consumer Groups (The Number
+// Of Messages Of The Topics Which They Have Yet To Consume)
+type ConsumerGroupLagReader interface {
+	ConsumerGroupLag(ctx context.Context, groupId string, topics []string) (int64, error)
+}
+
+// Optional AdminClientInterface Extension For Implementations Spanning Several Kafka Secrets (Clusters), Whose Topics
+// Are Managed By The AdminClient Of The Kafka Secret Selected By The Caller (See EKKafkaSecretsConfig)
+type KafkaSecretsAdminClient interface {
+	GetKafkaSecretNames() []string
+	GetKafkaSecretAdminClient(kafkaSecretName string) AdminClientInterface
+}
+
 // AdminClient Type Enumeration
 type AdminClientType int
 
@@ -49,7 +73,7 @@ const (
 // The K8S Namespace parameter indicates the Kubernetes Namespace in which the Kafka Credentials secret(s)
 // will be found.  The secret(s) must contain the constants.KafkaSecretLabel label indicating it is a "Kafka Secret".
 //
-// For the normal Kafka use case (Confluent, etc.) there should be only one Secret with the following content...
+// For the normal Kafka use case (Confluent, etc.) there should be one Secret per Kafka cluster with the following content...
 //
 //      data:
 //		  brokers: SASL_SSL://<host>.<region>.aws.confluent.cloud:9092
@@ -66,6 +90,9 @@ const (
 //
 // * If no authorization is required (local dev instance) then specify username and password as the empty string ""
 //
+// * With several Kafka (or Custom) Secrets the returned AdminClient implements the KafkaSecretsAdminClient interface,
+//   and the topics are managed by the AdminClient of the Kafka Secret selected for them.
+//
 func CreateAdminClient(ctx context.Context, saramaConfig *sarama.Config, clientId string, adminClientType AdminClientType) (AdminClientInterface, error) {
 	switch adminClientType {
 	case Kafka:
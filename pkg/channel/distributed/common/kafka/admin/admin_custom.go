@@ -56,7 +56,7 @@ type CustomAdminClient struct {
 	httpClient  *http.Client
 }
 
-// Create A New Custom Kafka AdminClient Based On The Kafka Secret In The Specified K8S Namespace
+// Create A New Custom Kafka AdminClient Based On The Kafka Secret(s) In The Specified K8S Namespace
 func NewCustomAdminClient(ctx context.Context, namespace string) (AdminClientInterface, error) {
 
 	// Get The Logger From The Context
@@ -72,25 +72,47 @@ func NewCustomAdminClient(ctx context.Context, namespace string) (AdminClientInt
 		return nil, err
 	}
 
-	// Currently Only Support One Kafka Secret - Invalid AdminClient For All Other Cases!
-	var kafkaSecret corev1.Secret
-	if len(kafkaSecrets.Items) != 1 {
-		logger.Warn(fmt.Sprintf("Expected 1 Kafka Secret But Found %d - Kafka AdminClient Will Not Be Functional!", len(kafkaSecrets.Items)))
+	// At Least One Kafka Secret Is Required - Invalid AdminClient Otherwise!
+	if len(kafkaSecrets.Items) < 1 {
+		logger.Warn("Expected At Least 1 Kafka Secret But Found 0 - Kafka AdminClient Will Not Be Functional!")
 		return nil, nil
-	} else {
+	}
+
+	// Create A Custom HTTP Client With Custom Timeout (Shared By The Kafka Secrets)
+	httpClient := &http.Client{Timeout: custom.SidecarTimeout}
+
+	// A Single Kafka Secret Needs No Routing Of The Topics
+	if len(kafkaSecrets.Items) == 1 {
 		logger.Info("Found 1 Kafka Secret", zap.String("Secret", kafkaSecrets.Items[0].Name))
-		kafkaSecret = kafkaSecrets.Items[0]
+		return newCustomSecretAdminClient(logger, namespace, &kafkaSecrets.Items[0], httpClient)
+	}
+
+	// Otherwise Create The Custom AdminClient Of Each Kafka Secret (Identified To The Sidecar By The KafkaSecretHeader)
+	logger.Info("Found Multiple Kafka Secrets", zap.Int("Count", len(kafkaSecrets.Items)))
+	adminClients := make(map[string]AdminClientInterface, len(kafkaSecrets.Items))
+	for index := range kafkaSecrets.Items {
+		kafkaSecret := &kafkaSecrets.Items[index]
+		adminClient, err := newCustomSecretAdminClient(logger, namespace, kafkaSecret, httpClient)
+		if err != nil {
+			return nil, err
+		}
+		adminClients[kafkaSecret.Name] = adminClient
 	}
 
+	// Return The Multiple Kafka Secret AdminClient
+	logger.Debug("Successfully Created New Multiple Kafka Secret Custom (Sidecar) AdminClient")
+	return NewMultiKafkaSecretAdminClient(logger, adminClients), nil
+}
+
+// Create A New Custom Kafka AdminClient Based On The Specified Kafka Secret
+func newCustomSecretAdminClient(logger *zap.Logger, namespace string, kafkaSecret *corev1.Secret, httpClient *http.Client) (AdminClientInterface, error) {
+
 	// Validate Secret Data
-	if !adminutil.ValidateKafkaSecret(logger, &kafkaSecret) {
-		err = errors.New("invalid Kafka Secret found")
+	if !adminutil.ValidateKafkaSecret(logger, kafkaSecret) {
+		err := errors.New("invalid Kafka Secret found")
 		return nil, err
 	}
 
-	// Create A Custom HTTP Client With Custom Timeout
-	httpClient := &http.Client{Timeout: custom.SidecarTimeout}
-
 	// Create A Custom AdminClient (REST Sidecar Endpoint Pass-Through)
 	customAdminClient := &CustomAdminClient{
 		logger:      logger,
@@ -100,7 +122,7 @@ func NewCustomAdminClient(ctx context.Context, namespace string) (AdminClientInt
 	}
 
 	// Return The Custom AdminClient
-	logger.Debug("Successfully Created New Custom (Sidecar) AdminClient")
+	logger.Debug("Successfully Created New Custom (Sidecar) AdminClient", zap.String("Secret", kafkaSecret.Name))
 	return customAdminClient, nil
 }
 
@@ -140,6 +162,7 @@ func (c *CustomAdminClient) CreateTopic(_ context.Context, topicName string, top
 	// Populate Required Headers
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set(custom.TopicNameHeader, topicName)
+	request.Header.Set(custom.KafkaSecretHeader, c.kafkaSecret)
 
 	// Make The HTTP Request
 	response, err := c.httpClient.Do(request)
@@ -175,6 +198,9 @@ func (c *CustomAdminClient) DeleteTopic(_ context.Context, topicName string) *sa
 		return adminutil.NewTopicError(sarama.ErrUnknown, fmt.Sprintf("failed to create new http request for creation of topic '%s'", topicName))
 	}
 
+	// Populate Required Headers
+	request.Header.Set(custom.KafkaSecretHeader, c.kafkaSecret)
+
 	// Make The HTTP Request
 	response, err := c.httpClient.Do(request)
 	defer c.safeCloseHTTPResponseBody(response)
@@ -194,7 +220,7 @@ func (c *CustomAdminClient) Close() error {
 
 // Get The K8S Secret With Kafka Credentials For The Specified Topic Name
 func (c *CustomAdminClient) GetKafkaSecretName(_ string) string {
-	return c.kafkaSecret // Only supports 1 topic so just return Kafka Secret name ; )
+	return c.kafkaSecret // Each Custom AdminClient has a single Kafka Secret (see MultiKafkaSecretAdminClient for several)
 }
 
 // Safely Close The Specified HTTP Response Body
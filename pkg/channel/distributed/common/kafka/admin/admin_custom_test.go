@@ -31,6 +31,7 @@ import (
 
 	"k8s.io/client-go/kubernetes/fake"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/admin/custom"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/admin/custom/sidecar"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
 	injectionclient "knative.dev/pkg/client/injection/kube/client"
 	"knative.dev/pkg/logging"
@@ -109,6 +110,35 @@ func TestNewCustomAdminClientInvalidKafkaSecret(t *testing.T) {
 	assert.Nil(t, adminClient)
 }
 
+// Test The NewCustomAdminClient() Constructor - Multiple Kafka Secrets
+func TestNewCustomAdminClientMultipleKafkaSecrets(t *testing.T) {
+
+	// Test Data
+	namespace := "TestNamespace"
+
+	// Create Test Kafka Secrets
+	kafkaSecret1 := createKafkaSecret("TestKafkaSecretName1", namespace, "Brokers1", "Username1", "Password1")
+	kafkaSecret2 := createKafkaSecret("TestKafkaSecretName2", namespace, "Brokers2", "Username2", "Password2")
+
+	// Create A Context With Test Logger & K8S Client
+	ctx := logging.WithLogger(context.TODO(), logtesting.TestLogger(t))
+	ctx = context.WithValue(ctx, injectionclient.Key{}, fake.NewSimpleClientset(kafkaSecret1, kafkaSecret2))
+
+	// Perform The Test
+	adminClient, err := NewCustomAdminClient(ctx, namespace)
+
+	// Verify The Result
+	assert.Nil(t, err)
+	kafkaSecretsAdminClient, ok := adminClient.(KafkaSecretsAdminClient)
+	assert.True(t, ok)
+	assert.Equal(t, []string{kafkaSecret1.Name, kafkaSecret2.Name}, kafkaSecretsAdminClient.GetKafkaSecretNames())
+	for _, kafkaSecretName := range kafkaSecretsAdminClient.GetKafkaSecretNames() {
+		customAdminClient, ok := kafkaSecretsAdminClient.GetKafkaSecretAdminClient(kafkaSecretName).(*CustomAdminClient)
+		assert.True(t, ok)
+		assert.Equal(t, kafkaSecretName, customAdminClient.kafkaSecret)
+	}
+}
+
 // Test The Custom AdminClient CreateTopic() Functionality
 func TestCustomAdminClientCreateTopic(t *testing.T) {
 
@@ -197,6 +227,53 @@ func TestCustomAdminClientDeleteTopic(t *testing.T) {
 	}
 }
 
+// Test The Custom AdminClient Against The Reference Sidecar Server
+func TestCustomAdminClientWithSidecarServer(t *testing.T) {
+
+	// Test Data
+	namespace := "TestNamespace"
+	topicName := "TestTopicName"
+	topicRetentionMillisString := strconv.FormatInt(constants.MillisPerDay, 10)
+	topicDetail := &sarama.TopicDetail{
+		NumPartitions:     4,
+		ReplicationFactor: 2,
+		ConfigEntries:     map[string]*string{constants.TopicDetailConfigRetentionMs: &topicRetentionMillisString},
+	}
+
+	// Create A Context With Test Logger & K8S Client
+	logger := logtesting.TestLogger(t)
+	ctx := logging.WithLogger(context.TODO(), logger)
+	ctx = context.WithValue(ctx, injectionclient.Key{}, fake.NewSimpleClientset(createKafkaSecret("Name", namespace, "Brokers", "Username", "Password")))
+
+	// Create & Start The Reference Sidecar Server With A MemoryBackend On The Expected Sidecar Host:Port & Defer Close
+	backend := sidecar.NewMemoryBackend()
+	listener, err := net.Listen("tcp", custom.SidecarHost+":"+custom.SidecarPort)
+	assert.Nil(t, err)
+	sidecarServer := httptest.NewUnstartedServer(sidecar.NewServer(logger.Desugar(), backend))
+	sidecarServer.Listener = listener
+	sidecarServer.Start()
+	defer sidecarServer.Close()
+
+	// Create A New Custom AdminClient
+	adminClient, err := NewCustomAdminClient(ctx, namespace)
+	assert.Nil(t, err)
+	assert.NotNil(t, adminClient)
+
+	// Verify The Topic Creation
+	assert.Equal(t, sarama.ErrNoError, adminClient.CreateTopic(ctx, topicName, topicDetail).Err)
+	assert.Equal(t, sarama.ErrTopicAlreadyExists, adminClient.CreateTopic(ctx, topicName, topicDetail).Err)
+	topics := backend.Topics("Name")
+	assert.Len(t, topics, 1)
+	assert.Equal(t, topicDetail.NumPartitions, topics[topicName].NumPartitions)
+	assert.Equal(t, topicDetail.ReplicationFactor, topics[topicName].ReplicationFactor)
+	assert.Equal(t, topicRetentionMillisString, *topics[topicName].ConfigEntries[constants.TopicDetailConfigRetentionMs])
+
+	// Verify The Topic Deletion
+	assert.Equal(t, sarama.ErrNoError, adminClient.DeleteTopic(ctx, topicName).Err)
+	assert.Equal(t, sarama.ErrUnknownTopicOrPartition, adminClient.DeleteTopic(ctx, topicName).Err)
+	assert.Empty(t, backend.Topics("Name"))
+}
+
 // Test The Custom AdminClient Close() Functionality
 func TestCustomAdminClientClose(t *testing.T) {
 
@@ -298,6 +375,7 @@ func verifySidecarRequest(t *testing.T, request *http.Request, body []byte, topi
 
 	// Verify Common Request Data
 	assert.Equal(t, custom.SidecarHost+":"+custom.SidecarPort, request.Host)
+	assert.Equal(t, "Name", request.Header.Get(custom.KafkaSecretHeader))
 
 	// Verify Method Specific Request Data
 	switch request.Method {
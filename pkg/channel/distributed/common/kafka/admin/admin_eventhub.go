@@ -46,9 +46,10 @@ var _ AdminClientInterface = &EventHubAdminClient{}
 
 // EventHub AdminClient Definition
 type EventHubAdminClient struct {
-	logger    *zap.Logger
-	namespace string
-	cache     eventhubcache.CacheInterface
+	logger        *zap.Logger
+	namespace     string
+	cache         eventhubcache.CacheInterface
+	stopRefresher context.CancelFunc // Stops The Periodic Refresh Of The Cache (Nil If Not Refreshed)
 }
 
 // EventHub ErrorCode RegExp - For Extracting Azure ErrorCodes From Error Messages
@@ -59,6 +60,9 @@ var NewCacheWrapper = func(ctx context.Context, k8sNamespace string) eventhubcac
 	return eventhubcache.NewCache(ctx, k8sNamespace)
 }
 
+// The Interval At Which The EventHub Cache Of An AdminClient Is Refreshed (Non-Positive To Disable)
+var EventHubCacheRefreshInterval = eventhubcache.DefaultRefreshInterval
+
 // Create A New Azure EventHub AdminClient Based On Kafka Secrets In The Specified K8S Namespace
 func NewEventHubAdminClient(ctx context.Context, namespace string) (AdminClientInterface, error) {
 
@@ -75,12 +79,17 @@ func NewEventHubAdminClient(ctx context.Context, namespace string) (AdminClientI
 		return nil, err
 	}
 
+	// Keep The Cache Up To Date With The EventHubs Created / Deleted Outside The AdminClient Until It Is Closed
+	refreshCtx, stopRefresher := context.WithCancel(ctx)
+	go eventhubcache.Refresh(refreshCtx, cache, EventHubCacheRefreshInterval)
+
 	// Create And Return A New EventHub AdminClient With Namespace Cache
 	logger.Debug("Successfully Created New Azure EventHub AdminClient")
 	return &EventHubAdminClient{
-		logger:    logger,
-		namespace: namespace,
-		cache:     cache,
+		logger:        logger,
+		namespace:     namespace,
+		cache:         cache,
+		stopRefresher: stopRefresher,
 	}, nil
 }
 
@@ -204,7 +213,11 @@ func (c *EventHubAdminClient) GetKafkaSecretName(topicName string) string {
 
 // Kafka AdminClient Close Implementation Using Azure EventHub API
 func (c *EventHubAdminClient) Close() error {
-	return nil // Nothing to "close" in the HubManager (just a REST client) so this is just a compatibility no-op.
+	// Nothing to "close" in the HubManager (just a REST client), only the refresh of the cache is stopped.
+	if c.stopRefresher != nil {
+		c.stopRefresher()
+	}
+	return nil
 }
 
 // Utility Function For Converting Millis To Days (Rounded Up To Larger Day Value)
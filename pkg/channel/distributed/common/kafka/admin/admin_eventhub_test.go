@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"strconv"
 	"testing"
+	"time"
 
 	eventhub "github.com/Azure/azure-event-hubs-go/v3"
 	"github.com/Shopify/sarama"
@@ -89,6 +90,46 @@ func TestNewEventHubAdminClientError(t *testing.T) {
 	mockCache.AssertExpectations(t)
 }
 
+// Test The NewEventHubAdminClient() Constructor - Periodic Cache Refresh Until Closed
+func TestNewEventHubAdminClientRefresh(t *testing.T) {
+
+	// Create A Mock EventHub Cache Signaling Its Updates
+	updated := make(chan struct{}, 1)
+	mockCache := &MockCache{}
+	mockCache.On("Update", mock.Anything).Return(nil).Run(func(mock.Arguments) {
+		select {
+		case updated <- struct{}{}:
+		default:
+		}
+	})
+
+	// Replace The NewCache Wrapper & The Refresh Interval & Defer Reset
+	newCacheWrapperPlaceholder := NewCacheWrapper
+	NewCacheWrapper = func(ctxArg context.Context, namespaceArg string) eventhubcache.CacheInterface {
+		return mockCache
+	}
+	refreshIntervalPlaceholder := EventHubCacheRefreshInterval
+	EventHubCacheRefreshInterval = time.Millisecond
+	defer func() {
+		NewCacheWrapper = newCacheWrapperPlaceholder
+		EventHubCacheRefreshInterval = refreshIntervalPlaceholder
+	}()
+
+	// Perform The Test
+	adminClient, err := NewEventHubAdminClient(context.TODO(), "TestNamespace")
+	assert.Nil(t, err)
+
+	// Verify The Cache Is Refreshed Beyond Its Initial Update, Until The AdminClient Is Closed
+	for i := 0; i < 3; i++ {
+		select {
+		case <-updated:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for the refresh of the cache")
+		}
+	}
+	assert.Nil(t, adminClient.Close())
+}
+
 // Test The EventHub AdminClient CreateTopic() Functionality - Success Path
 func TestEventHubAdminClientCreateTopicSuccess(t *testing.T) {
 
@@ -28,6 +28,7 @@ import (
 	adminutil "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/admin/util"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
 	kafkasarama "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/sarama"
+	commonconsumer "knative.dev/eventing-kafka/pkg/common/consumer"
 	kubeclient "knative.dev/pkg/client/injection/kube/client"
 	"knative.dev/pkg/logging"
 )
@@ -37,8 +38,11 @@ import (
 // a pass-through to the Sarama ClusterAdmin with some additional functionality layered on top.
 //
 
-// Ensure The KafkaAdminClient Struct Implements The AdminClientInterface
+// Ensure The KafkaAdminClient Struct Implements The AdminClientInterface & TopicLister
 var _ AdminClientInterface = &KafkaAdminClient{}
+var _ TopicLister = &KafkaAdminClient{}
+var _ ConsumerGroupAdmin = &KafkaAdminClient{}
+var _ ConsumerGroupLagReader = &KafkaAdminClient{}
 
 // Kafka AdminClient Definition
 type KafkaAdminClient struct {
@@ -46,10 +50,12 @@ type KafkaAdminClient struct {
 	namespace    string
 	kafkaSecret  string
 	clientId     string
+	brokers      []string
+	saramaConfig *sarama.Config
 	clusterAdmin sarama.ClusterAdmin
 }
 
-// Create A New Kafka AdminClient Based On The Kafka Secret In The Specified K8S Namespace
+// Create A New Kafka AdminClient Based On The Kafka Secret(s) In The Specified K8S Namespace
 func NewKafkaAdminClient(ctx context.Context, saramaConfig *sarama.Config, clientId string, namespace string) (AdminClientInterface, error) {
 
 	// Get The Logger From The Context
@@ -65,19 +71,43 @@ func NewKafkaAdminClient(ctx context.Context, saramaConfig *sarama.Config, clien
 		return nil, err
 	}
 
-	// Currently Only Support One Kafka Secret - Invalid AdminClient For All Other Cases!
-	var kafkaSecret corev1.Secret
-	if len(kafkaSecrets.Items) != 1 {
-		logger.Warn(fmt.Sprintf("Expected 1 Kafka Secret But Found %d - Kafka AdminClient Will Not Be Functional!", len(kafkaSecrets.Items)))
+	// At Least One Kafka Secret Is Required - Invalid AdminClient Otherwise!
+	if len(kafkaSecrets.Items) < 1 {
+		logger.Warn("Expected At Least 1 Kafka Secret But Found 0 - Kafka AdminClient Will Not Be Functional!")
 		return &KafkaAdminClient{logger: logger, namespace: namespace, clientId: clientId}, nil
-	} else {
+	}
+
+	// A Single Kafka Secret Needs No Routing Of The Topics
+	if len(kafkaSecrets.Items) == 1 {
 		logger.Info("Found 1 Kafka Secret", zap.String("Secret", kafkaSecrets.Items[0].Name))
-		kafkaSecret = kafkaSecrets.Items[0]
+		return newKafkaSecretAdminClient(logger, saramaConfig, clientId, namespace, &kafkaSecrets.Items[0])
+	}
+
+	// Otherwise Create The AdminClient Of Each Kafka Secret (Each With Its Own Copy Of The Sarama Config)
+	logger.Info("Found Multiple Kafka Secrets", zap.Int("Count", len(kafkaSecrets.Items)))
+	adminClients := make(map[string]AdminClientInterface, len(kafkaSecrets.Items))
+	for index := range kafkaSecrets.Items {
+		kafkaSecret := &kafkaSecrets.Items[index]
+		kafkaSecretSaramaConfig := *saramaConfig
+		adminClient, err := newKafkaSecretAdminClient(logger, &kafkaSecretSaramaConfig, clientId, namespace, kafkaSecret)
+		if err != nil {
+			_ = NewMultiKafkaSecretAdminClient(logger, adminClients).Close()
+			return nil, err
+		}
+		adminClients[kafkaSecret.Name] = adminClient
 	}
 
+	// Return The Multiple Kafka Secret AdminClient - Success
+	logger.Debug("Successfully Created New Multiple Kafka Secret AdminClient")
+	return NewMultiKafkaSecretAdminClient(logger, adminClients), nil
+}
+
+// Create A New Kafka AdminClient Based On The Specified Kafka Secret
+func newKafkaSecretAdminClient(logger *zap.Logger, saramaConfig *sarama.Config, clientId string, namespace string, kafkaSecret *corev1.Secret) (AdminClientInterface, error) {
+
 	// Validate Secret Data
-	if !adminutil.ValidateKafkaSecret(logger, &kafkaSecret) {
-		err = errors.New("invalid Kafka Secret found")
+	if !adminutil.ValidateKafkaSecret(logger, kafkaSecret) {
+		err := errors.New("invalid Kafka Secret found")
 		return nil, err
 	}
 
@@ -92,7 +122,7 @@ func NewKafkaAdminClient(ctx context.Context, saramaConfig *sarama.Config, clien
 	// Create A New Sarama ClusterAdmin
 	clusterAdmin, err := NewClusterAdminWrapper(brokers, saramaConfig)
 	if err != nil {
-		logger.Error("Failed To Create New ClusterAdmin", zap.Any("Config", saramaConfig), zap.Error(err))
+		logger.Error("Failed To Create New ClusterAdmin", zap.String("Secret", kafkaSecret.Name), zap.Any("Config", saramaConfig), zap.Error(err))
 		return nil, err
 	}
 
@@ -102,11 +132,13 @@ func NewKafkaAdminClient(ctx context.Context, saramaConfig *sarama.Config, clien
 		namespace:    namespace,
 		kafkaSecret:  kafkaSecret.Name,
 		clientId:     clientId,
+		brokers:      brokers,
+		saramaConfig: saramaConfig,
 		clusterAdmin: clusterAdmin,
 	}
 
 	// Return The KafkaAdminClient - Success
-	logger.Debug("Successfully Created New Kafka AdminClient")
+	logger.Debug("Successfully Created New Kafka AdminClient", zap.String("Secret", kafkaSecret.Name))
 	return kafkaAdminClient, nil
 }
 
@@ -115,6 +147,11 @@ var NewClusterAdminWrapper = func(brokers []string, config *sarama.Config) (sara
 	return sarama.NewClusterAdmin(brokers, config)
 }
 
+// Sarama NewClient() Wrapper Function Variable To Facilitate Unit Testing
+var NewClientWrapper = func(brokers []string, config *sarama.Config) (sarama.Client, error) {
+	return sarama.NewClient(brokers, config)
+}
+
 // Sarama Pass-Through Function For Creating Topics
 func (k KafkaAdminClient) CreateTopic(_ context.Context, topicName string, topicDetail *sarama.TopicDetail) *sarama.TopicError {
 	if k.clusterAdmin == nil {
@@ -137,6 +174,55 @@ func (k KafkaAdminClient) DeleteTopic(_ context.Context, topicName string) *sara
 	}
 }
 
+// Sarama Pass-Through Function For Listing Topics
+func (k KafkaAdminClient) ListTopics(_ context.Context) (map[string]sarama.TopicDetail, error) {
+	if k.clusterAdmin == nil {
+		k.logger.Error("Unable To List Topics Due To Invalid ClusterAdmin - Check Kafka Authorization Secret")
+		return nil, fmt.Errorf("unable to list topics due to invalid ClusterAdmin - check Kafka authorization secrets")
+	} else {
+		return k.clusterAdmin.ListTopics()
+	}
+}
+
+// Sarama Pass-Through Function For Listing Consumer Groups
+func (k KafkaAdminClient) ListConsumerGroups(_ context.Context) (map[string]string, error) {
+	if k.clusterAdmin == nil {
+		k.logger.Error("Unable To List Consumer Groups Due To Invalid ClusterAdmin - Check Kafka Authorization Secret")
+		return nil, fmt.Errorf("unable to list consumer groups due to invalid ClusterAdmin - check Kafka authorization secrets")
+	} else {
+		return k.clusterAdmin.ListConsumerGroups()
+	}
+}
+
+// Sarama Pass-Through Function For Deleting A Consumer Group
+func (k KafkaAdminClient) DeleteConsumerGroup(_ context.Context, group string) error {
+	if k.clusterAdmin == nil {
+		k.logger.Error("Unable To Delete Consumer Group Due To Invalid ClusterAdmin - Check Kafka Authorization Secret", zap.String("Group", group))
+		return fmt.Errorf("unable to delete consumer group due to invalid ClusterAdmin - check Kafka authorization secrets")
+	} else {
+		return k.clusterAdmin.DeleteConsumerGroup(group)
+	}
+}
+
+// Measure The Lag Of A Consumer Group From Its Committed Offsets (Using A Short-Lived Sarama Client To Read The Newest
+// Offsets Of The Topics, Which The ClusterAdmin Is Unable To)
+func (k KafkaAdminClient) ConsumerGroupLag(_ context.Context, groupId string, topics []string) (int64, error) {
+	if k.clusterAdmin == nil {
+		k.logger.Error("Unable To Measure Consumer Group Lag Due To Invalid ClusterAdmin - Check Kafka Authorization Secret", zap.String("Group", groupId))
+		return 0, fmt.Errorf("unable to measure consumer group lag due to invalid ClusterAdmin - check Kafka authorization secrets")
+	}
+	client, err := NewClientWrapper(k.brokers, k.saramaConfig)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := client.Close(); err != nil {
+			k.logger.Warn("Failed To Close Sarama Client", zap.Error(err))
+		}
+	}()
+	return commonconsumer.GroupLag(k.clusterAdmin, client, groupId, topics)
+}
+
 // Sarama Pass-Through Function For Closing ClusterAdmin
 func (k KafkaAdminClient) Close() error {
 	if k.clusterAdmin == nil {
@@ -36,6 +36,7 @@ import (
 	"knative.dev/pkg/system"
 
 	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -99,6 +100,49 @@ Metadata:
 	assert.NotNil(t, adminClient)
 }
 
+// Test The NewKafkaAdminClient() Constructor - Multiple Kafka Secrets Path
+func TestNewKafkaAdminClientMultipleSecrets(t *testing.T) {
+
+	// Test Data
+	clientId := "TestClientId"
+	namespace := "TestNamespace"
+
+	// Create Test Kafka Secrets
+	kafkaSecret1 := createKafkaSecret("TestKafkaSecretName1", namespace, "TestKafkaSecretBrokers1", "TestKafkaSecretUsername1", "TestKafkaSecretPassword1")
+	kafkaSecret2 := createKafkaSecret("TestKafkaSecretName2", namespace, "TestKafkaSecretBrokers2", "TestKafkaSecretUsername2", "TestKafkaSecretPassword2")
+
+	// Create A Context With Test Logger & K8S Client
+	ctx := logging.WithLogger(context.TODO(), logtesting.TestLogger(t))
+	ctx = context.WithValue(ctx, injectionclient.Key{}, fake.NewSimpleClientset(kafkaSecret1, kafkaSecret2))
+
+	// Mock The Sarama ClusterAdmin Creation For Testing (One Per Kafka Secret, With Its Own Credentials)
+	clusterAdmins := make(map[string]*MockClusterAdmin)
+	newClusterAdminWrapperPlaceholder := NewClusterAdminWrapper
+	NewClusterAdminWrapper = func(brokers []string, config *sarama.Config) (sarama.ClusterAdmin, error) {
+		assert.Len(t, brokers, 1)
+		assert.Equal(t, clientId, config.ClientID)
+		assert.Equal(t, strings.Replace(brokers[0], "Brokers", "Username", 1), config.Net.SASL.User)
+		clusterAdmins[brokers[0]] = &MockClusterAdmin{}
+		return clusterAdmins[brokers[0]], nil
+	}
+	defer func() {
+		NewClusterAdminWrapper = newClusterAdminWrapperPlaceholder
+	}()
+
+	// Perform The Test
+	adminClient, err := NewKafkaAdminClient(ctx, commontesting.GetDefaultSaramaConfig(t), clientId, namespace)
+
+	// Verify The Results
+	assert.Nil(t, err)
+	assert.Len(t, clusterAdmins, 2)
+	kafkaSecretsAdminClient, ok := adminClient.(KafkaSecretsAdminClient)
+	assert.True(t, ok)
+	assert.Equal(t, []string{kafkaSecret1.Name, kafkaSecret2.Name}, kafkaSecretsAdminClient.GetKafkaSecretNames())
+	assert.Equal(t, kafkaSecret1.Name, kafkaSecretsAdminClient.GetKafkaSecretAdminClient(kafkaSecret1.Name).GetKafkaSecretName("TestTopicName"))
+	assert.Equal(t, clusterAdmins["TestKafkaSecretBrokers2"], kafkaSecretsAdminClient.GetKafkaSecretAdminClient(kafkaSecret2.Name).(*KafkaAdminClient).clusterAdmin)
+	assert.Nil(t, kafkaSecretsAdminClient.GetKafkaSecretAdminClient("UnknownKafkaSecretName"))
+}
+
 // Test The NewKafkaAdminClient() Constructor - No Kafka Secrets Path
 func TestNewKafkaAdminClientNoSecrets(t *testing.T) {
 
@@ -262,6 +306,132 @@ func TestKafkaAdminClientDeleteTopicInvalidAdminClient(t *testing.T) {
 	assert.Equal(t, errMsg, *resultTopicError.ErrMsg)
 }
 
+// Test The Kafka AdminClient ListTopics() Functionality
+func TestKafkaAdminClientListTopics(t *testing.T) {
+
+	// Test Data
+	ctx := context.TODO()
+	topics := map[string]sarama.TopicDetail{"TestTopicName": {NumPartitions: 4}}
+
+	// Create A Mock Sarama ClusterAdmin To Test Against
+	mockClusterAdmin := &MockClusterAdmin{}
+	mockClusterAdmin.On("ListTopics").Return(topics, nil)
+
+	// Test Logger
+	logger := logtesting.TestLogger(t).Desugar()
+
+	// Create A New Kafka AdminClient To Test
+	adminClient := &KafkaAdminClient{
+		logger:       logger,
+		clusterAdmin: mockClusterAdmin,
+	}
+
+	// Perform The Test
+	resultTopics, err := adminClient.ListTopics(ctx)
+
+	// Verify The Results
+	assert.Nil(t, err)
+	assert.Equal(t, topics, resultTopics)
+	mockClusterAdmin.AssertExpectations(t)
+}
+
+// Test The Kafka AdminClient ListTopics() Without AdminClient Functionality
+func TestKafkaAdminClientListTopicsInvalidAdminClient(t *testing.T) {
+
+	// Test Logger
+	logger := logtesting.TestLogger(t).Desugar()
+
+	// Create A New Kafka AdminClient To Test
+	adminClient := &KafkaAdminClient{logger: logger}
+
+	// Perform The Test
+	resultTopics, err := adminClient.ListTopics(context.TODO())
+
+	// Verify The Results
+	assert.Nil(t, resultTopics)
+	assert.NotNil(t, err)
+	assert.Equal(t, "unable to list topics due to invalid ClusterAdmin - check Kafka authorization secrets", err.Error())
+}
+
+// Test The Kafka AdminClient ListConsumerGroups() & DeleteConsumerGroup() Functionality
+func TestKafkaAdminClientConsumerGroups(t *testing.T) {
+
+	// Test Data
+	ctx := context.TODO()
+	groups := map[string]string{"TestGroupId": "consumer"}
+
+	// Create A Mock Sarama ClusterAdmin To Test Against
+	mockClusterAdmin := &MockClusterAdmin{}
+	mockClusterAdmin.On("ListConsumerGroups").Return(groups, nil)
+	mockClusterAdmin.On("DeleteConsumerGroup", "TestGroupId").Return(nil)
+
+	// Create A New Kafka AdminClient To Test
+	adminClient := &KafkaAdminClient{
+		logger:       logtesting.TestLogger(t).Desugar(),
+		clusterAdmin: mockClusterAdmin,
+	}
+
+	// Perform The Test & Verify The Results
+	resultGroups, err := adminClient.ListConsumerGroups(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, groups, resultGroups)
+	assert.Nil(t, adminClient.DeleteConsumerGroup(ctx, "TestGroupId"))
+	mockClusterAdmin.AssertExpectations(t)
+}
+
+// Test The Kafka AdminClient ListConsumerGroups() & DeleteConsumerGroup() Without AdminClient Functionality
+func TestKafkaAdminClientConsumerGroupsInvalidAdminClient(t *testing.T) {
+
+	// Create A New Kafka AdminClient To Test
+	adminClient := &KafkaAdminClient{logger: logtesting.TestLogger(t).Desugar()}
+
+	// Perform The Test & Verify The Results
+	resultGroups, err := adminClient.ListConsumerGroups(context.TODO())
+	assert.Nil(t, resultGroups)
+	assert.NotNil(t, err)
+	assert.Equal(t, "unable to list consumer groups due to invalid ClusterAdmin - check Kafka authorization secrets", err.Error())
+	err = adminClient.DeleteConsumerGroup(context.TODO(), "TestGroupId")
+	assert.NotNil(t, err)
+	assert.Equal(t, "unable to delete consumer group due to invalid ClusterAdmin - check Kafka authorization secrets", err.Error())
+}
+
+// Test The Kafka AdminClient ConsumerGroupLag() Functionality
+func TestKafkaAdminClientConsumerGroupLag(t *testing.T) {
+
+	// Test Data
+	committed := &sarama.OffsetFetchResponse{}
+	committed.AddBlock("TestTopic", 0, &sarama.OffsetFetchResponseBlock{Offset: 7})
+
+	// Create A Mock Sarama ClusterAdmin & Client To Test Against
+	mockClusterAdmin := &MockClusterAdmin{}
+	mockClusterAdmin.On("ListConsumerGroupOffsets", "TestGroupId", map[string][]int32{"TestTopic": {0}}).Return(committed, nil)
+	mockClient := &MockClient{newest: 10}
+	newClientWrapperPlaceholder := NewClientWrapper
+	NewClientWrapper = func(brokers []string, config *sarama.Config) (sarama.Client, error) {
+		assert.Equal(t, []string{"TestBroker"}, brokers)
+		return mockClient, nil
+	}
+	defer func() { NewClientWrapper = newClientWrapperPlaceholder }()
+
+	// Create A New Kafka AdminClient To Test
+	adminClient := &KafkaAdminClient{
+		logger:       logtesting.TestLogger(t).Desugar(),
+		brokers:      []string{"TestBroker"},
+		clusterAdmin: mockClusterAdmin,
+	}
+
+	// Perform The Test & Verify The Results
+	lag, err := adminClient.ConsumerGroupLag(context.TODO(), "TestGroupId", []string{"TestTopic"})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), lag)
+	assert.True(t, mockClient.closed)
+	mockClusterAdmin.AssertExpectations(t)
+
+	// Without A ClusterAdmin The Lag Can Not Be Measured
+	_, err = (&KafkaAdminClient{logger: logtesting.TestLogger(t).Desugar()}).ConsumerGroupLag(context.TODO(), "TestGroupId", []string{"TestTopic"})
+	assert.NotNil(t, err)
+}
+
 // Test The Kafka AdminClient Close() Functionality
 func TestKafkaAdminClientClose(t *testing.T) {
 
@@ -376,7 +546,8 @@ func (m *MockClusterAdmin) CreateTopic(topic string, detail *sarama.TopicDetail,
 }
 
 func (m *MockClusterAdmin) ListTopics() (map[string]sarama.TopicDetail, error) {
-	panic("implement me")
+	args := m.Called()
+	return args.Get(0).(map[string]sarama.TopicDetail), args.Error(1)
 }
 
 func (m *MockClusterAdmin) DescribeTopics(topics []string) (metadata []*sarama.TopicMetadata, err error) {
@@ -425,7 +596,8 @@ func (m *MockClusterAdmin) DeleteACL(filter sarama.AclFilter, validateOnly bool)
 }
 
 func (m *MockClusterAdmin) ListConsumerGroups() (map[string]string, error) {
-	panic("implement me")
+	args := m.Called()
+	return args.Get(0).(map[string]string), args.Error(1)
 }
 
 func (m *MockClusterAdmin) DescribeConsumerGroups(groups []string) ([]*sarama.GroupDescription, error) {
@@ -433,11 +605,13 @@ func (m *MockClusterAdmin) DescribeConsumerGroups(groups []string) ([]*sarama.Gr
 }
 
 func (m *MockClusterAdmin) ListConsumerGroupOffsets(group string, topicPartitions map[string][]int32) (*sarama.OffsetFetchResponse, error) {
-	panic("implement me")
+	args := m.Called(group, topicPartitions)
+	return args.Get(0).(*sarama.OffsetFetchResponse), args.Error(1)
 }
 
 func (m *MockClusterAdmin) DeleteConsumerGroup(group string) error {
-	panic("implement me")
+	args := m.Called(group)
+	return args.Error(0)
 }
 
 func (m *MockClusterAdmin) DescribeCluster() (brokers []*sarama.Broker, controllerID int32, err error) {
@@ -448,3 +622,30 @@ func (m *MockClusterAdmin) Close() error {
 	args := m.Called()
 	return args.Error(0)
 }
+
+//
+// Mock Sarama Kafka Client
+//
+
+// The Mock Sarama Client, Whose Topics Have A Single Partition (Only The Offset Functions Are Implemented)
+type MockClient struct {
+	sarama.Client
+	newest int64
+	closed bool
+}
+
+func (m *MockClient) Partitions(_ string) ([]int32, error) {
+	return []int32{0}, nil
+}
+
+func (m *MockClient) GetOffset(_ string, _ int32, time int64) (int64, error) {
+	if time == sarama.OffsetOldest {
+		return 0, nil
+	}
+	return m.newest, nil
+}
+
+func (m *MockClient) Close() error {
+	m.closed = true
+	return nil
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Shopify/sarama"
+	"go.uber.org/zap"
+	adminutil "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/admin/util"
+)
+
+//
+// This is an implementation of the AdminClient interface spanning several Kafka Secrets (clusters), each with
+// its own AdminClient.  As the Kafka Secret of a topic cannot be derived from its name, the topics are created and
+// deleted by the AdminClient of the Kafka Secret selected by the caller (see KafkaSecretsAdminClient).
+//
+
+// Ensure The MultiKafkaSecretAdminClient Struct Implements The AdminClientInterface, TopicLister & KafkaSecretsAdminClient
+var _ AdminClientInterface = &MultiKafkaSecretAdminClient{}
+var _ TopicLister = &MultiKafkaSecretAdminClient{}
+var _ KafkaSecretsAdminClient = &MultiKafkaSecretAdminClient{}
+
+// Multiple Kafka Secret AdminClient Definition
+type MultiKafkaSecretAdminClient struct {
+	logger           *zap.Logger
+	kafkaSecretNames []string
+	adminClients     map[string]AdminClientInterface
+}
+
+// Create A New Multiple Kafka Secret AdminClient From The AdminClients Of The Kafka Secrets (Mapped By Kafka Secret Name)
+func NewMultiKafkaSecretAdminClient(logger *zap.Logger, adminClients map[string]AdminClientInterface) *MultiKafkaSecretAdminClient {
+	kafkaSecretNames := make([]string, 0, len(adminClients))
+	for kafkaSecretName := range adminClients {
+		kafkaSecretNames = append(kafkaSecretNames, kafkaSecretName)
+	}
+	sort.Strings(kafkaSecretNames)
+	return &MultiKafkaSecretAdminClient{
+		logger:           logger,
+		kafkaSecretNames: kafkaSecretNames,
+		adminClients:     adminClients,
+	}
+}
+
+// Topics Cannot Be Created Without Selecting The AdminClient Of Their Kafka Secret
+func (m *MultiKafkaSecretAdminClient) CreateTopic(_ context.Context, topicName string, _ *sarama.TopicDetail) *sarama.TopicError {
+	m.logger.Error("Unable To Create Topic Without Selecting Its Kafka Secret", zap.String("TopicName", topicName))
+	return adminutil.NewUnknownTopicError("unable to create topic without selecting its kafka secret among multiple kafka secrets")
+}
+
+// Topics Cannot Be Deleted Without Selecting The AdminClient Of Their Kafka Secret
+func (m *MultiKafkaSecretAdminClient) DeleteTopic(_ context.Context, topicName string) *sarama.TopicError {
+	m.logger.Error("Unable To Delete Topic Without Selecting Its Kafka Secret", zap.String("TopicName", topicName))
+	return adminutil.NewUnknownTopicError("unable to delete topic without selecting its kafka secret among multiple kafka secrets")
+}
+
+// List The Topics Of All The Kafka Secrets Whose AdminClients Are Able To List Them
+func (m *MultiKafkaSecretAdminClient) ListTopics(ctx context.Context) (map[string]sarama.TopicDetail, error) {
+	topics := make(map[string]sarama.TopicDetail)
+	for _, kafkaSecretName := range m.kafkaSecretNames {
+		if topicLister, ok := m.adminClients[kafkaSecretName].(TopicLister); ok {
+			kafkaSecretTopics, err := topicLister.ListTopics(ctx)
+			if err != nil {
+				m.logger.Error("Failed To List Topics Of Kafka Secret", zap.String("Secret", kafkaSecretName), zap.Error(err))
+				return nil, err
+			}
+			for topicName, topicDetail := range kafkaSecretTopics {
+				topics[topicName] = topicDetail
+			}
+		}
+	}
+	return topics, nil
+}
+
+// Close The AdminClients Of All The Kafka Secrets (Returning The First Error)
+func (m *MultiKafkaSecretAdminClient) Close() error {
+	var result error
+	for _, kafkaSecretName := range m.kafkaSecretNames {
+		err := m.adminClients[kafkaSecretName].Close()
+		if err != nil {
+			m.logger.Error("Failed To Close AdminClient Of Kafka Secret", zap.String("Secret", kafkaSecretName), zap.Error(err))
+			if result == nil {
+				result = err
+			}
+		}
+	}
+	return result
+}
+
+// The Kafka Secret Of A Topic Is Selected By The Caller And Cannot Be Determined From Its Name
+func (m *MultiKafkaSecretAdminClient) GetKafkaSecretName(_ string) string {
+	return ""
+}
+
+// Get The (Sorted) Names Of The Kafka Secrets
+func (m *MultiKafkaSecretAdminClient) GetKafkaSecretNames() []string {
+	return append([]string(nil), m.kafkaSecretNames...)
+}
+
+// Get The AdminClient Of The Specified Kafka Secret (Nil If Unknown)
+func (m *MultiKafkaSecretAdminClient) GetKafkaSecretAdminClient(kafkaSecretName string) AdminClientInterface {
+	return m.adminClients[kafkaSecretName]
+}
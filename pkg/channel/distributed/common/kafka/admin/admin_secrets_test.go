@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test The MultiKafkaSecretAdminClient Functionality
+func TestMultiKafkaSecretAdminClient(t *testing.T) {
+
+	// Test Data
+	ctx := context.TODO()
+	logger := logtesting.TestLogger(t).Desugar()
+	topicDetail := sarama.TopicDetail{NumPartitions: 4}
+
+	// Create The Mock ClusterAdmins Of Two Kafka Secrets (Only The Second Failing To Close)
+	mockClusterAdmin1 := &MockClusterAdmin{}
+	mockClusterAdmin1.On("ListTopics").Return(map[string]sarama.TopicDetail{"TestTopicName1": topicDetail}, nil)
+	mockClusterAdmin1.On("Close").Return(nil)
+	mockClusterAdmin2 := &MockClusterAdmin{}
+	mockClusterAdmin2.On("ListTopics").Return(map[string]sarama.TopicDetail{"TestTopicName2": topicDetail}, nil)
+	mockClusterAdmin2.On("Close").Return(errors.New("test close error"))
+
+	// Create The Multiple Kafka Secret AdminClient To Test (Including A Kafka Secret Unable To List Topics)
+	kafkaAdminClient1 := &KafkaAdminClient{logger: logger, kafkaSecret: "TestKafkaSecretName1", clusterAdmin: mockClusterAdmin1}
+	kafkaAdminClient2 := &KafkaAdminClient{logger: logger, kafkaSecret: "TestKafkaSecretName2", clusterAdmin: mockClusterAdmin2}
+	adminClient := NewMultiKafkaSecretAdminClient(logger, map[string]AdminClientInterface{
+		"TestKafkaSecretName2": kafkaAdminClient2,
+		"TestKafkaSecretName1": kafkaAdminClient1,
+		"TestKafkaSecretName3": &MockAdminClient{kafkaSecret: "TestKafkaSecretName3"},
+	})
+
+	// Verify The Kafka Secrets & Their AdminClients
+	assert.Equal(t, []string{"TestKafkaSecretName1", "TestKafkaSecretName2", "TestKafkaSecretName3"}, adminClient.GetKafkaSecretNames())
+	assert.Equal(t, kafkaAdminClient1, adminClient.GetKafkaSecretAdminClient("TestKafkaSecretName1"))
+	assert.Equal(t, kafkaAdminClient2, adminClient.GetKafkaSecretAdminClient("TestKafkaSecretName2"))
+	assert.Nil(t, adminClient.GetKafkaSecretAdminClient("UnknownKafkaSecretName"))
+	assert.Equal(t, "", adminClient.GetKafkaSecretName("TestTopicName1"))
+
+	// Verify Topics Are Not Managed Without Selecting Their Kafka Secret
+	topicError := adminClient.CreateTopic(ctx, "TestTopicName", &topicDetail)
+	assert.NotNil(t, topicError)
+	assert.Equal(t, sarama.ErrUnknown, topicError.Err)
+	topicError = adminClient.DeleteTopic(ctx, "TestTopicName")
+	assert.NotNil(t, topicError)
+	assert.Equal(t, sarama.ErrUnknown, topicError.Err)
+
+	// Verify The Topics Of All The Kafka Secrets Are Listed
+	topics, err := adminClient.ListTopics(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]sarama.TopicDetail{"TestTopicName1": topicDetail, "TestTopicName2": topicDetail}, topics)
+
+	// Verify All The AdminClients Are Closed (Returning The Error)
+	assert.NotNil(t, adminClient.Close())
+	mockClusterAdmin1.AssertCalled(t, "Close")
+	mockClusterAdmin2.AssertCalled(t, "Close")
+}
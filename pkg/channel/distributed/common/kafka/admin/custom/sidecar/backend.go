@@ -0,0 +1,81 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecar
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/Shopify/sarama"
+)
+
+//
+// Custom Sidecar Backend
+//
+// The Backend performs the Topic creation / deletion requested of the sidecar by the "custom" AdminClient.  Its
+// errors are expected to be (or to wrap) Sarama KErrors or TopicErrors, which the Server maps to the HTTP status
+// codes expected by the AdminClient (e.g. ErrTopicAlreadyExists to 409 Conflict).  Additional behavior, such as
+// governance checks of the requested Topics, may be implemented by a Backend wrapping another one.
+//
+type Backend interface {
+	CreateTopic(ctx context.Context, kafkaSecretName string, topicName string, topicDetail *sarama.TopicDetail) error
+	DeleteTopic(ctx context.Context, kafkaSecretName string, topicName string) error
+}
+
+// Error Returned For Requests Whose Kafka Secret (Cluster) Is Not Served By The Backend
+var ErrUnknownKafkaSecret = errors.New("unknown kafka secret")
+
+// Map The Specified Backend Error To The HTTP Status Code Expected By The Custom AdminClient
+func StatusCode(err error) int {
+
+	// Success
+	if err == nil {
+		return http.StatusOK
+	}
+
+	// Requests For An Unknown Kafka Secret Are Invalid
+	if errors.Is(err, ErrUnknownKafkaSecret) {
+		return http.StatusBadRequest
+	}
+
+	// Extract The Kafka Error Code, If Any
+	var kError sarama.KError
+	var topicError *sarama.TopicError
+	if errors.As(err, &topicError) {
+		kError = topicError.Err
+	} else if !errors.As(err, &kError) {
+		return http.StatusInternalServerError
+	}
+
+	// Map The Kafka Error Code
+	switch kError {
+	case sarama.ErrNoError:
+		return http.StatusOK
+	case sarama.ErrTopicAlreadyExists:
+		return http.StatusConflict
+	case sarama.ErrUnknownTopicOrPartition:
+		return http.StatusNotFound
+	case sarama.ErrInvalidTopic, sarama.ErrInvalidPartitions, sarama.ErrInvalidReplicationFactor,
+		sarama.ErrInvalidReplicaAssignment, sarama.ErrInvalidConfig, sarama.ErrInvalidRequest, sarama.ErrPolicyViolation:
+		return http.StatusBadRequest
+	case sarama.ErrTopicAuthorizationFailed, sarama.ErrClusterAuthorizationFailed:
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecar
+
+import (
+	"context"
+	"regexp"
+	"sync"
+
+	"github.com/Shopify/sarama"
+)
+
+// The Legal Kafka Topic Names
+var topicNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9._-]{1,249}$`)
+
+// Verify The MemoryBackend Implements The Backend Interface
+var _ Backend = &MemoryBackend{}
+
+// In-Memory Backend (For Testing) Validating The Requests Like Kafka & Tracking The Topics Of Each Kafka Secret
+type MemoryBackend struct {
+	mutex  sync.Mutex
+	topics map[string]map[string]sarama.TopicDetail // Maps The Kafka Secret Name To Its Topics
+}
+
+// MemoryBackend Constructor
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{topics: make(map[string]map[string]sarama.TopicDetail)}
+}
+
+// Create The Topic In The Kafka Secret, Unless Invalid Or Already Existing
+func (b *MemoryBackend) CreateTopic(_ context.Context, kafkaSecretName string, topicName string, topicDetail *sarama.TopicDetail) error {
+
+	// Validate The Topic Like Kafka
+	if !topicNameRegexp.MatchString(topicName) || topicName == "." || topicName == ".." {
+		return sarama.ErrInvalidTopic
+	} else if topicDetail == nil || topicDetail.NumPartitions < 1 {
+		return sarama.ErrInvalidPartitions
+	} else if topicDetail.ReplicationFactor < 1 {
+		return sarama.ErrInvalidReplicationFactor
+	}
+
+	// Create The Topic If It Doesn't Exist
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	topics := b.topics[kafkaSecretName]
+	if topics == nil {
+		topics = make(map[string]sarama.TopicDetail)
+		b.topics[kafkaSecretName] = topics
+	}
+	if _, ok := topics[topicName]; ok {
+		return sarama.ErrTopicAlreadyExists
+	}
+	topics[topicName] = *topicDetail
+	return nil
+}
+
+// Delete The Topic Of The Kafka Secret, Unless Not Existing
+func (b *MemoryBackend) DeleteTopic(_ context.Context, kafkaSecretName string, topicName string) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if _, ok := b.topics[kafkaSecretName][topicName]; !ok {
+		return sarama.ErrUnknownTopicOrPartition
+	}
+	delete(b.topics[kafkaSecretName], topicName)
+	return nil
+}
+
+// Get (A Copy Of) The Topics Of The Specified Kafka Secret
+func (b *MemoryBackend) Topics(kafkaSecretName string) map[string]sarama.TopicDetail {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	topics := make(map[string]sarama.TopicDetail, len(b.topics[kafkaSecretName]))
+	for topicName, topicDetail := range b.topics[kafkaSecretName] {
+		topics[topicName] = topicDetail
+	}
+	return topics
+}
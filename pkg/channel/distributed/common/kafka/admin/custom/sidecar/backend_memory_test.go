@@ -0,0 +1,66 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecar
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test The MemoryBackend Functionality
+func TestMemoryBackend(t *testing.T) {
+
+	// Test Data
+	ctx := context.TODO()
+	kafkaSecretName1 := "TestKafkaSecretName1"
+	kafkaSecretName2 := "TestKafkaSecretName2"
+	topicName := "TestTopicName"
+	topicDetail := &sarama.TopicDetail{NumPartitions: 4, ReplicationFactor: 2}
+
+	// Create The MemoryBackend To Test
+	backend := NewMemoryBackend()
+
+	// Verify Invalid Topics Are Rejected
+	assert.Equal(t, sarama.ErrInvalidTopic, backend.CreateTopic(ctx, kafkaSecretName1, "", topicDetail))
+	assert.Equal(t, sarama.ErrInvalidTopic, backend.CreateTopic(ctx, kafkaSecretName1, "Invalid/Topic", topicDetail))
+	assert.Equal(t, sarama.ErrInvalidTopic, backend.CreateTopic(ctx, kafkaSecretName1, "..", topicDetail))
+	assert.Equal(t, sarama.ErrInvalidPartitions, backend.CreateTopic(ctx, kafkaSecretName1, topicName, nil))
+	assert.Equal(t, sarama.ErrInvalidPartitions, backend.CreateTopic(ctx, kafkaSecretName1, topicName, &sarama.TopicDetail{ReplicationFactor: 1}))
+	assert.Equal(t, sarama.ErrInvalidReplicationFactor, backend.CreateTopic(ctx, kafkaSecretName1, topicName, &sarama.TopicDetail{NumPartitions: 1}))
+	assert.Empty(t, backend.Topics(kafkaSecretName1))
+
+	// Verify The Topic Creation Per Kafka Secret
+	assert.Nil(t, backend.CreateTopic(ctx, kafkaSecretName1, topicName, topicDetail))
+	assert.Equal(t, sarama.ErrTopicAlreadyExists, backend.CreateTopic(ctx, kafkaSecretName1, topicName, topicDetail))
+	assert.Nil(t, backend.CreateTopic(ctx, kafkaSecretName2, topicName, topicDetail))
+	assert.Equal(t, map[string]sarama.TopicDetail{topicName: *topicDetail}, backend.Topics(kafkaSecretName1))
+	assert.Equal(t, map[string]sarama.TopicDetail{topicName: *topicDetail}, backend.Topics(kafkaSecretName2))
+
+	// Verify The Returned Topics Are A Copy
+	delete(backend.Topics(kafkaSecretName1), topicName)
+	assert.Len(t, backend.Topics(kafkaSecretName1), 1)
+
+	// Verify The Topic Deletion Per Kafka Secret
+	assert.Nil(t, backend.DeleteTopic(ctx, kafkaSecretName1, topicName))
+	assert.Equal(t, sarama.ErrUnknownTopicOrPartition, backend.DeleteTopic(ctx, kafkaSecretName1, topicName))
+	assert.Equal(t, sarama.ErrUnknownTopicOrPartition, backend.DeleteTopic(ctx, "UnknownKafkaSecretName", topicName))
+	assert.Empty(t, backend.Topics(kafkaSecretName1))
+	assert.Len(t, backend.Topics(kafkaSecretName2), 1)
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecar
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Shopify/sarama"
+)
+
+// The Kafka Secret Name Whose ClusterAdmin Serves The Requests Of Any Kafka Secret Without Its Own
+const DefaultKafkaSecretName = ""
+
+// Verify The SaramaBackend Implements The Backend Interface
+var _ Backend = &SaramaBackend{}
+
+// Backend Forwarding The Requests To The Sarama ClusterAdmin Of Their Kafka Secret (Cluster)
+type SaramaBackend struct {
+	clusterAdmins map[string]sarama.ClusterAdmin
+}
+
+//
+// SaramaBackend Constructor
+//
+// The ClusterAdmins are mapped by the name of their Kafka Secret, the ClusterAdmin of the DefaultKafkaSecretName
+// serving the requests of any other Kafka Secret (e.g. a single ClusterAdmin for all of them).  The ClusterAdmins
+// remain owned (and closed) by the caller.
+//
+func NewSaramaBackend(clusterAdmins map[string]sarama.ClusterAdmin) *SaramaBackend {
+	return &SaramaBackend{clusterAdmins: clusterAdmins}
+}
+
+// Create The Topic Via The ClusterAdmin Of The Kafka Secret
+func (b *SaramaBackend) CreateTopic(_ context.Context, kafkaSecretName string, topicName string, topicDetail *sarama.TopicDetail) error {
+	clusterAdmin, err := b.clusterAdmin(kafkaSecretName)
+	if err != nil {
+		return err
+	}
+	return clusterAdmin.CreateTopic(topicName, topicDetail, false)
+}
+
+// Delete The Topic Via The ClusterAdmin Of The Kafka Secret
+func (b *SaramaBackend) DeleteTopic(_ context.Context, kafkaSecretName string, topicName string) error {
+	clusterAdmin, err := b.clusterAdmin(kafkaSecretName)
+	if err != nil {
+		return err
+	}
+	return clusterAdmin.DeleteTopic(topicName)
+}
+
+// Get The ClusterAdmin Of The Specified Kafka Secret, Else The Default One
+func (b *SaramaBackend) clusterAdmin(kafkaSecretName string) (sarama.ClusterAdmin, error) {
+	if clusterAdmin, ok := b.clusterAdmins[kafkaSecretName]; ok {
+		return clusterAdmin, nil
+	} else if clusterAdmin, ok := b.clusterAdmins[DefaultKafkaSecretName]; ok {
+		return clusterAdmin, nil
+	}
+	return nil, fmt.Errorf("%w '%s'", ErrUnknownKafkaSecret, kafkaSecretName)
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecar
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	sidecartesting "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/admin/custom/sidecar/testing"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test The SaramaBackend Routing Of Requests To The ClusterAdmin Of Their Kafka Secret
+func TestSaramaBackend(t *testing.T) {
+
+	// Test Data
+	ctx := context.TODO()
+	topicName := "TestTopicName"
+	topicDetail := &sarama.TopicDetail{NumPartitions: 1, ReplicationFactor: 1}
+
+	// Create The Fake ClusterAdmins
+	clusterAdmin := newFakeClusterAdmin()
+	defaultClusterAdmin := newFakeClusterAdmin()
+
+	// Verify Requests Of Unknown Kafka Secrets Fail Without A Default ClusterAdmin
+	backend := NewSaramaBackend(map[string]sarama.ClusterAdmin{"TestKafkaSecretName": clusterAdmin})
+	err := backend.CreateTopic(ctx, "UnknownKafkaSecretName", topicName, topicDetail)
+	assert.True(t, errors.Is(err, ErrUnknownKafkaSecret))
+	err = backend.DeleteTopic(ctx, "UnknownKafkaSecretName", topicName)
+	assert.True(t, errors.Is(err, ErrUnknownKafkaSecret))
+
+	// Verify Requests Are Forwarded To The ClusterAdmin Of Their Kafka Secret, Else The Default One
+	backend = NewSaramaBackend(map[string]sarama.ClusterAdmin{"TestKafkaSecretName": clusterAdmin, DefaultKafkaSecretName: defaultClusterAdmin})
+	assert.Nil(t, backend.CreateTopic(ctx, "TestKafkaSecretName", topicName, topicDetail))
+	assert.Nil(t, backend.CreateTopic(ctx, "OtherKafkaSecretName", topicName, topicDetail))
+	assert.Len(t, clusterAdmin.memoryBackend.Topics(""), 1)
+	assert.Len(t, defaultClusterAdmin.memoryBackend.Topics(""), 1)
+	assert.Nil(t, backend.DeleteTopic(ctx, "OtherKafkaSecretName", topicName))
+	assert.Len(t, clusterAdmin.memoryBackend.Topics(""), 1)
+	assert.Empty(t, defaultClusterAdmin.memoryBackend.Topics(""))
+
+	// Verify The ClusterAdmin Errors Are Returned
+	err = backend.CreateTopic(ctx, "TestKafkaSecretName", topicName, topicDetail)
+	assert.Equal(t, &sarama.TopicError{Err: sarama.ErrTopicAlreadyExists}, err)
+	err = backend.DeleteTopic(ctx, "OtherKafkaSecretName", topicName)
+	assert.Equal(t, sarama.ErrUnknownTopicOrPartition, err)
+}
+
+// Test The Server With A SaramaBackend Against The Custom Sidecar Contract
+func TestSaramaBackendContract(t *testing.T) {
+	backend := NewSaramaBackend(map[string]sarama.ClusterAdmin{DefaultKafkaSecretName: newFakeClusterAdmin()})
+	server := httptest.NewServer(NewServer(logtesting.TestLogger(t).Desugar(), backend))
+	defer server.Close()
+	contract := &sidecartesting.Contract{URL: server.URL, KafkaSecretName: "TestKafkaSecretName"}
+	contract.Run(t)
+}
+
+//
+// Fake Sarama ClusterAdmin
+//
+
+// Fake Sarama ClusterAdmin Storing Topics In A MemoryBackend & Returning Errors Like The Real One
+type fakeClusterAdmin struct {
+	sarama.ClusterAdmin // Unimplemented Functions Will Panic
+	memoryBackend       *MemoryBackend
+}
+
+// Fake Sarama ClusterAdmin Constructor
+func newFakeClusterAdmin() *fakeClusterAdmin {
+	return &fakeClusterAdmin{memoryBackend: NewMemoryBackend()}
+}
+
+// The Real ClusterAdmin Returns A TopicError When Topic Creation Fails
+func (f *fakeClusterAdmin) CreateTopic(topic string, detail *sarama.TopicDetail, _ bool) error {
+	if err := f.memoryBackend.CreateTopic(context.TODO(), "", topic, detail); err != nil {
+		return &sarama.TopicError{Err: err.(sarama.KError)}
+	}
+	return nil
+}
+
+// The Real ClusterAdmin Returns A KError When Topic Deletion Fails
+func (f *fakeClusterAdmin) DeleteTopic(topic string) error {
+	return f.memoryBackend.DeleteTopic(context.TODO(), "", topic)
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecar
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test The StatusCode() Functionality
+func TestStatusCode(t *testing.T) {
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name     string
+		err      error
+		expected int
+	}
+
+	// Create The TestCases
+	testCases := []TestCase{
+		{name: "Nil", err: nil, expected: http.StatusOK},
+		{name: "No Error", err: sarama.ErrNoError, expected: http.StatusOK},
+		{name: "Topic Already Exists", err: sarama.ErrTopicAlreadyExists, expected: http.StatusConflict},
+		{name: "Topic Already Exists TopicError", err: &sarama.TopicError{Err: sarama.ErrTopicAlreadyExists}, expected: http.StatusConflict},
+		{name: "Unknown Topic", err: sarama.ErrUnknownTopicOrPartition, expected: http.StatusNotFound},
+		{name: "Wrapped Unknown Topic", err: fmt.Errorf("wrapped: %w", sarama.ErrUnknownTopicOrPartition), expected: http.StatusNotFound},
+		{name: "Invalid Topic", err: sarama.ErrInvalidTopic, expected: http.StatusBadRequest},
+		{name: "Invalid Partitions", err: &sarama.TopicError{Err: sarama.ErrInvalidPartitions}, expected: http.StatusBadRequest},
+		{name: "Invalid Replication Factor", err: sarama.ErrInvalidReplicationFactor, expected: http.StatusBadRequest},
+		{name: "Policy Violation", err: sarama.ErrPolicyViolation, expected: http.StatusBadRequest},
+		{name: "Unknown Kafka Secret", err: fmt.Errorf("%w 'foo'", ErrUnknownKafkaSecret), expected: http.StatusBadRequest},
+		{name: "Authorization Failed", err: sarama.ErrTopicAuthorizationFailed, expected: http.StatusForbidden},
+		{name: "Other Kafka Error", err: sarama.ErrRequestTimedOut, expected: http.StatusInternalServerError},
+		{name: "Other Error", err: errors.New("other"), expected: http.StatusInternalServerError},
+	}
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, StatusCode(testCase.err))
+		})
+	}
+}
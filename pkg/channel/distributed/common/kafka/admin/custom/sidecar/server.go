@@ -0,0 +1,158 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecar
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/admin/custom"
+)
+
+//
+// This package is a reference implementation of the REST sidecar of the "custom" AdminClient (see the "Custom
+// (REST Sidecar)" section of the common/kafka README.md), serving its Topic creation / deletion requests with a
+// pluggable Backend.  A sidecar only has to provide the Backend...
+//
+//   server := sidecar.NewServer(logger, sidecar.NewSaramaBackend(clusterAdmins))
+//   err := server.ListenAndServe(ctx, "")
+//
+
+// The Maximum Size Of A Topic Creation Request Body
+const maxRequestBodyBytes = 1 << 20
+
+// Custom Sidecar Server Implementing The Endpoints Called By The Custom AdminClient
+type Server struct {
+	logger  *zap.Logger
+	backend Backend
+}
+
+// Verify The Server Implements The HTTP Handler Interface
+var _ http.Handler = &Server{}
+
+// Custom Sidecar Server Constructor
+func NewServer(logger *zap.Logger, backend Backend) *Server {
+	return &Server{logger: logger, backend: backend}
+}
+
+// Serve Requests On The Specified Address (The Address Called By The Custom AdminClient If Empty) Until The Context Is Done
+func (s *Server) ListenAndServe(ctx context.Context, address string) error {
+
+	// Default To The Address Called By The Custom AdminClient
+	if len(address) == 0 {
+		address = net.JoinHostPort(custom.SidecarHost, custom.SidecarPort)
+	}
+
+	// Shutdown The HTTP Server Once The Context Is Done
+	httpServer := &http.Server{Addr: address, Handler: s}
+	go func() {
+		<-ctx.Done()
+		if err := httpServer.Shutdown(context.Background()); err != nil {
+			s.logger.Error("Failed To Shutdown Custom Sidecar Server", zap.Error(err))
+		}
+	}()
+
+	// Serve Requests Until Shutdown
+	s.logger.Info("Starting Custom Sidecar Server", zap.String("Address", address))
+	err := httpServer.ListenAndServe()
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
+
+// Route The Topic Creation (POST /topics) & Deletion (DELETE /topics/<topic-name>) Requests
+func (s *Server) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	switch {
+	case request.URL.Path == custom.TopicsPath:
+		if request.Method != http.MethodPost {
+			writer.Header().Set("Allow", http.MethodPost)
+			http.Error(writer, fmt.Sprintf("method %s not allowed", request.Method), http.StatusMethodNotAllowed)
+			return
+		}
+		s.createTopic(writer, request)
+	case strings.HasPrefix(request.URL.Path, custom.TopicsPath+"/"):
+		if request.Method != http.MethodDelete {
+			writer.Header().Set("Allow", http.MethodDelete)
+			http.Error(writer, fmt.Sprintf("method %s not allowed", request.Method), http.StatusMethodNotAllowed)
+			return
+		}
+		s.deleteTopic(writer, request)
+	default:
+		http.NotFound(writer, request)
+	}
+}
+
+// Create The Topic Named By The "Slug" Header, With The TopicDetail Of The JSON Body
+func (s *Server) createTopic(writer http.ResponseWriter, request *http.Request) {
+
+	// Get The Topic Name & Kafka Secret From The Headers
+	topicName := request.Header.Get(custom.TopicNameHeader)
+	kafkaSecretName := request.Header.Get(custom.KafkaSecretHeader)
+	logger := s.logger.With(zap.String("Topic", topicName), zap.String("KafkaSecret", kafkaSecretName))
+	if len(topicName) == 0 {
+		logger.Warn("Received Topic Creation Request Without Topic Name")
+		http.Error(writer, fmt.Sprintf("missing topic name in %s header", custom.TopicNameHeader), http.StatusBadRequest)
+		return
+	}
+
+	// Parse The TopicDetail From The Body
+	topicDetail := &custom.TopicDetail{}
+	decoder := json.NewDecoder(http.MaxBytesReader(writer, request.Body, maxRequestBodyBytes))
+	if err := decoder.Decode(topicDetail); err != nil {
+		logger.Warn("Received Topic Creation Request With Invalid TopicDetail", zap.Error(err))
+		http.Error(writer, fmt.Sprintf("invalid topic detail: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	// Create The Topic Via The Backend
+	if err := s.backend.CreateTopic(request.Context(), kafkaSecretName, topicName, topicDetail.ToSaramaTopicDetail()); err != nil {
+		logger.Info("Failed To Create Topic", zap.Error(err))
+		http.Error(writer, err.Error(), StatusCode(err))
+		return
+	}
+	logger.Info("Successfully Created Topic")
+	writer.WriteHeader(http.StatusCreated)
+}
+
+// Delete The Topic Named By The Last Path Segment
+func (s *Server) deleteTopic(writer http.ResponseWriter, request *http.Request) {
+
+	// Get The Topic Name From The Path & The Kafka Secret From The Header
+	topicName := strings.TrimPrefix(request.URL.Path, custom.TopicsPath+"/")
+	kafkaSecretName := request.Header.Get(custom.KafkaSecretHeader)
+	logger := s.logger.With(zap.String("Topic", topicName), zap.String("KafkaSecret", kafkaSecretName))
+	if len(topicName) == 0 || strings.Contains(topicName, "/") {
+		logger.Warn("Received Topic Deletion Request With Invalid Topic Name")
+		http.Error(writer, fmt.Sprintf("invalid topic name '%s'", topicName), http.StatusBadRequest)
+		return
+	}
+
+	// Delete The Topic Via The Backend
+	if err := s.backend.DeleteTopic(request.Context(), kafkaSecretName, topicName); err != nil {
+		logger.Info("Failed To Delete Topic", zap.Error(err))
+		http.Error(writer, err.Error(), StatusCode(err))
+		return
+	}
+	logger.Info("Successfully Deleted Topic")
+	writer.WriteHeader(http.StatusNoContent)
+}
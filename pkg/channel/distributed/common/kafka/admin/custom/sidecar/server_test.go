@@ -0,0 +1,131 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecar
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/admin/custom"
+	sidecartesting "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/admin/custom/sidecar/testing"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test The Server's Handling Of Topic Creation / Deletion Requests
+func TestServer(t *testing.T) {
+
+	// Test Data
+	kafkaSecretName := "TestKafkaSecretName"
+	topicName := "TestTopicName"
+	validBody := `{"numPartitions":4,"replicationFactor":2,"configEntries":{"retention.ms":"86400000"}}`
+
+	// Define The TestCase Struct
+	type TestCase struct {
+		name          string
+		method        string
+		path          string
+		topicName     string
+		body          string
+		expectedCode  int
+		expectedTopic bool
+	}
+
+	// Create The TestCases (Run In Order Against The Same Backend)
+	testCases := []TestCase{
+		{name: "Create Topic", method: http.MethodPost, path: custom.TopicsPath, topicName: topicName, body: validBody, expectedCode: http.StatusCreated, expectedTopic: true},
+		{name: "Create Existing Topic", method: http.MethodPost, path: custom.TopicsPath, topicName: topicName, body: validBody, expectedCode: http.StatusConflict, expectedTopic: true},
+		{name: "Create Topic Without Name", method: http.MethodPost, path: custom.TopicsPath, body: validBody, expectedCode: http.StatusBadRequest, expectedTopic: true},
+		{name: "Create Topic With Invalid Body", method: http.MethodPost, path: custom.TopicsPath, topicName: "OtherTopicName", body: "invalid", expectedCode: http.StatusBadRequest, expectedTopic: true},
+		{name: "Create Topic With Invalid Partitions", method: http.MethodPost, path: custom.TopicsPath, topicName: "OtherTopicName", body: `{"replicationFactor":1}`, expectedCode: http.StatusBadRequest, expectedTopic: true},
+		{name: "Invalid Topics Method", method: http.MethodGet, path: custom.TopicsPath, expectedCode: http.StatusMethodNotAllowed, expectedTopic: true},
+		{name: "Invalid Topic Method", method: http.MethodPost, path: custom.TopicsPath + "/" + topicName, body: validBody, expectedCode: http.StatusMethodNotAllowed, expectedTopic: true},
+		{name: "Invalid Path", method: http.MethodPost, path: "/invalid", body: validBody, expectedCode: http.StatusNotFound, expectedTopic: true},
+		{name: "Delete Topic Without Name", method: http.MethodDelete, path: custom.TopicsPath + "/", expectedCode: http.StatusBadRequest, expectedTopic: true},
+		{name: "Delete Topic", method: http.MethodDelete, path: custom.TopicsPath + "/" + topicName, expectedCode: http.StatusNoContent, expectedTopic: false},
+		{name: "Delete Unknown Topic", method: http.MethodDelete, path: custom.TopicsPath + "/" + topicName, expectedCode: http.StatusNotFound, expectedTopic: false},
+	}
+
+	// Create The Server To Test With A MemoryBackend
+	backend := NewMemoryBackend()
+	server := NewServer(logtesting.TestLogger(t).Desugar(), backend)
+
+	// Run The TestCases
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+
+			// Create The Request
+			request := httptest.NewRequest(testCase.method, testCase.path, strings.NewReader(testCase.body))
+			request.Header.Set(custom.KafkaSecretHeader, kafkaSecretName)
+			if len(testCase.topicName) > 0 {
+				request.Header.Set(custom.TopicNameHeader, testCase.topicName)
+			}
+
+			// Perform The Test
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, request)
+
+			// Verify The Results
+			assert.Equal(t, testCase.expectedCode, recorder.Code)
+			topics := backend.Topics(kafkaSecretName)
+			if testCase.expectedTopic {
+				assert.Equal(t, map[string]sarama.TopicDetail{topicName: {
+					NumPartitions:     4,
+					ReplicationFactor: 2,
+					ConfigEntries:     map[string]*string{"retention.ms": topics[topicName].ConfigEntries["retention.ms"]},
+				}}, topics)
+				assert.Equal(t, "86400000", *topics[topicName].ConfigEntries["retention.ms"])
+			} else {
+				assert.Empty(t, topics)
+			}
+		})
+	}
+}
+
+// Test The Server With A MemoryBackend Against The Custom Sidecar Contract
+func TestMemoryBackendContract(t *testing.T) {
+	server := httptest.NewServer(NewServer(logtesting.TestLogger(t).Desugar(), NewMemoryBackend()))
+	defer server.Close()
+	contract := &sidecartesting.Contract{URL: server.URL, KafkaSecretName: "TestKafkaSecretName"}
+	contract.Run(t)
+}
+
+// Test The Server's ListenAndServe() Shutdown When The Context Is Done
+func TestServerListenAndServe(t *testing.T) {
+
+	// Create The Server To Test & A Cancellable Context
+	server := NewServer(logtesting.TestLogger(t).Desugar(), NewMemoryBackend())
+	ctx, cancel := context.WithCancel(context.TODO())
+
+	// Perform The Test
+	errChan := make(chan error)
+	go func() { errChan <- server.ListenAndServe(ctx, "127.0.0.1:0") }()
+	cancel()
+
+	// Verify The Server Was Shutdown Gracefully
+	select {
+	case err := <-errChan:
+		assert.Nil(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("ListenAndServe() did not return after the context was done")
+	}
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testing
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/admin/custom"
+)
+
+// The Default Prefix Of The Topics Created By The Contract Tests
+const DefaultTopicPrefix = "custom-sidecar-contract-"
+
+//
+// Contract Test Suite Of The Sidecars Of The "custom" AdminClient
+//
+// The Contract issues the same requests as the "custom" AdminClient against a running sidecar, verifying that
+// it responds with the HTTP status codes the AdminClient relies upon.  Any sidecar implementation can run it from
+// its own tests...
+//
+//   contract := &testing.Contract{URL: "http://localhost:8888", KafkaSecretName: "kafka-credentials"}
+//   contract.Run(t)
+//
+// The Topics created by the Contract are deleted by it, but are real Topics when the sidecar fronts a real Kafka
+// cluster, so a dedicated TopicPrefix may be desirable.
+//
+type Contract struct {
+	URL             string       // The Base URL Of The Sidecar (e.g. "http://localhost:8888")
+	KafkaSecretName string       // The Kafka Secret Name Sent With Each Request
+	TopicPrefix     string       // The Prefix Of The Created Topics (DefaultTopicPrefix If Empty)
+	Client          *http.Client // The HTTP Client Issuing The Requests (Using The custom.SidecarTimeout If Nil)
+}
+
+// Run The Contract Tests Against The Sidecar
+func (c *Contract) Run(t *testing.T) {
+
+	// Unique Topic Names For This Run
+	topicName := c.topicName("topic")
+	unknownTopicName := c.topicName("unknown")
+
+	// The Valid TopicDetail Used By The Tests
+	retentionMillis := strconv.FormatInt((24 * time.Hour).Milliseconds(), 10)
+	topicDetail := custom.NewTopicDetail(1, 1, nil, map[string]*string{"retention.ms": &retentionMillis})
+
+	// Ensure The Topic Is Deleted Regardless Of The Test Results
+	defer func() { _, _ = c.deleteTopic(topicName) }()
+
+	t.Run("Create Topic", func(t *testing.T) {
+		statusCode, err := c.createTopic(topicName, topicDetail)
+		c.expectSuccess(t, statusCode, err)
+	})
+
+	t.Run("Create Existing Topic", func(t *testing.T) {
+		statusCode, err := c.createTopic(topicName, topicDetail)
+		c.expectStatus(t, http.StatusConflict, statusCode, err)
+	})
+
+	t.Run("Delete Topic", func(t *testing.T) {
+		statusCode, err := c.deleteTopic(topicName)
+		c.expectSuccess(t, statusCode, err)
+	})
+
+	t.Run("Delete Unknown Topic", func(t *testing.T) {
+		statusCode, err := c.deleteTopic(unknownTopicName)
+		c.expectStatus(t, http.StatusNotFound, statusCode, err)
+	})
+
+	t.Run("Create Topic Without Name", func(t *testing.T) {
+		statusCode, err := c.createTopic("", topicDetail)
+		c.expectClientError(t, statusCode, err)
+	})
+
+	t.Run("Create Topic With Invalid Body", func(t *testing.T) {
+		statusCode, err := c.post(unknownTopicName, []byte("invalid"))
+		c.expectClientError(t, statusCode, err)
+	})
+}
+
+// Get A Unique Topic Name For The Specified Purpose
+func (c *Contract) topicName(purpose string) string {
+	prefix := c.TopicPrefix
+	if len(prefix) == 0 {
+		prefix = DefaultTopicPrefix
+	}
+	return prefix + purpose + "-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+}
+
+// Request The Topic Creation Exactly Like The Custom AdminClient
+func (c *Contract) createTopic(topicName string, topicDetail *custom.TopicDetail) (int, error) {
+	body, err := json.Marshal(topicDetail)
+	if err != nil {
+		return 0, err
+	}
+	return c.post(topicName, body)
+}
+
+// POST The Specified Body For The Topic
+func (c *Contract) post(topicName string, body []byte) (int, error) {
+	request, err := http.NewRequest(http.MethodPost, c.URL+custom.TopicsPath, bytes.NewBuffer(body))
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if len(topicName) > 0 {
+		request.Header.Set(custom.TopicNameHeader, topicName)
+	}
+	return c.do(request)
+}
+
+// Request The Topic Deletion Exactly Like The Custom AdminClient
+func (c *Contract) deleteTopic(topicName string) (int, error) {
+	request, err := http.NewRequest(http.MethodDelete, c.URL+custom.TopicsPath+"/"+topicName, nil)
+	if err != nil {
+		return 0, err
+	}
+	return c.do(request)
+}
+
+// Perform The Request (With The Kafka Secret Header) & Return The Response Status Code
+func (c *Contract) do(request *http.Request) (int, error) {
+	if len(c.KafkaSecretName) > 0 {
+		request.Header.Set(custom.KafkaSecretHeader, c.KafkaSecretName)
+	}
+	client := c.Client
+	if client == nil {
+		client = &http.Client{Timeout: custom.SidecarTimeout}
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, response.Body)
+	return response.StatusCode, nil
+}
+
+// Expect A 2XX Response
+func (c *Contract) expectSuccess(t *testing.T, statusCode int, err error) {
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	} else if statusCode < 200 || statusCode > 299 {
+		t.Errorf("expected a 2XX status code, got %d", statusCode)
+	}
+}
+
+// Expect A Response With The Specified Status Code
+func (c *Contract) expectStatus(t *testing.T, expectedStatusCode int, statusCode int, err error) {
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	} else if statusCode != expectedStatusCode {
+		t.Errorf("expected status code %d, got %d", expectedStatusCode, statusCode)
+	}
+}
+
+// Expect A 4XX Response Which The Custom AdminClient Won't Mistake For An Existing / Unknown Topic
+func (c *Contract) expectClientError(t *testing.T, statusCode int, err error) {
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	} else if statusCode < 400 || statusCode > 499 || statusCode == http.StatusConflict || statusCode == http.StatusNotFound {
+		t.Errorf("expected a 4XX status code other than %d and %d, got %d", http.StatusConflict, http.StatusNotFound, statusCode)
+	}
+}
@@ -20,6 +20,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
+	"sync"
+	"time"
 
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
@@ -33,6 +36,8 @@ import (
 /* ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
    This package implements a cache of the state of Azure EventHub Namespaces.  This is necessary to facilitate the
    usage of the Azure Namespace grouping of EventHubs (which each have their own Connection String / Credentials).
+   The cache is safe for concurrent use, and may be kept up to date with the EventHubs created / deleted outside
+   of the AdminClient (e.g. in the Azure Portal) by periodically refreshing it (see Refresh()).
    ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~ */
 
 // Define An Interface For The EventHub Cache
@@ -47,13 +52,19 @@ type CacheInterface interface {
 // Verify The Cache Struct Implements The Interface
 var _ CacheInterface = &Cache{}
 
+// The Default Interval At Which The Cache Is Refreshed From K8S & Azure
+const DefaultRefreshInterval = 5 * time.Minute
+
 // Azure EventHubs Cache Struct
 type Cache struct {
 	logger       *zap.Logger
 	k8sClient    kubernetes.Interface
 	k8sNamespace string
+	mutex        sync.RWMutex          // Guards The Fields Below & The Count Of The Namespaces
 	namespaceMap map[string]*Namespace // Map Of The Azure Namespace Name To Namespace Struct
 	eventhubMap  map[string]*Namespace // Maps The Azure EventHub Name To It's Namespace Struct
+	updates      int                   // The Number Of Updates In Progress
+	changes      map[string]*Namespace // The EventHubs Added (Or Removed If Nil) During The Updates In Progress
 }
 
 // Azure EventHubs Cache Constructor
@@ -75,9 +86,17 @@ func NewCache(ctx context.Context, k8sNamespace string) CacheInterface {
 	}
 }
 
-// Update The Cache From K8S & Azure
+// Update The Cache From K8S & Azure (Replacing Its Content Only Once Fully Loaded)
 func (c *Cache) Update(ctx context.Context) error {
 
+	// Track The EventHubs Added / Removed Meanwhile, Which The Loaded Maps May Have Missed
+	c.startUpdate()
+	defer c.endUpdate()
+
+	// Load The Namespaces & EventHubs Into New Maps, So That The Cache Remains Usable Meanwhile
+	namespaceMap := make(map[string]*Namespace)
+	eventhubMap := make(map[string]*Namespace)
+
 	// Get A List Of The Kafka Secrets From The K8S Namespace
 	kafkaSecrets, err := util.GetKafkaSecrets(ctx, c.k8sClient, c.k8sNamespace)
 	if err != nil {
@@ -103,7 +122,7 @@ func (c *Cache) Update(ctx context.Context) error {
 		}
 
 		// Add The Namespace To The Namespace Map
-		c.namespaceMap[namespace.Name] = namespace
+		namespaceMap[namespace.Name] = namespace
 
 		// List The EventHubs For The Namespace
 		eventHubs, err := namespace.HubManager.List(ctx)
@@ -116,11 +135,27 @@ func (c *Cache) Update(ctx context.Context) error {
 		for _, eventHub := range eventHubs {
 
 			// Add The EventHub To The Namespace & Increment The Namespace EventHub Count
-			c.eventhubMap[eventHub.Name] = namespace
+			eventhubMap[eventHub.Name] = namespace
 			namespace.Count = namespace.Count + 1
 		}
 	}
 
+	// Replace The Content Of The Cache
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.namespaceMap = namespaceMap
+	c.eventhubMap = eventhubMap
+	for eventhub, namespace := range c.changes {
+		if namespace != nil {
+			c.addEventHub(eventhub, namespace)
+		} else {
+			c.removeEventHub(eventhub)
+		}
+	}
+	for _, namespace := range namespaceMap {
+		reportNamespaceUtilization(namespace)
+	}
+
 	// Log Some Basic Cache Information
 	c.logger.Info("Updating EventHub Cache",
 		zap.Any("Namespaces", c.getNamespaceNames()),
@@ -134,39 +169,110 @@ func (c *Cache) Update(ctx context.Context) error {
 // Add The Specified EventHub / Namespace To The Cache
 func (c *Cache) AddEventHub(ctx context.Context, eventhub string, namespace *Namespace) {
 	if namespace != nil {
-		namespace.Count = namespace.Count + 1
-		c.eventhubMap[eventhub] = namespace
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+		if c.changes != nil {
+			c.changes[eventhub] = namespace
+		}
+		c.addEventHub(eventhub, namespace)
 	}
 }
 
 // Remove The Specified EventHub / Namespace From The Cache
 func (c *Cache) RemoveEventHub(ctx context.Context, eventhub string) {
-	namespace := c.GetNamespace(eventhub)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.changes != nil {
+		c.changes[eventhub] = nil
+	}
+	c.removeEventHub(eventhub)
+}
+
+// Add The Specified EventHub / Namespace To The Maps (The Mutex Must Be Locked)
+func (c *Cache) addEventHub(eventhub string, namespace *Namespace) {
+
+	// Use The Current Namespace Of The Same Name (The Specified One May Predate A Refresh)
+	if currentNamespace := c.namespaceMap[namespace.Name]; currentNamespace != nil {
+		namespace = currentNamespace
+	}
+
+	// Count The EventHub Only Once (A Refresh May Already Have Found It)
+	previousNamespace := c.eventhubMap[eventhub]
+	if previousNamespace == namespace {
+		return
+	} else if previousNamespace != nil && previousNamespace.Count > 0 {
+		previousNamespace.Count = previousNamespace.Count - 1
+		reportNamespaceUtilization(previousNamespace)
+	}
+	namespace.Count = namespace.Count + 1
+	c.eventhubMap[eventhub] = namespace
+	reportNamespaceUtilization(namespace)
+}
+
+// Remove The Specified EventHub From The Maps (The Mutex Must Be Locked)
+func (c *Cache) removeEventHub(eventhub string) {
+	namespace := c.eventhubMap[eventhub]
 	if namespace != nil && namespace.Count > 0 {
 		namespace.Count = namespace.Count - 1
+		reportNamespaceUtilization(namespace)
 	}
 	delete(c.eventhubMap, eventhub)
 }
 
+// Start Tracking The EventHubs Added / Removed During An Update
+func (c *Cache) startUpdate() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.updates == 0 {
+		c.changes = make(map[string]*Namespace)
+	}
+	c.updates = c.updates + 1
+}
+
+// Stop Tracking The EventHubs Added / Removed Once No Update Is In Progress
+func (c *Cache) endUpdate() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.updates = c.updates - 1
+	if c.updates == 0 {
+		c.changes = nil
+	}
+}
+
 // Get The Namespace Associated With The Specified EventHub (Topic) Name
 func (c *Cache) GetNamespace(eventhub string) *Namespace {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
 	return c.eventhubMap[eventhub]
 }
 
-// Get The Namespace With The Least Number Of EventHubs
+// Get The Namespace With The Least Number Of EventHubs (Excluding Those Which Reached The Capacity Of Their Tier)
 func (c *Cache) GetLeastPopulatedNamespace() *Namespace {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
 
 	// Track The Least Populated Namespace
 	var leastPopulatedNamespace *Namespace
 
-	// Loop Over The Namespaces In The Map
-	for _, namespace := range c.namespaceMap {
+	// Loop Over The Namespaces In The Map (In Name Order For Determinism)
+	namespaceNames := make([]string, 0, len(c.namespaceMap))
+	for namespaceName := range c.namespaceMap {
+		namespaceNames = append(namespaceNames, namespaceName)
+	}
+	sort.Strings(namespaceNames)
+	for _, namespaceName := range namespaceNames {
+		namespace := c.namespaceMap[namespaceName]
 
 		// Skip Any Invalid Data (Precautionary - Shouldn't Happen)
 		if namespace == nil {
 			continue
 		}
 
+		// Skip The Namespaces Which Can't Hold Another EventHub
+		if namespace.Full() {
+			continue
+		}
+
 		// Initialize The Least Populated Namespace If Not Set
 		if leastPopulatedNamespace == nil {
 			leastPopulatedNamespace = namespace
@@ -191,13 +297,32 @@ func (c *Cache) GetLeastPopulatedNamespace() *Namespace {
 			zap.Int("Count", leastPopulatedNamespace.Count),
 		)
 	} else {
-		c.logger.Warn("No Azure EventHub Namespaces In Cache!")
+		c.logger.Warn("No Azure EventHub Namespaces With Available Capacity In Cache!")
 	}
 
 	// Return The Least Populated Namespace
 	return leastPopulatedNamespace
 }
 
+// Periodically Update The Specified Cache Until The Context Is Done (A Non-Positive Interval Disables The Refresh)
+func Refresh(ctx context.Context, cache CacheInterface, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := cache.Update(ctx); err != nil {
+				logging.FromContext(ctx).Desugar().Warn("Failed To Refresh EventHub Cache - Keeping Previous Content", zap.Error(err))
+			}
+		}
+	}
+}
+
 // Utility Function For Validating Kafka Secret
 func (c *Cache) validateKafkaSecret(secret *corev1.Secret) bool {
 
@@ -20,7 +20,9 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	eventhub "github.com/Azure/azure-event-hubs-go/v3"
 	"github.com/stretchr/testify/assert"
@@ -332,10 +334,211 @@ func TestGetLeastPopulatedNamespace(t *testing.T) {
 	assert.Equal(t, namespaceCount3, namespace.Count)
 }
 
+// Test The Cache's GetLeastPopulatedNamespace() Functionality With Namespaces At The Capacity Of Their Tier
+func TestGetLeastPopulatedNamespaceCapacity(t *testing.T) {
+
+	// Create A Test Logger
+	logger := logtesting.TestLogger(t).Desugar()
+
+	// Create A Cache To Test With A Full Namespace (Least Populated) & One With Room Left
+	fullNamespace := &Namespace{Name: "TestNamespaceName1", Count: 10, Capacity: 10}
+	availableNamespace := &Namespace{Name: "TestNamespaceName2", Count: 99, Capacity: 100}
+	cache := &Cache{
+		logger:       logger,
+		namespaceMap: map[string]*Namespace{fullNamespace.Name: fullNamespace, availableNamespace.Name: availableNamespace},
+	}
+
+	// Verify The Namespace With Room Left Is Returned Until It Is Full As Well
+	assert.Equal(t, availableNamespace, cache.GetLeastPopulatedNamespace())
+	availableNamespace.Count = 100
+	assert.Nil(t, cache.GetLeastPopulatedNamespace())
+}
+
+// Test The Cache's AddEventHub() Functionality With An EventHub / Namespace Already Refreshed
+func TestAddEventHubAfterRefresh(t *testing.T) {
+
+	// Create A Cache To Test Whose Namespace Was Refreshed (Replaced) After The AdminClient Selected It
+	staleNamespace := &Namespace{Name: "TestNamespaceName"}
+	currentNamespace := &Namespace{Name: "TestNamespaceName", Count: 1}
+	cache := &Cache{
+		logger:       logtesting.TestLogger(t).Desugar(),
+		namespaceMap: map[string]*Namespace{currentNamespace.Name: currentNamespace},
+		eventhubMap:  map[string]*Namespace{"TestEventHubName1": currentNamespace},
+	}
+
+	// Perform The Test - Adding A New EventHub And One The Refresh Already Found
+	cache.AddEventHub(context.TODO(), "TestEventHubName2", staleNamespace)
+	cache.AddEventHub(context.TODO(), "TestEventHubName1", staleNamespace)
+
+	// Verify The EventHubs Are Counted Once In The Current Namespace
+	assert.Equal(t, 0, staleNamespace.Count)
+	assert.Equal(t, 2, currentNamespace.Count)
+	assert.Equal(t, currentNamespace, cache.GetNamespace("TestEventHubName2"))
+}
+
+// Test The Cache's Reporting Of The Namespace Utilization Metrics
+func TestNamespaceUtilizationMetrics(t *testing.T) {
+
+	// Replace The Namespace Utilization Reporting To Track The Reported Counts & Defer Reset
+	reported := make(map[string]int)
+	reportNamespaceUtilizationPlaceholder := reportNamespaceUtilization
+	reportNamespaceUtilization = func(namespace *Namespace) {
+		reported[namespace.Name] = namespace.Count
+	}
+	defer func() { reportNamespaceUtilization = reportNamespaceUtilizationPlaceholder }()
+
+	// Create A Cache To Test
+	namespace := &Namespace{Name: "TestNamespaceName", Capacity: 10}
+	cache := &Cache{
+		logger:       logtesting.TestLogger(t).Desugar(),
+		namespaceMap: map[string]*Namespace{namespace.Name: namespace},
+		eventhubMap:  make(map[string]*Namespace),
+	}
+
+	// Perform The Test & Verify The Reported Utilization
+	cache.AddEventHub(context.TODO(), "TestEventHubName1", namespace)
+	cache.AddEventHub(context.TODO(), "TestEventHubName2", namespace)
+	assert.Equal(t, 2, reported[namespace.Name])
+	cache.RemoveEventHub(context.TODO(), "TestEventHubName1")
+	assert.Equal(t, 1, reported[namespace.Name])
+}
+
+// Test The Periodic Refresh() Of The Cache With EventHubs Created Outside The AdminClient
+func TestRefresh(t *testing.T) {
+
+	// Test Data
+	kafkaSecret := createKafkaSecret("TestKafkaSecretName", "TestK8SNamespace", "TestBrokers", "TestUsername", "TestPassword", "TestNamespaceName")
+	hubManager := &fakeHubManager{hubs: map[string]bool{"TestEventHubName1": true}}
+
+	// Replace The NewHubManagerFromConnectionString Wrapper To Provide The Fake HubManager & Defer Reset
+	newHubManagerFromConnectionStringWrapperPlaceholder := NewHubManagerFromConnectionStringWrapper
+	NewHubManagerFromConnectionStringWrapper = func(connectionString string) (managerInterface HubManagerInterface, e error) {
+		return hubManager, nil
+	}
+	defer func() { NewHubManagerFromConnectionStringWrapper = newHubManagerFromConnectionStringWrapperPlaceholder }()
+
+	// Create A Cache To Test & Load It Initially
+	ctx := logging.WithLogger(context.TODO(), logtesting.TestLogger(t))
+	ctx = context.WithValue(ctx, injectionclient.Key{}, fake.NewSimpleClientset(kafkaSecret))
+	cache := NewCache(ctx, "TestK8SNamespace")
+	assert.Nil(t, cache.Update(ctx))
+	assert.NotNil(t, cache.GetNamespace("TestEventHubName1"))
+	assert.Nil(t, cache.GetNamespace("TestEventHubName2"))
+
+	// Start Refreshing The Cache While Using It Concurrently
+	refreshCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go Refresh(refreshCtx, cache, time.Millisecond)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			eventhub := fmt.Sprintf("TestConcurrentEventHubName%d", i)
+			cache.AddEventHub(ctx, eventhub, cache.GetLeastPopulatedNamespace())
+			cache.GetNamespace(eventhub)
+			cache.RemoveEventHub(ctx, eventhub)
+		}(i)
+	}
+	wg.Wait()
+
+	// Create / Delete EventHubs Outside The AdminClient & Verify The Refresh Picks Them Up
+	hubManager.set("TestEventHubName2", true)
+	hubManager.set("TestEventHubName1", false)
+	assert.Eventually(t, func() bool {
+		return cache.GetNamespace("TestEventHubName2") != nil && cache.GetNamespace("TestEventHubName1") == nil
+	}, 5*time.Second, time.Millisecond)
+	namespace := cache.GetLeastPopulatedNamespace()
+	assert.NotNil(t, namespace)
+	assert.Equal(t, "TestNamespaceName", namespace.Name)
+}
+
+// Test That The EventHubs Added / Removed By The AdminClient During An Update Aren't Lost When It Completes
+func TestUpdateWithConcurrentChanges(t *testing.T) {
+
+	// Test Data
+	kafkaSecret := createKafkaSecret("TestKafkaSecretName", "TestK8SNamespace", "TestBrokers", "TestUsername", "TestPassword", "TestNamespaceName")
+	hubManager := &fakeHubManager{hubs: map[string]bool{"TestEventHubName1": true}}
+
+	// Replace The NewHubManagerFromConnectionString Wrapper To Provide The Fake HubManager & Defer Reset
+	newHubManagerFromConnectionStringWrapperPlaceholder := NewHubManagerFromConnectionStringWrapper
+	NewHubManagerFromConnectionStringWrapper = func(connectionString string) (managerInterface HubManagerInterface, e error) {
+		return hubManager, nil
+	}
+	defer func() { NewHubManagerFromConnectionStringWrapper = newHubManagerFromConnectionStringWrapperPlaceholder }()
+
+	// Create A Cache To Test & Load It Initially
+	ctx := logging.WithLogger(context.TODO(), logtesting.TestLogger(t))
+	ctx = context.WithValue(ctx, injectionclient.Key{}, fake.NewSimpleClientset(kafkaSecret))
+	cache := NewCache(ctx, "TestK8SNamespace")
+	assert.Nil(t, cache.Update(ctx))
+
+	// Create & Delete EventHubs Via The AdminClient Once The Next Update Has Listed Them
+	hubManager.listed = func() {
+		hubManager.listed = nil
+		namespace := cache.GetLeastPopulatedNamespace()
+		_, err := namespace.HubManager.Put(ctx, "TestEventHubName2")
+		assert.Nil(t, err)
+		cache.AddEventHub(ctx, "TestEventHubName2", namespace)
+		assert.Nil(t, namespace.HubManager.Delete(ctx, "TestEventHubName1"))
+		cache.RemoveEventHub(ctx, "TestEventHubName1")
+	}
+
+	// Perform The Test & Verify The Changes Survived The Update
+	assert.Nil(t, cache.Update(ctx))
+	namespace := cache.GetNamespace("TestEventHubName2")
+	assert.Nil(t, cache.GetNamespace("TestEventHubName1"))
+	if assert.NotNil(t, namespace) {
+		assert.Equal(t, 1, namespace.Count)
+		assert.Equal(t, namespace, cache.GetLeastPopulatedNamespace())
+	}
+}
+
 //
 // Utilities
 //
 
+// Fake HubManager Whose EventHubs May Be Changed Concurrently (As If Created / Deleted Outside The AdminClient)
+type fakeHubManager struct {
+	mutex  sync.Mutex
+	hubs   map[string]bool
+	listed func() // Called After Each List, As If The AdminClient Changed The EventHubs Meanwhile
+}
+
+func (m *fakeHubManager) set(name string, exists bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if exists {
+		m.hubs[name] = true
+	} else {
+		delete(m.hubs, name)
+	}
+}
+
+func (m *fakeHubManager) Delete(_ context.Context, name string) error {
+	m.set(name, false)
+	return nil
+}
+
+func (m *fakeHubManager) List(_ context.Context) ([]*eventhub.HubEntity, error) {
+	m.mutex.Lock()
+	hubEntities := make([]*eventhub.HubEntity, 0, len(m.hubs))
+	for name := range m.hubs {
+		hubEntities = append(hubEntities, createEventHubEntity(name))
+	}
+	listed := m.listed
+	m.mutex.Unlock()
+	if listed != nil {
+		listed()
+	}
+	return hubEntities, nil
+}
+
+func (m *fakeHubManager) Put(_ context.Context, name string, _ ...eventhub.HubManagementOption) (*eventhub.HubEntity, error) {
+	m.set(name, true)
+	return createEventHubEntity(name), nil
+}
+
 // Create K8S Kafka Secret With Specified Config
 func createKafkaSecret(name string, namespace string, brokers string, username string, password string, eventHubNamespace string) *corev1.Secret {
 	return &corev1.Secret{
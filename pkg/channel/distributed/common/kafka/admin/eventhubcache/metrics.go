@@ -0,0 +1,89 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventhubcache
+
+import (
+	"context"
+	"log"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"knative.dev/pkg/metrics"
+)
+
+const (
+
+	// LabelNamespace is the label for the name of the Azure EventHub Namespace.
+	LabelNamespace = "eventhub_namespace"
+)
+
+var (
+	// Gauge For The Number Of EventHubs In An Azure Namespace
+	namespaceEventHubCount = stats.Int64(
+		"eventhub_namespace_eventhub_count", // The METRICS_DOMAIN will be prepended to the name.
+		"Number Of EventHubs In The Azure EventHub Namespace",
+		stats.UnitDimensionless,
+	)
+
+	// Gauge For The Percentage Of The Capacity Of Its Tier Used By An Azure Namespace (Only If The Tier Is Known)
+	namespaceUtilization = stats.Float64(
+		"eventhub_namespace_utilization", // The METRICS_DOMAIN will be prepended to the name.
+		"Percentage Of The Maximum Number Of EventHubs Of Its Tier Used By The Azure EventHub Namespace",
+		"%",
+	)
+
+	// The Tag Key Of The Azure Namespace Name
+	namespaceKey = tag.MustNewKey(LabelNamespace)
+)
+
+// Register the OpenCensus View Structures
+func init() {
+	err := view.Register(
+		&view.View{
+			Description: namespaceEventHubCount.Description(),
+			Measure:     namespaceEventHubCount,
+			Aggregation: view.LastValue(),
+			TagKeys:     []tag.Key{namespaceKey},
+		},
+		&view.View{
+			Description: namespaceUtilization.Description(),
+			Measure:     namespaceUtilization,
+			Aggregation: view.LastValue(),
+			TagKeys:     []tag.Key{namespaceKey},
+		},
+	)
+	if err != nil {
+		log.Printf("failed to register opencensus views, %v", err)
+	}
+}
+
+// Namespace Utilization Reporting Function Reference Variable To Facilitate Mocking In Unit Tests
+var reportNamespaceUtilization = func(namespace *Namespace) {
+
+	// Create A New OpenCensus Tag / Context For The Namespace
+	ctx, err := tag.New(context.Background(), tag.Insert(namespaceKey, namespace.Name))
+	if err != nil {
+		return
+	}
+
+	// Record The EventHub Count, And The Utilization Of The Namespace's Tier If Known
+	metrics.Record(ctx, namespaceEventHubCount.M(int64(namespace.Count)))
+	if namespace.Capacity > 0 {
+		metrics.Record(ctx, namespaceUtilization.M(100*float64(namespace.Count)/float64(namespace.Capacity)))
+	}
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package eventhubcache
 
 import (
+	"strings"
+
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
@@ -30,6 +32,13 @@ type Namespace struct {
 	Secret     string
 	HubManager HubManagerInterface
 	Count      int
+	Tier       string // The Azure Namespace Tier (Optional)
+	Capacity   int    // The Maximum Number Of EventHubs Of The Tier (Zero If Unknown / Unbounded)
+}
+
+// Determine Whether The Namespace Has Reached The Maximum Number Of EventHubs Of Its Tier
+func (n *Namespace) Full() bool {
+	return n.Capacity > 0 && n.Count >= n.Capacity
 }
 
 // Namespace Complete Argument Constructor
@@ -61,8 +70,36 @@ func NewNamespaceFromKafkaSecret(logger *zap.Logger, kafkaSecret *corev1.Secret)
 	username := string(data[constants.KafkaSecretKeyUsername])
 	password := string(data[constants.KafkaSecretKeyPassword])
 	namespace := string(data[constants.KafkaSecretKeyNamespace])
+	tier := string(data[constants.KafkaSecretKeyTier])
 	secret := kafkaSecret.Name
 
 	// Create A New Namespace From The Secret
-	return NewNamespace(logger, namespace, username, password, secret, 0)
+	eventHubNamespace, err := NewNamespace(logger, namespace, username, password, secret, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bound The Namespace By The Capacity Of Its Tier, If Known
+	eventHubNamespace.Tier = tier
+	eventHubNamespace.Capacity = TierCapacity(tier)
+	if len(tier) > 0 && eventHubNamespace.Capacity == 0 {
+		logger.Warn("Unknown Azure EventHub Namespace Tier - Not Bounding Its EventHubs", zap.String("Namespace", namespace), zap.String("Tier", tier))
+	}
+	return eventHubNamespace, nil
+}
+
+// Get The Maximum Number Of EventHubs Of The Specified Azure Namespace Tier (Zero If Unknown)
+func TierCapacity(tier string) int {
+	switch strings.ToLower(tier) {
+	case constants.EventHubTierBasic:
+		return constants.MaxEventHubsPerBasicNamespace
+	case constants.EventHubTierStandard:
+		return constants.MaxEventHubsPerStandardNamespace
+	case constants.EventHubTierPremium:
+		return constants.MaxEventHubsPerPremiumNamespace
+	case constants.EventHubTierDedicated:
+		return constants.MaxEventHubsPerDedicatedNamespace
+	default:
+		return 0
+	}
 }
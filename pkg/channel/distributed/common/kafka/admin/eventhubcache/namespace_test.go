@@ -137,3 +137,50 @@ func TestNewNamespaceFromKafkaSecret(t *testing.T) {
 	assert.Equal(t, count, namespace.Count)
 	assert.Equal(t, mockHubManager, namespace.HubManager)
 }
+
+// Test The NewNamespaceFromKafkaSecret() Constructor With An Azure Namespace Tier
+func TestNewNamespaceFromKafkaSecretWithTier(t *testing.T) {
+
+	// Replace The NewHubManagerFromConnectionString Wrapper To Provide Mock Implementation & Defer Reset
+	newHubManagerFromConnectionStringWrapperPlaceholder := NewHubManagerFromConnectionStringWrapper
+	NewHubManagerFromConnectionStringWrapper = func(connectionString string) (managerInterface HubManagerInterface, e error) {
+		return &MockHubManager{}, nil
+	}
+	defer func() { NewHubManagerFromConnectionStringWrapper = newHubManagerFromConnectionStringWrapperPlaceholder }()
+
+	// Define The TestCases
+	tests := []struct {
+		name     string
+		tier     string
+		capacity int
+	}{
+		{name: "No Tier", tier: "", capacity: 0},
+		{name: "Unknown Tier", tier: "TestTier", capacity: 0},
+		{name: "Basic Tier", tier: "Basic", capacity: constants.MaxEventHubsPerBasicNamespace},
+		{name: "Standard Tier", tier: "standard", capacity: constants.MaxEventHubsPerStandardNamespace},
+		{name: "Premium Tier", tier: "Premium", capacity: constants.MaxEventHubsPerPremiumNamespace},
+		{name: "Dedicated Tier", tier: "Dedicated", capacity: constants.MaxEventHubsPerDedicatedNamespace},
+	}
+
+	// Run The TestCases
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			kafkaSecret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "TestSecret"},
+				Data: map[string][]byte{
+					constants.KafkaSecretKeyNamespace: []byte("TestNamespace"),
+					constants.KafkaSecretKeyUsername:  []byte("TestUsername"),
+					constants.KafkaSecretKeyPassword:  []byte("TestPassword"),
+					constants.KafkaSecretKeyTier:      []byte(test.tier),
+				},
+			}
+			namespace, err := NewNamespaceFromKafkaSecret(logtesting.TestLogger(t).Desugar(), kafkaSecret)
+			assert.Nil(t, err)
+			assert.Equal(t, test.tier, namespace.Tier)
+			assert.Equal(t, test.capacity, namespace.Capacity)
+			assert.False(t, namespace.Full())
+			namespace.Count = test.capacity
+			assert.Equal(t, test.capacity > 0, namespace.Full())
+		})
+	}
+}
@@ -28,11 +28,15 @@ const (
 	// Kafka Secret Label
 	KafkaSecretLabel = "eventing-kafka.knative.dev/kafka-secret"
 
+	// KafkaChannel Kafka Secret Label (Indicates The Kafka Secret Of The KafkaChannel)
+	KafkaChannelSecretLabel = "kafkasecret"
+
 	// Kafka Secret Keys
 	KafkaSecretKeyBrokers   = "brokers"
 	KafkaSecretKeyNamespace = "namespace"
 	KafkaSecretKeyUsername  = "username"
 	KafkaSecretKeyPassword  = "password"
+	KafkaSecretKeyTier      = "tier" // Optional Azure EventHub Namespace Tier (Bounding The EventHubs Created In It)
 
 	// Kafka Admin/Consumer/Producer Config Values
 	ConfigNetSaslVersion = sarama.SASLHandshakeV1 // Latest version, seems to work with EventHubs as well.
@@ -49,6 +53,16 @@ const (
 	// EventHub Constraints
 	MaxEventHubNamespaces = 100
 
+	// EventHub Namespace Tiers & Their Maximum Number Of EventHubs (Premium Per Processing Unit, Dedicated Per Capacity Unit)
+	EventHubTierBasic                 = "basic"
+	EventHubTierStandard              = "standard"
+	EventHubTierPremium               = "premium"
+	EventHubTierDedicated             = "dedicated"
+	MaxEventHubsPerBasicNamespace     = 10
+	MaxEventHubsPerStandardNamespace  = 10
+	MaxEventHubsPerPremiumNamespace   = 100
+	MaxEventHubsPerDedicatedNamespace = 1000
+
 	// KafkaChannel Constants
 	KafkaChannelServiceNameSuffix = "kn-channel" // Specific Value For Use With Knative e2e Tests!
 )
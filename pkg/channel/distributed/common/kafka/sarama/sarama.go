@@ -42,6 +42,16 @@ import (
 // Regular Expression To Find All Certificates In Net.TLS.Config.RootPEMs Field
 var regexRootPEMs = regexp.MustCompile(`(?s)\s*RootPEMs:.*-----END CERTIFICATE-----`)
 
+// Regular Expression To Find The Consumer.Group.Rebalance.Strategy Field (The Only "Strategy" In The Sarama Config)
+var regexRebalanceStrategy = regexp.MustCompile(`(?m)^[ \t]*Strategy:[ \t]*\S+[ \t]*(#.*)?$`)
+
+// The Consumer Group Rebalance Strategies Which May Be Specified By Name
+var rebalanceStrategies = map[string]sarama.BalanceStrategy{
+	sarama.RangeBalanceStrategyName:      sarama.BalanceStrategyRange,
+	sarama.RoundRobinBalanceStrategyName: sarama.BalanceStrategyRoundRobin,
+	sarama.StickyBalanceStrategyName:     sarama.BalanceStrategySticky,
+}
+
 // Utility Function For Enabling Sarama Logging (Debugging)
 func EnableSaramaLogging(enable bool) {
 	if enable {
@@ -113,6 +123,53 @@ func extractKafkaVersion(saramaConfigYamlString string) (string, sarama.KafkaVer
 	}
 }
 
+//
+// Extract (Parse & Remove) The Consumer.Group.Rebalance.Strategy From Specified Sarama Config YAML String
+//
+// The Sarama.Config struct contains a Consumer.Group.Rebalance.Strategy field of the interface type
+// sarama.BalanceStrategy, which cannot be unmarshalled.  Therefore, we instead support the user providing
+// the name of one of Sarama's strategies ("range", "roundrobin" or "sticky") which we will "extract" out
+// of the YAML string and return as the actual sarama.BalanceStrategy.  In the case where the user has NOT
+// specified a Strategy we will return nil (leaving the strategy of the config being merged into as is).
+//
+func extractRebalanceStrategy(saramaConfigYamlString string) (string, sarama.BalanceStrategy, error) {
+
+	// Define Inline Struct To Marshall The Rebalance Strategy Name Into
+	type rebalanceShell struct {
+		Consumer struct {
+			Group struct {
+				Rebalance struct {
+					Strategy string
+				}
+			}
+		}
+	}
+
+	// Unmarshal The Rebalance Strategy Into The Shell
+	shell := &rebalanceShell{}
+	err := yaml.Unmarshal([]byte(saramaConfigYamlString), shell)
+	if err != nil {
+		return saramaConfigYamlString, nil, err
+	}
+
+	// Exit Early If No Strategy Was Specified
+	strategyName := shell.Consumer.Group.Rebalance.Strategy
+	if len(strategyName) <= 0 {
+		return saramaConfigYamlString, nil, nil
+	}
+
+	// Look Up The Named Strategy
+	strategy, ok := rebalanceStrategies[strategyName]
+	if !ok {
+		return saramaConfigYamlString, nil, fmt.Errorf("unknown rebalance strategy %q (expected %q, %q or %q)", strategyName,
+			sarama.RangeBalanceStrategyName, sarama.RoundRobinBalanceStrategyName, sarama.StickyBalanceStrategyName)
+	}
+
+	// Remove The Strategy From The Sarama Config YAML String
+	updatedSaramaConfigYamlBytes := regexRebalanceStrategy.ReplaceAll([]byte(saramaConfigYamlString), []byte{})
+	return string(updatedSaramaConfigYamlBytes), strategy, nil
+}
+
 /* Extract (Parse & Remove) TLS.Config Level RootPEMs From Specified Sarama Confirm YAML String
 
 The Sarama.Config struct contains Net.TLS.Config which is a *tls.Config which cannot be parsed.
@@ -271,8 +328,14 @@ func MergeSaramaSettings(config *sarama.Config, configMap *corev1.ConfigMap) (*s
 		return nil, fmt.Errorf("failed to extract RootPEMs from Sarama Config YAML: err=%s : config=%+v", err, saramaSettingsYamlString)
 	}
 
+	// Extract (Remove) Any Consumer.Group.Rebalance.Strategy Name
+	saramaSettingsYamlString, rebalanceStrategy, err := extractRebalanceStrategy(saramaSettingsYamlString)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract Rebalance.Strategy from Sarama Config YAML: err=%s : config=%+v", err, saramaSettingsYamlString)
+	}
+
 	// Unmarshall The Sarama Config Yaml Into The Provided Sarama.Config Object
-	err = yaml.Unmarshal([]byte(saramaSettingsYamlString), &config)
+	err = yaml.Unmarshal([]byte(saramaSettingsYamlString), config) // Not &config, Which Empty Settings Would Set To nil
 	if err != nil {
 		return nil, fmt.Errorf("ConfigMap's sarama value could not be converted to a Sarama.Config struct: %s : %v", err, saramaSettingsYamlString)
 	}
@@ -280,6 +343,11 @@ func MergeSaramaSettings(config *sarama.Config, configMap *corev1.ConfigMap) (*s
 	// Override The Custom Parsed KafkaVersion
 	config.Version = kafkaVersion
 
+	// Override Any Custom Parsed Rebalance.Strategy
+	if rebalanceStrategy != nil {
+		config.Consumer.Group.Rebalance.Strategy = rebalanceStrategy
+	}
+
 	// Override Any Custom Parsed TLS.Config.RootCAs
 	if certPool != nil && len(certPool.Subjects()) > 0 {
 		config.Net.TLS.Config = &tls.Config{RootCAs: certPool}
@@ -291,6 +291,14 @@ func TestMergeSaramaSettings(t *testing.T) {
 	config, err = MergeSaramaSettings(config, configMap)
 	assert.Nil(t, err)
 	assert.True(t, config.Net.TLS.Config.InsecureSkipVerify)
+
+	// Verify that settings which are empty once the Version is extracted leave the config intact
+	configMap = commontesting.GetTestSaramaConfigMap("Version: 2.3.0", commontesting.TestEKConfig)
+	config, err = MergeSaramaSettings(config, configMap)
+	assert.Nil(t, err)
+	assert.NotNil(t, config)
+	assert.Equal(t, sarama.V2_3_0_0, config.Version)
+	assert.True(t, config.Net.TLS.Config.InsecureSkipVerify)
 }
 
 // Verify that comparisons of sarama config structs function as expected
@@ -469,3 +477,42 @@ func TestExtractRootCerts(t *testing.T) {
 	assert.Nil(t, certPool)
 	assert.Nil(t, err)
 }
+
+func TestExtractRebalanceStrategy(t *testing.T) {
+
+	// The Sarama Config YAML String To Test (With Settings Following The Strategy)
+	beforeSaramaConfigYaml := `
+Consumer:
+  Group:
+    Rebalance:
+      Strategy: sticky # Comments Are Removed Too
+      Timeout: 30000000000
+  Fetch:
+    Default: 2097152
+`
+
+	// Perform The Test (Extract The Strategy)
+	afterSaramaConfigYaml, strategy, err := extractRebalanceStrategy(beforeSaramaConfigYaml)
+
+	// Verify The Strategy Was Extracted Successfully & The Remaining YAML Is Intact
+	assert.Nil(t, err)
+	assert.Equal(t, sarama.BalanceStrategySticky, strategy)
+	assert.False(t, strings.Contains(afterSaramaConfigYaml, "Strategy"))
+	config, err := MergeSaramaSettings(nil, commontesting.GetTestSaramaConfigMap(beforeSaramaConfigYaml, commontesting.TestEKConfig))
+	assert.Nil(t, err)
+	assert.Equal(t, sarama.StickyBalanceStrategyName, config.Consumer.Group.Rebalance.Strategy.Name())
+	assert.Equal(t, 30*time.Second, config.Consumer.Group.Rebalance.Timeout)
+	assert.Equal(t, int32(2097152), config.Consumer.Fetch.Default)
+
+	// Verify The YAML String Is Unchanged And Strategy Is Nil When Not Specified
+	finalSaramaConfigYaml, strategy, err := extractRebalanceStrategy(afterSaramaConfigYaml)
+	assert.Nil(t, err)
+	assert.Equal(t, afterSaramaConfigYaml, finalSaramaConfigYaml)
+	assert.Nil(t, strategy)
+
+	// Verify An Unknown Strategy Is An Error
+	_, _, err = extractRebalanceStrategy("Consumer:\n  Group:\n    Rebalance:\n      Strategy: INVALID\n")
+	assert.NotNil(t, err)
+	_, err = MergeSaramaSettings(nil, commontesting.GetTestSaramaConfigMap("Consumer:\n  Group:\n    Rebalance:\n      Strategy: INVALID\n", commontesting.TestEKConfig))
+	assert.NotNil(t, err)
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package util
 
 import (
+	"hash/fnv"
 	"os"
 	"os/signal"
 
@@ -36,3 +37,13 @@ func WaitForSignal(logger *zap.Logger, signals ...os.Signal) {
 	// Log Signal Receipt
 	logger.Info("Received Signal", zap.String("Signal", sig.String()))
 }
+
+// Get The Member Of A Pool Of Shared Dispatchers Which Dispatches The Specified KafkaChannel (By Hash Of Its Key)
+func DispatcherPoolMember(channelKey string, poolSize int) int {
+	if poolSize <= 1 {
+		return 0
+	}
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(channelKey))
+	return int(hash.Sum32() % uint32(poolSize))
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package util
 
 import (
+	"fmt"
 	"syscall"
 	"testing"
 	"time"
@@ -60,3 +61,22 @@ func TestWaitForSignal(t *testing.T) {
 	// Done!  (If The Test Completes (Doesn't Hang) Then It Was Successful)
 	logger.Info("Done!")
 }
+
+// Test The DispatcherPoolMember Functionality
+func TestDispatcherPoolMember(t *testing.T) {
+
+	// A Single Dispatcher Dispatches Every KafkaChannel
+	assert.Equal(t, 0, DispatcherPoolMember("namespace/name", 0))
+	assert.Equal(t, 0, DispatcherPoolMember("namespace/name", 1))
+
+	// The KafkaChannels Are Spread Over The Pool, Always To The Same Member
+	members := make(map[int]bool)
+	for i := 0; i < 100; i++ {
+		channelKey := fmt.Sprintf("namespace/name-%d", i)
+		member := DispatcherPoolMember(channelKey, 5)
+		assert.True(t, member >= 0 && member < 5)
+		assert.Equal(t, member, DispatcherPoolMember(channelKey, 5))
+		members[member] = true
+	}
+	assert.Len(t, members, 5)
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package autoscalerinformer
+
+import (
+	"context"
+
+	informersautoscalingv2beta2 "k8s.io/client-go/informers/autoscaling/v2beta2"
+	"knative.dev/pkg/client/injection/kube/informers/factory"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection"
+	"knative.dev/pkg/logging"
+)
+
+//
+// Autoscaling/v2beta2 HorizontalPodAutoscalerInformer
+//
+// Note:  The Receiver & Dispatcher HorizontalPodAutoscalers are autoscaling/v2beta2 (for their custom metrics),
+//        which the generated Knative injection informers do not include, so the following injects the v2beta2
+//        HorizontalPodAutoscalerInformer from the same shared (cluster-wide) factory as the generated ones.
+//
+
+// Add The InformerInjector Function With The Knative Injection Framework
+func init() {
+	injection.Default.RegisterInformer(withInformer)
+}
+
+// Key Used To Associate The Informer Inside The Context
+type Key struct{}
+
+// InformerInjector For The HorizontalPodAutoscalerInformer
+func withInformer(ctx context.Context) (context.Context, controller.Informer) {
+	informer := factory.Get(ctx).Autoscaling().V2beta2().HorizontalPodAutoscalers()
+	return context.WithValue(ctx, Key{}, informer), informer.Informer()
+}
+
+// Extract The Typed HorizontalPodAutoscalerInformer From The Specified Context
+func Get(ctx context.Context) informersautoscalingv2beta2.HorizontalPodAutoscalerInformer {
+	untyped := ctx.Value(Key{})
+	if untyped == nil {
+		logging.FromContext(ctx).Panic("Unable to fetch k8s.io/client-go/informers/autoscaling/v2beta2.HorizontalPodAutoscalerInformer from context.")
+	}
+	return untyped.(informersautoscalingv2beta2.HorizontalPodAutoscalerInformer)
+}
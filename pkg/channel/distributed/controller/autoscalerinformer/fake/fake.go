@@ -0,0 +1,37 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/autoscalerinformer"
+	"knative.dev/pkg/client/injection/kube/informers/factory/fake" // Knative Fake Informer Factory Injection
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection"
+)
+
+var Get = autoscalerinformer.Get
+
+func init() {
+	injection.Fake.RegisterInformer(withInformer)
+}
+
+func withInformer(ctx context.Context) (context.Context, controller.Informer) {
+	inf := fake.Get(ctx).Autoscaling().V2beta2().HorizontalPodAutoscalers() // Using Standard Fake Informer Factory
+	return context.WithValue(ctx, autoscalerinformer.Key{}, inf), inf.Informer()
+}
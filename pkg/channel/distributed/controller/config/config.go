@@ -74,5 +74,35 @@ func VerifyConfiguration(configuration *config.EventingKafkaConfig) error {
 	case configuration.Receiver.Replicas < 1:
 		return ControllerConfigurationError("Receiver.Replicas must be > 0")
 	}
+
+	// Verify the optional autoscaling settings (the Receiver scales on request rate & the Dispatcher on consumer lag)
+	receiverAutoscaling := configuration.Receiver.Autoscaling
+	if err := verifyAutoscaling("Receiver", receiverAutoscaling, receiverAutoscaling.TargetRequestRate.Sign() > 0); err != nil {
+		return err
+	}
+	dispatcherAutoscaling := configuration.Dispatcher.Autoscaling
+	if err := verifyAutoscaling("Dispatcher", dispatcherAutoscaling, dispatcherAutoscaling.TargetLag > 0); err != nil {
+		return err
+	}
 	return nil // no problems found
 }
+
+// verifyAutoscaling returns an error if the enabled autoscaling settings of the named component have invalid
+// bounds or no metric target (CPU utilization or the component specific metric)
+func verifyAutoscaling(component string, autoscaling config.EKAutoscalingConfig, hasMetricTarget bool) error {
+	switch {
+	case !autoscaling.Enabled():
+		return nil
+	case autoscaling.MinReplicas < 0:
+		return ControllerConfigurationError(component + ".Autoscaling.MinReplicas must be >= 0")
+	case autoscaling.MinimumReplicas() > autoscaling.MaxReplicas:
+		return ControllerConfigurationError(component + ".Autoscaling.MinReplicas must be <= MaxReplicas")
+	case autoscaling.TargetCpuUtilization < 0:
+		return ControllerConfigurationError(component + ".Autoscaling.TargetCpuUtilization must be >= 0")
+	case autoscaling.TargetRequestRate.Sign() > 0 && len(autoscaling.RequestRateMetric) == 0:
+		return ControllerConfigurationError(component + ".Autoscaling.RequestRateMetric must be specified with TargetRequestRate")
+	case autoscaling.TargetCpuUtilization == 0 && !hasMetricTarget:
+		return ControllerConfigurationError(component + ".Autoscaling must specify a metric target")
+	}
+	return nil
+}
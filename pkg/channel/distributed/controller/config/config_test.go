@@ -64,6 +64,8 @@ type TestCase struct {
 	channelMemoryLimit                 resource.Quantity
 	channelMemoryRequest               resource.Quantity
 	channelReplicas                    int
+	receiverAutoscaling                config.EKAutoscalingConfig
+	dispatcherAutoscaling              config.EKAutoscalingConfig
 
 	expectedError error
 }
@@ -164,6 +166,26 @@ func TestVerifyConfiguration(t *testing.T) {
 	testCase.expectedError = ControllerConfigurationError("Receiver.Replicas must be > 0")
 	testCases = append(testCases, testCase)
 
+	testCase = getValidTestCase("Valid Config - Autoscaling")
+	testCase.receiverAutoscaling = config.EKAutoscalingConfig{MaxReplicas: 5, TargetCpuUtilization: 80}
+	testCase.dispatcherAutoscaling = config.EKAutoscalingConfig{MinReplicas: 2, MaxReplicas: 10, TargetLag: 1000}
+	testCases = append(testCases, testCase)
+
+	testCase = getValidTestCase("Invalid Config - Receiver.Autoscaling.MinReplicas")
+	testCase.receiverAutoscaling = config.EKAutoscalingConfig{MinReplicas: 6, MaxReplicas: 5, TargetCpuUtilization: 80}
+	testCase.expectedError = ControllerConfigurationError("Receiver.Autoscaling.MinReplicas must be <= MaxReplicas")
+	testCases = append(testCases, testCase)
+
+	testCase = getValidTestCase("Invalid Config - Receiver.Autoscaling.RequestRateMetric")
+	testCase.receiverAutoscaling = config.EKAutoscalingConfig{MaxReplicas: 5, TargetRequestRate: resource.MustParse("100")}
+	testCase.expectedError = ControllerConfigurationError("Receiver.Autoscaling.RequestRateMetric must be specified with TargetRequestRate")
+	testCases = append(testCases, testCase)
+
+	testCase = getValidTestCase("Invalid Config - Dispatcher.Autoscaling Without Target")
+	testCase.dispatcherAutoscaling = config.EKAutoscalingConfig{MaxReplicas: 5, TargetRequestRate: resource.MustParse("100"), RequestRateMetric: "requests"}
+	testCase.expectedError = ControllerConfigurationError("Dispatcher.Autoscaling must specify a metric target")
+	testCases = append(testCases, testCase)
+
 	testCase = getValidTestCase("Invalid Config - Kafka.Provider")
 	testCase.kafkaAdminType = "invalidadmintype"
 	testCase.expectedError = ControllerConfigurationError("Invalid / Unknown Kafka Admin Type: invalidadmintype")
@@ -186,6 +208,8 @@ func TestVerifyConfiguration(t *testing.T) {
 			testConfig.Receiver.MemoryLimit = testCase.channelMemoryLimit
 			testConfig.Receiver.MemoryRequest = testCase.channelMemoryRequest
 			testConfig.Receiver.Replicas = testCase.channelReplicas
+			testConfig.Receiver.Autoscaling = testCase.receiverAutoscaling
+			testConfig.Dispatcher.Autoscaling = testCase.dispatcherAutoscaling
 
 			// Perform The Test
 			err := VerifyConfiguration(testConfig)
@@ -33,6 +33,7 @@ const (
 	SecretKind              = "Secret"
 	ServiceKind             = "Service"
 	DeploymentKind          = "Deployment"
+	AutoscalerKind          = "HorizontalPodAutoscaler"
 	KnativeSubscriptionKind = "Subscription"
 	KafkaChannelKind        = "KafkaChannel"
 
@@ -74,9 +75,19 @@ const (
 	KafkaChannelNamespaceLabel  = "kafkachannel-namespace"
 	KafkaChannelReceiverLabel   = "kafkachannel-receiver"   // Receiver Label - Used To Mark Deployment As Receiver
 	KafkaChannelDispatcherLabel = "kafkachannel-dispatcher" // Dispatcher Label - Used To Mark Deployment As Dispatcher
-	KafkaSecretLabel            = "kafkasecret"             // Secret Label - Indicates The Kafka Secret Of The KafkaChannel
+	KafkaSecretLabel            = "kafkasecret"             // Secret Label - Indicates The Kafka Secret Of The KafkaChannel (Matches KafkaChannelSecretLabel)
 	KafkaTopicLabel             = "kafkaTopic"              // Topic Label - Indicates The Kafka Topic Of The KnativeChannel
 
+	// Shared Dispatcher Pool Labels
+	KafkaChannelDispatcherPoolMemberLabel = "kafkachannel-dispatcher-pool-member" // Pool Member Label - Indicates The Member Number Of A Shared Dispatcher
+	KafkaChannelDispatcherPoolSizeLabel   = "kafkachannel-dispatcher-pool-size"   // Pool Size Label - Indicates The Number Of Shared Dispatchers In The Pool
+
+	// Pod Template Annotation Holding A Hash Of The Kafka Secret's Data (Rolls Out The Pods When The Credentials Change)
+	KafkaSecretHashAnnotation = "kafka.eventing.knative.dev/kafka-secret-hash"
+
+	// The Label Of The Consumer Lag Metric Identifying The Kafka Topic (As Exported By The Prometheus kafka-exporter)
+	AutoscalerTopicMetricLabel = "topic"
+
 	// Prometheus ServiceMonitor Selector Labels / Values
 	K8sAppChannelSelectorLabel    = "k8s-app"
 	K8sAppChannelSelectorValue    = "eventing-kafka-channels"
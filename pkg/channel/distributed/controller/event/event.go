@@ -39,6 +39,7 @@ const (
 	// Receiver (Kafka Producer) Reconciliation
 	ReceiverServiceReconciliationFailed
 	ReceiverDeploymentReconciliationFailed
+	ReceiverAutoscalerReconciliationFailed
 
 	// Kafka Topic Reconciliation
 	KafkaTopicReconciliationFailed
@@ -48,10 +49,13 @@ const (
 	DispatcherDeploymentReconciliationFailed
 	DispatcherServiceFinalizationFailed
 	DispatcherDeploymentFinalizationFailed
+	DispatcherAutoscalerReconciliationFailed
+	DispatcherAutoscalerFinalizationFailed
 
 	// Kafka Secret Reconciliation
 	KafkaSecretReconciled
 	KafkaSecretFinalized
+	KafkaSecretChanged
 )
 
 // CoreV1 EventType String Value
@@ -78,6 +82,8 @@ func (et CoreV1EventType) String() string {
 		eventTypeString = "ReceiverServiceReconciliationFailed"
 	case ReceiverDeploymentReconciliationFailed:
 		eventTypeString = "ReceiverDeploymentReconciliationFailed"
+	case ReceiverAutoscalerReconciliationFailed:
+		eventTypeString = "ReceiverAutoscalerReconciliationFailed"
 	case ChannelStatusReconciliationFailed:
 		eventTypeString = "ChannelStatusReconciliationFailed"
 	case KafkaTopicReconciliationFailed:
@@ -90,10 +96,16 @@ func (et CoreV1EventType) String() string {
 		eventTypeString = "DispatcherServiceFinalizationFailed"
 	case DispatcherDeploymentFinalizationFailed:
 		eventTypeString = "DispatcherDeploymentFinalizationFailed"
+	case DispatcherAutoscalerReconciliationFailed:
+		eventTypeString = "DispatcherAutoscalerReconciliationFailed"
+	case DispatcherAutoscalerFinalizationFailed:
+		eventTypeString = "DispatcherAutoscalerFinalizationFailed"
 	case KafkaSecretReconciled:
 		eventTypeString = "KafkaSecretReconciled"
 	case KafkaSecretFinalized:
 		eventTypeString = "KafkaSecretFinalized"
+	case KafkaSecretChanged:
+		eventTypeString = "KafkaSecretChanged"
 	}
 
 	// Return The EventType String Value
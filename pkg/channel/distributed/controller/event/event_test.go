@@ -31,13 +31,17 @@ func TestEventTypes(t *testing.T) {
 	performEventTypeStringTest(t, ReceiverServiceReconciliationFailed, "ReceiverServiceReconciliationFailed")
 	performEventTypeStringTest(t, ReceiverServiceReconciliationFailed, "ReceiverServiceReconciliationFailed")
 	performEventTypeStringTest(t, ReceiverDeploymentReconciliationFailed, "ReceiverDeploymentReconciliationFailed")
+	performEventTypeStringTest(t, ReceiverAutoscalerReconciliationFailed, "ReceiverAutoscalerReconciliationFailed")
 	performEventTypeStringTest(t, KafkaTopicReconciliationFailed, "KafkaTopicReconciliationFailed")
 	performEventTypeStringTest(t, DispatcherServiceReconciliationFailed, "DispatcherServiceReconciliationFailed")
 	performEventTypeStringTest(t, DispatcherDeploymentReconciliationFailed, "DispatcherDeploymentReconciliationFailed")
 	performEventTypeStringTest(t, DispatcherServiceFinalizationFailed, "DispatcherServiceFinalizationFailed")
 	performEventTypeStringTest(t, DispatcherDeploymentFinalizationFailed, "DispatcherDeploymentFinalizationFailed")
+	performEventTypeStringTest(t, DispatcherAutoscalerReconciliationFailed, "DispatcherAutoscalerReconciliationFailed")
+	performEventTypeStringTest(t, DispatcherAutoscalerFinalizationFailed, "DispatcherAutoscalerFinalizationFailed")
 	performEventTypeStringTest(t, KafkaSecretReconciled, "KafkaSecretReconciled")
 	performEventTypeStringTest(t, KafkaSecretFinalized, "KafkaSecretFinalized")
+	performEventTypeStringTest(t, KafkaSecretChanged, "KafkaSecretChanged")
 }
 
 // Perform A Single Instance Of The CoreV1 EventType String Test
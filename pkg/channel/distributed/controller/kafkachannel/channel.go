@@ -25,6 +25,8 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	commonk8s "knative.dev/eventing-kafka/pkg/channel/distributed/common/k8s"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/admin"
 	kafkautil "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/util"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/event"
@@ -164,5 +166,47 @@ func (r *Reconciler) newKafkaChannelService(channel *kafkav1beta1.KafkaChannel)
 
 // Get The Kafka Auth Secret Corresponding To The Specified KafkaChannel
 func (r *Reconciler) kafkaSecretName(channel *kafkav1beta1.KafkaChannel) string {
-	return r.adminClient.GetKafkaSecretName(util.TopicName(channel))
+	return r.kafkaSecretNameOf(r.adminClient, channel)
+}
+
+// Get The Kafka Auth Secret Corresponding To The Specified KafkaChannel, Among Those Of The Specified AdminClient
+func (r *Reconciler) kafkaSecretNameOf(adminClient admin.AdminClientInterface, channel *kafkav1beta1.KafkaChannel) string {
+
+	// An AdminClient Spanning Several Kafka Secrets Requires Routing The KafkaChannel To One Of Them
+	if kafkaSecretsAdminClient, ok := adminClient.(admin.KafkaSecretsAdminClient); ok {
+		kafkaSecretNames := kafkaSecretsAdminClient.GetKafkaSecretNames()
+
+		// A KafkaChannel Remains With The (Still Existing) Kafka Secret Of Its Label, Where Its Topics Were Created
+		if labelValue := channel.Labels[constants.KafkaSecretLabel]; len(labelValue) > 0 {
+			for _, kafkaSecretName := range kafkaSecretNames {
+				if commonk8s.TruncateLabelValue(kafkaSecretName) == labelValue {
+					return kafkaSecretName
+				}
+			}
+		}
+
+		// Otherwise Route The KafkaChannel By Its Cluster Annotation / Namespace
+		return r.config.Kafka.Secrets.KafkaSecretName(channel.KafkaCluster(), channel.Namespace, kafkaSecretNames)
+	}
+
+	// Otherwise The AdminClient Knows The Kafka Secret Of The Topic
+	return adminClient.GetKafkaSecretName(util.TopicName(channel))
+}
+
+// Get The AdminClient Managing The Topics Of The Specified KafkaChannel (That Of Its Kafka Secret When Several Exist)
+func (r *Reconciler) topicAdminClient(channel *kafkav1beta1.KafkaChannel) (admin.AdminClientInterface, error) {
+	return r.topicAdminClientOf(r.adminClient, channel)
+}
+
+// Get The AdminClient Managing The Topics Of The Specified KafkaChannel, Among Those Of The Specified AdminClient
+func (r *Reconciler) topicAdminClientOf(adminClient admin.AdminClientInterface, channel *kafkav1beta1.KafkaChannel) (admin.AdminClientInterface, error) {
+	if kafkaSecretsAdminClient, ok := adminClient.(admin.KafkaSecretsAdminClient); ok {
+		kafkaSecretName := r.kafkaSecretNameOf(adminClient, channel)
+		secretAdminClient := kafkaSecretsAdminClient.GetKafkaSecretAdminClient(kafkaSecretName)
+		if secretAdminClient == nil {
+			return nil, fmt.Errorf("no kafka secret for kafkachannel (cluster annotation '%s')", channel.KafkaCluster())
+		}
+		return secretAdminClient, nil
+	}
+	return adminClient, nil
 }
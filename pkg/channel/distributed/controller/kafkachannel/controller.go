@@ -21,23 +21,39 @@ import (
 	"sync"
 
 	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	kafkachannelv1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
 	commonconfig "knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	commonk8s "knative.dev/eventing-kafka/pkg/channel/distributed/common/k8s"
 	kafkaadmin "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/admin"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/sarama"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/autoscalerinformer"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/env"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/kafkasecretinformer"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/util"
 	kafkaclientsetinjection "knative.dev/eventing-kafka/pkg/client/injection/client"
 	"knative.dev/eventing-kafka/pkg/client/injection/informers/messaging/v1beta1/kafkachannel"
 	kafkachannelreconciler "knative.dev/eventing-kafka/pkg/client/injection/reconciler/messaging/v1beta1/kafkachannel"
+	kafkalisters "knative.dev/eventing-kafka/pkg/client/listers/messaging/v1beta1"
+	commonconstants "knative.dev/eventing-kafka/pkg/common/constants"
+	dispatchmetrics "knative.dev/eventing-kafka/pkg/common/metrics"
+	"knative.dev/eventing-kafka/pkg/common/orphans"
 	kubeclient "knative.dev/pkg/client/injection/kube/client"
 	"knative.dev/pkg/client/injection/kube/informers/apps/v1/deployment"
 	"knative.dev/pkg/client/injection/kube/informers/core/v1/service"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/logging"
+	"knative.dev/pkg/reconciler"
 )
 
 // Track The Reconciler For Shutdown() Usage
@@ -53,6 +69,8 @@ func NewController(ctx context.Context, _ configmap.Watcher) *controller.Impl {
 	kafkachannelInformer := kafkachannel.Get(ctx)
 	deploymentInformer := deployment.Get(ctx)
 	serviceInformer := service.Get(ctx)
+	autoscalerInformer := autoscalerinformer.Get(ctx)
+	kafkaSecretInformer := kafkasecretinformer.Get(ctx)
 
 	// Load The Environment Variables
 	environment, err := env.GetEnvironment(logger)
@@ -95,9 +113,12 @@ func NewController(ctx context.Context, _ configmap.Watcher) *controller.Impl {
 		kafkachannelInformer: kafkachannelInformer.Informer(),
 		deploymentLister:     deploymentInformer.Lister(),
 		serviceLister:        serviceInformer.Lister(),
+		autoscalerLister:     autoscalerInformer.Lister(),
+		secretLister:         kafkaSecretInformer.Lister(),
 		adminClientType:      kafkaAdminClientType,
 		adminClient:          nil,
 		adminMutex:           &sync.Mutex{},
+		orphansSeen:          orphans.NewSeen(),
 		configObserver:       rec.configMapObserver, // Maintains a reference so that the ConfigWatcher can call it
 	}
 
@@ -110,6 +131,26 @@ func NewController(ctx context.Context, _ configmap.Watcher) *controller.Impl {
 	// Create A New KafkaChannel Controller Impl With The Reconciler
 	controllerImpl := kafkachannelreconciler.NewImpl(ctx, rec)
 
+	// Reconcile The Shared Dispatcher Pools On Their Own Work Queue (Keyed By Their Kafka Secret)
+	dispatcherPoolImpl := controller.NewImpl(&dispatcherPoolReconciler{
+		reconciler: rec,
+		recorder:   newDispatcherPoolEventRecorder(ctx),
+	}, logger.Sugar(), "KafkaChannelDispatcherPools")
+	rec.enqueuePool = func(kafkaSecret string) {
+		dispatcherPoolImpl.EnqueueKey(types.NamespacedName{Namespace: commonconstants.KnativeEventingNamespace, Name: kafkaSecret})
+	}
+	go func() {
+		if err := dispatcherPoolImpl.RunContext(ctx, 1); err != nil {
+			logger.Error("Failed To Run Shared Dispatcher Pool Controller", zap.Error(err))
+		}
+	}()
+
+	// Periodically Collect The Topics & Consumer Groups Left Behind By Deleted KafkaChannels & Subscribers
+	go orphans.Run(ctx, rec.orphanCollectionInterval, func() { rec.collectOrphans(ctx) })
+
+	// Periodically Measure The Lag Of The Subscribers For The Status Of Their KafkaChannels
+	go rec.refreshSubscriberLag(ctx, dispatchmetrics.HealthRefreshInterval)
+
 	//
 	// Configure The Informers' EventHandlers
 	//
@@ -130,6 +171,17 @@ func NewController(ctx context.Context, _ configmap.Watcher) *controller.Impl {
 		FilterFunc: FilterKafkaChannelOwnerByReferenceOrLabel(),
 		Handler:    controller.HandleAll(controllerImpl.EnqueueLabelOfNamespaceScopedResource(constants.KafkaChannelNamespaceLabel, constants.KafkaChannelNameLabel)),
 	})
+	autoscalerInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: FilterKafkaChannelOwnerByReferenceOrLabel(),
+		Handler:    controller.HandleAll(controllerImpl.EnqueueLabelOfNamespaceScopedResource(constants.KafkaChannelNamespaceLabel, constants.KafkaChannelNameLabel)),
+	})
+	deploymentInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: reconciler.LabelExistsFilterFunc(constants.KafkaChannelDispatcherPoolMemberLabel),
+		Handler:    controller.HandleAll(enqueueKafkaChannelsOfDispatcherPool(controllerImpl, kafkachannelInformer.Lister())),
+	})
+	kafkaSecretInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: enqueueKafkaChannelsOfChangedKafkaSecret(controllerImpl, kafkachannelInformer.Lister()),
+	})
 
 	// Return The KafkaChannel Controller Impl
 	return controllerImpl
@@ -167,6 +219,63 @@ func FilterKafkaChannelOwnerByReferenceOrLabel() func(obj interface{}) bool {
 	}
 }
 
+// Enqueue The KafkaChannels Of The Kafka Secret Of The Specified Shared Dispatcher Deployment (For Their Dispatcher Status)
+func enqueueKafkaChannelsOfDispatcherPool(impl *controller.Impl, lister kafkalisters.KafkaChannelLister) func(obj interface{}) {
+	return func(obj interface{}) {
+		if object, ok := obj.(metav1.Object); ok {
+			secretName := object.GetLabels()[constants.KafkaSecretLabel]
+			if len(secretName) > 0 {
+				channels, err := lister.List(labels.SelectorFromSet(map[string]string{constants.KafkaSecretLabel: secretName}))
+				if err != nil {
+					return
+				}
+				for _, channel := range channels {
+					impl.Enqueue(channel)
+				}
+			}
+		}
+	}
+}
+
+// Enqueue The KafkaChannels Of A Kafka Secret Whose Data Changed (Restarting Their Dispatchers With The New Credentials)
+func enqueueKafkaChannelsOfChangedKafkaSecret(impl *controller.Impl, lister kafkalisters.KafkaChannelLister) func(oldObj, newObj interface{}) {
+	return func(oldObj, newObj interface{}) {
+		oldSecret, oldOk := oldObj.(*corev1.Secret)
+		newSecret, newOk := newObj.(*corev1.Secret)
+		if oldOk && newOk && util.SecretDataHash(oldSecret) != util.SecretDataHash(newSecret) {
+			if rec != nil && rec.enqueuePool != nil {
+				rec.enqueuePool(newSecret.Name)
+			}
+			channels, err := lister.List(labels.SelectorFromSet(map[string]string{constants.KafkaSecretLabel: commonk8s.TruncateLabelValue(newSecret.Name)}))
+			if err != nil {
+				return
+			}
+			for _, channel := range channels {
+				impl.Enqueue(channel)
+			}
+		}
+	}
+}
+
+// Create The Event Recorder Of The Shared Dispatcher Pools (Their Events Are Recorded On Their Kafka Secret)
+func newDispatcherPoolEventRecorder(ctx context.Context) record.EventRecorder {
+	if recorder := controller.GetEventRecorder(ctx); recorder != nil {
+		return recorder
+	}
+	eventBroadcaster := record.NewBroadcaster()
+	watches := []watch.Interface{
+		eventBroadcaster.StartLogging(logging.FromContext(ctx).Named("event-broadcaster").Infof),
+		eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeclient.Get(ctx).CoreV1().Events("")}),
+	}
+	go func() {
+		<-ctx.Done()
+		for _, w := range watches {
+			w.Stop()
+		}
+	}()
+	return eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: constants.ControllerComponentName})
+}
+
 // Graceful Shutdown Hook
 func Shutdown() {
 	rec.ClearKafkaAdminClient()
@@ -30,8 +30,10 @@ import (
 	kafkachannelv1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
 	commonenv "knative.dev/eventing-kafka/pkg/channel/distributed/common/env"
 	commontesting "knative.dev/eventing-kafka/pkg/channel/distributed/common/testing"
+	_ "knative.dev/eventing-kafka/pkg/channel/distributed/controller/autoscalerinformer/fake" // Knative Fake Informer Injection
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
 	controllerenv "knative.dev/eventing-kafka/pkg/channel/distributed/controller/env"
+	_ "knative.dev/eventing-kafka/pkg/channel/distributed/controller/kafkasecretinformer/fake" // Knative Fake Informer Injection
 	controllertesting "knative.dev/eventing-kafka/pkg/channel/distributed/controller/testing"
 	fakeKafkaClient "knative.dev/eventing-kafka/pkg/client/injection/client/fake"
 	_ "knative.dev/eventing-kafka/pkg/client/injection/informers/messaging/v1beta1/kafkachannel/fake" // Knative Fake Informer Injection
@@ -24,8 +24,11 @@ import (
 
 	"go.uber.org/zap"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
@@ -56,6 +59,24 @@ func (r *Reconciler) reconcileDispatcher(ctx context.Context, channel *kafkav1be
 	// Get Channel Specific Logger
 	logger := util.ChannelLogger(r.logger, channel)
 
+	// KafkaChannels Without A Dedicated Dispatcher Are Dispatched By The Shared Dispatcher Pool Of Their Kafka Secret
+	if r.sharedDispatcher(channel) {
+		return r.reconcileSharedDispatcher(ctx, logger, channel)
+	}
+
+	// Remove The Shared Dispatcher Pools Once The KafkaChannels No Longer Share Dispatchers, Which Is Only Checked When
+	// A KafkaChannel Is Assigned Its Own Dispatcher (So That It Is Not Dispatched By Both Until The Pool Is Removed)
+	if !r.config.Dispatcher.Shared() {
+		if _, err := r.getDispatcherDeployment(channel); errors.IsNotFound(err) {
+			if poolErr := r.deleteDispatcherPools(ctx, logger, "", 0); poolErr != nil {
+				controller.GetEventRecorder(ctx).Eventf(channel, corev1.EventTypeWarning, event.DispatcherDeploymentReconciliationFailed.String(), "Failed To Delete Shared Dispatcher Pool: %v", poolErr)
+				logger.Error("Failed To Delete Shared Dispatcher Pool", zap.Error(poolErr))
+				channel.Status.MarkDispatcherFailed(event.DispatcherDeploymentReconciliationFailed.String(), "Failed To Delete Shared Dispatcher Pool: %v", poolErr)
+				return fmt.Errorf("failed to reconcile dispatcher resources")
+			}
+		}
+	}
+
 	// Reconcile The Dispatcher's Service (For Prometheus Only)
 	serviceErr := r.reconcileDispatcherService(ctx, logger, channel)
 	if serviceErr != nil {
@@ -74,8 +95,17 @@ func (r *Reconciler) reconcileDispatcher(ctx context.Context, channel *kafkav1be
 		logger.Info("Successfully Reconciled Dispatcher Deployment")
 	}
 
+	// Reconcile The Dispatcher's HorizontalPodAutoscaler
+	autoscalerErr := r.reconcileDispatcherAutoscaler(ctx, logger, channel)
+	if autoscalerErr != nil {
+		controller.GetEventRecorder(ctx).Eventf(channel, corev1.EventTypeWarning, event.DispatcherAutoscalerReconciliationFailed.String(), "Failed To Reconcile Dispatcher HorizontalPodAutoscaler: %v", autoscalerErr)
+		logger.Error("Failed To Reconcile Dispatcher HorizontalPodAutoscaler", zap.Error(autoscalerErr))
+	} else {
+		logger.Info("Successfully Reconciled Dispatcher HorizontalPodAutoscaler")
+	}
+
 	// Return Results
-	if serviceErr != nil || deploymentErr != nil {
+	if serviceErr != nil || deploymentErr != nil || autoscalerErr != nil {
 		return fmt.Errorf("failed to reconcile dispatcher resources")
 	} else {
 		return nil
@@ -106,8 +136,17 @@ func (r *Reconciler) finalizeDispatcher(ctx context.Context, channel *kafkav1bet
 		logger.Info("Successfully Finalized Dispatcher Deployment")
 	}
 
+	// Finalize The Dispatcher's HorizontalPodAutoscaler
+	autoscalerErr := util.ReconcileHorizontalPodAutoscaler(ctx, logger, r.kubeClientset, r.autoscalerLister, commonconstants.KnativeEventingNamespace, util.DispatcherDnsSafeName(channel), nil)
+	if autoscalerErr != nil {
+		controller.GetEventRecorder(ctx).Eventf(channel, corev1.EventTypeWarning, event.DispatcherAutoscalerFinalizationFailed.String(), "Failed To Finalize Dispatcher HorizontalPodAutoscaler: %v", autoscalerErr)
+		logger.Error("Failed To Finalize Dispatcher HorizontalPodAutoscaler", zap.Error(autoscalerErr))
+	} else {
+		logger.Info("Successfully Finalized Dispatcher HorizontalPodAutoscaler")
+	}
+
 	// Return Results
-	if serviceErr != nil || deploymentErr != nil {
+	if serviceErr != nil || deploymentErr != nil || autoscalerErr != nil {
 		return fmt.Errorf("failed to finalize dispatcher resources")
 	} else {
 		return nil
@@ -221,6 +260,16 @@ func (r *Reconciler) newDispatcherService(channel *kafkav1beta1.KafkaChannel) *c
 	serviceName := util.DispatcherDnsSafeName(channel)
 
 	// Create & Return The Service Model
+	return r.dispatcherServiceModel(serviceName, map[string]string{
+		constants.KafkaChannelDispatcherLabel:   "true",                                  // Identifies the Service as being a KafkaChannel "Dispatcher"
+		constants.KafkaChannelNameLabel:         channel.Name,                            // Identifies the Service's Owning KafkaChannel's Name
+		constants.KafkaChannelNamespaceLabel:    channel.Namespace,                       // Identifies the Service's Owning KafkaChannel's Namespace
+		constants.K8sAppDispatcherSelectorLabel: constants.K8sAppDispatcherSelectorValue, // Prometheus ServiceMonitor
+	}, []string{r.finalizerName()})
+}
+
+// Create A Dispatcher Service Model With The Specified Name, Labels & Finalizers
+func (r *Reconciler) dispatcherServiceModel(serviceName string, labels map[string]string, finalizers []string) *corev1.Service {
 	return &corev1.Service{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: corev1.SchemeGroupVersion.String(),
@@ -229,15 +278,10 @@ func (r *Reconciler) newDispatcherService(channel *kafkav1beta1.KafkaChannel) *c
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      serviceName,
 			Namespace: commonconstants.KnativeEventingNamespace,
-			Labels: map[string]string{
-				constants.KafkaChannelDispatcherLabel:   "true",                                  // Identifies the Service as being a KafkaChannel "Dispatcher"
-				constants.KafkaChannelNameLabel:         channel.Name,                            // Identifies the Service's Owning KafkaChannel's Name
-				constants.KafkaChannelNamespaceLabel:    channel.Namespace,                       // Identifies the Service's Owning KafkaChannel's Namespace
-				constants.K8sAppDispatcherSelectorLabel: constants.K8sAppDispatcherSelectorValue, // Prometheus ServiceMonitor
-			},
+			Labels:    labels,
 			// K8S Does NOT Support Cross-Namespace OwnerReferences
 			// Instead Manage The Lifecycle Directly Via Finalizers (No K8S Garbage Collection)
-			Finalizers: []string{r.finalizerName()},
+			Finalizers: finalizers,
 		},
 		Spec: corev1.ServiceSpec{
 			Ports: []corev1.ServicePort{
@@ -297,7 +341,34 @@ func (r *Reconciler) reconcileDispatcherDeployment(ctx context.Context, logger *
 
 		// Log Deletion Timestamp & Finalizer State
 		if deployment.DeletionTimestamp.IsZero() {
-			logger.Info("Successfully Verified Dispatcher Deployment")
+
+			// Update The Deployment If The KafkaChannel's Dispatcher Template (Or The Dispatcher Config) Changed
+			desired, err := r.newDispatcherDeployment(logger, channel)
+			if err != nil {
+				logger.Error("Failed To Create Dispatcher Deployment YAML", zap.Error(err))
+				channel.Status.MarkDispatcherFailed(event.DispatcherDeploymentReconciliationFailed.String(), "Failed To Generate Dispatcher Deployment: %v", err)
+				return err
+			}
+			if r.config.Dispatcher.Autoscaling.Enabled() {
+				desired.Spec.Replicas = deployment.Spec.Replicas // Scaled By The HorizontalPodAutoscaler
+			}
+			updated := deployment.DeepCopy()
+			templateChanged := updateDispatcherDeploymentTemplate(updated, desired)
+			secretHashChanged, secretChanged := util.UpdateKafkaSecretHashAnnotation(updated, desired, templateChanged)
+			if templateChanged || secretHashChanged {
+				deployment, err = r.kubeClientset.AppsV1().Deployments(updated.Namespace).Update(ctx, updated, metav1.UpdateOptions{})
+				if err != nil {
+					logger.Error("Failed To Update Dispatcher Deployment", zap.Error(err))
+					channel.Status.MarkDispatcherFailed(event.DispatcherDeploymentReconciliationFailed.String(), "Failed To Update Dispatcher Deployment: %v", err)
+					return err
+				}
+				if secretChanged {
+					controller.GetEventRecorder(ctx).Eventf(channel, corev1.EventTypeNormal, event.KafkaSecretChanged.String(), "Kafka Secret Changed - Restarting Dispatcher Deployment: \"%s/%s\"", updated.Namespace, updated.Name)
+				}
+				logger.Info("Successfully Updated Dispatcher Deployment", zap.Bool("KafkaSecretChanged", secretChanged))
+			} else {
+				logger.Info("Successfully Verified Dispatcher Deployment")
+			}
 		} else {
 			if util.HasFinalizer(r.finalizerName(), &deployment.ObjectMeta) {
 				logger.Info("Blocking Pending Deletion Of Dispatcher Deployment (Finalizer Detected)")
@@ -373,9 +444,6 @@ func (r *Reconciler) newDispatcherDeployment(logger *zap.Logger, channel *kafkav
 	// Get The Dispatcher Deployment Name For The Channel
 	deploymentName := util.DispatcherDnsSafeName(channel)
 
-	// Replicas Int Value For De-Referencing
-	replicas := int32(r.config.Dispatcher.Replicas)
-
 	// Create The Dispatcher Container Environment Variables
 	envVars, err := r.dispatcherDeploymentEnvVars(channel)
 	if err != nil {
@@ -383,6 +451,78 @@ func (r *Reconciler) newDispatcherDeployment(logger *zap.Logger, channel *kafkav
 		return nil, err
 	}
 
+	// Create The Dispatcher's Deployment
+	deployment := r.dispatcherDeploymentModel(deploymentName, map[string]string{
+		constants.AppLabel:                    deploymentName,    // Matches K8S Service Selector Key/Value
+		constants.KafkaChannelDispatcherLabel: "true",            // Identifies the Deployment as being a KafkaChannel "Dispatcher"
+		constants.KafkaChannelNameLabel:       channel.Name,      // Identifies the Deployment's Owning KafkaChannel's Name
+		constants.KafkaChannelNamespaceLabel:  channel.Namespace, // Identifies the Deployment's Owning KafkaChannel's Namespace
+	}, []string{r.finalizerName()}, envVars)
+
+	// Apply The KafkaChannel's Overrides Of The Dispatcher Config (Already Validated By The Webhook)
+	template, err := channel.DispatcherTemplate()
+	if err != nil {
+		logger.Warn("Invalid Dispatcher Template Annotations - Using The Dispatcher Config", zap.Error(err))
+	} else {
+		applyDispatcherTemplate(deployment, template)
+	}
+
+	// Roll Out New Dispatchers When The Kafka Secret Changes
+	util.SetKafkaSecretHashAnnotation(deployment, r.kafkaSecretHash(r.kafkaSecretName(channel)))
+
+	// Return The Dispatcher's Deployment
+	return deployment, nil
+}
+
+// Apply The Overrides Of The Specified DispatcherTemplate To The Dispatcher Deployment
+func applyDispatcherTemplate(deployment *appsv1.Deployment, template kafkav1beta1.DispatcherTemplate) {
+	if template.Replicas != nil {
+		replicas := *template.Replicas
+		deployment.Spec.Replicas = &replicas
+	}
+	resources := &deployment.Spec.Template.Spec.Containers[0].Resources
+	for name, quantity := range template.Resources.Requests {
+		resources.Requests[name] = quantity
+	}
+	for name, quantity := range template.Resources.Limits {
+		resources.Limits[name] = quantity
+	}
+	deployment.Spec.Template.Spec.NodeSelector = template.NodeSelector
+	deployment.Spec.Template.Spec.Tolerations = template.Tolerations
+	deployment.Spec.Template.Spec.PriorityClassName = template.PriorityClassName
+}
+
+// Update The Settings Of The Existing Dispatcher Deployment Which Differ From Those Of The Desired Deployment
+// (Replicas, Resources & Scheduling - Returns False If They Are All Up To Date)
+func updateDispatcherDeploymentTemplate(existing *appsv1.Deployment, desired *appsv1.Deployment) bool {
+	existingPodSpec := &existing.Spec.Template.Spec
+	desiredPodSpec := &desired.Spec.Template.Spec
+	if equality.Semantic.DeepEqual(existing.Spec.Replicas, desired.Spec.Replicas) &&
+		len(existingPodSpec.Containers) > 0 &&
+		equality.Semantic.DeepEqual(existingPodSpec.Containers[0].Resources, desiredPodSpec.Containers[0].Resources) &&
+		equality.Semantic.DeepEqual(existingPodSpec.NodeSelector, desiredPodSpec.NodeSelector) &&
+		equality.Semantic.DeepEqual(existingPodSpec.Tolerations, desiredPodSpec.Tolerations) &&
+		existingPodSpec.PriorityClassName == desiredPodSpec.PriorityClassName {
+		return false
+	}
+	existing.Spec.Replicas = desired.Spec.Replicas
+	if len(existingPodSpec.Containers) > 0 {
+		existingPodSpec.Containers[0].Resources = desiredPodSpec.Containers[0].Resources
+	} else {
+		existingPodSpec.Containers = desiredPodSpec.Containers
+	}
+	existingPodSpec.NodeSelector = desiredPodSpec.NodeSelector
+	existingPodSpec.Tolerations = desiredPodSpec.Tolerations
+	existingPodSpec.PriorityClassName = desiredPodSpec.PriorityClassName
+	return true
+}
+
+// Create A Dispatcher Deployment Model With The Specified Name, Labels, Finalizers & Env Vars
+func (r *Reconciler) dispatcherDeploymentModel(deploymentName string, labels map[string]string, finalizers []string, envVars []corev1.EnvVar) *appsv1.Deployment {
+
+	// Replicas Int Value For De-Referencing
+	replicas := int32(r.config.Dispatcher.Replicas)
+
 	// Create The Dispatcher's Deployment
 	deployment := &appsv1.Deployment{
 		TypeMeta: metav1.TypeMeta{
@@ -392,15 +532,10 @@ func (r *Reconciler) newDispatcherDeployment(logger *zap.Logger, channel *kafkav
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      deploymentName,
 			Namespace: commonconstants.KnativeEventingNamespace,
-			Labels: map[string]string{
-				constants.AppLabel:                    deploymentName,    // Matches K8S Service Selector Key/Value Below
-				constants.KafkaChannelDispatcherLabel: "true",            // Identifies the Deployment as being a KafkaChannel "Dispatcher"
-				constants.KafkaChannelNameLabel:       channel.Name,      // Identifies the Deployment's Owning KafkaChannel's Name
-				constants.KafkaChannelNamespaceLabel:  channel.Namespace, // Identifies the Deployment's Owning KafkaChannel's Namespace
-			},
+			Labels:    labels,
 			// K8S Does NOT Support Cross-Namespace OwnerReferences
 			// Instead Manage The Lifecycle Directly Via Finalizers (No K8S Garbage Collection)
-			Finalizers: []string{r.finalizerName()},
+			Finalizers: finalizers,
 		},
 		Spec: appsv1.DeploymentSpec{
 			Replicas: &replicas,
@@ -461,7 +596,65 @@ func (r *Reconciler) newDispatcherDeployment(logger *zap.Logger, channel *kafkav
 	}
 
 	// Return The Dispatcher's Deployment
-	return deployment, nil
+	return deployment
+}
+
+//
+// Kafka Dispatcher HorizontalPodAutoscaler
+//
+
+// Reconcile The Dispatcher HorizontalPodAutoscaler (Removed When Autoscaling Is Disabled)
+func (r *Reconciler) reconcileDispatcherAutoscaler(ctx context.Context, logger *zap.Logger, channel *kafkav1beta1.KafkaChannel) error {
+	autoscaler, err := r.newDispatcherAutoscaler(logger, channel)
+	if err != nil {
+		return err
+	}
+	return util.ReconcileHorizontalPodAutoscaler(ctx, logger, r.kubeClientset, r.autoscalerLister, commonconstants.KnativeEventingNamespace, util.DispatcherDnsSafeName(channel), autoscaler)
+}
+
+// Create The Dispatcher HorizontalPodAutoscaler Model For The Specified KafkaChannel (Nil When Autoscaling Is Disabled)
+func (r *Reconciler) newDispatcherAutoscaler(logger *zap.Logger, channel *kafkav1beta1.KafkaChannel) (*autoscalingv2beta2.HorizontalPodAutoscaler, error) {
+
+	// Nothing To Create Unless The Dispatcher Is Autoscaled
+	autoscaling := r.config.Dispatcher.Autoscaling
+	if !autoscaling.Enabled() {
+		return nil, nil
+	}
+
+	// The Dispatcher Scales On CPU Utilization And/Or The Consumer Lag (Of All Subscribers) Of The Topic Per Pod
+	metrics := make([]autoscalingv2beta2.MetricSpec, 0)
+	if autoscaling.TargetCpuUtilization > 0 {
+		metrics = append(metrics, util.CpuUtilizationMetric(autoscaling.TargetCpuUtilization))
+	}
+	if autoscaling.TargetLag > 0 {
+		selector := map[string]string{constants.AutoscalerTopicMetricLabel: util.TopicName(channel)}
+		metrics = append(metrics, util.ExternalMetric(autoscaling.ConsumerLagMetric(), selector, *resource.NewQuantity(autoscaling.TargetLag, resource.DecimalSI)))
+	}
+
+	// Create The HorizontalPodAutoscaler Of The Dispatcher Deployment - Replicas Beyond The Number
+	// Of Partitions Would Not Be Assigned Any Partition By The ConsumerGroups
+	deployment, err := r.newDispatcherDeployment(logger, channel)
+	if err != nil {
+		return nil, err
+	}
+	return util.NewHorizontalPodAutoscaler(deployment, autoscaling, channel.Spec.NumPartitions, metrics), nil
+}
+
+// Get The Specified Kafka Secret (Nil If Not Found)
+func (r *Reconciler) kafkaSecret(kafkaSecret string) *corev1.Secret {
+	secret, err := r.secretLister.Secrets(commonconstants.KnativeEventingNamespace).Get(kafkaSecret)
+	if err != nil {
+		return nil
+	}
+	return secret
+}
+
+// Get The Hash Of The Data Of The Specified Kafka Secret (Empty If Not Found)
+func (r *Reconciler) kafkaSecretHash(kafkaSecret string) string {
+	if secret := r.kafkaSecret(kafkaSecret); secret != nil {
+		return util.SecretDataHash(secret)
+	}
+	return ""
 }
 
 // Create The Dispatcher Container's Env Vars
@@ -471,7 +664,47 @@ func (r *Reconciler) dispatcherDeploymentEnvVars(channel *kafkav1beta1.KafkaChan
 	topicName := util.TopicName(channel)
 
 	// Create The Dispatcher Deployment EnvVars
-	envVars := []corev1.EnvVar{
+	envVars := append(r.dispatcherBaseEnvVars(),
+		corev1.EnvVar{
+			Name:  commonenv.ChannelKeyEnvVarKey,
+			Value: util.ChannelKey(channel),
+		},
+		corev1.EnvVar{
+			Name:  commonenv.ServiceNameEnvVarKey,
+			Value: util.DispatcherDnsSafeName(channel),
+		},
+		corev1.EnvVar{
+			Name:  commonenv.KafkaTopicEnvVarKey,
+			Value: topicName,
+		},
+		corev1.EnvVar{
+			Name:  commonenv.ResyncPeriodMinutesEnvVarKey,
+			Value: strconv.Itoa(int(r.environment.ResyncPeriod / time.Minute)),
+		},
+	)
+
+	// Get The Kafka Secret Of The Channel
+	kafkaSecret := r.kafkaSecretName(channel)
+
+	// If The Kafka Secret Env Var Is Specified Then Append Relevant Env Vars
+	if len(kafkaSecret) <= 0 {
+
+		// Received Invalid Kafka Secret - Cannot Proceed
+		return nil, fmt.Errorf("invalid kafkaSecret for topic '%s'", topicName)
+
+	} else {
+
+		// Append The Kafka Brokers, Username & Password As Env Vars
+		envVars = append(envVars, dispatcherKafkaSecretEnvVars(kafkaSecret)...)
+	}
+
+	// Return The Dispatcher Deployment EnvVars Array
+	return envVars, nil
+}
+
+// Create The Env Vars Common To All Dispatcher Containers
+func (r *Reconciler) dispatcherBaseEnvVars() []corev1.EnvVar {
+	return []corev1.EnvVar{
 		{
 			Name:  system.NamespaceEnvKey,
 			Value: commonconstants.KnativeEventingNamespace,
@@ -504,37 +737,13 @@ func (r *Reconciler) dispatcherDeploymentEnvVars(channel *kafkav1beta1.KafkaChan
 			Name:  commonenv.HealthPortEnvVarKey,
 			Value: strconv.Itoa(constants.HealthPort),
 		},
-		{
-			Name:  commonenv.ChannelKeyEnvVarKey,
-			Value: util.ChannelKey(channel),
-		},
-		{
-			Name:  commonenv.ServiceNameEnvVarKey,
-			Value: util.DispatcherDnsSafeName(channel),
-		},
-		{
-			Name:  commonenv.KafkaTopicEnvVarKey,
-			Value: topicName,
-		},
-		{
-			Name:  commonenv.ResyncPeriodMinutesEnvVarKey,
-			Value: strconv.Itoa(int(r.environment.ResyncPeriod / time.Minute)),
-		},
 	}
+}
 
-	// Get The Kafka Secret From The Kafka Admin Client
-	kafkaSecret := r.adminClient.GetKafkaSecretName(topicName)
-
-	// If The Kafka Secret Env Var Is Specified Then Append Relevant Env Vars
-	if len(kafkaSecret) <= 0 {
-
-		// Received Invalid Kafka Secret - Cannot Proceed
-		return nil, fmt.Errorf("invalid kafkaSecret for topic '%s'", topicName)
-
-	} else {
-
-		// Append The Kafka Brokers As Env Var
-		envVars = append(envVars, corev1.EnvVar{
+// Create The Dispatcher Container's Kafka Brokers, Username & Password Env Vars From The Specified Kafka Secret
+func dispatcherKafkaSecretEnvVars(kafkaSecret string) []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{
 			Name: commonenv.KafkaBrokerEnvVarKey,
 			ValueFrom: &corev1.EnvVarSource{
 				SecretKeyRef: &corev1.SecretKeySelector{
@@ -542,10 +751,8 @@ func (r *Reconciler) dispatcherDeploymentEnvVars(channel *kafkav1beta1.KafkaChan
 					Key:                  constants.KafkaSecretDataKeyBrokers,
 				},
 			},
-		})
-
-		// Append The Kafka Username As Env Var
-		envVars = append(envVars, corev1.EnvVar{
+		},
+		{
 			Name: commonenv.KafkaUsernameEnvVarKey,
 			ValueFrom: &corev1.EnvVarSource{
 				SecretKeyRef: &corev1.SecretKeySelector{
@@ -553,10 +760,8 @@ func (r *Reconciler) dispatcherDeploymentEnvVars(channel *kafkav1beta1.KafkaChan
 					Key:                  constants.KafkaSecretDataKeyUsername,
 				},
 			},
-		})
-
-		// Append The Kafka Password As Env Var
-		envVars = append(envVars, corev1.EnvVar{
+		},
+		{
 			Name: commonenv.KafkaPasswordEnvVarKey,
 			ValueFrom: &corev1.EnvVarSource{
 				SecretKeyRef: &corev1.SecretKeySelector{
@@ -564,11 +769,8 @@ func (r *Reconciler) dispatcherDeploymentEnvVars(channel *kafkav1beta1.KafkaChan
 					Key:                  constants.KafkaSecretDataKeyPassword,
 				},
 			},
-		})
+		},
 	}
-
-	// Return The Dispatcher Deployment EnvVars Array
-	return envVars, nil
 }
 
 // Utility Function To Get The Finalizer Name For K8S Resources (Service, Deployment, etc.)
@@ -0,0 +1,136 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafkachannel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	commonconfig "knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/event"
+	controllertesting "knative.dev/eventing-kafka/pkg/channel/distributed/controller/testing"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/util"
+	commonconstants "knative.dev/eventing-kafka/pkg/common/constants"
+	"knative.dev/pkg/controller"
+)
+
+// The Dispatcher Template Annotations Used For Testing
+var dispatcherTemplateTestAnnotations = map[string]string{
+	kafkav1beta1.DispatcherReplicasAnnotation:      "3",
+	kafkav1beta1.DispatcherMemoryLimitAnnotation:   "1Gi",
+	kafkav1beta1.DispatcherNodeSelectorAnnotation:  "pool=kafka",
+	kafkav1beta1.DispatcherTolerationsAnnotation:   `[{"key":"dedicated","operator":"Exists","effect":"NoSchedule"}]`,
+	kafkav1beta1.DispatcherPriorityClassAnnotation: "high-priority",
+}
+
+// Verify The Dispatcher Deployment Of The Test KafkaChannel Reflects The Test Dispatcher Template Annotations
+func assertDispatcherTemplateApplied(t *testing.T, r *Reconciler, channel *kafkav1beta1.KafkaChannel) {
+	deployment, err := r.kubeClientset.AppsV1().Deployments(commonconstants.KnativeEventingNamespace).Get(context.TODO(), util.DispatcherDnsSafeName(channel), metav1.GetOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, int32(3), *deployment.Spec.Replicas)
+	resources := deployment.Spec.Template.Spec.Containers[0].Resources
+	assert.True(t, resource.MustParse("1Gi").Equal(resources.Limits[corev1.ResourceMemory]))
+	assert.True(t, resource.MustParse(controllertesting.DispatcherMemoryRequest).Equal(resources.Requests[corev1.ResourceMemory]))
+	assert.True(t, resource.MustParse(controllertesting.DispatcherCpuLimit).Equal(resources.Limits[corev1.ResourceCPU]))
+	assert.Equal(t, map[string]string{"pool": "kafka"}, deployment.Spec.Template.Spec.NodeSelector)
+	assert.Equal(t, []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}}, deployment.Spec.Template.Spec.Tolerations)
+	assert.Equal(t, "high-priority", deployment.Spec.Template.Spec.PriorityClassName)
+}
+
+// Test The Creation Of A Dispatcher Deployment Customized By The KafkaChannel's Annotations
+func TestReconcileDispatcherTemplate(t *testing.T) {
+	r := newDispatcherPoolTestReconciler(t, "", 0)
+	channel := controllertesting.NewKafkaChannel(controllertesting.WithInitializedConditions)
+	channel.Annotations = dispatcherTemplateTestAnnotations
+
+	assert.Nil(t, r.reconcileDispatcher(newDispatcherPoolTestContext(), channel))
+	assertDispatcherTemplateApplied(t, r, channel)
+}
+
+// Test The Update Of An Existing Dispatcher Deployment When The KafkaChannel's Annotations Change
+func TestReconcileDispatcherTemplateChanged(t *testing.T) {
+	r := newDispatcherPoolTestReconciler(t, "", 0,
+		controllertesting.NewKafkaChannelDispatcherService(),
+		controllertesting.NewKafkaChannelDispatcherDeployment())
+	channel := controllertesting.NewKafkaChannel(controllertesting.WithInitializedConditions)
+	channel.Annotations = dispatcherTemplateTestAnnotations
+
+	assert.Nil(t, r.reconcileDispatcher(newDispatcherPoolTestContext(), channel))
+	assertDispatcherTemplateApplied(t, r, channel)
+}
+
+// Test The Reconciliation Of The Dispatcher HorizontalPodAutoscaler Scaling On The Consumer Lag Of The Topic
+func TestReconcileDispatcherAutoscaler(t *testing.T) {
+	deployment := controllertesting.NewKafkaChannelDispatcherDeployment()
+	scaledReplicas := int32(2)
+	deployment.Spec.Replicas = &scaledReplicas
+	r := newDispatcherPoolTestReconciler(t, "", 0, controllertesting.NewKafkaChannelDispatcherService(), deployment)
+	r.config.Dispatcher.Autoscaling = commonconfig.EKAutoscalingConfig{MaxReplicas: 1000, TargetLag: 500}
+	channel := controllertesting.NewKafkaChannel(controllertesting.WithInitializedConditions)
+
+	assert.Nil(t, r.reconcileDispatcher(newDispatcherPoolTestContext(), channel))
+
+	// The Maximum Replicas Are Capped By The Number Of Partitions
+	name := util.DispatcherDnsSafeName(channel)
+	autoscaler, err := r.kubeClientset.AutoscalingV2beta2().HorizontalPodAutoscalers(commonconstants.KnativeEventingNamespace).Get(context.TODO(), name, metav1.GetOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, name, autoscaler.Spec.ScaleTargetRef.Name)
+	assert.Equal(t, channel.Spec.NumPartitions, autoscaler.Spec.MaxReplicas)
+	assert.Len(t, autoscaler.Spec.Metrics, 1)
+	assert.Equal(t, commonconfig.DefaultAutoscalingLagMetric, autoscaler.Spec.Metrics[0].External.Metric.Name)
+	assert.Equal(t, util.TopicName(channel), autoscaler.Spec.Metrics[0].External.Metric.Selector.MatchLabels[constants.AutoscalerTopicMetricLabel])
+	assert.Equal(t, int64(500), autoscaler.Spec.Metrics[0].External.Target.AverageValue.Value())
+
+	// The Replicas Of The Existing Deployment Are Left To The HorizontalPodAutoscaler
+	updated, err := r.kubeClientset.AppsV1().Deployments(commonconstants.KnativeEventingNamespace).Get(context.TODO(), name, metav1.GetOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, scaledReplicas, *updated.Spec.Replicas)
+
+	// The HorizontalPodAutoscaler Is Removed With The Dispatcher (Once The Informer Has Cached It)
+	listers := controllertesting.NewListers([]runtime.Object{autoscaler})
+	r.autoscalerLister = listers.GetHorizontalPodAutoscalerLister()
+	assert.Nil(t, r.finalizeDispatcher(newDispatcherPoolTestContext(), channel))
+	_, err = r.kubeClientset.AutoscalingV2beta2().HorizontalPodAutoscalers(commonconstants.KnativeEventingNamespace).Get(context.TODO(), name, metav1.GetOptions{})
+	assert.True(t, errors.IsNotFound(err))
+}
+
+// Test That The Dispatcher Deployment Is Rolled Out With A New Kafka Secret Hash When The Kafka Secret Changes
+func TestReconcileDispatcherKafkaSecretChanged(t *testing.T) {
+	deployment := controllertesting.NewKafkaChannelDispatcherDeployment()
+	util.SetKafkaSecretHashAnnotation(deployment, "PreviousHash")
+	secret := controllertesting.NewKafkaSecret()
+	r := newDispatcherPoolTestReconciler(t, "", 0, secret, controllertesting.NewKafkaChannelDispatcherService(), deployment)
+	channel := controllertesting.NewKafkaChannel(controllertesting.WithInitializedConditions)
+	recorder := record.NewFakeRecorder(10)
+
+	assert.Nil(t, r.reconcileDispatcher(controller.WithEventRecorder(context.TODO(), recorder), channel))
+
+	updated, err := r.kubeClientset.AppsV1().Deployments(commonconstants.KnativeEventingNamespace).Get(context.TODO(), deployment.Name, metav1.GetOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, util.SecretDataHash(secret), updated.Spec.Template.Annotations[constants.KafkaSecretHashAnnotation])
+	assert.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, event.KafkaSecretChanged.String())
+}
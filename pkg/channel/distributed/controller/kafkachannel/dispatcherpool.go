@@ -0,0 +1,389 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafkachannel
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	commonenv "knative.dev/eventing-kafka/pkg/channel/distributed/common/env"
+	commonk8s "knative.dev/eventing-kafka/pkg/channel/distributed/common/k8s"
+	commonutil "knative.dev/eventing-kafka/pkg/channel/distributed/common/util"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/event"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/util"
+	commonconstants "knative.dev/eventing-kafka/pkg/common/constants"
+	"knative.dev/pkg/controller"
+)
+
+//
+// Shared Dispatcher Pool
+//
+// When the Dispatcher mode is "shared" the KafkaChannels of each Kafka Secret are dispatched by a pool of
+// Dispatcher Deployments instead of one Deployment per KafkaChannel.  Each pool member dispatches the
+// KafkaChannels whose key hashes to its member number (see DispatcherPoolMember()), and KafkaChannels
+// annotated with a dedicated dispatcher keep their own Deployment.  The pool is shared by many KafkaChannels,
+// so it is not owned (or finalized) by any of them, and is only removed when the pool shrinks or the
+// Dispatcher mode changes.  The members of a pool are reconciled on a work queue of their own, keyed by
+// their Kafka Secret, so that they are reconciled once for all of its KafkaChannels (which only enqueue
+// the pool and take the status of their member).  The dedicated Dispatcher of a KafkaChannel joining
+// the pool is only removed once its member is ready to take over.
+//
+
+// Determine Whether The Specified KafkaChannel Is Dispatched By The Shared Dispatcher Pool Of Its Kafka Secret
+func (r *Reconciler) sharedDispatcher(channel *kafkav1beta1.KafkaChannel) bool {
+	if !r.config.Dispatcher.Shared() {
+		return false
+	}
+	dedicated, err := channel.DedicatedDispatcher()
+	return err == nil && !dedicated // Invalid Annotations Are Rejected By The Webhook, But Dispatchers Treat Them As Dedicated
+}
+
+// Reconcile The Shared Dispatcher Of The Specified KafkaChannel (The Pool Member Its Key Hashes To)
+func (r *Reconciler) reconcileSharedDispatcher(ctx context.Context, logger *zap.Logger, channel *kafkav1beta1.KafkaChannel) error {
+
+	// Get The KafkaChannel's Kafka Secret
+	kafkaSecret := r.kafkaSecretName(channel)
+	if len(kafkaSecret) <= 0 {
+		channel.Status.MarkDispatcherFailed(event.DispatcherDeploymentReconciliationFailed.String(), "No Kafka Secret For Shared Dispatcher Pool")
+		return fmt.Errorf("invalid kafkaSecret for topic '%s'", util.TopicName(channel))
+	}
+
+	// The Members Of The Pool Are Reconciled From The Pool's Own Key (Once For All Its KafkaChannels)
+	if r.enqueuePool != nil {
+		r.enqueuePool(kafkaSecret)
+	}
+
+	// The KafkaChannel's Dispatcher Status Is That Of The Pool Member Dispatching It
+	poolSize := r.config.Dispatcher.SharedPoolSize()
+	channelMember := commonutil.DispatcherPoolMember(util.ChannelKey(channel), poolSize)
+	logger = logger.With(zap.String("KafkaSecret", kafkaSecret), zap.Int("PoolMember", channelMember), zap.Int("PoolSize", poolSize))
+	deployment, err := r.deploymentLister.Deployments(commonconstants.KnativeEventingNamespace).Get(util.DispatcherPoolDnsSafeName(kafkaSecret, channelMember))
+	if errors.IsNotFound(err) {
+		channel.Status.MarkDispatcherUnknown(event.DispatcherDeploymentReconciliationFailed.String(), "Shared Dispatcher Deployment Not Yet Created")
+		logger.Info("Shared Dispatcher Deployment Not Yet Created")
+		return nil // Enqueued Again When The Pool Member Is Created
+	} else if err != nil {
+		channel.Status.MarkDispatcherFailed(event.DispatcherDeploymentReconciliationFailed.String(), "Failed To Get Shared Dispatcher Deployment: %v", err)
+		return fmt.Errorf("failed to reconcile shared dispatcher resources")
+	} else if deployment.Labels[constants.KafkaChannelDispatcherPoolSizeLabel] != strconv.Itoa(poolSize) {
+		channel.Status.MarkDispatcherUnknown(event.DispatcherDeploymentReconciliationFailed.String(), "Shared Dispatcher Deployment Not Yet Resized")
+		logger.Info("Shared Dispatcher Deployment Not Yet Resized")
+		return nil // Enqueued Again When The Pool Member Is Updated
+	}
+	channel.Status.PropagateDispatcherStatus(&deployment.Status)
+
+	// Remove Any Dedicated Dispatcher The KafkaChannel Had Before Joining The Pool, Once The Pool Member Is Ready To
+	// Take Over (So That The KafkaChannel Is Never Left Without A Dispatcher)
+	if !channel.Status.GetCondition(kafkav1beta1.KafkaChannelConditionDispatcherReady).IsTrue() {
+		logger.Info("Shared Dispatcher Deployment Not Yet Ready - Keeping Any Dedicated Dispatcher")
+		return nil // Enqueued Again When The Pool Member Becomes Ready
+	}
+	if err = r.finalizeDispatcher(ctx, channel); err != nil {
+		return fmt.Errorf("failed to reconcile shared dispatcher resources")
+	}
+
+	// Return Success
+	logger.Info("Successfully Reconciled Shared Dispatcher")
+	return nil
+}
+
+// Reconciles The Shared Dispatcher Pools On Their Own Work Queue, Keyed By The Namespace/Name Of Their Kafka Secret
+type dispatcherPoolReconciler struct {
+	reconciler *Reconciler
+	recorder   record.EventRecorder
+}
+
+// Verify The Dispatcher Pool Reconciler Implements The Reconciler Interface
+var _ controller.Reconciler = (*dispatcherPoolReconciler)(nil)
+
+// Reconcile The Shared Dispatcher Pool Of The Specified Kafka Secret Key
+func (p *dispatcherPoolReconciler) Reconcile(ctx context.Context, key string) error {
+	_, kafkaSecret, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		p.reconciler.logger.Error("Invalid Shared Dispatcher Pool Key", zap.String("Key", key), zap.Error(err))
+		return nil // Not Retried
+	}
+	return p.reconciler.reconcileDispatcherPool(controller.WithEventRecorder(ctx, p.recorder), kafkaSecret)
+}
+
+// Reconcile Every Member Of The Shared Dispatcher Pool Of The Specified Kafka Secret, Removing Those Beyond Its Size
+func (r *Reconciler) reconcileDispatcherPool(ctx context.Context, kafkaSecret string) error {
+
+	// Get The Pool's Logger & The Kafka Secret (Recording The Pool's Events If Found)
+	logger := r.logger.With(zap.String("KafkaSecret", kafkaSecret))
+	secret := r.kafkaSecret(kafkaSecret)
+	recordWarning := func(reason event.CoreV1EventType, message string, err error) {
+		logger.Error(message, zap.Error(err))
+		if secret != nil {
+			controller.GetEventRecorder(ctx).Eventf(secret, corev1.EventTypeWarning, reason.String(), "%s: %v", message, err)
+		}
+	}
+
+	// A Pool Is Only Kept While The KafkaChannels Share Dispatchers (Otherwise Removed With Their Dedicated Dispatchers)
+	poolSize := 0
+	if r.config.Dispatcher.Shared() {
+		poolSize = r.config.Dispatcher.SharedPoolSize()
+	}
+
+	// Reconcile Every Member Of The Pool (Each KafkaChannel Is Dispatched By The Member Its Key Hashes To)
+	poolErr := false
+	for member := 0; member < poolSize; member++ {
+		memberLogger := logger.With(zap.Int("PoolMember", member))
+
+		// Reconcile The Pool Member's Service (For Prometheus Only)
+		serviceErr := r.reconcileDispatcherPoolService(ctx, memberLogger, kafkaSecret, member, poolSize)
+		if serviceErr != nil {
+			recordWarning(event.DispatcherServiceReconciliationFailed, "Failed To Reconcile Shared Dispatcher Service", serviceErr)
+			poolErr = true
+		}
+
+		// Reconcile The Pool Member's Deployment
+		_, deploymentErr := r.reconcileDispatcherPoolDeployment(ctx, memberLogger, kafkaSecret, member, poolSize)
+		if deploymentErr != nil {
+			recordWarning(event.DispatcherDeploymentReconciliationFailed, "Failed To Reconcile Shared Dispatcher Deployment", deploymentErr)
+			poolErr = true
+		}
+	}
+
+	// Remove The Members Beyond The Size Of The Pool
+	deleteErr := r.deleteDispatcherPools(ctx, logger, kafkaSecret, poolSize)
+	if deleteErr != nil {
+		recordWarning(event.DispatcherDeploymentReconciliationFailed, "Failed To Delete Shared Dispatcher Pool Members", deleteErr)
+	}
+
+	// Return Results
+	if poolErr || deleteErr != nil {
+		return fmt.Errorf("failed to reconcile shared dispatcher pool of kafka secret '%s'", kafkaSecret)
+	} else {
+		logger.Info("Successfully Reconciled Shared Dispatcher Pool", zap.Int("PoolSize", poolSize))
+		return nil
+	}
+}
+
+// Reconcile The Service Of The Specified Pool Member
+func (r *Reconciler) reconcileDispatcherPoolService(ctx context.Context, logger *zap.Logger, kafkaSecret string, member int, poolSize int) error {
+
+	// Attempt To Get The Pool Member's Service
+	serviceName := util.DispatcherPoolDnsSafeName(kafkaSecret, member)
+	_, err := r.serviceLister.Services(commonconstants.KnativeEventingNamespace).Get(serviceName)
+	if errors.IsNotFound(err) {
+
+		// If The Service Was Not Found - Then Create A New One For The Pool Member
+		logger.Info("Shared Dispatcher Service Not Found - Creating New One")
+		service := r.dispatcherServiceModel(serviceName, r.dispatcherPoolLabels(kafkaSecret, member, poolSize, map[string]string{
+			constants.K8sAppDispatcherSelectorLabel: constants.K8sAppDispatcherSelectorValue, // Prometheus ServiceMonitor
+		}), nil)
+		_, err = r.kubeClientset.CoreV1().Services(service.Namespace).Create(ctx, service, metav1.CreateOptions{})
+		if err != nil {
+			return err
+		}
+		logger.Info("Successfully Created Shared Dispatcher Service")
+		return nil
+	}
+	return err
+}
+
+// Reconcile The Deployment Of The Specified Pool Member, Updating It When The Size Of The Pool Has Changed
+func (r *Reconciler) reconcileDispatcherPoolDeployment(ctx context.Context, logger *zap.Logger, kafkaSecret string, member int, poolSize int) (*appsv1.Deployment, error) {
+
+	// Attempt To Get The Pool Member's Deployment
+	deploymentName := util.DispatcherPoolDnsSafeName(kafkaSecret, member)
+	deployment, err := r.deploymentLister.Deployments(commonconstants.KnativeEventingNamespace).Get(deploymentName)
+	if errors.IsNotFound(err) {
+
+		// If The Deployment Was Not Found - Then Create A New One For The Pool Member
+		logger.Info("Shared Dispatcher Deployment Not Found - Creating New One")
+		deployment, err = r.kubeClientset.AppsV1().Deployments(commonconstants.KnativeEventingNamespace).Create(ctx, r.newDispatcherPoolDeployment(kafkaSecret, member, poolSize), metav1.CreateOptions{})
+		if err != nil {
+			return nil, err
+		}
+		logger.Info("Successfully Created Shared Dispatcher Deployment")
+		return deployment, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	// The Members Of A Resized Pool Are Updated To Reassign The KafkaChannels (Rolling Out New Dispatchers)
+	if deployment.Labels[constants.KafkaChannelDispatcherPoolSizeLabel] != strconv.Itoa(poolSize) {
+		desired := r.newDispatcherPoolDeployment(kafkaSecret, member, poolSize)
+		updated := deployment.DeepCopy()
+		updateDispatcherPoolDeploymentSize(updated, desired)
+		util.UpdateKafkaSecretHashAnnotation(updated, desired, true) // Along With The Roll Out (If Created Without It)
+		deployment, err = r.kubeClientset.AppsV1().Deployments(updated.Namespace).Update(ctx, updated, metav1.UpdateOptions{})
+		if err != nil {
+			return nil, err
+		}
+		logger.Info("Successfully Updated Shared Dispatcher Deployment With New Pool Size", zap.Int("PoolSize", poolSize))
+		return deployment, nil
+	}
+
+	// The Members Of The Pool Are Restarted With The New Credentials When The Kafka Secret Changes
+	updated := deployment.DeepCopy()
+	if changed, secretChanged := util.UpdateKafkaSecretHashAnnotation(updated, r.newDispatcherPoolDeployment(kafkaSecret, member, poolSize), false); changed {
+		deployment, err = r.kubeClientset.AppsV1().Deployments(updated.Namespace).Update(ctx, updated, metav1.UpdateOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if secret := r.kafkaSecret(kafkaSecret); secretChanged && secret != nil {
+			controller.GetEventRecorder(ctx).Eventf(secret, corev1.EventTypeNormal, event.KafkaSecretChanged.String(), "Kafka Secret Changed - Restarting Shared Dispatcher Deployment: \"%s/%s\"", updated.Namespace, updated.Name)
+		}
+		logger.Info("Successfully Updated Shared Dispatcher Deployment With Kafka Secret Hash", zap.Bool("KafkaSecretChanged", secretChanged))
+		return deployment, nil
+	}
+
+	// Return The Verified Deployment
+	logger.Debug("Successfully Verified Shared Dispatcher Deployment")
+	return deployment, nil
+}
+
+// Update The Pool Size Of An Existing Pool Member Deployment (Leaving The Fields It Does Not Own As They Are)
+func updateDispatcherPoolDeploymentSize(existing *appsv1.Deployment, desired *appsv1.Deployment) {
+	existing.Labels = desired.Labels
+	existing.Spec.Replicas = desired.Spec.Replicas
+	existingPodSpec := &existing.Spec.Template.Spec
+	desiredPodSpec := &desired.Spec.Template.Spec
+	if len(existingPodSpec.Containers) > 0 {
+		existingPodSpec.Containers[0].Env = desiredPodSpec.Containers[0].Env
+	} else {
+		existingPodSpec.Containers = desiredPodSpec.Containers
+	}
+}
+
+// Create The Deployment Model Of The Specified Pool Member
+func (r *Reconciler) newDispatcherPoolDeployment(kafkaSecret string, member int, poolSize int) *appsv1.Deployment {
+	deploymentName := util.DispatcherPoolDnsSafeName(kafkaSecret, member)
+
+	// The Pool Member Dispatches The KafkaChannels Of The Kafka Secret Assigned To It (Instead Of A ChannelKey & Topic)
+	envVars := append(r.dispatcherBaseEnvVars(),
+		corev1.EnvVar{
+			Name:  commonenv.DispatcherPoolMemberEnvVarKey,
+			Value: strconv.Itoa(member),
+		},
+		corev1.EnvVar{
+			Name:  commonenv.DispatcherPoolSizeEnvVarKey,
+			Value: strconv.Itoa(poolSize),
+		},
+		corev1.EnvVar{
+			Name:  commonenv.DispatcherPoolSecretEnvVarKey,
+			Value: kafkaSecret,
+		},
+		corev1.EnvVar{
+			Name:  commonenv.ServiceNameEnvVarKey,
+			Value: deploymentName,
+		},
+		corev1.EnvVar{
+			Name:  commonenv.ResyncPeriodMinutesEnvVarKey,
+			Value: strconv.Itoa(int(r.environment.ResyncPeriod / time.Minute)),
+		},
+	)
+	envVars = append(envVars, dispatcherKafkaSecretEnvVars(kafkaSecret)...)
+
+	deployment := r.dispatcherDeploymentModel(deploymentName, r.dispatcherPoolLabels(kafkaSecret, member, poolSize, map[string]string{
+		constants.AppLabel: deploymentName, // Matches K8S Service Selector Key/Value
+	}), nil, envVars)
+	util.SetKafkaSecretHashAnnotation(deployment, r.kafkaSecretHash(kafkaSecret)) // Rolls Out New Dispatchers When The Kafka Secret Changes
+	return deployment
+}
+
+// Create The Labels Of The Resources Of The Specified Pool Member (Plus The Specified Extra Labels)
+func (r *Reconciler) dispatcherPoolLabels(kafkaSecret string, member int, poolSize int, extraLabels map[string]string) map[string]string {
+	poolLabels := map[string]string{
+		constants.KafkaChannelDispatcherLabel:           "true",                                    // Identifies the Resource as being a KafkaChannel "Dispatcher"
+		constants.KafkaSecretLabel:                      commonk8s.TruncateLabelValue(kafkaSecret), // Identifies the Kafka Secret of the KafkaChannels in the Pool
+		constants.KafkaChannelDispatcherPoolMemberLabel: strconv.Itoa(member),                      // Identifies the Resource's Pool Member
+		constants.KafkaChannelDispatcherPoolSizeLabel:   strconv.Itoa(poolSize),                    // Identifies the Size of the Pool
+	}
+	for key, value := range extraLabels {
+		poolLabels[key] = value
+	}
+	return poolLabels
+}
+
+// Delete The Pool Members Of The Specified Kafka Secret (Or Of All Kafka Secrets If Empty) From The Specified Member On
+func (r *Reconciler) deleteDispatcherPools(ctx context.Context, logger *zap.Logger, kafkaSecret string, fromMember int) error {
+
+	// Select The Resources Of The Pool Members
+	selector, err := dispatcherPoolSelector(kafkaSecret)
+	if err != nil {
+		return err
+	}
+
+	// Delete The Deployments Of The Selected Pool Members
+	deployments, err := r.deploymentLister.Deployments(commonconstants.KnativeEventingNamespace).List(selector)
+	if err != nil {
+		return err
+	}
+	for _, deployment := range deployments {
+		if member, err := strconv.Atoi(deployment.Labels[constants.KafkaChannelDispatcherPoolMemberLabel]); err == nil && member < fromMember {
+			continue
+		}
+		err = r.kubeClientset.AppsV1().Deployments(deployment.Namespace).Delete(ctx, deployment.Name, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		logger.Info("Successfully Deleted Shared Dispatcher Deployment", zap.String("Deployment", deployment.Name))
+	}
+
+	// Delete The Services Of The Selected Pool Members
+	services, err := r.serviceLister.Services(commonconstants.KnativeEventingNamespace).List(selector)
+	if err != nil {
+		return err
+	}
+	for _, service := range services {
+		if member, err := strconv.Atoi(service.Labels[constants.KafkaChannelDispatcherPoolMemberLabel]); err == nil && member < fromMember {
+			continue
+		}
+		err = r.kubeClientset.CoreV1().Services(service.Namespace).Delete(ctx, service.Name, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		logger.Info("Successfully Deleted Shared Dispatcher Service", zap.String("Service", service.Name))
+	}
+
+	// Return Success
+	return nil
+}
+
+// Create A Selector Of The Resources Of The Pool Members Of The Specified Kafka Secret (Or Of All Kafka Secrets If Empty)
+func dispatcherPoolSelector(kafkaSecret string) (labels.Selector, error) {
+	requirement, err := labels.NewRequirement(constants.KafkaChannelDispatcherPoolMemberLabel, selection.Exists, nil)
+	if err != nil {
+		return nil, err
+	}
+	selector := labels.NewSelector().Add(*requirement)
+	if len(kafkaSecret) > 0 {
+		secretRequirement, err := labels.NewRequirement(constants.KafkaSecretLabel, selection.Equals, []string{commonk8s.TruncateLabelValue(kafkaSecret)})
+		if err != nil {
+			return nil, err
+		}
+		selector = selector.Add(*secretRequirement)
+	}
+	return selector, nil
+}
@@ -0,0 +1,291 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafkachannel
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakekubeclientset "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	commonconfig "knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	commonenv "knative.dev/eventing-kafka/pkg/channel/distributed/common/env"
+	commonutil "knative.dev/eventing-kafka/pkg/channel/distributed/common/util"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	controllertesting "knative.dev/eventing-kafka/pkg/channel/distributed/controller/testing"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/util"
+	commonconstants "knative.dev/eventing-kafka/pkg/common/constants"
+	"knative.dev/pkg/controller"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Create A Reconciler With The Specified Dispatcher Mode, Pool Size & Existing K8S Resources
+func newDispatcherPoolTestReconciler(t *testing.T, mode string, poolSize int, objects ...runtime.Object) *Reconciler {
+	listers := controllertesting.NewListers(objects)
+	config := controllertesting.NewConfig()
+	config.Dispatcher.Mode = mode
+	config.Dispatcher.PoolSize = poolSize
+	return &Reconciler{
+		logger:           logtesting.TestLogger(t).Desugar(),
+		kubeClientset:    fakekubeclientset.NewSimpleClientset(objects...),
+		adminClient:      &controllertesting.MockAdminClient{},
+		environment:      controllertesting.NewEnvironment(),
+		config:           config,
+		deploymentLister: listers.GetDeploymentLister(),
+		serviceLister:    listers.GetServiceLister(),
+		autoscalerLister: listers.GetHorizontalPodAutoscalerLister(),
+		secretLister:     listers.GetSecretLister(),
+	}
+}
+
+// Create A Context With An Event Recorder For Testing
+func newDispatcherPoolTestContext() context.Context {
+	recorder := record.NewBroadcaster().NewRecorder(scheme.Scheme, corev1.EventSource{Component: "TestEventSource"})
+	return controller.WithEventRecorder(context.TODO(), recorder)
+}
+
+// Get The Names Of The Dispatcher Deployments & Services In The Fake K8S ClientSet (Sorted)
+func dispatcherPoolTestNames(t *testing.T, r *Reconciler) ([]string, []string) {
+	deployments, err := r.kubeClientset.AppsV1().Deployments(commonconstants.KnativeEventingNamespace).List(context.TODO(), metav1.ListOptions{})
+	assert.Nil(t, err)
+	deploymentNames := make([]string, 0)
+	for _, deployment := range deployments.Items {
+		deploymentNames = append(deploymentNames, deployment.Name)
+	}
+	sort.Strings(deploymentNames)
+	services, err := r.kubeClientset.CoreV1().Services(commonconstants.KnativeEventingNamespace).List(context.TODO(), metav1.ListOptions{})
+	assert.Nil(t, err)
+	serviceNames := make([]string, 0)
+	for _, service := range services.Items {
+		serviceNames = append(serviceNames, service.Name)
+	}
+	sort.Strings(serviceNames)
+	return deploymentNames, serviceNames
+}
+
+// Test Which KafkaChannels Are Dispatched By A Shared Dispatcher Pool
+func TestSharedDispatcher(t *testing.T) {
+	shared := newDispatcherPoolTestReconciler(t, commonconfig.DispatcherModeShared, 2)
+	perChannel := newDispatcherPoolTestReconciler(t, "", 2)
+
+	channel := controllertesting.NewKafkaChannel()
+	assert.True(t, shared.sharedDispatcher(channel))
+	assert.False(t, perChannel.sharedDispatcher(channel))
+
+	channel.Annotations = map[string]string{kafkav1beta1.DedicatedDispatcherAnnotation: "true"}
+	assert.False(t, shared.sharedDispatcher(channel))
+	channel.Annotations[kafkav1beta1.DedicatedDispatcherAnnotation] = "false"
+	assert.True(t, shared.sharedDispatcher(channel))
+	channel.Annotations[kafkav1beta1.DedicatedDispatcherAnnotation] = "invalid"
+	assert.False(t, shared.sharedDispatcher(channel))
+}
+
+// Test The Reconciliation Of The Members Of A Shared Dispatcher Pool From The Pool's Key
+func TestReconcileDispatcherPool(t *testing.T) {
+	r := newDispatcherPoolTestReconciler(t, commonconfig.DispatcherModeShared, 2,
+		controllertesting.NewKafkaChannelDispatcherService(),
+		controllertesting.NewKafkaChannelDispatcherDeployment())
+	poolReconciler := &dispatcherPoolReconciler{reconciler: r, recorder: controller.GetEventRecorder(newDispatcherPoolTestContext())}
+
+	assert.Nil(t, poolReconciler.Reconcile(context.TODO(), commonconstants.KnativeEventingNamespace+"/"+controllertesting.KafkaSecretName))
+
+	// The Members Of The Pool Are Created Alongside The (Untouched) Dedicated Dispatcher
+	dedicated := util.DispatcherDnsSafeName(controllertesting.NewKafkaChannel())
+	member0 := util.DispatcherPoolDnsSafeName(controllertesting.KafkaSecretName, 0)
+	member1 := util.DispatcherPoolDnsSafeName(controllertesting.KafkaSecretName, 1)
+	deploymentNames, serviceNames := dispatcherPoolTestNames(t, r)
+	assert.ElementsMatch(t, []string{dedicated, member0, member1}, deploymentNames)
+	assert.ElementsMatch(t, []string{dedicated, member0, member1}, serviceNames)
+
+	// The Pool Members Dispatch The KafkaChannels Of The Kafka Secret Assigned To Them
+	deployment, err := r.kubeClientset.AppsV1().Deployments(commonconstants.KnativeEventingNamespace).Get(context.TODO(), member1, metav1.GetOptions{})
+	assert.Nil(t, err)
+	assert.Empty(t, deployment.Finalizers)
+	assert.Equal(t, "1", deployment.Labels[constants.KafkaChannelDispatcherPoolMemberLabel])
+	assert.Equal(t, "2", deployment.Labels[constants.KafkaChannelDispatcherPoolSizeLabel])
+	assert.Equal(t, controllertesting.KafkaSecretName, deployment.Labels[constants.KafkaSecretLabel])
+	envVars := make(map[string]string)
+	for _, envVar := range deployment.Spec.Template.Spec.Containers[0].Env {
+		envVars[envVar.Name] = envVar.Value
+	}
+	assert.Equal(t, "1", envVars[commonenv.DispatcherPoolMemberEnvVarKey])
+	assert.Equal(t, "2", envVars[commonenv.DispatcherPoolSizeEnvVarKey])
+	assert.Equal(t, controllertesting.KafkaSecretName, envVars[commonenv.DispatcherPoolSecretEnvVarKey])
+	assert.Equal(t, member1, envVars[commonenv.ServiceNameEnvVarKey])
+	assert.NotContains(t, envVars, commonenv.ChannelKeyEnvVarKey)
+	assert.NotContains(t, envVars, commonenv.KafkaTopicEnvVarKey)
+}
+
+// Test The Reconciliation Of A KafkaChannel Joining The Shared Dispatcher Pool, Whose Dedicated Dispatcher Is Only
+// Removed Once The Pool Member Dispatching It Is Ready
+func TestReconcileSharedDispatcher(t *testing.T) {
+	channel := controllertesting.NewKafkaChannel(controllertesting.WithInitializedConditions)
+	dedicated := util.DispatcherDnsSafeName(channel)
+	member := commonutil.DispatcherPoolMember(util.ChannelKey(channel), 2)
+	template := newDispatcherPoolTestReconciler(t, commonconfig.DispatcherModeShared, 2)
+	newMemberDeployment := func(available corev1.ConditionStatus) *appsv1.Deployment {
+		deployment := template.newDispatcherPoolDeployment(controllertesting.KafkaSecretName, member, 2)
+		deployment.Status.Conditions = []appsv1.DeploymentCondition{{Type: appsv1.DeploymentAvailable, Status: available}}
+		return deployment
+	}
+
+	tests := []struct {
+		name             string
+		memberDeployment *appsv1.Deployment
+		expectReady      corev1.ConditionStatus
+		expectDedicated  bool
+	}{
+		{
+			name:            "Pool Member Not Yet Created",
+			expectReady:     corev1.ConditionUnknown,
+			expectDedicated: true,
+		},
+		{
+			name:             "Pool Member Not Yet Resized",
+			memberDeployment: template.newDispatcherPoolDeployment(controllertesting.KafkaSecretName, member, 3),
+			expectReady:      corev1.ConditionUnknown,
+			expectDedicated:  true,
+		},
+		{
+			name:             "Pool Member Not Yet Ready",
+			memberDeployment: newMemberDeployment(corev1.ConditionFalse),
+			expectReady:      corev1.ConditionFalse,
+			expectDedicated:  true,
+		},
+		{
+			name:             "Pool Member Ready",
+			memberDeployment: newMemberDeployment(corev1.ConditionTrue),
+			expectReady:      corev1.ConditionTrue,
+			expectDedicated:  false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			objects := []runtime.Object{controllertesting.NewKafkaChannelDispatcherService(), controllertesting.NewKafkaChannelDispatcherDeployment()}
+			if test.memberDeployment != nil {
+				objects = append(objects, test.memberDeployment)
+			}
+			r := newDispatcherPoolTestReconciler(t, commonconfig.DispatcherModeShared, 2, objects...)
+			enqueuedPools := make([]string, 0)
+			r.enqueuePool = func(kafkaSecret string) { enqueuedPools = append(enqueuedPools, kafkaSecret) }
+			testChannel := channel.DeepCopy()
+
+			assert.Nil(t, r.reconcileDispatcher(newDispatcherPoolTestContext(), testChannel))
+
+			// The Pool Is Enqueued Rather Than Reconciled With The KafkaChannel
+			assert.Equal(t, []string{controllertesting.KafkaSecretName}, enqueuedPools)
+			assert.Equal(t, test.expectReady, testChannel.Status.GetCondition(kafkav1beta1.KafkaChannelConditionDispatcherReady).Status)
+			deploymentNames, _ := dispatcherPoolTestNames(t, r)
+			if test.expectDedicated {
+				assert.Contains(t, deploymentNames, dedicated)
+			} else {
+				assert.NotContains(t, deploymentNames, dedicated)
+			}
+		})
+	}
+}
+
+// Test The Reconciliation Of A Resized Shared Dispatcher Pool
+func TestReconcileSharedDispatcherResized(t *testing.T) {
+
+	// A Pool Of Three Members Shrinks To Two
+	objects := make([]runtime.Object, 0)
+	template := newDispatcherPoolTestReconciler(t, commonconfig.DispatcherModeShared, 3)
+	for member := 0; member < 3; member++ {
+		deployment := template.newDispatcherPoolDeployment(controllertesting.KafkaSecretName, member, 3)
+		deployment.Spec.Template.Annotations = map[string]string{"TestAnnotation": "TestValue"} // Not Owned By The Controller
+		objects = append(objects, deployment)
+		objects = append(objects, template.dispatcherServiceModel(util.DispatcherPoolDnsSafeName(controllertesting.KafkaSecretName, member),
+			template.dispatcherPoolLabels(controllertesting.KafkaSecretName, member, 3, nil), nil))
+	}
+	r := newDispatcherPoolTestReconciler(t, commonconfig.DispatcherModeShared, 2, objects...)
+
+	assert.Nil(t, r.reconcileDispatcherPool(newDispatcherPoolTestContext(), controllertesting.KafkaSecretName))
+
+	// The Remaining Members Are Updated With The New Size Of The Pool
+	member0 := util.DispatcherPoolDnsSafeName(controllertesting.KafkaSecretName, 0)
+	member1 := util.DispatcherPoolDnsSafeName(controllertesting.KafkaSecretName, 1)
+	deploymentNames, serviceNames := dispatcherPoolTestNames(t, r)
+	assert.Equal(t, []string{member0, member1}, deploymentNames)
+	assert.Equal(t, []string{member0, member1}, serviceNames)
+	for _, name := range deploymentNames {
+		deployment, err := r.kubeClientset.AppsV1().Deployments(commonconstants.KnativeEventingNamespace).Get(context.TODO(), name, metav1.GetOptions{})
+		assert.Nil(t, err)
+		assert.Equal(t, strconv.Itoa(2), deployment.Labels[constants.KafkaChannelDispatcherPoolSizeLabel])
+		assert.Contains(t, deployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: commonenv.DispatcherPoolSizeEnvVarKey, Value: strconv.Itoa(2)})
+		assert.Equal(t, "TestValue", deployment.Spec.Template.Annotations["TestAnnotation"])
+	}
+}
+
+// Test That The Shared Dispatcher Pools Are Removed Once The KafkaChannels Have Dedicated Dispatchers
+func TestReconcileDedicatedDispatcherDeletesPools(t *testing.T) {
+	template := newDispatcherPoolTestReconciler(t, commonconfig.DispatcherModeShared, 1)
+	r := newDispatcherPoolTestReconciler(t, "", 0,
+		template.newDispatcherPoolDeployment(controllertesting.KafkaSecretName, 0, 1),
+		template.dispatcherServiceModel(util.DispatcherPoolDnsSafeName(controllertesting.KafkaSecretName, 0),
+			template.dispatcherPoolLabels(controllertesting.KafkaSecretName, 0, 1, nil), nil))
+	channel := controllertesting.NewKafkaChannel(controllertesting.WithInitializedConditions)
+
+	assert.Nil(t, r.reconcileDispatcher(newDispatcherPoolTestContext(), channel))
+
+	dedicated := util.DispatcherDnsSafeName(channel)
+	deploymentNames, serviceNames := dispatcherPoolTestNames(t, r)
+	assert.Equal(t, []string{dedicated}, deploymentNames)
+	assert.Equal(t, []string{dedicated}, serviceNames)
+}
+
+// Test That The Shared Dispatcher Pools Are Only Removed When A KafkaChannel Is Assigned Its Own Dispatcher
+func TestReconcileDedicatedDispatcherKeepsPoolsOnceAssigned(t *testing.T) {
+	template := newDispatcherPoolTestReconciler(t, commonconfig.DispatcherModeShared, 1)
+	pool := util.DispatcherPoolDnsSafeName(controllertesting.KafkaSecretName, 0)
+	r := newDispatcherPoolTestReconciler(t, "", 0,
+		template.newDispatcherPoolDeployment(controllertesting.KafkaSecretName, 0, 1),
+		controllertesting.NewKafkaChannelDispatcherService(),
+		controllertesting.NewKafkaChannelDispatcherDeployment())
+	channel := controllertesting.NewKafkaChannel(controllertesting.WithInitializedConditions)
+
+	assert.Nil(t, r.reconcileDispatcher(newDispatcherPoolTestContext(), channel))
+
+	deploymentNames, _ := dispatcherPoolTestNames(t, r)
+	assert.Contains(t, deploymentNames, pool)
+	assert.Contains(t, deploymentNames, util.DispatcherDnsSafeName(channel))
+}
+
+// Test That The Members Of The Shared Dispatcher Pool Are Rolled Out With A New Kafka Secret Hash When The Kafka Secret Changes
+func TestReconcileSharedDispatcherKafkaSecretChanged(t *testing.T) {
+	secret := controllertesting.NewKafkaSecret()
+	template := newDispatcherPoolTestReconciler(t, commonconfig.DispatcherModeShared, 1)
+	deployment := template.newDispatcherPoolDeployment(controllertesting.KafkaSecretName, 0, 1)
+	util.SetKafkaSecretHashAnnotation(deployment, "PreviousHash")
+	r := newDispatcherPoolTestReconciler(t, commonconfig.DispatcherModeShared, 1, secret, deployment)
+
+	assert.Nil(t, r.reconcileDispatcherPool(newDispatcherPoolTestContext(), controllertesting.KafkaSecretName))
+
+	updated, err := r.kubeClientset.AppsV1().Deployments(commonconstants.KnativeEventingNamespace).Get(context.TODO(), deployment.Name, metav1.GetOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, util.SecretDataHash(secret), updated.Spec.Template.Annotations[constants.KafkaSecretHashAnnotation])
+}
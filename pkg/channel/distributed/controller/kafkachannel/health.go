@@ -0,0 +1,129 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafkachannel
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	kafkaadmin "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/admin"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/util"
+	commonconsumer "knative.dev/eventing-kafka/pkg/common/consumer"
+)
+
+// Update The Lag Of The Subscribers Of The KafkaChannels Every Interval, Until The Context Is Done
+func (r *Reconciler) refreshSubscriberLag(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.updateSubscriberLag(ctx)
+		}
+	}
+}
+
+// Measure The Lag Of The Subscribers Of The Ready KafkaChannels & Write It To Their Status
+//
+// The lag is measured by the controller from the offsets committed by the consumer groups of the subscribers, rather
+// than by the replicas of the dispatcher which each only consume some of the partitions, so that a stalled consumer
+// is reported as lagging too.  The status is updated directly (without reconciling the KafkaChannels), a conflicting
+// update being retried at the next interval.  AdminClients unable to measure the lag (e.g. EventHubs) leave it unset.
+func (r *Reconciler) updateSubscriberLag(ctx context.Context) {
+
+	// The KafkaChannels Are Only Known Once The Informer Has Synced
+	if !r.kafkachannelInformer.HasSynced() {
+		return
+	}
+	channels, err := r.kafkachannelLister.List(labels.Everything())
+	if err != nil {
+		r.logger.Error("Failed To List KafkaChannels To Update The Lag Of Their Subscribers", zap.Error(err))
+		return
+	}
+
+	// Use A Dedicated AdminClient (Rather Than The Reconciler's) So As Not To Block Reconciliation Meanwhile
+	adminClient, err := kafkaadmin.CreateAdminClient(ctx, r.saramaConfig, constants.ControllerComponentName, r.adminClientType)
+	if err != nil {
+		r.logger.Error("Failed To Create Kafka AdminClient To Update The Lag Of The Subscribers", zap.Error(err))
+		return
+	}
+	defer func() {
+		if err := adminClient.Close(); err != nil {
+			r.logger.Error("Failed To Close Kafka AdminClient Used To Update The Lag Of The Subscribers", zap.Error(err))
+		}
+	}()
+
+	thresholds := kafkav1beta1.SubscriberHealthThresholds{Lag: r.config.Dispatcher.SubscriberLagThreshold}
+	for _, channel := range channels {
+		if channel.DeletionTimestamp != nil || !channel.Status.IsReady() {
+			continue
+		}
+		logger := util.ChannelLogger(r.logger, channel)
+		lags, err := r.subscriberLags(ctx, adminClient, channel)
+		if err != nil {
+			logger.Warn("Unable To Measure The Lag Of The Subscribers", zap.Error(err))
+			continue
+		} else if lags == nil {
+			continue
+		}
+
+		// Don't Modify The Informer's Copy
+		desired := channel.DeepCopy()
+		desired.Status.SetSubscriberLag(lags, thresholds)
+		if equality.Semantic.DeepEqual(channel.Status, desired.Status) {
+			continue
+		}
+		_, err = r.kafkaClientSet.MessagingV1beta1().KafkaChannels(channel.Namespace).UpdateStatus(ctx, desired, metav1.UpdateOptions{})
+		if err != nil {
+			logger.Warn("Failed To Update The Lag Of The Subscribers - Retrying Next Interval", zap.Error(err))
+		}
+	}
+}
+
+// Measure The Lag Of Each Subscriber Of The Specified KafkaChannel (Nil If The AdminClient Is Unable To)
+func (r *Reconciler) subscriberLags(ctx context.Context, adminClient kafkaadmin.AdminClientInterface, channel *kafkav1beta1.KafkaChannel) (map[types.UID]int64, error) {
+	topicAdminClient, err := r.topicAdminClientOf(adminClient, channel)
+	if err != nil {
+		return nil, err
+	}
+	lagReader, ok := topicAdminClient.(kafkaadmin.ConsumerGroupLagReader)
+	if !ok {
+		return nil, nil
+	}
+	lags := make(map[types.UID]int64, len(channel.Spec.Subscribers))
+	for _, subscriber := range channel.Spec.Subscribers {
+		// The Consumer Groups Are Those Of The Dispatcher (See DispatcherImpl.subscribe())
+		delivery, _ := channel.SubscriberDelivery(subscriber.UID)
+		groupId := commonconsumer.GroupID(fmt.Sprintf("kafka.%s", subscriber.UID), delivery)
+		lag, err := lagReader.ConsumerGroupLag(ctx, groupId, []string{util.TopicName(channel)})
+		if err != nil {
+			return nil, fmt.Errorf("consumer group %s: %w", groupId, err)
+		}
+		lags[subscriber.UID] = lag
+	}
+	return lags, nil
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafkachannel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	kafkaadmin "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/admin"
+	controllertesting "knative.dev/eventing-kafka/pkg/channel/distributed/controller/testing"
+	fakekafkaclientset "knative.dev/eventing-kafka/pkg/client/clientset/versioned/fake"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Mock AdminClient Able To Measure The Lag Of Consumer Groups
+type lagAdminClient struct {
+	controllertesting.MockAdminClient
+	lags map[string]int64
+}
+
+func (m *lagAdminClient) ConsumerGroupLag(_ context.Context, groupId string, _ []string) (int64, error) {
+	return m.lags[groupId], nil
+}
+
+// Test The Reconciler's Update Of The Lag Of The Subscribers Of The KafkaChannels
+func TestUpdateSubscriberLag(t *testing.T) {
+
+	// Test Data
+	withSubscribers := func(channel *kafkav1beta1.KafkaChannel) {
+		channel.Spec.Subscribers = []eventingduck.SubscriberSpec{{UID: ownedSubscriberUID}, {UID: orphanedSubscriberUID}}
+	}
+	readyChannel := controllertesting.NewKafkaChannel(
+		withSubscribers,
+		controllertesting.WithInitializedConditions,
+		controllertesting.WithAddress,
+		controllertesting.WithKafkaChannelServiceReady,
+		controllertesting.WithReceiverServiceReady,
+		controllertesting.WithReceiverDeploymentReady,
+		controllertesting.WithTopicReady,
+		func(channel *kafkav1beta1.KafkaChannel) {
+			channel.Status.PropagateDispatcherStatus(&appsv1.DeploymentStatus{
+				Conditions: []appsv1.DeploymentCondition{{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue}},
+			})
+		},
+	)
+	assert.True(t, readyChannel.Status.IsReady())
+	unreadyChannel := controllertesting.NewKafkaChannel(withSubscribers, controllertesting.WithInitializedConditions)
+	unreadyChannel.Name = "unready-channel"
+
+	// Mock The Creation Of The Kafka AdminClient
+	adminClient := &lagAdminClient{
+		lags: map[string]int64{
+			"kafka." + ownedSubscriberUID:    5,
+			"kafka." + orphanedSubscriberUID: 500,
+		},
+	}
+	newKafkaAdminClientWrapperPlaceholder := kafkaadmin.NewKafkaAdminClientWrapper
+	kafkaadmin.NewKafkaAdminClientWrapper = func(ctx context.Context, saramaConfig *sarama.Config, clientId string, namespace string) (kafkaadmin.AdminClientInterface, error) {
+		return adminClient, nil
+	}
+	defer func() {
+		kafkaadmin.NewKafkaAdminClientWrapper = newKafkaAdminClientWrapperPlaceholder
+	}()
+
+	// Create A Reconciler To Test
+	configuration := controllertesting.NewConfig()
+	configuration.Dispatcher.SubscriberLagThreshold = 100
+	listers := controllertesting.NewListers([]runtime.Object{readyChannel, unreadyChannel})
+	kafkaClientSet := fakekafkaclientset.NewSimpleClientset(readyChannel, unreadyChannel)
+	reconciler := &Reconciler{
+		logger:               logtesting.TestLogger(t).Desugar(),
+		adminClientType:      kafkaadmin.Kafka,
+		config:               configuration,
+		kafkachannelLister:   listers.GetKafkaChannelLister(),
+		kafkachannelInformer: &syncedInformer{synced: true},
+		kafkaClientSet:       kafkaClientSet,
+	}
+
+	// Perform The Test
+	reconciler.updateSubscriberLag(context.TODO())
+
+	// Verify The Lag Of The Ready KafkaChannel's Subscribers Was Written To Its Status
+	channel, err := kafkaClientSet.MessagingV1beta1().KafkaChannels(readyChannel.Namespace).Get(context.TODO(), readyChannel.Name, metav1.GetOptions{})
+	assert.Nil(t, err)
+	lags := make(map[types.UID]int64)
+	for _, subscriberHealth := range channel.Status.SubscriberHealth {
+		lags[subscriberHealth.UID] = subscriberHealth.Lag
+	}
+	assert.Equal(t, map[types.UID]int64{ownedSubscriberUID: 5, orphanedSubscriberUID: 500}, lags)
+	assert.True(t, channel.Status.GetCondition(kafkav1beta1.KafkaChannelConditionSubscriberLagging).IsTrue())
+	assert.True(t, adminClient.CloseCalled())
+
+	// Verify The Unready KafkaChannel Was Left Alone
+	channel, err = kafkaClientSet.MessagingV1beta1().KafkaChannels(unreadyChannel.Namespace).Get(context.TODO(), unreadyChannel.Name, metav1.GetOptions{})
+	assert.Nil(t, err)
+	assert.Empty(t, channel.Status.SubscriberHealth)
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafkachannel
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+	kafkaadmin "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/admin"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/util"
+	"knative.dev/eventing-kafka/pkg/common/orphans"
+)
+
+// The Interval Of The Orphan Collection (Zero While Disabled)
+func (r *Reconciler) orphanCollectionInterval() time.Duration {
+	return r.config.Kafka.OrphanCollection.Interval()
+}
+
+//
+// Collect The Orphaned Topics & Consumer Groups
+//
+// The topics and consumer groups of the KafkaChannels & subscribers whose finalization failed (or whose namespace
+// was force-deleted) are left behind in Kafka.  They are found by comparing the topics & consumer groups of every
+// Kafka Secret with the existing KafkaChannels, and are deleted if so configured (otherwise only logged).  The
+// topics of the distributed KafkaChannels are only named "<namespace>.<name>", like the topics of many other
+// applications, so only the topics of the KafkaChannels seen since the controller started (and the topics derived
+// from them) are ever collected, and never the topics which have been used as a dead letter sink.
+//
+func (r *Reconciler) collectOrphans(ctx context.Context) {
+
+	// The KafkaChannels Of An Unsynced Cache Would Make Every Topic Look Orphaned
+	if !r.kafkachannelInformer.HasSynced() {
+		r.logger.Info("Skipping Orphan Collection Until The KafkaChannels Have Synced")
+		return
+	}
+
+	// Use A Dedicated AdminClient (Rather Than The Reconciler's) So As Not To Block Reconciliation Meanwhile
+	adminClient, err := kafkaadmin.CreateAdminClient(ctx, r.saramaConfig, constants.ControllerComponentName, r.adminClientType)
+	if err != nil {
+		r.logger.Error("Failed To Create Kafka AdminClient For Orphan Collection", zap.Error(err))
+		return
+	}
+	defer func() {
+		if err := adminClient.Close(); err != nil {
+			r.logger.Error("Failed To Close Kafka AdminClient For Orphan Collection", zap.Error(err))
+		}
+	}()
+
+	// Every KafkaChannel Owns Its Topic & Consumer Groups In All The Kafka Secrets (Clusters)
+	adminClients := map[string]kafkaadmin.AdminClientInterface{adminClient.GetKafkaSecretName(""): adminClient}
+	if secretsAdminClient, ok := adminClient.(kafkaadmin.KafkaSecretsAdminClient); ok {
+		adminClients = make(map[string]kafkaadmin.AdminClientInterface)
+		for _, kafkaSecretName := range secretsAdminClient.GetKafkaSecretNames() {
+			adminClients[kafkaSecretName] = secretsAdminClient.GetKafkaSecretAdminClient(kafkaSecretName)
+		}
+	}
+	for kafkaSecretName, secretAdminClient := range adminClients {
+		logger := r.logger.With(zap.String("KafkaSecret", kafkaSecretName))
+		collector := &orphans.Collector{
+			IsChannelTopic: isChannelTopic,
+			Seen:           r.orphansSeen,
+			DryRun:         !r.config.Kafka.OrphanCollection.Delete,
+			Logger:         logger.Sugar(),
+		}
+		result, err := collector.Collect(orphanTopicAdmin(ctx, secretAdminClient), orphanGroupAdmin(ctx, secretAdminClient), r.orphanOwners)
+		if err != nil {
+			logger.Error("Failed To Collect Orphaned Topics & Consumer Groups", zap.Error(err))
+		} else {
+			logger.Info("Collected Orphaned Topics & Consumer Groups", zap.Int("Topics", len(result.Topics)),
+				zap.Int("Groups", len(result.Groups)), zap.Bool("DryRun", collector.DryRun))
+		}
+	}
+}
+
+// The Owners Of The Topics & Consumer Groups Of The KafkaChannels Currently Known To The Lister
+func (r *Reconciler) orphanOwners() (*orphans.Owners, error) {
+	channels, err := r.kafkachannelLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	owners := orphans.NewOwners()
+	for _, channel := range channels {
+		owners.AddChannel(channel, util.TopicName(channel))
+	}
+	return owners, nil
+}
+
+// Determine Whether The Specified Topic Is Named Like The Topic Of A KafkaChannel ("<namespace>.<name>")
+func isChannelTopic(topic string) bool {
+	separator := strings.Index(topic, ".")
+	return separator > 0 && separator < len(topic)-1 && len(validation.IsDNS1123Label(topic[:separator])) == 0
+}
+
+// Adapt The Specified AdminClient To An Orphan TopicAdmin (Nil If Unable To List Topics)
+func orphanTopicAdmin(ctx context.Context, adminClient kafkaadmin.AdminClientInterface) orphans.TopicAdmin {
+	if topicLister, ok := adminClient.(kafkaadmin.TopicLister); ok {
+		return &topicAdmin{ctx: ctx, adminClient: adminClient, topicLister: topicLister}
+	}
+	return nil
+}
+
+// Adapt The Specified AdminClient To An Orphan GroupAdmin (Nil If Unable To Manage Consumer Groups)
+func orphanGroupAdmin(ctx context.Context, adminClient kafkaadmin.AdminClientInterface) orphans.GroupAdmin {
+	if consumerGroupAdmin, ok := adminClient.(kafkaadmin.ConsumerGroupAdmin); ok {
+		return &groupAdmin{ctx: ctx, consumerGroupAdmin: consumerGroupAdmin}
+	}
+	return nil
+}
+
+// Orphan TopicAdmin Implementation Wrapping An AdminClient
+type topicAdmin struct {
+	ctx         context.Context
+	adminClient kafkaadmin.AdminClientInterface
+	topicLister kafkaadmin.TopicLister
+}
+
+func (a *topicAdmin) ListTopics() (map[string]sarama.TopicDetail, error) {
+	return a.topicLister.ListTopics(a.ctx)
+}
+
+// Delete The Topic, Converting The TopicError (Avoiding A Non-Nil Error Wrapping A Nil Or Successful TopicError)
+func (a *topicAdmin) DeleteTopic(topic string) error {
+	topicError := a.adminClient.DeleteTopic(a.ctx, topic)
+	if topicError == nil || topicError.Err == sarama.ErrNoError {
+		return nil
+	} else if topicError.Err == sarama.ErrUnknownTopicOrPartition {
+		return sarama.ErrUnknownTopicOrPartition
+	}
+	return topicError
+}
+
+// Orphan GroupAdmin Implementation Wrapping An AdminClient
+type groupAdmin struct {
+	ctx                context.Context
+	consumerGroupAdmin kafkaadmin.ConsumerGroupAdmin
+}
+
+func (a *groupAdmin) ListConsumerGroups() (map[string]string, error) {
+	return a.consumerGroupAdmin.ListConsumerGroups(a.ctx)
+}
+
+func (a *groupAdmin) DeleteConsumerGroup(group string) error {
+	return a.consumerGroupAdmin.DeleteConsumerGroup(a.ctx, group)
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafkachannel
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	kafkaadmin "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/admin"
+	controllertesting "knative.dev/eventing-kafka/pkg/channel/distributed/controller/testing"
+	"knative.dev/eventing-kafka/pkg/common/orphans"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test Subscriber UIDs
+const (
+	ownedSubscriberUID    = "6f8a4c7e-7b1a-4b59-9a54-8a3e2f7c1d20"
+	orphanedSubscriberUID = "0c9b0e1c-3d55-4c36-8f44-5b8f2d0a7e11"
+)
+
+// Mock AdminClient Able To List Topics & Manage Consumer Groups, Tracking Its Deletions
+type orphansAdminClient struct {
+	controllertesting.MockAdminClient
+	topics  map[string]sarama.TopicDetail
+	groups  map[string]string
+	deleted []string
+}
+
+func (m *orphansAdminClient) ListTopics(_ context.Context) (map[string]sarama.TopicDetail, error) {
+	return m.topics, nil
+}
+
+func (m *orphansAdminClient) DeleteTopic(_ context.Context, topicName string) *sarama.TopicError {
+	m.deleted = append(m.deleted, topicName)
+	return &sarama.TopicError{Err: sarama.ErrNoError}
+}
+
+func (m *orphansAdminClient) ListConsumerGroups(_ context.Context) (map[string]string, error) {
+	return m.groups, nil
+}
+
+func (m *orphansAdminClient) DeleteConsumerGroup(_ context.Context, group string) error {
+	m.deleted = append(m.deleted, group)
+	return nil
+}
+
+// Mock SharedIndexInformer Only Reporting Whether It Has Synced
+type syncedInformer struct {
+	cache.SharedIndexInformer
+	synced bool
+}
+
+func (i *syncedInformer) HasSynced() bool {
+	return i.synced
+}
+
+// Test The Reconciler's Collection Of Orphaned Topics & Consumer Groups
+func TestCollectOrphans(t *testing.T) {
+
+	// Test Data
+	channel := controllertesting.NewKafkaChannel(func(channel *kafkav1beta1.KafkaChannel) {
+		channel.Spec.Subscribers = []eventingduck.SubscriberSpec{{UID: ownedSubscriberUID}}
+	})
+	channelTopicName := controllertesting.KafkaChannelNamespace + "." + controllertesting.KafkaChannelName
+	orphanedTopicName := controllertesting.KafkaChannelNamespace + ".deleted-channel"
+	orphanedGroupId := "kafka." + orphanedSubscriberUID
+
+	// Define The TestCases
+	tests := []struct {
+		name        string
+		synced      bool
+		delete      bool
+		wantDeleted []string
+	}{
+		{name: "Not Synced", synced: false, delete: true, wantDeleted: nil},
+		{name: "Dry Run", synced: true, delete: false, wantDeleted: nil},
+		{name: "Delete", synced: true, delete: true, wantDeleted: []string{orphanedGroupId, orphanedTopicName}},
+	}
+
+	// Run The TestCases
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+
+			// Mock The Creation Of The Kafka AdminClient
+			adminClient := &orphansAdminClient{
+				topics: map[string]sarama.TopicDetail{
+					channelTopicName:                 {NumPartitions: 1},
+					channelTopicName + ".retry-1":    {NumPartitions: 1},
+					orphanedTopicName:                {NumPartitions: 1},
+					"unrelated.topic":                {NumPartitions: 1},
+					"__consumer_offsets":             {NumPartitions: 1},
+					"Not_A_Namespace.other-topic":    {NumPartitions: 1},
+					"topic-without-namespace-prefix": {NumPartitions: 1},
+				},
+				groups: map[string]string{
+					"kafka." + ownedSubscriberUID: "consumer",
+					orphanedGroupId:               "consumer",
+					"other-application":           "consumer",
+				},
+			}
+			newKafkaAdminClientWrapperPlaceholder := kafkaadmin.NewKafkaAdminClientWrapper
+			kafkaadmin.NewKafkaAdminClientWrapper = func(ctx context.Context, saramaConfig *sarama.Config, clientId string, namespace string) (kafkaadmin.AdminClientInterface, error) {
+				return adminClient, nil
+			}
+			defer func() {
+				kafkaadmin.NewKafkaAdminClientWrapper = newKafkaAdminClientWrapperPlaceholder
+			}()
+
+			// Create A Reconciler To Test
+			configuration := controllertesting.NewConfig()
+			configuration.Kafka.OrphanCollection.IntervalMinutes = 60
+			configuration.Kafka.OrphanCollection.Delete = test.delete
+			listers := controllertesting.NewListers([]runtime.Object{channel})
+			seen := orphans.NewSeen() // The Deleted KafkaChannel Was Seen By An Earlier Collection
+			seenOwners := orphans.NewOwners()
+			seenOwners.AddChannel(&kafkav1beta1.KafkaChannel{}, orphanedTopicName)
+			seen.Add(seenOwners)
+			reconciler := &Reconciler{
+				logger:               logtesting.TestLogger(t).Desugar(),
+				adminClientType:      kafkaadmin.Kafka,
+				config:               configuration,
+				kafkachannelLister:   listers.GetKafkaChannelLister(),
+				kafkachannelInformer: &syncedInformer{synced: test.synced},
+				orphansSeen:          seen,
+			}
+
+			// Perform The Test
+			reconciler.collectOrphans(context.TODO())
+
+			// Verify The Results
+			assert.Equal(t, time.Hour, reconciler.orphanCollectionInterval())
+			sort.Strings(adminClient.deleted)
+			assert.Equal(t, test.wantDeleted, adminClient.deleted)
+			assert.Equal(t, test.synced, adminClient.CloseCalled())
+		})
+	}
+}
+
+// Test The Determination Of Topics Named Like KafkaChannel Topics
+func TestIsChannelTopic(t *testing.T) {
+	assert.True(t, isChannelTopic("namespace.name"))
+	assert.True(t, isChannelTopic("namespace.name.retry-1"))
+	assert.False(t, isChannelTopic("name"))
+	assert.False(t, isChannelTopic("namespace."))
+	assert.False(t, isChannelTopic(".name"))
+	assert.False(t, isChannelTopic("Namespace.name"))
+	assert.False(t, isChannelTopic("__consumer_offsets"))
+}
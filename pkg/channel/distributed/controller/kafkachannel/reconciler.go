@@ -26,6 +26,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
 	appsv1listers "k8s.io/client-go/listers/apps/v1"
+	autoscalingv2beta2listers "k8s.io/client-go/listers/autoscaling/v2beta2"
 	corev1listers "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
@@ -39,6 +40,7 @@ import (
 	kafkaclientset "knative.dev/eventing-kafka/pkg/client/clientset/versioned"
 	"knative.dev/eventing-kafka/pkg/client/injection/reconciler/messaging/v1beta1/kafkachannel"
 	kafkalisters "knative.dev/eventing-kafka/pkg/client/listers/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/common/orphans"
 	kubeclient "knative.dev/pkg/client/injection/kube/client"
 	"knative.dev/pkg/reconciler"
 )
@@ -57,8 +59,12 @@ type Reconciler struct {
 	kafkachannelInformer cache.SharedIndexInformer
 	deploymentLister     appsv1listers.DeploymentLister
 	serviceLister        corev1listers.ServiceLister
+	autoscalerLister     autoscalingv2beta2listers.HorizontalPodAutoscalerLister
+	secretLister         corev1listers.SecretLister
 	configObserver       func(configMap *corev1.ConfigMap)
+	enqueuePool          func(kafkaSecret string)
 	adminMutex           *sync.Mutex
+	orphansSeen          *orphans.Seen
 }
 
 var (
@@ -152,6 +158,7 @@ func (r *Reconciler) FinalizeKind(ctx context.Context, channel *kafkav1beta1.Kaf
 	defer r.ClearKafkaAdminClient()
 
 	// Finalize The Dispatcher (Manual Finalization Due To Cross-Namespace Ownership)
+	// (The Resources Are Torn Down Immediately - There Is No Drain Mode As With The Consolidated KafkaChannel)
 	err := r.finalizeDispatcher(ctx, channel)
 	if err != nil {
 		logger.Info("Failed To Finalize KafkaChannel", zap.Error(err))
@@ -189,7 +196,7 @@ func (r *Reconciler) reconcile(ctx context.Context, channel *kafkav1beta1.KafkaC
 	// instead check the Kafka Secret associated with the KafkaChannel here.
 	//
 
-	if len(r.adminClient.GetKafkaSecretName(util.TopicName(channel))) > 0 {
+	if len(r.kafkaSecretName(channel)) > 0 {
 		channel.Status.MarkConfigTrue()
 	} else {
 		channel.Status.MarkConfigFailed(event.KafkaSecretReconciled.String(), "No Kafka Secret For KafkaChannel")
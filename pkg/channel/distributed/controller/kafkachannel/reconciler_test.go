@@ -606,6 +606,8 @@ func TestReconcile(t *testing.T) {
 			kafkachannelInformer: nil,
 			deploymentLister:     listers.GetDeploymentLister(),
 			serviceLister:        listers.GetServiceLister(),
+			autoscalerLister:     listers.GetHorizontalPodAutoscalerLister(),
+			secretLister:         listers.GetSecretLister(),
 			kafkaClientSet:       fakekafkaclient.Get(ctx),
 			adminMutex:           &sync.Mutex{},
 		}
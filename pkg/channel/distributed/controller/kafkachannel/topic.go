@@ -25,9 +25,11 @@ import (
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/admin"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/event"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/util"
+	"knative.dev/eventing-kafka/pkg/common/retrytopic"
 	"knative.dev/pkg/controller"
 )
 
@@ -45,8 +47,18 @@ func (r *Reconciler) reconcileKafkaTopic(ctx context.Context, channel *kafkav1be
 	replicationFactor := util.ReplicationFactor(channel, r.config, r.logger)
 	retentionMillis := util.RetentionMillis(channel, r.config, r.logger)
 
+	// Get The AdminClient Of The Channel's Kafka Secret
+	adminClient, err := r.topicAdminClient(channel)
+
 	// Create The Topic (Handles Case Where Already Exists)
-	err := r.createTopic(ctx, logger, topicName, numPartitions, replicationFactor, retentionMillis)
+	if err == nil {
+		err = r.createTopic(ctx, logger, adminClient, topicName, numPartitions, replicationFactor, retentionMillis)
+	}
+
+	// Create / Delete The Subscribers' Retry Topics (Sharing The Topic Configuration)
+	if err == nil {
+		err = r.reconcileRetryTopics(ctx, logger, adminClient, channel, topicName, numPartitions, replicationFactor, retentionMillis)
+	}
 
 	// Log Results & Return Status
 	if err != nil {
@@ -69,8 +81,16 @@ func (r *Reconciler) finalizeKafkaTopic(ctx context.Context, channel *kafkav1bet
 	// Get Channel Specific Logger & Add Topic Name
 	logger := util.ChannelLogger(r.logger, channel).With(zap.String("TopicName", topicName))
 
-	// Delete The Kafka Topic & Handle Error Response
-	err := r.deleteTopic(ctx, logger, topicName)
+	// Get The AdminClient Of The Channel's Kafka Secret
+	adminClient, err := r.topicAdminClient(channel)
+
+	// Delete The Kafka Topic & Any Retry Topics & Handle Error Response
+	if err == nil {
+		err = r.deleteTopic(ctx, logger, adminClient, topicName)
+	}
+	if err == nil {
+		err = r.deleteRetryTopics(ctx, logger, adminClient, channel, topicName, nil)
+	}
 	if err != nil {
 		logger.Error("Failed To Finalize Kafka Topic", zap.Error(err))
 		return err
@@ -80,8 +100,76 @@ func (r *Reconciler) finalizeKafkaTopic(ctx context.Context, channel *kafkav1bet
 	}
 }
 
+// Reconcile The Retry Topics Of The Channel's Subscribers Which Use Them, Deleting Any Others
+func (r *Reconciler) reconcileRetryTopics(ctx context.Context, logger *zap.Logger, adminClient admin.AdminClientInterface, channel *kafkav1beta1.KafkaChannel, topicName string, partitions int32, replicationFactor int16, retentionMillis int64) error {
+
+	// Create The Retry Topics Of Each Subscriber (If Any)
+	retryTopicNames := make(map[string]bool)
+	for _, subscriber := range channel.Spec.Subscribers {
+		delivery, _ := channel.SubscriberDelivery(subscriber.UID) // Already Validated By The Webhook
+		retryTopics, err := retrytopic.SubscriberTopics(topicName, subscriber, delivery)
+		if err != nil {
+			return err
+		}
+		for _, retryTopicName := range retryTopics.Names() {
+			retryTopicNames[retryTopicName] = true
+			err = r.createTopic(ctx, logger.With(zap.String("RetryTopicName", retryTopicName)), adminClient, retryTopicName, partitions, replicationFactor, retentionMillis)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// Delete Any Other Retry Topics (Removed Subscribers, Reduced Retry Counts, etc...)
+	return r.deleteRetryTopics(ctx, logger, adminClient, channel, topicName, retryTopicNames)
+}
+
+//
+// Delete The Retry Topics Of The Channel, Except Those To Keep
+//
+// Note - Only AdminClients able to list topics can find the retry topics of removed subscribers.  With any other
+//        AdminClient only the retry topics of the channel's current subscribers are deleted.
+//
+func (r *Reconciler) deleteRetryTopics(ctx context.Context, logger *zap.Logger, adminClient admin.AdminClientInterface, channel *kafkav1beta1.KafkaChannel, topicName string, keep map[string]bool) error {
+
+	// Determine The Candidate Retry Topics
+	retryTopicNames := make(map[string]bool)
+	if topicLister, ok := adminClient.(admin.TopicLister); ok {
+		topics, err := topicLister.ListTopics(ctx)
+		if err != nil {
+			logger.Error("Failed To List Topics", zap.Error(err))
+			return err
+		}
+		for name := range topics {
+			if retrytopic.IsName(topicName, name) {
+				retryTopicNames[name] = true
+			}
+		}
+	} else {
+		for _, subscriber := range channel.Spec.Subscribers {
+			delivery, _ := channel.SubscriberDelivery(subscriber.UID)
+			delivery.RetryTopics = true // Regardless Of Whether They Are Currently Enabled
+			retryTopics, _ := retrytopic.SubscriberTopics(topicName, subscriber, delivery)
+			for _, name := range retryTopics.Names() {
+				retryTopicNames[name] = true
+			}
+		}
+	}
+
+	// Delete Those Not To Be Kept
+	for name := range retryTopicNames {
+		if !keep[name] {
+			err := r.deleteTopic(ctx, logger.With(zap.String("RetryTopicName", name)), adminClient, name)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // Create The Specified Kafka Topic
-func (r *Reconciler) createTopic(ctx context.Context, logger *zap.Logger, topicName string, partitions int32, replicationFactor int16, retentionMillis int64) error {
+func (r *Reconciler) createTopic(ctx context.Context, logger *zap.Logger, adminClient admin.AdminClientInterface, topicName string, partitions int32, replicationFactor int16, retentionMillis int64) error {
 
 	// Create The TopicDefinition
 	retentionMillisString := strconv.FormatInt(retentionMillis, 10)
@@ -95,7 +183,7 @@ func (r *Reconciler) createTopic(ctx context.Context, logger *zap.Logger, topicN
 	}
 
 	// Attempt To Create The Topic & Process TopicError Results (Including Success ;)
-	err := r.adminClient.CreateTopic(ctx, topicName, topicDetail)
+	err := adminClient.CreateTopic(ctx, topicName, topicDetail)
 	if err != nil {
 		logger := logger.With(zap.Int16("KError", int16(err.Err)))
 		switch err.Err {
@@ -116,10 +204,10 @@ func (r *Reconciler) createTopic(ctx context.Context, logger *zap.Logger, topicN
 }
 
 // Delete The Specified Kafka Topic
-func (r *Reconciler) deleteTopic(ctx context.Context, logger *zap.Logger, topicName string) error {
+func (r *Reconciler) deleteTopic(ctx context.Context, logger *zap.Logger, adminClient admin.AdminClientInterface, topicName string) error {
 
 	// Attempt To Delete The Topic & Process Results
-	err := r.adminClient.DeleteTopic(ctx, topicName)
+	err := adminClient.DeleteTopic(ctx, topicName)
 	if err != nil {
 		logger := logger.With(zap.Int16("KError", int16(err.Err)))
 		switch err.Err {
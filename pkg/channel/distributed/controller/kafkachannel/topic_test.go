@@ -22,14 +22,21 @@ import (
 
 	"github.com/Shopify/sarama"
 	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
 	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	commonconfig "knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/admin"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
 	controllertesting "knative.dev/eventing-kafka/pkg/channel/distributed/controller/testing"
+	"knative.dev/eventing-kafka/pkg/common/retrytopic"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
 	"knative.dev/pkg/controller"
 	logtesting "knative.dev/pkg/logging/testing"
+	"knative.dev/pkg/ptr"
 )
 
 // Define The Topic TestCase Type
@@ -284,3 +291,102 @@ func createMockAdminClientForTestCase(t *testing.T, tc TopicTestCase) *controlle
 		},
 	}
 }
+
+// Test The Reconciliation & Finalization Of The Subscribers' Retry Topics
+func TestReconcileRetryTopics(t *testing.T) {
+
+	// Test Data - A Channel Whose Single Subscriber Uses Two Retry Topics
+	subscriberUID := types.UID("6f8a4c7e-7b1a-4b59-9a54-8a3e2f7c1d20")
+	channel := controllertesting.NewKafkaChannel(controllertesting.WithFinalizer)
+	channel.Annotations = map[string]string{kafkav1beta1.DeliveryRetryTopicsAnnotation: "true"}
+	channel.Spec.Subscribers = []eventingduck.SubscriberSpec{{
+		UID:      subscriberUID,
+		Delivery: &eventingduck.DeliverySpec{Retry: ptr.Int32(2)},
+	}}
+	retryTopicNames := []string{
+		retrytopic.Name(controllertesting.TopicName, subscriberUID, 1),
+		retrytopic.Name(controllertesting.TopicName, subscriberUID, 2),
+	}
+
+	// Create A Mock Kafka AdminClient Tracking The Created & Deleted Topics
+	var createdTopics, deletedTopics []string
+	mockAdminClient := &controllertesting.MockAdminClient{
+		MockCreateTopicFunc: func(ctx context.Context, topicName string, topicDetail *sarama.TopicDetail) *sarama.TopicError {
+			createdTopics = append(createdTopics, topicName)
+			return nil
+		},
+		MockDeleteTopicFunc: func(ctx context.Context, topicName string) *sarama.TopicError {
+			deletedTopics = append(deletedTopics, topicName)
+			return nil
+		},
+	}
+
+	// Initialize The Reconciler
+	recorder := record.NewBroadcaster().NewRecorder(scheme.Scheme, corev1.EventSource{Component: "TestEventSource"})
+	ctx := controller.WithEventRecorder(context.TODO(), recorder)
+	r := &Reconciler{
+		logger:      logtesting.TestLogger(t).Desugar(),
+		adminClient: mockAdminClient,
+		config:      controllertesting.NewConfig(),
+	}
+
+	// Perform The Test (Create) & Verify The Channel Topic Is Followed By The Retry Topics
+	err := r.reconcileKafkaTopic(ctx, channel)
+	assert.Nil(t, err)
+	assert.Equal(t, append([]string{controllertesting.TopicName}, retryTopicNames...), createdTopics)
+	assert.Empty(t, deletedTopics)
+
+	// Perform The Test (Delete) & Verify The Retry Topics Are Deleted Along With The Channel Topic
+	err = r.finalizeKafkaTopic(ctx, channel)
+	assert.Nil(t, err)
+	assert.Equal(t, controllertesting.TopicName, deletedTopics[0])
+	assert.ElementsMatch(t, retryTopicNames, deletedTopics[1:])
+}
+
+// Test The Routing Of KafkaChannels (And Their Topics) To One Of Several Kafka Secrets
+func TestReconcileTopicMultipleKafkaSecrets(t *testing.T) {
+
+	// Test Data - The Mock AdminClients Of Three Kafka Secrets (The Second Mapped To The Channel's Namespace)
+	adminClients := map[string]admin.AdminClientInterface{
+		"kafka-secret-1": &controllertesting.MockAdminClient{},
+		"kafka-secret-2": &controllertesting.MockAdminClient{},
+		"kafka-secret-3": &controllertesting.MockAdminClient{},
+	}
+	configuration := controllertesting.NewConfig()
+	configuration.Kafka.Secrets = commonconfig.EKKafkaSecretsConfig{
+		Default:    "kafka-secret-1",
+		Namespaces: map[string][]string{"kafka-secret-2": {controllertesting.KafkaChannelNamespace}},
+	}
+
+	// Initialize The Reconciler
+	recorder := record.NewBroadcaster().NewRecorder(scheme.Scheme, corev1.EventSource{Component: "TestEventSource"})
+	ctx := controller.WithEventRecorder(context.TODO(), recorder)
+	r := &Reconciler{
+		logger:      logtesting.TestLogger(t).Desugar(),
+		adminClient: admin.NewMultiKafkaSecretAdminClient(logtesting.TestLogger(t).Desugar(), adminClients),
+		config:      configuration,
+	}
+
+	// Verify The Channel Is Routed By Its Namespace & Its Topic Created By The AdminClient Of That Kafka Secret
+	channel := controllertesting.NewKafkaChannel()
+	assert.Equal(t, "kafka-secret-2", r.kafkaSecretName(channel))
+	assert.Nil(t, r.reconcileKafkaTopic(ctx, channel))
+	assert.False(t, adminClients["kafka-secret-1"].(*controllertesting.MockAdminClient).CreateTopicsCalled())
+	assert.True(t, adminClients["kafka-secret-2"].(*controllertesting.MockAdminClient).CreateTopicsCalled())
+
+	// Verify The Channel's Cluster Annotation Takes Precedence Over Its Namespace
+	channel.Annotations = map[string]string{kafkav1beta1.KafkaClusterAnnotation: "kafka-secret-3"}
+	assert.Equal(t, "kafka-secret-3", r.kafkaSecretName(channel))
+
+	// Verify The Channel Remains With The Kafka Secret Of Its Label (Where Its Topics Were Created)
+	channel.Labels = map[string]string{constants.KafkaSecretLabel: "kafka-secret-1"}
+	assert.Equal(t, "kafka-secret-1", r.kafkaSecretName(channel))
+	assert.Nil(t, r.finalizeKafkaTopic(ctx, channel))
+	assert.True(t, adminClients["kafka-secret-1"].(*controllertesting.MockAdminClient).DeleteTopicsCalled())
+
+	// Verify A Channel Of An Unknown Cluster Has No Kafka Secret & Its Topic Fails
+	channel = controllertesting.NewKafkaChannel()
+	channel.Annotations = map[string]string{kafkav1beta1.KafkaClusterAnnotation: "unknown-kafka-secret"}
+	assert.Equal(t, "", r.kafkaSecretName(channel))
+	assert.NotNil(t, r.reconcileKafkaTopic(ctx, channel))
+}
@@ -25,6 +25,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/cache"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/sarama"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/autoscalerinformer"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/config"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/env"
@@ -52,6 +53,7 @@ func NewController(ctx context.Context, _ configmap.Watcher) *controller.Impl {
 	kafkachannelInformer := kafkachannel.Get(ctx)
 	deploymentInformer := deployment.Get(ctx)
 	serviceInformer := service.Get(ctx)
+	autoscalerInformer := autoscalerinformer.Get(ctx)
 
 	// Load The Environment Variables
 	environment, err := env.GetEnvironment(logger)
@@ -81,6 +83,7 @@ func NewController(ctx context.Context, _ configmap.Watcher) *controller.Impl {
 		kafkachannelLister: kafkachannelInformer.Lister(),
 		deploymentLister:   deploymentInformer.Lister(),
 		serviceLister:      serviceInformer.Lister(),
+		autoscalerLister:   autoscalerInformer.Lister(),
 	}
 
 	// Create A New KafkaSecret Controller Impl With The Reconciler
@@ -99,6 +102,10 @@ func NewController(ctx context.Context, _ configmap.Watcher) *controller.Impl {
 		FilterFunc: controller.FilterControllerGVK(corev1.SchemeGroupVersion.WithKind(constants.SecretKind)),
 		Handler:    controller.HandleAll(controllerImpl.EnqueueControllerOf),
 	})
+	autoscalerInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.FilterControllerGVK(corev1.SchemeGroupVersion.WithKind(constants.SecretKind)),
+		Handler:    controller.HandleAll(controllerImpl.EnqueueControllerOf),
+	})
 	kafkachannelInformer.Informer().AddEventHandler(
 		controller.HandleAll(enqueueSecretOfKafkaChannel(controllerImpl)),
 	)
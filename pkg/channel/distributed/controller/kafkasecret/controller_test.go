@@ -27,6 +27,7 @@ import (
 	"k8s.io/client-go/rest"
 	commonenv "knative.dev/eventing-kafka/pkg/channel/distributed/common/env"
 	commontesting "knative.dev/eventing-kafka/pkg/channel/distributed/common/testing"
+	_ "knative.dev/eventing-kafka/pkg/channel/distributed/controller/autoscalerinformer/fake" // Knative Fake Informer Injection
 	controllerenv "knative.dev/eventing-kafka/pkg/channel/distributed/controller/env"
 	_ "knative.dev/eventing-kafka/pkg/channel/distributed/controller/kafkasecretinformer/fake" // Knative Fake Informer Injection
 	controllertesting "knative.dev/eventing-kafka/pkg/channel/distributed/controller/testing"
@@ -24,6 +24,7 @@ import (
 
 	"go.uber.org/zap"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -63,6 +64,15 @@ func (r *Reconciler) reconcileChannel(ctx context.Context, secret *corev1.Secret
 		logger.Info("Successfully Reconciled Receiver Deployment")
 	}
 
+	// Reconcile The Receiver HorizontalPodAutoscaler
+	autoscalerErr := r.reconcileReceiverAutoscaler(ctx, logger, secret)
+	if autoscalerErr != nil {
+		controller.GetEventRecorder(ctx).Eventf(secret, corev1.EventTypeWarning, event.ReceiverAutoscalerReconciliationFailed.String(), "Failed To Reconcile Receiver HorizontalPodAutoscaler: %v", autoscalerErr)
+		logger.Error("Failed To Reconcile Receiver HorizontalPodAutoscaler", zap.Error(autoscalerErr))
+	} else {
+		logger.Info("Successfully Reconciled Receiver HorizontalPodAutoscaler")
+	}
+
 	// Reconcile Channel's KafkaChannel Status
 	statusErr := r.reconcileKafkaChannelStatus(ctx,
 		secret,
@@ -76,7 +86,7 @@ func (r *Reconciler) reconcileChannel(ctx context.Context, secret *corev1.Secret
 	}
 
 	// Return Results
-	if serviceErr != nil || deploymentErr != nil || statusErr != nil {
+	if serviceErr != nil || deploymentErr != nil || autoscalerErr != nil || statusErr != nil {
 		return fmt.Errorf("failed to reconcile channel resources")
 	} else {
 		return nil // Success
@@ -225,8 +235,7 @@ func (r *Reconciler) reconcileReceiverDeployment(ctx context.Context, logger *za
 
 		// Verify Receiver Deployment Is Not Terminating
 		if deployment.DeletionTimestamp.IsZero() {
-			logger.Info("Successfully Verified Receiver Deployment")
-			return nil
+			return r.updateReceiverDeploymentKafkaSecretHash(ctx, logger, secret, deployment)
 		} else {
 			logger.Warn("Encountered Receiver Deployment With DeletionTimestamp - Forcing Reconciliation", zap.String("Namespace", deployment.Namespace), zap.String("Name", deployment.Name))
 			return fmt.Errorf("encountered Receiver Deployment with DeletionTimestamp %s/%s - potential race condition", deployment.Namespace, deployment.Name)
@@ -234,6 +243,35 @@ func (r *Reconciler) reconcileReceiverDeployment(ctx context.Context, logger *za
 	}
 }
 
+// Update The Kafka Secret Hash Of The Receiver Deployment - Rolling Out New Receivers With The Changed Kafka Credentials
+func (r *Reconciler) updateReceiverDeploymentKafkaSecretHash(ctx context.Context, logger *zap.Logger, secret *corev1.Secret, deployment *appsv1.Deployment) error {
+
+	// Nothing To Update Unless The Kafka Secret Changed
+	desired, err := r.newReceiverDeployment(logger, secret)
+	if err != nil {
+		logger.Error("Failed To Create Receiver Deployment YAML", zap.Error(err))
+		return err
+	}
+	updated := deployment.DeepCopy()
+	changed, secretChanged := util.UpdateKafkaSecretHashAnnotation(updated, desired, false)
+	if !changed {
+		logger.Info("Successfully Verified Receiver Deployment")
+		return nil
+	}
+
+	// Update The Receiver Deployment's Pod Template (Replicas Are Left As Is)
+	_, err = r.kubeClientset.AppsV1().Deployments(updated.Namespace).Update(ctx, updated, metav1.UpdateOptions{})
+	if err != nil {
+		logger.Error("Failed To Update Receiver Deployment With Kafka Secret Hash", zap.Error(err))
+		return err
+	}
+	if secretChanged {
+		controller.GetEventRecorder(ctx).Eventf(secret, corev1.EventTypeNormal, event.KafkaSecretChanged.String(), "Kafka Secret Changed - Restarting Receiver Deployment: \"%s/%s\"", updated.Namespace, updated.Name)
+	}
+	logger.Info("Successfully Updated Receiver Deployment With Kafka Secret Hash", zap.Bool("SecretChanged", secretChanged))
+	return nil
+}
+
 // Get The Receiver Deployment Associated With The Specified Secret
 func (r *Reconciler) getReceiverDeployment(secret *corev1.Secret) (*appsv1.Deployment, error) {
 
@@ -247,6 +285,45 @@ func (r *Reconciler) getReceiverDeployment(secret *corev1.Secret) (*appsv1.Deplo
 	return deployment, err
 }
 
+//
+// Kafka Receiver HorizontalPodAutoscaler
+//
+
+// Reconcile The Receiver HorizontalPodAutoscaler (Removed When Autoscaling Is Disabled)
+func (r *Reconciler) reconcileReceiverAutoscaler(ctx context.Context, logger *zap.Logger, secret *corev1.Secret) error {
+	autoscaler, err := r.newReceiverAutoscaler(logger, secret)
+	if err != nil {
+		return err
+	}
+	return util.ReconcileHorizontalPodAutoscaler(ctx, logger, r.kubeClientset, r.autoscalerLister, commonconstants.KnativeEventingNamespace, util.ReceiverDnsSafeName(secret.Name), autoscaler)
+}
+
+// Create The Receiver HorizontalPodAutoscaler Model For The Specified Secret (Nil When Autoscaling Is Disabled)
+func (r *Reconciler) newReceiverAutoscaler(logger *zap.Logger, secret *corev1.Secret) (*autoscalingv2beta2.HorizontalPodAutoscaler, error) {
+
+	// Nothing To Create Unless The Receiver Is Autoscaled
+	autoscaling := r.config.Receiver.Autoscaling
+	if !autoscaling.Enabled() {
+		return nil, nil
+	}
+
+	// The Receiver Scales On CPU Utilization And/Or The Request Rate Per Pod
+	metrics := make([]autoscalingv2beta2.MetricSpec, 0)
+	if autoscaling.TargetCpuUtilization > 0 {
+		metrics = append(metrics, util.CpuUtilizationMetric(autoscaling.TargetCpuUtilization))
+	}
+	if autoscaling.TargetRequestRate.Sign() > 0 {
+		metrics = append(metrics, util.PodsMetric(autoscaling.RequestRateMetric, autoscaling.TargetRequestRate))
+	}
+
+	// Create The HorizontalPodAutoscaler Of The Receiver Deployment
+	deployment, err := r.newReceiverDeployment(logger, secret)
+	if err != nil {
+		return nil, err
+	}
+	return util.NewHorizontalPodAutoscaler(deployment, autoscaling, 0, metrics), nil
+}
+
 // Create Receiver Deployment Model For The Specified Secret
 func (r *Reconciler) newReceiverDeployment(logger *zap.Logger, secret *corev1.Secret) (*appsv1.Deployment, error) {
 
@@ -344,6 +421,9 @@ func (r *Reconciler) newReceiverDeployment(logger *zap.Logger, secret *corev1.Se
 		},
 	}
 
+	// Roll Out New Receivers When The Kafka Secret Changes
+	util.SetKafkaSecretHashAnnotation(deployment, util.SecretDataHash(secret))
+
 	// Return Receiver Deployment
 	return deployment, nil
 }
@@ -0,0 +1,119 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kafkasecret
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakekubeclientset "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/event"
+	controllertesting "knative.dev/eventing-kafka/pkg/channel/distributed/controller/testing"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/util"
+	commonconstants "knative.dev/eventing-kafka/pkg/common/constants"
+	"knative.dev/pkg/controller"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test The Reconciliation Of The Receiver HorizontalPodAutoscaler
+func TestReconcileReceiverAutoscaler(t *testing.T) {
+
+	// Test Data
+	logger := logtesting.TestLogger(t).Desugar()
+	secret := controllertesting.NewKafkaSecret()
+	listers := controllertesting.NewListers(nil)
+	r := &Reconciler{
+		logger:           logger,
+		kubeClientset:    fakekubeclientset.NewSimpleClientset(),
+		environment:      controllertesting.NewEnvironment(),
+		config:           controllertesting.NewConfig(),
+		autoscalerLister: listers.GetHorizontalPodAutoscalerLister(),
+	}
+	r.config.Receiver.Autoscaling = config.EKAutoscalingConfig{
+		MinReplicas:          2,
+		MaxReplicas:          8,
+		TargetCpuUtilization: 70,
+		TargetRequestRate:    resource.MustParse("500"),
+		RequestRateMetric:    "receiver_requests_per_second",
+	}
+	autoscalers := r.kubeClientset.AutoscalingV2beta2().HorizontalPodAutoscalers(commonconstants.KnativeEventingNamespace)
+	name := util.ReceiverDnsSafeName(secret.Name)
+
+	// The Receiver Deployment Is Scaled On CPU Utilization & Request Rate
+	assert.Nil(t, r.reconcileReceiverAutoscaler(context.TODO(), logger, secret))
+	autoscaler, err := autoscalers.Get(context.TODO(), name, metav1.GetOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, name, autoscaler.Spec.ScaleTargetRef.Name)
+	assert.Equal(t, int32(2), *autoscaler.Spec.MinReplicas)
+	assert.Equal(t, int32(8), autoscaler.Spec.MaxReplicas)
+	assert.Len(t, autoscaler.Spec.Metrics, 2)
+	assert.Equal(t, autoscalingv2beta2.ResourceMetricSourceType, autoscaler.Spec.Metrics[0].Type)
+	assert.Equal(t, "receiver_requests_per_second", autoscaler.Spec.Metrics[1].Pods.Metric.Name)
+	assert.Equal(t, secret.Name, autoscaler.OwnerReferences[0].Name)
+
+	// The HorizontalPodAutoscaler Is Removed Once Autoscaling Is Disabled (And The Informer Has Cached It)
+	listers = controllertesting.NewListers([]runtime.Object{autoscaler})
+	r.autoscalerLister = listers.GetHorizontalPodAutoscalerLister()
+	r.config.Receiver.Autoscaling = config.EKAutoscalingConfig{}
+	assert.Nil(t, r.reconcileReceiverAutoscaler(context.TODO(), logger, secret))
+	_, err = autoscalers.Get(context.TODO(), name, metav1.GetOptions{})
+	assert.True(t, errors.IsNotFound(err))
+}
+
+// Test That The Receiver Deployment Is Rolled Out With A New Kafka Secret Hash When The Kafka Secret Changes
+func TestReconcileReceiverDeploymentKafkaSecretChanged(t *testing.T) {
+
+	// Test Data
+	logger := logtesting.TestLogger(t).Desugar()
+	secret := controllertesting.NewKafkaSecret()
+	deployment := controllertesting.NewKafkaChannelReceiverDeployment()
+	listers := controllertesting.NewListers([]runtime.Object{secret, deployment})
+	r := &Reconciler{
+		logger:           logger,
+		kubeClientset:    fakekubeclientset.NewSimpleClientset(secret, deployment),
+		environment:      controllertesting.NewEnvironment(),
+		config:           controllertesting.NewConfig(),
+		deploymentLister: listers.GetDeploymentLister(),
+		autoscalerLister: listers.GetHorizontalPodAutoscalerLister(),
+	}
+	recorder := record.NewFakeRecorder(10)
+	ctx := controller.WithEventRecorder(context.TODO(), recorder)
+
+	// An Unchanged Kafka Secret Leaves The Receiver Deployment As Is
+	assert.Nil(t, r.reconcileReceiverDeployment(ctx, logger, secret))
+	assert.Len(t, recorder.Events, 0)
+
+	// A Changed Kafka Secret Updates The Hash Of The Receiver Deployment's Pod Template
+	rotated := secret.DeepCopy()
+	rotated.Data[constants.KafkaSecretDataKeyPassword] = []byte("RotatedPassword")
+	assert.Nil(t, r.reconcileReceiverDeployment(ctx, logger, rotated))
+	updated, err := r.kubeClientset.AppsV1().Deployments(commonconstants.KnativeEventingNamespace).Get(context.TODO(), deployment.Name, metav1.GetOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, util.SecretDataHash(rotated), updated.Spec.Template.Annotations[constants.KafkaSecretHashAnnotation])
+	assert.Equal(t, deployment.Spec.Replicas, updated.Spec.Replicas)
+	assert.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, event.KafkaSecretChanged.String())
+}
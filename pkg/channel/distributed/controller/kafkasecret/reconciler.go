@@ -24,6 +24,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
 	appsv1listers "k8s.io/client-go/listers/apps/v1"
+	autoscalingv2beta2listers "k8s.io/client-go/listers/autoscaling/v2beta2"
 	corev1listers "k8s.io/client-go/listers/core/v1"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
@@ -45,6 +46,7 @@ type Reconciler struct {
 	kafkachannelLister kafkalisters.KafkaChannelLister
 	deploymentLister   appsv1listers.DeploymentLister
 	serviceLister      corev1listers.ServiceLister
+	autoscalerLister   autoscalingv2beta2listers.HorizontalPodAutoscalerLister
 }
 
 var (
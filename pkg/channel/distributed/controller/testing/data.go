@@ -572,6 +572,9 @@ func NewKafkaChannelReceiverDeployment(options ...DeploymentOption) *appsv1.Depl
 					Labels: map[string]string{
 						"app": ReceiverDeploymentName,
 					},
+					Annotations: map[string]string{
+						constants.KafkaSecretHashAnnotation: util.SecretDataHash(NewKafkaSecret()),
+					},
 				},
 				Spec: corev1.PodSpec{
 					ServiceAccountName: ServiceAccount,
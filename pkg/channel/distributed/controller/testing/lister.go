@@ -18,10 +18,12 @@ package testing
 
 import (
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	fakekubeclientset "k8s.io/client-go/kubernetes/fake"
 	appsv1listers "k8s.io/client-go/listers/apps/v1"
+	autoscalingv2beta2listers "k8s.io/client-go/listers/autoscaling/v2beta2"
 	corev1listers "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
@@ -106,3 +108,7 @@ func (l *Listers) GetKafkaChannelLister() kafkalisters.KafkaChannelLister {
 func (l *Listers) GetDeploymentLister() appsv1listers.DeploymentLister {
 	return appsv1listers.NewDeploymentLister(l.indexerFor(&appsv1.Deployment{}))
 }
+
+func (l *Listers) GetHorizontalPodAutoscalerLister() autoscalingv2beta2listers.HorizontalPodAutoscalerLister {
+	return autoscalingv2beta2listers.NewHorizontalPodAutoscalerLister(l.indexerFor(&autoscalingv2beta2.HorizontalPodAutoscaler{}))
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	autoscalingv2beta2listers "k8s.io/client-go/listers/autoscaling/v2beta2"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+)
+
+// Create A HorizontalPodAutoscaler Model Scaling The Specified Deployment Between The Specified Bounds
+// (The Maximum Is Capped By The Specified Limit When Positive, And The Minimum By The Maximum)
+func NewHorizontalPodAutoscaler(deployment *appsv1.Deployment, autoscaling config.EKAutoscalingConfig, maxReplicasLimit int32, metrics []autoscalingv2beta2.MetricSpec) *autoscalingv2beta2.HorizontalPodAutoscaler {
+
+	// Determine The Replica Bounds
+	maxReplicas := autoscaling.MaxReplicas
+	if maxReplicasLimit > 0 && maxReplicas > maxReplicasLimit {
+		maxReplicas = maxReplicasLimit
+	}
+	minReplicas := autoscaling.MinimumReplicas()
+	if minReplicas > maxReplicas {
+		minReplicas = maxReplicas
+	}
+
+	// Create & Return The HorizontalPodAutoscaler Model (Named & Labelled As Its Deployment)
+	return &autoscalingv2beta2.HorizontalPodAutoscaler{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: autoscalingv2beta2.SchemeGroupVersion.String(),
+			Kind:       constants.AutoscalerKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            deployment.Name,
+			Namespace:       deployment.Namespace,
+			Labels:          deployment.Labels,
+			OwnerReferences: deployment.OwnerReferences,
+		},
+		Spec: autoscalingv2beta2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2beta2.CrossVersionObjectReference{
+				APIVersion: appsv1.SchemeGroupVersion.String(),
+				Kind:       constants.DeploymentKind,
+				Name:       deployment.Name,
+			},
+			MinReplicas: &minReplicas,
+			MaxReplicas: maxReplicas,
+			Metrics:     metrics,
+		},
+	}
+}
+
+// Create The Metric Spec Targeting The Specified Average CPU Utilization (Percentage Of The CPU Request)
+func CpuUtilizationMetric(targetUtilization int32) autoscalingv2beta2.MetricSpec {
+	return autoscalingv2beta2.MetricSpec{
+		Type: autoscalingv2beta2.ResourceMetricSourceType,
+		Resource: &autoscalingv2beta2.ResourceMetricSource{
+			Name: corev1.ResourceCPU,
+			Target: autoscalingv2beta2.MetricTarget{
+				Type:               autoscalingv2beta2.UtilizationMetricType,
+				AverageUtilization: &targetUtilization,
+			},
+		},
+	}
+}
+
+// Create The Metric Spec Targeting The Specified Average Value Of A Custom Metric Of The Pods
+func PodsMetric(metricName string, targetAverageValue resource.Quantity) autoscalingv2beta2.MetricSpec {
+	return autoscalingv2beta2.MetricSpec{
+		Type: autoscalingv2beta2.PodsMetricSourceType,
+		Pods: &autoscalingv2beta2.PodsMetricSource{
+			Metric: autoscalingv2beta2.MetricIdentifier{Name: metricName},
+			Target: autoscalingv2beta2.MetricTarget{
+				Type:         autoscalingv2beta2.AverageValueMetricType,
+				AverageValue: &targetAverageValue,
+			},
+		},
+	}
+}
+
+// Create The Metric Spec Targeting The Specified Value Per Pod Of The (Summed) Series Of An External Metric
+func ExternalMetric(metricName string, selector map[string]string, targetAverageValue resource.Quantity) autoscalingv2beta2.MetricSpec {
+	return autoscalingv2beta2.MetricSpec{
+		Type: autoscalingv2beta2.ExternalMetricSourceType,
+		External: &autoscalingv2beta2.ExternalMetricSource{
+			Metric: autoscalingv2beta2.MetricIdentifier{
+				Name:     metricName,
+				Selector: &metav1.LabelSelector{MatchLabels: selector},
+			},
+			Target: autoscalingv2beta2.MetricTarget{
+				Type:         autoscalingv2beta2.AverageValueMetricType,
+				AverageValue: &targetAverageValue,
+			},
+		},
+	}
+}
+
+// Reconcile The Named HorizontalPodAutoscaler With The Desired Model - Creating Or Updating It As Necessary,
+// Or Deleting It When There Is No Desired Model (Autoscaling Disabled)
+func ReconcileHorizontalPodAutoscaler(ctx context.Context, logger *zap.Logger, kubeClientset kubernetes.Interface, autoscalerLister autoscalingv2beta2listers.HorizontalPodAutoscalerLister, namespace string, name string, desired *autoscalingv2beta2.HorizontalPodAutoscaler) error {
+
+	// Get The Existing HorizontalPodAutoscaler (From The Informer's Cache)
+	autoscalers := kubeClientset.AutoscalingV2beta2().HorizontalPodAutoscalers(namespace)
+	autoscaler, err := autoscalerLister.HorizontalPodAutoscalers(namespace).Get(name)
+	if errors.IsNotFound(err) {
+
+		// Create The HorizontalPodAutoscaler If Desired
+		if desired == nil {
+			return nil
+		}
+		logger.Info("HorizontalPodAutoscaler Not Found - Creating New One", zap.String("Name", name))
+		_, err = autoscalers.Create(ctx, desired, metav1.CreateOptions{})
+		if err != nil {
+			logger.Error("Failed To Create HorizontalPodAutoscaler", zap.String("Name", name), zap.Error(err))
+		}
+		return err
+
+	} else if err != nil {
+		logger.Error("Failed To Get HorizontalPodAutoscaler", zap.String("Name", name), zap.Error(err))
+		return err
+	}
+
+	// Delete The HorizontalPodAutoscaler If No Longer Desired
+	if desired == nil {
+		logger.Info("Deleting HorizontalPodAutoscaler (Autoscaling Disabled)", zap.String("Name", name))
+		err = autoscalers.Delete(ctx, name, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			logger.Error("Failed To Delete HorizontalPodAutoscaler", zap.String("Name", name), zap.Error(err))
+			return err
+		}
+		return nil
+	}
+
+	// Update The HorizontalPodAutoscaler If Its Spec Or Labels Changed (Leaving Its Status To The Autoscaler)
+	if equality.Semantic.DeepEqual(autoscaler.Spec, desired.Spec) && equality.Semantic.DeepEqual(autoscaler.Labels, desired.Labels) {
+		return nil
+	}
+	updated := autoscaler.DeepCopy()
+	updated.Spec = desired.Spec
+	updated.Labels = desired.Labels
+	logger.Info("Updating HorizontalPodAutoscaler", zap.String("Name", name))
+	_, err = autoscalers.Update(ctx, updated, metav1.UpdateOptions{})
+	if err != nil {
+		logger.Error("Failed To Update HorizontalPodAutoscaler", zap.String("Name", name), zap.Error(err))
+	}
+	return err
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	fakekubeclientset "k8s.io/client-go/kubernetes/fake"
+	autoscalingv2beta2listers "k8s.io/client-go/listers/autoscaling/v2beta2"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
+	logtesting "knative.dev/pkg/logging/testing"
+)
+
+// Test The NewHorizontalPodAutoscaler() Functionality
+func TestNewHorizontalPodAutoscaler(t *testing.T) {
+
+	// Test Data
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "TestName", Namespace: "TestNamespace", Labels: map[string]string{"TestLabel": "TestValue"}},
+	}
+	metrics := []autoscalingv2beta2.MetricSpec{CpuUtilizationMetric(80)}
+
+	// Perform The Test (Maximum Capped By The Limit & Minimum By The Maximum)
+	autoscaler := NewHorizontalPodAutoscaler(deployment, config.EKAutoscalingConfig{MinReplicas: 5, MaxReplicas: 10}, 4, metrics)
+
+	// Verify The Results
+	assert.Equal(t, deployment.Name, autoscaler.Name)
+	assert.Equal(t, deployment.Namespace, autoscaler.Namespace)
+	assert.Equal(t, deployment.Labels, autoscaler.Labels)
+	assert.Equal(t, constants.DeploymentKind, autoscaler.Spec.ScaleTargetRef.Kind)
+	assert.Equal(t, deployment.Name, autoscaler.Spec.ScaleTargetRef.Name)
+	assert.Equal(t, int32(4), *autoscaler.Spec.MinReplicas)
+	assert.Equal(t, int32(4), autoscaler.Spec.MaxReplicas)
+	assert.Equal(t, metrics, autoscaler.Spec.Metrics)
+
+	// Verify The Default Minimum & An Unlimited Maximum
+	autoscaler = NewHorizontalPodAutoscaler(deployment, config.EKAutoscalingConfig{MaxReplicas: 10}, 0, metrics)
+	assert.Equal(t, int32(config.DefaultAutoscalingMinReplicas), *autoscaler.Spec.MinReplicas)
+	assert.Equal(t, int32(10), autoscaler.Spec.MaxReplicas)
+}
+
+// Test The HorizontalPodAutoscaler Metric Spec Functions
+func TestAutoscalerMetrics(t *testing.T) {
+
+	cpu := CpuUtilizationMetric(75)
+	assert.Equal(t, autoscalingv2beta2.ResourceMetricSourceType, cpu.Type)
+	assert.Equal(t, int32(75), *cpu.Resource.Target.AverageUtilization)
+
+	pods := PodsMetric("TestMetric", resource.MustParse("100"))
+	assert.Equal(t, autoscalingv2beta2.PodsMetricSourceType, pods.Type)
+	assert.Equal(t, "TestMetric", pods.Pods.Metric.Name)
+	assert.Equal(t, int64(100), pods.Pods.Target.AverageValue.Value())
+
+	external := ExternalMetric("TestMetric", map[string]string{"topic": "TestTopic"}, resource.MustParse("1000"))
+	assert.Equal(t, autoscalingv2beta2.ExternalMetricSourceType, external.Type)
+	assert.Equal(t, "TestMetric", external.External.Metric.Name)
+	assert.Equal(t, map[string]string{"topic": "TestTopic"}, external.External.Metric.Selector.MatchLabels)
+	assert.Equal(t, int64(1000), external.External.Target.AverageValue.Value())
+}
+
+// Test The ReconcileHorizontalPodAutoscaler() Functionality
+func TestReconcileHorizontalPodAutoscaler(t *testing.T) {
+
+	// Test Data
+	ctx := context.TODO()
+	logger := logtesting.TestLogger(t).Desugar()
+	kubeClientset := fakekubeclientset.NewSimpleClientset()
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "TestName", Namespace: "TestNamespace"}}
+	autoscalers := kubeClientset.AutoscalingV2beta2().HorizontalPodAutoscalers(deployment.Namespace)
+
+	// Nothing Is Created When Autoscaling Is Disabled
+	assert.Nil(t, ReconcileHorizontalPodAutoscaler(ctx, logger, kubeClientset, autoscalerListerOf(t, kubeClientset, deployment.Namespace), deployment.Namespace, deployment.Name, nil))
+	_, err := autoscalers.Get(ctx, deployment.Name, metav1.GetOptions{})
+	assert.True(t, errors.IsNotFound(err))
+
+	// The HorizontalPodAutoscaler Is Created
+	desired := NewHorizontalPodAutoscaler(deployment, config.EKAutoscalingConfig{MaxReplicas: 3}, 0, []autoscalingv2beta2.MetricSpec{CpuUtilizationMetric(80)})
+	assert.Nil(t, ReconcileHorizontalPodAutoscaler(ctx, logger, kubeClientset, autoscalerListerOf(t, kubeClientset, deployment.Namespace), deployment.Namespace, deployment.Name, desired))
+	autoscaler, err := autoscalers.Get(ctx, deployment.Name, metav1.GetOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, int32(3), autoscaler.Spec.MaxReplicas)
+
+	// The HorizontalPodAutoscaler Is Updated
+	desired = NewHorizontalPodAutoscaler(deployment, config.EKAutoscalingConfig{MaxReplicas: 6}, 0, []autoscalingv2beta2.MetricSpec{CpuUtilizationMetric(80)})
+	assert.Nil(t, ReconcileHorizontalPodAutoscaler(ctx, logger, kubeClientset, autoscalerListerOf(t, kubeClientset, deployment.Namespace), deployment.Namespace, deployment.Name, desired))
+	autoscaler, err = autoscalers.Get(ctx, deployment.Name, metav1.GetOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, int32(6), autoscaler.Spec.MaxReplicas)
+
+	// The HorizontalPodAutoscaler Is Deleted Once Autoscaling Is Disabled
+	assert.Nil(t, ReconcileHorizontalPodAutoscaler(ctx, logger, kubeClientset, autoscalerListerOf(t, kubeClientset, deployment.Namespace), deployment.Namespace, deployment.Name, nil))
+	_, err = autoscalers.Get(ctx, deployment.Name, metav1.GetOptions{})
+	assert.True(t, errors.IsNotFound(err))
+}
+
+// Create A HorizontalPodAutoscalerLister Of The HorizontalPodAutoscalers Currently In The Specified K8S ClientSet
+func autoscalerListerOf(t *testing.T, kubeClientset kubernetes.Interface, namespace string) autoscalingv2beta2listers.HorizontalPodAutoscalerLister {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	autoscalers, err := kubeClientset.AutoscalingV2beta2().HorizontalPodAutoscalers(namespace).List(context.TODO(), metav1.ListOptions{})
+	assert.Nil(t, err)
+	for i := range autoscalers.Items {
+		assert.Nil(t, indexer.Add(&autoscalers.Items[i]))
+	}
+	return autoscalingv2beta2listers.NewHorizontalPodAutoscalerLister(indexer)
+}
@@ -34,3 +34,14 @@ func DispatcherDnsSafeName(channel *kafkav1beta1.KafkaChannel) string {
 	hash := GenerateHash(channel.Name+channel.Namespace, 8)
 	return fmt.Sprintf("%s-%s-%s-dispatcher", safeChannelName, safeChannelNamespace, hash)
 }
+
+// Create A DNS Safe Name For The Specified Member Of The Pool Of Shared Dispatchers Of A Kafka Secret
+func DispatcherPoolDnsSafeName(kafkaSecretName string, member int) string {
+
+	// In order for the resulting name to be a valid DNS component it's length must be no more than 63 characters.
+	// We are consuming 21 chars for the component separators, hash, and Dispatcher suffix, and up to 4 chars for
+	// the member number, which reduces the available length to 38. We will allocate 34 characters to the kafka
+	// secret name leaving an extra buffer.
+	safeSecretName := GenerateValidDnsName(kafkaSecretName, 34, true, false)
+	return fmt.Sprintf("%s-%s-dispatcher-%d", safeSecretName, GenerateHash(kafkaSecretName, 8), member)
+}
@@ -162,3 +162,15 @@ func TestDispatcherDnsSafeName_LongNamesDifferent(t *testing.T) {
 		})
 	}
 }
+
+// Test The DispatcherPoolDnsSafeName() Functionality
+func TestDispatcherPoolDnsSafeName(t *testing.T) {
+	hash := GenerateHash("kafka-secret", 8)
+	assert.Equal(t, "kafka-secret-"+hash+"-dispatcher-2", DispatcherPoolDnsSafeName("kafka-secret", 2))
+
+	longSecretName := "kubernetes-maximum-length-of-secret-name-is-sixty-three-chars"
+	actualResult := DispatcherPoolDnsSafeName(longSecretName, 1000)
+	assert.Equal(t, fmt.Sprintf("%.34s-%s-dispatcher-1000", longSecretName, GenerateHash(longSecretName, 8)), actualResult)
+	assert.True(t, len(actualResult) <= 63)
+	assert.NotEqual(t, DispatcherPoolDnsSafeName("kafka-secret", 0), DispatcherPoolDnsSafeName("kafka-secret", 1))
+}
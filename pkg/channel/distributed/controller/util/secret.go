@@ -18,8 +18,11 @@ package util
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -51,3 +54,42 @@ func NewSecretOwnerReference(secret *corev1.Secret) metav1.OwnerReference {
 		Controller:         &controller,
 	}
 }
+
+// Generate A Hash Of The Data Of The Specified K8S Secret (Which Changes When The Kafka Credentials Are Rotated)
+func SecretDataHash(secret *corev1.Secret) string {
+	keys := make([]string, 0, len(secret.Data))
+	for key := range secret.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var data strings.Builder
+	for _, key := range keys {
+		data.WriteString(fmt.Sprintf("%s=%x;", key, secret.Data[key]))
+	}
+	return GenerateHash(data.String(), 32)
+}
+
+// Set The Kafka Secret Hash Annotation On The Pod Template Of The Specified Deployment (Unless The Hash Is Unknown)
+func SetKafkaSecretHashAnnotation(deployment *appsv1.Deployment, hash string) {
+	if len(hash) > 0 {
+		if deployment.Spec.Template.Annotations == nil {
+			deployment.Spec.Template.Annotations = make(map[string]string)
+		}
+		deployment.Spec.Template.Annotations[constants.KafkaSecretHashAnnotation] = hash
+	}
+}
+
+// Update The Kafka Secret Hash Annotation Of The Existing Deployment To That Of The Desired Deployment, Returning
+// Whether It Changed (Rolling Out New Pods), And Whether The Kafka Secret Itself Changed Rather Than The Annotation
+// Being Added To A Deployment Created Without It.  Such A Deployment Only Gets The Annotation Along With Another
+// Change Of Its Pod Template (templateChanged), So That Upgrading The Controller Does Not Roll Out Every Deployment
+// At Once (Until Then A Change Of Its Kafka Secret Does Not Roll It Out, As Before The Upgrade)
+func UpdateKafkaSecretHashAnnotation(existing *appsv1.Deployment, desired *appsv1.Deployment, templateChanged bool) (changed bool, secretChanged bool) {
+	existingHash := existing.Spec.Template.Annotations[constants.KafkaSecretHashAnnotation]
+	desiredHash := desired.Spec.Template.Annotations[constants.KafkaSecretHashAnnotation]
+	if len(desiredHash) == 0 || existingHash == desiredHash || (len(existingHash) == 0 && !templateChanged) {
+		return false, false
+	}
+	SetKafkaSecretHashAnnotation(existing, desiredHash)
+	return true, len(existingHash) > 0
+}
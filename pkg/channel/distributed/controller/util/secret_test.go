@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/controller/constants"
@@ -64,3 +65,53 @@ func TestNewSecretOwnerReference(t *testing.T) {
 	assert.True(t, *controllerRef.BlockOwnerDeletion)
 	assert.True(t, *controllerRef.Controller)
 }
+
+// Test The SecretDataHash() Functionality
+func TestSecretDataHash(t *testing.T) {
+	secret := &corev1.Secret{Data: map[string][]byte{"username": []byte("TestUsername"), "password": []byte("TestPassword")}}
+	hash := SecretDataHash(secret)
+	assert.Len(t, hash, 32)
+	assert.Equal(t, hash, SecretDataHash(secret.DeepCopy()))
+	secret.Data["password"] = []byte("RotatedPassword")
+	assert.NotEqual(t, hash, SecretDataHash(secret))
+}
+
+// Test The SetKafkaSecretHashAnnotation() & UpdateKafkaSecretHashAnnotation() Functionality
+func TestUpdateKafkaSecretHashAnnotation(t *testing.T) {
+
+	// Test Data
+	existing := &appsv1.Deployment{}
+	desired := &appsv1.Deployment{}
+
+	// An Unknown Hash Is Neither Set Nor Updated
+	SetKafkaSecretHashAnnotation(desired, "")
+	assert.Nil(t, desired.Spec.Template.Annotations)
+	changed, secretChanged := UpdateKafkaSecretHashAnnotation(existing, desired, true)
+	assert.False(t, changed)
+	assert.False(t, secretChanged)
+
+	// The Hash Is Not Added To A Deployment Without One On Its Own (Rolling Out Every Deployment After An Upgrade)
+	SetKafkaSecretHashAnnotation(desired, "TestHash")
+	changed, secretChanged = UpdateKafkaSecretHashAnnotation(existing, desired, false)
+	assert.False(t, changed)
+	assert.False(t, secretChanged)
+	assert.Nil(t, existing.Spec.Template.Annotations)
+
+	// The Hash Is Added To A Deployment Without One Along With Another Change Of Its Pod Template
+	changed, secretChanged = UpdateKafkaSecretHashAnnotation(existing, desired, true)
+	assert.True(t, changed)
+	assert.False(t, secretChanged)
+	assert.Equal(t, "TestHash", existing.Spec.Template.Annotations[constants.KafkaSecretHashAnnotation])
+
+	// An Unchanged Hash Is Left As Is
+	changed, secretChanged = UpdateKafkaSecretHashAnnotation(existing, desired, false)
+	assert.False(t, changed)
+	assert.False(t, secretChanged)
+
+	// A Changed Hash Is Updated
+	SetKafkaSecretHashAnnotation(desired, "RotatedHash")
+	changed, secretChanged = UpdateKafkaSecretHashAnnotation(existing, desired, false)
+	assert.True(t, changed)
+	assert.True(t, secretChanged)
+	assert.Equal(t, "RotatedHash", existing.Spec.Template.Annotations[constants.KafkaSecretHashAnnotation])
+}
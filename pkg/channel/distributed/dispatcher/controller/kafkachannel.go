@@ -20,11 +20,13 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"time"
 
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
@@ -36,6 +38,7 @@ import (
 	"knative.dev/eventing-kafka/pkg/client/clientset/versioned/scheme"
 	informers "knative.dev/eventing-kafka/pkg/client/informers/externalversions/messaging/v1beta1"
 	listers "knative.dev/eventing-kafka/pkg/client/listers/messaging/v1beta1"
+	dispatchmetrics "knative.dev/eventing-kafka/pkg/common/metrics"
 	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/logging"
@@ -56,11 +59,13 @@ type Reconciler struct {
 	logger               *zap.Logger
 	channelKey           string
 	dispatcher           dispatcher.Dispatcher
+	pool                 *dispatcher.Pool // The KafkaChannels Dispatched By A Shared Dispatcher (Instead Of channelKey)
 	kafkachannelInformer cache.SharedIndexInformer
 	kafkachannelLister   listers.KafkaChannelLister
 	impl                 *controller.Impl
 	recorder             record.EventRecorder
 	kafkaClientSet       versioned.Interface
+	healthThresholds     kafkav1beta1.SubscriberHealthThresholds
 }
 
 var _ controller.Reconciler = Reconciler{}
@@ -74,6 +79,7 @@ func NewController(
 	kafkachannelInformer informers.KafkaChannelInformer,
 	kubeClient kubernetes.Interface,
 	kafkaClientSet versioned.Interface,
+	healthThresholds kafkav1beta1.SubscriberHealthThresholds,
 	stopChannel <-chan struct{},
 ) *controller.Impl {
 
@@ -84,21 +90,50 @@ func NewController(
 		kafkachannelInformer: kafkachannelInformer.Informer(),
 		kafkachannelLister:   kafkachannelInformer.Lister(),
 		kafkaClientSet:       kafkaClientSet,
+		healthThresholds:     healthThresholds,
 	}
-	reconciler.impl = controller.NewImpl(reconciler, reconciler.logger.Sugar(), ReconcilerName)
+	return reconciler.start(kafkachannelInformer, kubeClient, stopChannel)
+}
 
-	reconciler.logger.Info("Setting Up Event Handlers")
+// NewSharedController initializes the controller of a shared dispatcher, which dispatches the KafkaChannels
+// assigned to its pool member.
+func NewSharedController(
+	logger *zap.Logger,
+	pool *dispatcher.Pool,
+	kafkachannelInformer informers.KafkaChannelInformer,
+	kubeClient kubernetes.Interface,
+	kafkaClientSet versioned.Interface,
+	healthThresholds kafkav1beta1.SubscriberHealthThresholds,
+	stopChannel <-chan struct{},
+) *controller.Impl {
+
+	reconciler := &Reconciler{
+		logger:               logger,
+		pool:                 pool,
+		kafkachannelInformer: kafkachannelInformer.Informer(),
+		kafkachannelLister:   kafkachannelInformer.Lister(),
+		kafkaClientSet:       kafkaClientSet,
+		healthThresholds:     healthThresholds,
+	}
+	return reconciler.start(kafkachannelInformer, kubeClient, stopChannel)
+}
+
+// Create The Controller Impl Of The Reconciler & Register The Event Handlers
+func (r *Reconciler) start(kafkachannelInformer informers.KafkaChannelInformer, kubeClient kubernetes.Interface, stopChannel <-chan struct{}) *controller.Impl {
+	r.impl = controller.NewImpl(r, r.logger.Sugar(), ReconcilerName)
+
+	r.logger.Info("Setting Up Event Handlers")
 
 	// Watch for kafka channels.
-	kafkachannelInformer.Informer().AddEventHandler(controller.HandleAll(reconciler.impl.Enqueue))
-	logger.Debug("Creating event broadcaster")
+	kafkachannelInformer.Informer().AddEventHandler(controller.HandleAll(r.impl.Enqueue))
+	r.logger.Debug("Creating event broadcaster")
 	eventBroadcaster := record.NewBroadcaster()
 	watches := []watch.Interface{
-		eventBroadcaster.StartLogging(logger.Sugar().Named("event-broadcaster").Infof),
+		eventBroadcaster.StartLogging(r.logger.Sugar().Named("event-broadcaster").Infof),
 		eventBroadcaster.StartRecordingToSink(
 			&typedcorev1.EventSinkImpl{Interface: kubeClient.CoreV1().Events("")}),
 	}
-	reconciler.recorder = eventBroadcaster.NewRecorder(
+	r.recorder = eventBroadcaster.NewRecorder(
 		scheme.Scheme, corev1.EventSource{Component: ReconcilerName})
 	go func() {
 		<-stopChannel
@@ -107,7 +142,82 @@ func NewController(
 		}
 	}()
 
-	return reconciler.impl
+	// Periodically Merge The Failures Of The Subscribers Into The Status Of The KafkaChannels
+	go r.refreshSubscriberHealth(dispatchmetrics.HealthRefreshInterval, stopChannel)
+
+	return r.impl
+}
+
+// Merge The Failures Of The Subscribers Into The Status Of The KafkaChannels Every Interval, Until The Stop Channel Is
+// Closed.  The Failures Change With Every Event, So They Are Written Directly To The Status On A Timer Rather Than By
+// The Reconciliation (Which Would Otherwise Have To Run For Every KafkaChannel On Each Tick).
+func (r *Reconciler) refreshSubscriberHealth(interval time.Duration, stopChannel <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopChannel:
+			return
+		case <-ticker.C:
+			for _, channelKey := range r.channelKeys() {
+				if err := r.updateSubscriberFailures(context.Background(), channelKey); err != nil {
+					r.logger.Warn("Failed To Update The Subscribers' Failures - Retrying Next Interval", zap.String("ChannelKey", channelKey), zap.Error(err))
+				}
+			}
+		}
+	}
+}
+
+//
+// Merge The Failures Of The Subscribers Seen By This Dispatcher Into The Status Of The Specified KafkaChannel
+//
+// Each replica of the dispatcher only sees the events it dispatched, so the failures of all the replicas are merged
+// (see MergeSubscriberFailures) rather than overwritten, while the lag of the subscribers is left to the controller
+// which measures it from the offsets committed by their consumer groups.
+//
+func (r *Reconciler) updateSubscriberFailures(ctx context.Context, channelKey string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(channelKey)
+	if err != nil {
+		return err
+	}
+	channel, err := r.kafkachannelLister.KafkaChannels(namespace).Get(name)
+	if apierrs.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	channelDispatcher := r.channelDispatcher(channelKey, channel)
+	if channelDispatcher == nil {
+		return nil
+	}
+
+	// Don't Modify The Informer's Copy
+	desired := channel.DeepCopy()
+	desired.Status.MergeSubscriberFailures(r.subscriberHealth(channelDispatcher, channel.Spec.Subscribers), r.healthThresholds, dispatchmetrics.HealthWindow, time.Now())
+	if reflect.DeepEqual(channel.Status, desired.Status) {
+		return nil
+	}
+	_, err = r.kafkaClientSet.MessagingV1beta1().KafkaChannels(namespace).UpdateStatus(ctx, desired, metav1.UpdateOptions{})
+	return err
+}
+
+// Get The Keys Of The KafkaChannels Dispatched By This Dispatcher
+func (r *Reconciler) channelKeys() []string {
+	if r.pool != nil {
+		return r.pool.ChannelKeys()
+	}
+	return []string{r.channelKey}
+}
+
+// Get The Dispatcher Of The Specified KafkaChannel (Nil If It Is Not Dispatched By This Dispatcher)
+func (r Reconciler) channelDispatcher(key string, channel *kafkav1beta1.KafkaChannel) dispatcher.Dispatcher {
+	if r.pool != nil {
+		return r.pool.Dispatcher(channel)
+	}
+	if r.channelKey != key {
+		return nil
+	}
+	return r.dispatcher
 }
 
 func (r Reconciler) Reconcile(ctx context.Context, key string) error {
@@ -126,6 +236,9 @@ func (r Reconciler) Reconcile(ctx context.Context, key string) error {
 	if err != nil {
 		if apierrs.IsNotFound(err) {
 			r.logger.Warn("KafkaChannel No Longer Exists", zap.String("namespace", namespace), zap.String("name", name))
+			if r.pool != nil {
+				r.pool.Remove(key)
+			}
 			return nil
 		}
 		r.logger.Error("Error Retrieving KafkaChannel", zap.Error(err), zap.String("namespace", namespace), zap.String("name", name))
@@ -133,7 +246,8 @@ func (r Reconciler) Reconcile(ctx context.Context, key string) error {
 	}
 
 	// Only Reconcile KafkaChannel Associated With This Dispatcher
-	if r.channelKey != key {
+	channelDispatcher := r.channelDispatcher(key, original)
+	if channelDispatcher == nil {
 		return nil
 	}
 
@@ -144,7 +258,7 @@ func (r Reconciler) Reconcile(ctx context.Context, key string) error {
 	// Don't modify the informers copy
 	channel := original.DeepCopy()
 
-	reconcileError := r.reconcile(channel)
+	reconcileError := r.reconcile(channel, channelDispatcher)
 	if reconcileError != nil {
 		r.logger.Error("Error Reconciling KafkaChannel", zap.Error(reconcileError))
 		r.recorder.Eventf(channel, corev1.EventTypeWarning, channelReconcileFailed, "KafkaChannel Reconciliation Failed: %v", reconcileError)
@@ -167,7 +281,7 @@ func (r Reconciler) Reconcile(ctx context.Context, key string) error {
 }
 
 // Reconcile The Specified KafkaChannel
-func (r Reconciler) reconcile(channel *kafkav1beta1.KafkaChannel) error {
+func (r Reconciler) reconcile(channel *kafkav1beta1.KafkaChannel, channelDispatcher dispatcher.Dispatcher) error {
 
 	// The KafkaChannel's Subscribers
 	var subscribers []eventingduck.SubscriberSpec
@@ -179,8 +293,18 @@ func (r Reconciler) reconcile(channel *kafkav1beta1.KafkaChannel) error {
 		subscribers = make([]eventingduck.SubscriberSpec, 0)
 	}
 
+	// Determine The Delivery Settings Of Each Subscriber From The KafkaChannel's Annotations
+	subscriberDeliveries := make(map[types.UID]kafkav1beta1.SubscriberDelivery, len(subscribers))
+	for _, subscriber := range subscribers {
+		delivery, err := channel.SubscriberDelivery(subscriber.UID)
+		if err != nil {
+			r.logger.Warn("Invalid Subscriber Delivery Annotations - Using Defaults", zap.Any("UID", subscriber.UID), zap.Error(err))
+		}
+		subscriberDeliveries[subscriber.UID] = delivery
+	}
+
 	// Update The ConsumerGroups To Align With Current KafkaChannel Subscribers
-	failedSubscriptions := r.dispatcher.UpdateSubscriptions(subscribers)
+	failedSubscriptions := channelDispatcher.UpdateSubscriptions(subscribers, subscriberDeliveries)
 
 	// Update The KafkaChannel Subscribable Status Based On ConsumerGroup Creation Status
 	channel.Status.SubscribableStatus = r.createSubscribableStatus(channel.Spec.Subscribers, failedSubscriptions)
@@ -218,6 +342,18 @@ func (r *Reconciler) createSubscribableStatus(subscribers []eventingduck.Subscri
 	}
 }
 
+// Get The Health Of The Subscribers Which Are Consuming (Without Their Lag), In The Order Of The Spec
+func (r *Reconciler) subscriberHealth(channelDispatcher dispatcher.Dispatcher, subscribers []eventingduck.SubscriberSpec) []kafkav1beta1.SubscriberHealth {
+	health := channelDispatcher.SubscriberHealth()
+	subscriberHealth := make([]kafkav1beta1.SubscriberHealth, 0, len(subscribers))
+	for _, subscriber := range subscribers {
+		if h, ok := health[subscriber.UID]; ok {
+			subscriberHealth = append(subscriberHealth, h.SubscriberHealth(subscriber.UID))
+		}
+	}
+	return subscriberHealth
+}
+
 func (r *Reconciler) updateStatus(ctx context.Context, desired *kafkav1beta1.KafkaChannel) (*kafkav1beta1.KafkaChannel, error) {
 	kc, err := r.kafkachannelLister.KafkaChannels(desired.Namespace).Get(desired.Name)
 	if err != nil {
@@ -17,6 +17,7 @@ limitations under the License.
 package controller
 
 import (
+	"context"
 	"os"
 	"testing"
 	"time"
@@ -25,7 +26,9 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/kubernetes/scheme"
 	clientgotesting "k8s.io/client-go/testing"
@@ -36,6 +39,7 @@ import (
 	"knative.dev/eventing-kafka/pkg/client/clientset/versioned"
 	fakeclientset "knative.dev/eventing-kafka/pkg/client/clientset/versioned/fake"
 	"knative.dev/eventing-kafka/pkg/client/informers/externalversions"
+	dispatchmetrics "knative.dev/eventing-kafka/pkg/common/metrics"
 	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
 	"knative.dev/pkg/controller"
 	kncontroller "knative.dev/pkg/controller"
@@ -69,7 +73,7 @@ func TestNewController(t *testing.T) {
 	stopChan := make(chan struct{})
 
 	// Perform The Test
-	c := NewController(logger, channelKey, mockDispatcher, kafkaChannelInformer, fakeK8sClientSet, fakeKafkaChannelClientSet, stopChan)
+	c := NewController(logger, channelKey, mockDispatcher, kafkaChannelInformer, fakeK8sClientSet, fakeKafkaChannelClientSet, v1beta1.SubscriberHealthThresholds{}, stopChan)
 
 	// Verify Results
 	assert.NotNil(t, c)
@@ -177,6 +181,44 @@ func TestAllCases(t *testing.T) {
 	time.Sleep(1 * time.Second)
 }
 
+// Test The Periodic Merge Of The Subscribers' Failures Into The KafkaChannel Status
+func TestUpdateSubscriberFailures(t *testing.T) {
+
+	// Test Data
+	mockDispatcher := NewMockDispatcher(t)
+	failureTime := time.Now().Truncate(time.Second)
+	lastFailureTime := metav1.NewTime(failureTime)
+	mockDispatcher.health["1"] = dispatchmetrics.SubscriptionHealth{Dispatched: 4, Failed: 1, LastFailureTime: failureTime, LastFailure: "subscriber failed"}
+	channel := reconciletesting.NewKafkaChannel(kcName, testNS,
+		reconciletesting.WithInitKafkaChannelConditions,
+		reconciletesting.WithKafkaChannelAddress("http://channel"),
+		reconciletesting.WithKafkaChannelReady,
+		reconciletesting.WithSubscriber("1", "http://foobar"),
+		reconciletesting.WithSubscriber("2", "http://foobar2"))
+	channel.Status.SubscriberHealth = []v1beta1.SubscriberHealth{{UID: "1", Lag: 5000}} // Measured By The Controller
+	kafkaClient := fakeclientset.NewSimpleClientset(channel)
+	listers := reconciletesting.NewListers([]runtime.Object{channel})
+	reconciler := Reconciler{
+		logger:             logtesting.TestLogger(t).Desugar(),
+		channelKey:         testNS + "/" + kcName,
+		dispatcher:         mockDispatcher,
+		kafkachannelLister: listers.GetKafkaChannelLister(),
+		kafkaClientSet:     kafkaClient,
+		healthThresholds:   v1beta1.SubscriberHealthThresholds{Lag: 1000, FailurePercentage: 20},
+	}
+
+	// The Failures Of The Consuming Subscribers Are Merged Into The Status, Which Keeps The Lag
+	assert.Nil(t, reconciler.updateSubscriberFailures(context.TODO(), testNS+"/"+kcName))
+	updated, err := kafkaClient.MessagingV1beta1().KafkaChannels(testNS).Get(context.TODO(), kcName, metav1.GetOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, []v1beta1.SubscriberHealth{{UID: "1", Lag: 5000, FailurePercentage: 25, LastFailureTime: &lastFailureTime, LastFailure: "subscriber failed"}}, updated.Status.SubscriberHealth)
+	assert.Equal(t, corev1.ConditionTrue, updated.Status.GetCondition(v1beta1.KafkaChannelConditionSubscriberFailing).Status)
+	assert.True(t, updated.Status.IsReady())
+
+	// The KafkaChannels Not Dispatched By This Dispatcher Are Left Alone
+	assert.Nil(t, reconciler.updateSubscriberFailures(context.TODO(), testNS+"/other"))
+}
+
 // Utility Function For Populating Required Environment Variables For Testing
 func populateEnvironmentVariables(t *testing.T) {
 	// Most of these are not actually used, but they need to exist or the GetEnvironment call will fail
@@ -201,21 +243,26 @@ var _ dispatcher.Dispatcher = &MockDispatcher{}
 
 // Define The Mock Dispatcher
 type MockDispatcher struct {
-	t *testing.T
+	t      *testing.T
+	health map[types.UID]dispatchmetrics.SubscriptionHealth
 }
 
 // Mock Dispatcher Constructor
 func NewMockDispatcher(t *testing.T) MockDispatcher {
-	return MockDispatcher{t: t}
+	return MockDispatcher{t: t, health: make(map[types.UID]dispatchmetrics.SubscriptionHealth)}
 }
 
 func (m MockDispatcher) Shutdown() {
 }
 
-func (m MockDispatcher) UpdateSubscriptions(_ []eventingduck.SubscriberSpec) map[eventingduck.SubscriberSpec]error {
+func (m MockDispatcher) UpdateSubscriptions(_ []eventingduck.SubscriberSpec, _ map[types.UID]v1beta1.SubscriberDelivery) map[eventingduck.SubscriberSpec]error {
 	return nil
 }
 
 func (m MockDispatcher) ConfigChanged(*corev1.ConfigMap) dispatcher.Dispatcher {
 	return nil
 }
+
+func (m MockDispatcher) SubscriberHealth() map[types.UID]dispatchmetrics.SubscriptionHealth {
+	return m.health
+}
@@ -18,16 +18,26 @@ package dispatcher
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/Shopify/sarama"
+	gometrics "github.com/rcrowley/go-metrics"
 	"go.uber.org/zap"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/consumer"
+	kafkaproducer "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/producer"
 	kafkasarama "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/sarama"
 	"knative.dev/eventing-kafka/pkg/channel/distributed/common/metrics"
+	commonconsumer "knative.dev/eventing-kafka/pkg/common/consumer"
+	"knative.dev/eventing-kafka/pkg/common/deadletter"
+	dispatchmetrics "knative.dev/eventing-kafka/pkg/common/metrics"
+	"knative.dev/eventing-kafka/pkg/common/retrytopic"
 	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
 	"knative.dev/eventing/pkg/channel"
 )
@@ -43,27 +53,33 @@ type DispatcherConfig struct {
 	ChannelKey      string
 	StatsReporter   metrics.StatsReporter
 	SaramaConfig    *sarama.Config
+	Concurrency     int // Default Number Of Messages Dispatched Concurrently Per Partition
 	SubscriberSpecs []eventingduck.SubscriberSpec
+	// SubscriberDeliveries holds the delivery settings of the active SubscriberSpecs (keyed by subscriber UID)
+	SubscriberDeliveries map[types.UID]kafkav1beta1.SubscriberDelivery
 }
 
 // Knative Eventing SubscriberSpec Wrapper Enhanced With Sarama ConsumerGroup
 type SubscriberWrapper struct {
 	eventingduck.SubscriberSpec
+	Delivery      kafkav1beta1.SubscriberDelivery
 	GroupId       string
 	ConsumerGroup sarama.ConsumerGroup
 	StopChan      chan struct{}
+	Handler       *Handler // The ConsumerGroupHandler, Once Consuming Has Started
 }
 
 // SubscriberWrapper Constructor
-func NewSubscriberWrapper(subscriberSpec eventingduck.SubscriberSpec, groupId string, consumerGroup sarama.ConsumerGroup) *SubscriberWrapper {
-	return &SubscriberWrapper{subscriberSpec, groupId, consumerGroup, make(chan struct{})}
+func NewSubscriberWrapper(subscriberSpec eventingduck.SubscriberSpec, delivery kafkav1beta1.SubscriberDelivery, groupId string, consumerGroup sarama.ConsumerGroup) *SubscriberWrapper {
+	return &SubscriberWrapper{subscriberSpec, delivery, groupId, consumerGroup, make(chan struct{}), nil}
 }
 
 //  Dispatcher Interface
 type Dispatcher interface {
 	ConfigChanged(*v1.ConfigMap) Dispatcher
 	Shutdown()
-	UpdateSubscriptions(subscriberSpecs []eventingduck.SubscriberSpec) map[eventingduck.SubscriberSpec]error
+	UpdateSubscriptions(subscriberSpecs []eventingduck.SubscriberSpec, subscriberDeliveries map[types.UID]kafkav1beta1.SubscriberDelivery) map[eventingduck.SubscriberSpec]error
+	SubscriberHealth() map[types.UID]dispatchmetrics.SubscriptionHealth
 }
 
 // Define A DispatcherImpl Struct With Configuration & ConsumerGroup State
@@ -72,8 +88,26 @@ type DispatcherImpl struct {
 	subscribers        map[types.UID]*SubscriberWrapper
 	consumerUpdateLock sync.Mutex
 	messageDispatcher  channel.MessageDispatcher
+	producer           sarama.SyncProducer // Lazily Created For Subscribers With Retry Topics Or Kafka Topic Dead Letter Sinks
+	restarts           map[types.UID]*subscriberRestart // Subscribers Whose ConsumerGroup Failed To Restart With A New Config
 }
 
+// A Subscriber Whose ConsumerGroup Is Restarted Again After A Backoff
+type subscriberRestart struct {
+	subscriberSpec eventingduck.SubscriberSpec
+	delivery       kafkav1beta1.SubscriberDelivery
+	attempts       int
+}
+
+// The Backoff Between The Attempts To Restart The ConsumerGroup Of A Subscriber (Doubled After Each Failure)
+const (
+	restartInitialBackoff = 1 * time.Second
+	restartMaxBackoff     = 2 * time.Minute
+)
+
+// Error Reported For Subscribers Whose ConsumerGroup Was Stopped But Failed To Close
+var errConsumerGroupNotClosed = errors.New("consumer group failed to close - retrying")
+
 // Verify The DispatcherImpl Implements The Dispatcher Interface
 var _ Dispatcher = &DispatcherImpl{}
 
@@ -85,6 +119,7 @@ func NewDispatcher(dispatcherConfig DispatcherConfig) Dispatcher {
 		DispatcherConfig:  dispatcherConfig,
 		subscribers:       make(map[types.UID]*SubscriberWrapper),
 		messageDispatcher: channel.NewMessageDispatcher(dispatcherConfig.Logger),
+		restarts:          make(map[types.UID]*subscriberRestart),
 	}
 
 	// Return The DispatcherImpl
@@ -94,14 +129,27 @@ func NewDispatcher(dispatcherConfig DispatcherConfig) Dispatcher {
 // Shutdown The Dispatcher
 func (d *DispatcherImpl) Shutdown() {
 
+	// Thread Safe (Pending Restarts Are Abandoned)
+	d.consumerUpdateLock.Lock()
+	defer d.consumerUpdateLock.Unlock()
+	d.restarts = nil
+
 	// Close ConsumerGroups Of All Subscriptions
 	for _, subscriber := range d.subscribers {
 		d.closeConsumerGroup(subscriber)
 	}
+
+	// Close The Producer (If Any) Once No ConsumerGroups Can Use It
+	if d.producer != nil {
+		if err := d.producer.Close(); err != nil {
+			d.Logger.Error("Failed To Close Producer", zap.Error(err))
+		}
+		d.producer = nil
+	}
 }
 
-// Update The Dispatcher's Subscriptions To Align With New State
-func (d *DispatcherImpl) UpdateSubscriptions(subscriberSpecs []eventingduck.SubscriberSpec) map[eventingduck.SubscriberSpec]error {
+// Update The Dispatcher's Subscriptions To Align With New State (Subscribers Missing From The Deliveries Map Use The Defaults)
+func (d *DispatcherImpl) UpdateSubscriptions(subscriberSpecs []eventingduck.SubscriberSpec, subscriberDeliveries map[types.UID]kafkav1beta1.SubscriberDelivery) map[eventingduck.SubscriberSpec]error {
 
 	if d.SaramaConfig == nil {
 		d.Logger.Error("Dispatcher has no config!")
@@ -109,6 +157,7 @@ func (d *DispatcherImpl) UpdateSubscriptions(subscriberSpecs []eventingduck.Subs
 	}
 
 	// Maps For Tracking Subscriber State
+	specifiedSubscriptions := make(map[types.UID]bool)
 	activeSubscriptions := make(map[types.UID]bool)
 	failedSubscriptions := make(map[eventingduck.SubscriberSpec]error)
 
@@ -118,38 +167,41 @@ func (d *DispatcherImpl) UpdateSubscriptions(subscriberSpecs []eventingduck.Subs
 
 	// Loop Over All All The Specified Subscribers
 	for _, subscriberSpec := range subscriberSpecs {
+		specifiedSubscriptions[subscriberSpec.UID] = true
 
-		// If The Subscriber Wrapper For The SubscriberSpec Does Not Exist Then Create One
-		if _, ok := d.subscribers[subscriberSpec.UID]; !ok {
-
-			// Format The GroupId For The Specified Subscriber
-			groupId := fmt.Sprintf("kafka.%s", subscriberSpec.UID)
-
-			// Create A ConsumerGroup Logger
-			logger := d.Logger.With(zap.String("GroupId", groupId))
-
-			// Attempt To Create A Kafka ConsumerGroup
-			consumerGroup, _, err := consumer.CreateConsumerGroup(d.Brokers, d.SaramaConfig, groupId)
-			if err != nil {
+		// Determine The Subscriber's Delivery Settings (Ordering, Concurrency, Retry Topics & Offsets)
+		delivery, ok := subscriberDeliveries[subscriberSpec.UID]
+		if !ok {
+			delivery = kafkav1beta1.DefaultSubscriberDelivery()
+		}
 
-				// Log & Return Failure
-				logger.Error("Failed To Create ConsumerGroup", zap.Error(err))
+		// An Existing Subscriber Whose Messages Are Consumed Differently (Or Which Was Rewound) Needs A New ConsumerGroup
+		// Session (Close Failures Retry Next Time) - Any Other Change Is Applied In Place Without A Re-Balance
+		if subscriber, ok := d.subscribers[subscriberSpec.UID]; ok {
+			if subscriber.stopped() {
+				d.Logger.Info("Closing Stopped Subscriber ConsumerGroup", zap.Any("UID", subscriberSpec.UID))
+				d.closeConsumerGroup(subscriber)
+			} else if d.requiresNewSession(subscriber, subscriberSpec, delivery) {
+				d.Logger.Info("Restarting Subscriber ConsumerGroup", zap.Any("UID", subscriberSpec.UID), zap.String("Rewind", delivery.Rewind))
+				d.closeConsumerGroup(subscriber)
+			} else if err := d.updateSubscriber(subscriber, subscriberSpec, delivery); err != nil {
+				d.Logger.Error("Failed To Update Subscriber", zap.Any("UID", subscriberSpec.UID), zap.Error(err))
 				failedSubscriptions[subscriberSpec] = err
+			}
+		}
 
+		// If The Subscriber Wrapper For The SubscriberSpec Does Not Exist Then Create One
+		if subscriber, ok := d.subscribers[subscriberSpec.UID]; !ok {
+			if err := d.subscribe(subscriberSpec, delivery); err != nil {
+				failedSubscriptions[subscriberSpec] = err
 			} else {
-
-				// Create A New SubscriberWrapper With The ConsumerGroup
-				subscriber := NewSubscriberWrapper(subscriberSpec, groupId, consumerGroup)
-
-				// Should start observing metrics from Sarama Config.MetricsRegistry from CreateConsumerGroup() above ; )
-
-				// Start The ConsumerGroup Processing Messages
-				d.startConsuming(subscriber)
-
-				// Track The New SubscriberWrapper For The SubscriberSpec As Active
-				d.subscribers[subscriberSpec.UID] = subscriber
 				activeSubscriptions[subscriberSpec.UID] = true
 			}
+		} else if subscriber.stopped() {
+
+			// The ConsumerGroup Of The Subscriber Failed To Close Above So It Is Retried Next Time Around
+			failedSubscriptions[subscriberSpec] = errConsumerGroupNotClosed
+			activeSubscriptions[subscriberSpec.UID] = true
 
 		} else {
 
@@ -158,9 +210,17 @@ func (d *DispatcherImpl) UpdateSubscriptions(subscriberSpecs []eventingduck.Subs
 		}
 	}
 
-	// Save the current (active) subscriber specs so that ConfigChanged() can use them to recreate the Dispatcher
-	// if necessary without going through the inactive subscribers again.
+	// Abandon The Pending Restarts Of Subscribers Which Are Consuming Again Or Are No Longer Specified
+	for uid := range d.restarts {
+		if subscriber, ok := d.subscribers[uid]; !specifiedSubscriptions[uid] || (ok && !subscriber.stopped()) {
+			delete(d.restarts, uid)
+		}
+	}
+
+	// Save the current (active) subscriber specs, which ConfigChanged() restarts the ConsumerGroups of in place
+	// without going through the inactive subscribers again.
 	d.SubscriberSpecs = []eventingduck.SubscriberSpec{}
+	d.SubscriberDeliveries = make(map[types.UID]kafkav1beta1.SubscriberDelivery)
 
 	// Close ConsumerGroups For Removed Subscriptions (In Map But No Longer Active)
 	for _, subscriber := range d.subscribers {
@@ -168,6 +228,7 @@ func (d *DispatcherImpl) UpdateSubscriptions(subscriberSpecs []eventingduck.Subs
 			d.closeConsumerGroup(subscriber)
 		} else {
 			d.SubscriberSpecs = append(d.SubscriberSpecs, subscriber.SubscriberSpec)
+			d.SubscriberDeliveries[subscriber.UID] = subscriber.Delivery
 		}
 	}
 
@@ -175,6 +236,41 @@ func (d *DispatcherImpl) UpdateSubscriptions(subscriberSpecs []eventingduck.Subs
 	return failedSubscriptions
 }
 
+// Create The ConsumerGroup Of A Subscriber & Start Consuming Messages With It
+func (d *DispatcherImpl) subscribe(subscriberSpec eventingduck.SubscriberSpec, delivery kafkav1beta1.SubscriberDelivery) error {
+
+	// Format The GroupId For The Specified Subscriber (A New One For Each Rewind)
+	groupId := commonconsumer.GroupID(fmt.Sprintf("kafka.%s", subscriberSpec.UID), delivery)
+
+	// Create A ConsumerGroup Logger
+	logger := d.Logger.With(zap.String("GroupId", groupId))
+
+	// Ensure The Producer Exists If The Subscriber Uses Retry Topics Or Dead Letters To A Kafka Topic
+	if err := d.ensureProducer(subscriberSpec, delivery); err != nil {
+		logger.Error("Failed To Create Producer", zap.Error(err))
+		return err
+	}
+
+	// Attempt To Create A Kafka ConsumerGroup (New Groups Start At The Subscriber's Start Offset, If Any)
+	consumerGroup, _, err := consumer.CreateConsumerGroup(d.Brokers, commonconsumer.ConsumerConfig(d.SaramaConfig, delivery), groupId)
+	if err != nil {
+		logger.Error("Failed To Create ConsumerGroup", zap.Error(err))
+		return err
+	}
+
+	// Create A New SubscriberWrapper With The ConsumerGroup
+	subscriber := NewSubscriberWrapper(subscriberSpec, delivery, groupId, consumerGroup)
+
+	// Should start observing metrics from Sarama Config.MetricsRegistry from CreateConsumerGroup() above ; )
+
+	// Start The ConsumerGroup Processing Messages
+	d.startConsuming(subscriber)
+
+	// Track The New SubscriberWrapper For The SubscriberSpec
+	d.subscribers[subscriberSpec.UID] = subscriber
+	return nil
+}
+
 // Start Consuming Messages With The Specified Subscriber's ConsumerGroup
 func (d *DispatcherImpl) startConsuming(subscriber *SubscriberWrapper) {
 
@@ -193,8 +289,23 @@ func (d *DispatcherImpl) startConsuming(subscriber *SubscriberWrapper) {
 			logger.Info("ConsumerGroup Error Processing Terminated")
 		}()
 
-		// Create A New ConsumerGroupHandler To Consume Messages With
-		handler := NewHandler(logger, &subscriber.SubscriberSpec)
+		// Apply The Dispatcher's Default Concurrency Unless The KafkaChannel Specifies One
+		delivery := subscriber.Delivery
+		if delivery.Concurrency <= 0 {
+			delivery.Concurrency = d.Concurrency
+		}
+
+		// Determine The Subscriber's Retry Topics (If Any), Which The ConsumerGroup Consumes Along With The Channel Topic
+		retryTopics, err := retrytopic.SubscriberTopics(d.Topic, subscriber.SubscriberSpec, delivery)
+		if err != nil {
+			logger.Warn("Failed To Determine Retry Topics From DeliverySpec - Retrying In Place", zap.Error(err))
+		}
+		topics := append([]string{d.Topic}, retryTopics.Names()...)
+
+		// Create A New ConsumerGroupHandler To Consume Messages With (With Its Own Copy Of The SubscriberSpec)
+		subscriberSpec := subscriber.SubscriberSpec
+		handler := NewHandler(logger, &subscriberSpec, delivery, d.producer, retryTopics, d.subscriptionReporter(subscriberSpec.UID))
+		subscriber.Handler = handler
 
 		// Consume Messages Asynchronously
 		go func() {
@@ -212,7 +323,7 @@ func (d *DispatcherImpl) startConsuming(subscriber *SubscriberWrapper) {
 				// Start ConsumerGroup Consumption
 				default:
 					logger.Info("ConsumerGroup Message Consumption Initiated")
-					err := subscriber.ConsumerGroup.Consume(ctx, []string{d.Topic}, handler)
+					err := subscriber.ConsumerGroup.Consume(ctx, topics, handler)
 					if err != nil {
 						if err == sarama.ErrClosedConsumerGroup {
 							logger.Info("ConsumerGroup Closed Error - Ceasing Consumption") // Should be caught above but here as added precaution.
@@ -227,6 +338,98 @@ func (d *DispatcherImpl) startConsuming(subscriber *SubscriberWrapper) {
 	}
 }
 
+// Return The Health Of The Subscribers Which Are Consuming
+func (d *DispatcherImpl) SubscriberHealth() map[types.UID]dispatchmetrics.SubscriptionHealth {
+	d.consumerUpdateLock.Lock()
+	defer d.consumerUpdateLock.Unlock()
+	subscriberHealth := make(map[types.UID]dispatchmetrics.SubscriptionHealth, len(d.subscribers))
+	for uid, subscriber := range d.subscribers {
+		if subscriber.Handler != nil {
+			subscriberHealth[uid] = subscriber.Handler.Metrics.Health()
+		}
+	}
+	return subscriberHealth
+}
+
+// Create The Reporter Of The Dispatch Metrics Of A Subscriber
+func (d *DispatcherImpl) subscriptionReporter(uid types.UID) *dispatchmetrics.SubscriptionReporter {
+	namespace, name, err := cache.SplitMetaNamespaceKey(d.ChannelKey)
+	if err != nil {
+		d.Logger.Warn("Invalid ChannelKey - Dispatch Metrics Will Not Be Reported", zap.String("ChannelKey", d.ChannelKey), zap.Error(err))
+		return nil
+	}
+	return dispatchmetrics.NewSubscriptionReporter(namespace, name, uid)
+}
+
+// Determine Whether The New Settings Of A Subscriber Can Not Be Applied To Its Running ConsumerGroup
+func (d *DispatcherImpl) requiresNewSession(subscriber *SubscriberWrapper, subscriberSpec eventingduck.SubscriberSpec, delivery kafkav1beta1.SubscriberDelivery) bool {
+
+	// Changes To The Ordering, Concurrency, Retry Topics Or Rewind Offset Change How Messages Are Consumed
+	if commonconsumer.RequiresNewSession(subscriber.Delivery, delivery) {
+		return true
+	}
+
+	// As Does A Change To The Number Of Retry Topics (I.e. Retry Levels) Consumed Along With The Channel Topic
+	previousTopics, _ := retrytopic.SubscriberTopics(d.Topic, subscriber.SubscriberSpec, subscriber.Delivery)
+	topics, _ := retrytopic.SubscriberTopics(d.Topic, subscriberSpec, delivery)
+	return len(previousTopics.Delays) != len(topics.Delays)
+}
+
+// Update An Existing Subscriber In Place (The Handler Dispatches Subsequent Messages With The New SubscriberSpec)
+func (d *DispatcherImpl) updateSubscriber(subscriber *SubscriberWrapper, subscriberSpec eventingduck.SubscriberSpec, delivery kafkav1beta1.SubscriberDelivery) error {
+
+	// The Subscriber May Have Started Dead Lettering To A Kafka Topic
+	if err := d.ensureProducer(subscriberSpec, delivery); err != nil {
+		return err
+	}
+
+	// Track The New Settings (Concurrency Defaults Are Applied By startConsuming() So The Delivery Is Kept As Specified)
+	subscriber.SubscriberSpec = subscriberSpec
+	subscriber.Delivery = delivery
+
+	// Swap The Handler's Subscription (Retry Topic Delays May Have Changed Even Though Their Number Has Not)
+	if subscriber.Handler != nil {
+		retryTopics, err := retrytopic.SubscriberTopics(d.Topic, subscriberSpec, delivery)
+		if err != nil {
+			d.Logger.Warn("Failed To Determine Retry Topics From DeliverySpec - Retrying In Place", zap.Error(err))
+		}
+		handlerSpec := subscriberSpec
+		subscriber.Handler.UpdateSubscription(&handlerSpec, d.producer, retryTopics)
+	}
+	return nil
+}
+
+// Create The Producer If The Subscriber Uses Retry Topics Or Its Dead Letter Sink Is A Kafka Topic And It Doesn't Exist Yet
+func (d *DispatcherImpl) ensureProducer(subscriberSpec eventingduck.SubscriberSpec, delivery kafkav1beta1.SubscriberDelivery) error {
+
+	// Nothing To Do Unless The Subscriber Has Retry Topics Or A Kafka Topic Dead Letter Sink
+	if d.producer != nil || subscriberSpec.Delivery == nil {
+		return nil
+	}
+	usesRetryTopics := delivery.UsesRetryTopics() && subscriberSpec.Delivery.Retry != nil && *subscriberSpec.Delivery.Retry > 0
+	usesDeadLetterTopic := subscriberSpec.Delivery.DeadLetterSink != nil &&
+		subscriberSpec.Delivery.DeadLetterSink.URI != nil &&
+		deadletter.IsKafkaTopic(subscriberSpec.Delivery.DeadLetterSink.URI.URL())
+	if !usesRetryTopics && !usesDeadLetterTopic {
+		return nil
+	}
+
+	// Create A SyncProducer With The Dispatcher's Sarama Config (The SyncProducer Requires Successes To Be Returned)
+	config := *d.SaramaConfig
+	config.Producer.Return.Successes = true
+	producer, _, err := newSyncProducerWrapper(d.Brokers, &config)
+	if err != nil {
+		return err
+	}
+	d.producer = producer
+	return nil
+}
+
+// Wrapper Around Common Kafka SyncProducer Creation To Facilitate Unit Testing
+var newSyncProducerWrapper = func(brokers []string, config *sarama.Config) (sarama.SyncProducer, gometrics.Registry, error) {
+	return kafkaproducer.CreateSyncProducer(brokers, config)
+}
+
 // Close The ConsumerGroup Associated With A Single Subscriber
 func (d *DispatcherImpl) closeConsumerGroup(subscriber *SubscriberWrapper) {
 
@@ -239,8 +442,10 @@ func (d *DispatcherImpl) closeConsumerGroup(subscriber *SubscriberWrapper) {
 	// If The ConsumerGroup Is Valid
 	if consumerGroup != nil {
 
-		// Mark The Subscriber's ConsumerGroup As Stopped
-		close(subscriber.StopChan)
+		// Mark The Subscriber's ConsumerGroup As Stopped (Unless A Previous Close Already Did)
+		if !subscriber.stopped() {
+			close(subscriber.StopChan)
+		}
 
 		// Close The ConsumerGroup
 		err := consumerGroup.Close()
@@ -259,14 +464,27 @@ func (d *DispatcherImpl) closeConsumerGroup(subscriber *SubscriberWrapper) {
 	}
 }
 
+// Determine Whether The ConsumerGroup Of A Subscriber Has Been Stopped
+func (s *SubscriberWrapper) stopped() bool {
+	select {
+	case <-s.StopChan:
+		return true
+	default:
+		return false
+	}
+}
+
 // ConfigChanged is called by the configMapObserver handler function in main() so that
 // settings specific to the dispatcher may be extracted and the ConsumerGroups restarted if necessary.
 // The new configmap could technically have changes to the eventing-kafka section as well as the sarama
-// section, but none of those matter to a currently-running Dispatcher, so those are ignored here
-// (which avoids the necessity of calling env.GetEnvironment).  If those settings
+// section, but apart from the dispatcher concurrency none of those matter to a currently-running Dispatcher,
+// so those are ignored here (which avoids the necessity of calling env.GetEnvironment).  If those settings
 // are needed in the future, the environment will also need to be re-parsed here.
 // If there aren't any consumer-specific differences between the current config and the new one,
 // then just log that and move on; do not restart the ConsumerGroups unnecessarily.
+// Otherwise the ConsumerGroups are restarted one subscriber at a time, and those which fail to restart are
+// retried with a backoff rather than terminating the dispatcher.  Until they are restarted, UpdateSubscriptions()
+// reports them as failed, so main() resyncs the KafkaChannels after a change to mark their subscribers not ready.
 func (d *DispatcherImpl) ConfigChanged(configMap *v1.ConfigMap) Dispatcher {
 
 	// Create A New Sarama Config
@@ -279,6 +497,7 @@ func (d *DispatcherImpl) ConfigChanged(configMap *v1.ConfigMap) Dispatcher {
 	}
 
 	// Validate Configuration (Should Always Be Present)
+	newConcurrency := d.Concurrency
 	if d.SaramaConfig != nil {
 
 		// Some of the current config settings may not be overridden by the configmap (username, password, etc.)
@@ -288,26 +507,96 @@ func (d *DispatcherImpl) ConfigChanged(configMap *v1.ConfigMap) Dispatcher {
 		if ekConfig, err := kafkasarama.LoadEventingKafkaSettings(configMap); err == nil && ekConfig != nil {
 			kafkasarama.EnableSaramaLogging(ekConfig.Kafka.EnableSaramaLogging)
 			d.Logger.Debug("Updated Sarama logging", zap.Bool("Kafka.EnableSaramaLogging", ekConfig.Kafka.EnableSaramaLogging))
+			newConcurrency = ekConfig.Dispatcher.Concurrency
 		} else {
 			d.Logger.Error("Could Not Extract Eventing-Kafka Setting From Updated ConfigMap", zap.Error(err))
 		}
 
 		// Ignore the "Producer" section as changes to that do not require recreating the Dispatcher
-		if kafkasarama.ConfigEqual(newConfig, d.SaramaConfig, newConfig.Producer) {
+		if kafkasarama.ConfigEqual(newConfig, d.SaramaConfig, newConfig.Producer) && newConcurrency == d.Concurrency {
 			d.Logger.Info("No Consumer Changes Detected In New Configuration - Ignoring")
 			return nil
 		}
 	}
 
-	// Create A New Dispatcher With The New Configuration (Reusing All Other Existing Config)
-	d.Logger.Info("Consumer Changes Detected In New Configuration - Recreating Dispatcher")
-	d.Shutdown()
-	d.DispatcherConfig.SaramaConfig = newConfig
-	newDispatcher := NewDispatcher(d.DispatcherConfig)
-	failedSubscriptions := newDispatcher.UpdateSubscriptions(d.SubscriberSpecs)
-	if len(failedSubscriptions) > 0 {
-		d.Logger.Fatal("Failed To Subscribe Kafka Subscriptions For New Dispatcher", zap.Int("Count", len(failedSubscriptions)))
+	// A Bad Edit Of The ConfigMap Leaves The ConsumerGroups Running With The Current Configuration
+	if err := newConfig.Validate(); err != nil {
+		d.Logger.Error("Invalid Sarama Settings In New Configuration - Keeping The Current Configuration", zap.Error(err))
 		return nil
 	}
-	return newDispatcher
+
+	// Thread Safe ;)
+	d.consumerUpdateLock.Lock()
+	defer d.consumerUpdateLock.Unlock()
+
+	// Swap The Configuration & Restart The ConsumerGroups With It (Reusing All Other Existing Config)
+	d.Logger.Info("Consumer Changes Detected In New Configuration - Restarting ConsumerGroups")
+	d.DispatcherConfig.SaramaConfig = newConfig
+	d.DispatcherConfig.Concurrency = newConcurrency
+	restarts := make([]*subscriberRestart, 0, len(d.subscribers))
+	for _, subscriber := range d.subscribers {
+		restarts = append(restarts, &subscriberRestart{subscriberSpec: subscriber.SubscriberSpec, delivery: subscriber.Delivery})
+	}
+	failedRestarts := 0
+	for _, restart := range restarts {
+		if err := d.restartSubscriber(restart); err != nil {
+			d.Logger.Error("Failed To Restart Subscriber ConsumerGroup - Retrying", zap.Any("UID", restart.subscriberSpec.UID), zap.Error(err))
+			d.scheduleRestart(restart)
+			failedRestarts++
+		}
+	}
+	if failedRestarts > 0 {
+		d.Logger.Warn("Some Subscriber ConsumerGroups Failed To Restart With The New Configuration", zap.Int("Count", failedRestarts))
+	}
+	return d
+}
+
+// Restart The ConsumerGroup Of A Subscriber With The Current Configuration (Closing It Waits For The Messages Being
+// Dispatched & Commits Their Offsets, So The New ConsumerGroup Resumes Where The Previous One Left Off)
+func (d *DispatcherImpl) restartSubscriber(restart *subscriberRestart) error {
+	if subscriber, ok := d.subscribers[restart.subscriberSpec.UID]; ok {
+		d.closeConsumerGroup(subscriber)
+		if _, ok := d.subscribers[restart.subscriberSpec.UID]; ok {
+			return errConsumerGroupNotClosed
+		}
+	}
+	return d.subscribe(restart.subscriberSpec, restart.delivery)
+}
+
+// Schedule Another Attempt To Restart The ConsumerGroup Of A Subscriber After A Backoff
+func (d *DispatcherImpl) scheduleRestart(restart *subscriberRestart) {
+	if d.restarts == nil {
+		d.restarts = make(map[types.UID]*subscriberRestart)
+	}
+	d.restarts[restart.subscriberSpec.UID] = restart
+	time.AfterFunc(restartBackoff(restart.attempts), func() {
+		d.consumerUpdateLock.Lock()
+		defer d.consumerUpdateLock.Unlock()
+
+		// The Restart Was Abandoned By UpdateSubscriptions() Or Shutdown() In The Meantime
+		if d.restarts[restart.subscriberSpec.UID] != restart {
+			return
+		}
+		delete(d.restarts, restart.subscriberSpec.UID)
+
+		if err := d.restartSubscriber(restart); err != nil {
+			restart.attempts++
+			d.Logger.Error("Failed To Restart Subscriber ConsumerGroup - Retrying", zap.Any("UID", restart.subscriberSpec.UID), zap.Int("Attempts", restart.attempts), zap.Error(err))
+			d.scheduleRestart(restart)
+		} else {
+			d.Logger.Info("Successfully Restarted Subscriber ConsumerGroup", zap.Any("UID", restart.subscriberSpec.UID))
+		}
+	})
+}
+
+// Get The Backoff Before The Specified Attempt To Restart A ConsumerGroup
+func restartBackoff(attempts int) time.Duration {
+	backoff := restartInitialBackoff
+	for i := 0; i < attempts && backoff < restartMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > restartMaxBackoff {
+		backoff = restartMaxBackoff
+	}
+	return backoff
 }
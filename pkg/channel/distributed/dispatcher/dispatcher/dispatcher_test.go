@@ -18,21 +18,29 @@ package dispatcher
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"sync"
 
 	"github.com/Shopify/sarama"
 	"github.com/ghodss/yaml"
+	gometrics "github.com/rcrowley/go-metrics"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
 	commonconfig "knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
 	kafkaconsumer "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/consumer"
 	kafkatesting "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/testing"
 	"knative.dev/eventing-kafka/pkg/common/constants"
+	commonconsumer "knative.dev/eventing-kafka/pkg/common/consumer"
+	dispatchmetrics "knative.dev/eventing-kafka/pkg/common/metrics"
 	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
 	"knative.dev/eventing/pkg/channel"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
 	logtesting "knative.dev/pkg/logging/testing"
 	"knative.dev/pkg/system"
 
@@ -104,6 +112,10 @@ Admin:
 	TestEventingKafka = `
 kafka:
   enableSaramaLogging: true`
+
+	TestEventingKafkaConcurrencyChange = TestEventingKafka + `
+dispatcher:
+  concurrency: 10`
 )
 
 // Test The NewSubscriberWrapper() Functionality
@@ -115,7 +127,7 @@ func TestNewSubscriberWrapper(t *testing.T) {
 	consumerGroup := kafkatesting.NewMockConsumerGroup(t)
 
 	// Perform The Test
-	subscriberWrapper := NewSubscriberWrapper(subscriber, groupId, consumerGroup)
+	subscriberWrapper := NewSubscriberWrapper(subscriber, kafkav1beta1.DefaultSubscriberDelivery(), groupId, consumerGroup)
 
 	// Verify Results
 	assert.NotNil(t, subscriberWrapper)
@@ -160,9 +172,9 @@ func TestShutdown(t *testing.T) {
 			Logger: logtesting.TestLogger(t).Desugar(),
 		},
 		subscribers: map[types.UID]*SubscriberWrapper{
-			subscriber1.UID: NewSubscriberWrapper(subscriber1, groupId1, consumerGroup1),
-			subscriber2.UID: NewSubscriberWrapper(subscriber2, groupId2, consumerGroup2),
-			subscriber3.UID: NewSubscriberWrapper(subscriber3, groupId3, consumerGroup3),
+			subscriber1.UID: NewSubscriberWrapper(subscriber1, kafkav1beta1.DefaultSubscriberDelivery(), groupId1, consumerGroup1),
+			subscriber2.UID: NewSubscriberWrapper(subscriber2, kafkav1beta1.DefaultSubscriberDelivery(), groupId2, consumerGroup2),
+			subscriber3.UID: NewSubscriberWrapper(subscriber3, kafkav1beta1.DefaultSubscriberDelivery(), groupId3, consumerGroup3),
 		},
 	}
 
@@ -319,7 +331,7 @@ func TestUpdateSubscriptions(t *testing.T) {
 			}
 
 			// Perform The Test
-			got := dispatcher.UpdateSubscriptions(tt.args.subscriberSpecs)
+			got := dispatcher.UpdateSubscriptions(tt.args.subscriberSpecs, nil)
 
 			// Verify Results
 			assert.Equal(t, tt.want, got)
@@ -340,9 +352,175 @@ func TestUpdateSubscriptions(t *testing.T) {
 	}
 }
 
+// Test The UpdateSubscriptions() Functionality With A Kafka Topic Dead Letter Sink
+func TestUpdateSubscriptionsKafkaDeadLetterSink(t *testing.T) {
+
+	// Mock ConsumerGroup & SyncProducer Creation (And Restore After The Test)
+	newConsumerGroupWrapperPlaceholder := kafkaconsumer.NewConsumerGroupWrapper
+	kafkaconsumer.NewConsumerGroupWrapper = func(brokersArg []string, groupIdArg string, configArg *sarama.Config) (sarama.ConsumerGroup, error) {
+		return kafkatesting.NewMockConsumerGroup(t), nil
+	}
+	newSyncProducerWrapperPlaceholder := newSyncProducerWrapper
+	producerCount := 0
+	newSyncProducerWrapper = func(brokers []string, config *sarama.Config) (sarama.SyncProducer, gometrics.Registry, error) {
+		assert.True(t, config.Producer.Return.Successes)
+		producerCount++
+		return nil, nil, errors.New("test producer error")
+	}
+	defer func() {
+		kafkaconsumer.NewConsumerGroupWrapper = newConsumerGroupWrapperPlaceholder
+		newSyncProducerWrapper = newSyncProducerWrapperPlaceholder
+	}()
+
+	// Create A New DispatcherImpl To Test
+	dispatcher := &DispatcherImpl{
+		DispatcherConfig: DispatcherConfig{
+			SaramaConfig: getSaramaConfigFromYaml(t, TestConfigBase),
+			Logger:       logtesting.TestLogger(t).Desugar(),
+		},
+		subscribers: map[types.UID]*SubscriberWrapper{},
+	}
+
+	// A Subscriber With A Kafka Topic Dead Letter Sink Fails When The Producer Cannot Be Created
+	deadLetterURI, _ := apis.ParseURL("kafka://dead-letters")
+	kafkaDeadLetterSpec := eventingduck.SubscriberSpec{UID: uid123, Delivery: &eventingduck.DeliverySpec{DeadLetterSink: &duckv1.Destination{URI: deadLetterURI}}}
+	httpSpec := eventingduck.SubscriberSpec{UID: uid456}
+	failed := dispatcher.UpdateSubscriptions([]eventingduck.SubscriberSpec{kafkaDeadLetterSpec, httpSpec}, nil)
+
+	// Verify Results
+	assert.Len(t, failed, 1)
+	assert.NotNil(t, failed[kafkaDeadLetterSpec])
+	assert.Equal(t, 1, producerCount)
+	assert.Nil(t, dispatcher.subscribers[uid123])
+	assert.NotNil(t, dispatcher.subscribers[uid456])
+	dispatcher.Shutdown()
+}
+
+// Test The Creation Of The Subscribers' Dispatch Metrics Reporters
+func TestSubscriptionReporter(t *testing.T) {
+	dispatcher := &DispatcherImpl{
+		DispatcherConfig: DispatcherConfig{
+			ChannelKey: "channel-namespace/channel-name",
+			Logger:     logtesting.TestLogger(t).Desugar(),
+		},
+	}
+	assert.Equal(t, dispatchmetrics.NewSubscriptionReporter("channel-namespace", "channel-name", uid123), dispatcher.subscriptionReporter(uid123))
+
+	// An Invalid ChannelKey Disables The Dispatch Metrics
+	dispatcher.ChannelKey = "a/b/c"
+	assert.Nil(t, dispatcher.subscriptionReporter(uid123))
+}
+
+// Test The UpdateSubscriptions() Functionality With Rewound Subscribers & Start Offsets
+func TestUpdateSubscriptionsRewind(t *testing.T) {
+
+	// Mock ConsumerGroup Creation, Recording The GroupIds & Initial Offsets (And Restore After The Test)
+	var groupIds []string
+	var initialOffsets []int64
+	newConsumerGroupWrapperPlaceholder := kafkaconsumer.NewConsumerGroupWrapper
+	kafkaconsumer.NewConsumerGroupWrapper = func(brokersArg []string, groupIdArg string, configArg *sarama.Config) (sarama.ConsumerGroup, error) {
+		groupIds = append(groupIds, groupIdArg)
+		initialOffsets = append(initialOffsets, configArg.Consumer.Offsets.Initial)
+		return kafkatesting.NewMockConsumerGroup(t), nil
+	}
+	defer func() {
+		kafkaconsumer.NewConsumerGroupWrapper = newConsumerGroupWrapperPlaceholder
+	}()
+
+	// Create A New DispatcherImpl To Test
+	dispatcher := &DispatcherImpl{
+		DispatcherConfig: DispatcherConfig{
+			SaramaConfig: getSaramaConfigFromYaml(t, TestConfigBase),
+			Logger:       logtesting.TestLogger(t).Desugar(),
+		},
+		subscribers: map[types.UID]*SubscriberWrapper{},
+	}
+	subscriberSpecs := []eventingduck.SubscriberSpec{{UID: uid123}}
+	deliveries := func(startOffset string, rewind string) map[types.UID]kafkav1beta1.SubscriberDelivery {
+		delivery := kafkav1beta1.DefaultSubscriberDelivery()
+		delivery.StartOffset = startOffset
+		delivery.Rewind = rewind
+		return map[types.UID]kafkav1beta1.SubscriberDelivery{uid123: delivery}
+	}
+
+	// A New Subscriber Starts At Its Start Offset, And Is Only Moved To A New ConsumerGroup When Rewound
+	assert.Empty(t, dispatcher.UpdateSubscriptions(subscriberSpecs, deliveries("latest", "")))
+	assert.Empty(t, dispatcher.UpdateSubscriptions(subscriberSpecs, deliveries("earliest", "")))
+	assert.Empty(t, dispatcher.UpdateSubscriptions(subscriberSpecs, deliveries("latest", "earliest")))
+	assert.Empty(t, dispatcher.UpdateSubscriptions(subscriberSpecs, deliveries("latest", "earliest")))
+
+	// Verify Results
+	assert.Len(t, groupIds, 2)
+	assert.Equal(t, fmt.Sprintf("kafka.%s", uid123), groupIds[0])
+	assert.Equal(t, commonconsumer.GroupID(fmt.Sprintf("kafka.%s", uid123), deliveries("", "earliest")[uid123]), groupIds[1])
+	assert.Equal(t, []int64{sarama.OffsetNewest, sarama.OffsetOldest}, initialOffsets)
+	assert.Len(t, dispatcher.subscribers, 1)
+	assert.Equal(t, groupIds[1], dispatcher.subscribers[uid123].GroupId)
+	assert.Equal(t, "earliest", dispatcher.SubscriberDeliveries[uid123].Rewind)
+	dispatcher.Shutdown()
+}
+
+// Test The UpdateSubscriptions() Functionality With Subscribers Updated In Place
+func TestUpdateSubscriptionsInPlace(t *testing.T) {
+
+	// Mock ConsumerGroup Creation, Counting The ConsumerGroups Created (And Restore After The Test)
+	consumerGroupCount := 0
+	newConsumerGroupWrapperPlaceholder := kafkaconsumer.NewConsumerGroupWrapper
+	kafkaconsumer.NewConsumerGroupWrapper = func(brokersArg []string, groupIdArg string, configArg *sarama.Config) (sarama.ConsumerGroup, error) {
+		consumerGroupCount++
+		return kafkatesting.NewMockConsumerGroup(t), nil
+	}
+	newSyncProducerWrapperPlaceholder := newSyncProducerWrapper
+	newSyncProducerWrapper = func(brokers []string, config *sarama.Config) (sarama.SyncProducer, gometrics.Registry, error) {
+		return nil, nil, nil // No Messages Are Produced By This Test
+	}
+	defer func() {
+		kafkaconsumer.NewConsumerGroupWrapper = newConsumerGroupWrapperPlaceholder
+		newSyncProducerWrapper = newSyncProducerWrapperPlaceholder
+	}()
+
+	// Create A New DispatcherImpl To Test
+	dispatcher := &DispatcherImpl{
+		DispatcherConfig: DispatcherConfig{
+			SaramaConfig: getSaramaConfigFromYaml(t, TestConfigBase),
+			Logger:       logtesting.TestLogger(t).Desugar(),
+		},
+		subscribers: map[types.UID]*SubscriberWrapper{},
+	}
+	subscriberURI1, _ := apis.ParseURL("http://subscriber-1")
+	subscriberURI2, _ := apis.ParseURL("http://subscriber-2")
+	retry := func(retries int32) *eventingduck.DeliverySpec {
+		return &eventingduck.DeliverySpec{Retry: &retries}
+	}
+	retryTopics := func(ordering kafkav1beta1.DeliveryOrdering) map[types.UID]kafkav1beta1.SubscriberDelivery {
+		return map[types.UID]kafkav1beta1.SubscriberDelivery{uid123: {Ordering: ordering, RetryTopics: true}}
+	}
+
+	// A Changed Subscriber URI Or Retry Delay Is Applied To The Existing Handler
+	assert.Empty(t, dispatcher.UpdateSubscriptions([]eventingduck.SubscriberSpec{{UID: uid123, SubscriberURI: subscriberURI1}}, nil))
+	handler := dispatcher.subscribers[uid123].Handler
+	assert.NotNil(t, handler)
+	assert.Empty(t, dispatcher.UpdateSubscriptions([]eventingduck.SubscriberSpec{{UID: uid123, SubscriberURI: subscriberURI2, Delivery: retry(2)}}, nil))
+	assert.Equal(t, 1, consumerGroupCount)
+	assert.Same(t, handler, dispatcher.subscribers[uid123].Handler)
+	assert.Equal(t, subscriberURI2, handler.Subscription().Subscriber.SubscriberURI)
+	assert.Equal(t, 2, handler.Subscription().retryConfig.RetryMax)
+	assert.Equal(t, subscriberURI2, dispatcher.SubscriberSpecs[0].SubscriberURI)
+
+	// Changes To The Ordering Or The Number Of Retry Topics Require A New ConsumerGroup
+	assert.Empty(t, dispatcher.UpdateSubscriptions([]eventingduck.SubscriberSpec{{UID: uid123, Delivery: retry(2)}}, retryTopics(kafkav1beta1.DeliveryUnordered)))
+	assert.Equal(t, 2, consumerGroupCount)
+	assert.Empty(t, dispatcher.UpdateSubscriptions([]eventingduck.SubscriberSpec{{UID: uid123, Delivery: retry(3)}}, retryTopics(kafkav1beta1.DeliveryUnordered)))
+	assert.Equal(t, 3, consumerGroupCount)
+	assert.Empty(t, dispatcher.UpdateSubscriptions([]eventingduck.SubscriberSpec{{UID: uid123, Delivery: retry(3)}}, retryTopics(kafkav1beta1.DeliveryOrderedByKey)))
+	assert.Equal(t, 4, consumerGroupCount)
+	assert.Len(t, dispatcher.subscribers, 1)
+	dispatcher.Shutdown()
+}
+
 // Utility Function For Creating A SubscriberWrapper With Specified UID & Mock ConsumerGroup
 func createSubscriberWrapper(t *testing.T, uid types.UID) *SubscriberWrapper {
-	return NewSubscriberWrapper(eventingduck.SubscriberSpec{UID: uid}, fmt.Sprintf("kafka.%s", string(uid)), kafkatesting.NewMockConsumerGroup(t))
+	return NewSubscriberWrapper(eventingduck.SubscriberSpec{UID: uid}, kafkav1beta1.DefaultSubscriberDelivery(), fmt.Sprintf("kafka.%s", string(uid)), kafkatesting.NewMockConsumerGroup(t))
 }
 
 func getBaseConfigMap() *corev1.ConfigMap {
@@ -394,6 +572,10 @@ func TestConfigChanged(t *testing.T) {
 	// Verify that having eventing-kafka settings in the configmap doesn't cause trouble
 	dispatcher = runConfigChangedTest(t, dispatcher, getBaseConfigMap(), TestConfigBase, TestEventingKafka, false)
 	assert.NotNil(t, dispatcher)
+
+	// Verify that a change to the dispatcher concurrency causes Reconfigure to be called
+	dispatcher = runConfigChangedTest(t, dispatcher, getBaseConfigMap(), TestConfigBase, TestEventingKafkaConcurrencyChange, true)
+	assert.Equal(t, 10, dispatcher.(*DispatcherImpl).Concurrency)
 }
 
 func runConfigChangedTest(t *testing.T, originalDispatcher Dispatcher, base *corev1.ConfigMap, changed string, eventingKafka string, expectedNewDispatcher bool) Dispatcher {
@@ -421,3 +603,108 @@ func runConfigChangedTest(t *testing.T, originalDispatcher Dispatcher, base *cor
 	}
 	return originalDispatcher
 }
+
+// Test That ConfigChanged() Restarts The ConsumerGroups Of The Existing Dispatcher, Retrying Those Which Fail
+func TestConfigChangedRestartsSubscribers(t *testing.T) {
+	logger := logtesting.TestLogger(t).Desugar()
+	assert.Nil(t, os.Setenv(system.NamespaceEnvKey, constants.KnativeEventingNamespace))
+
+	// Mock ConsumerGroup Creation, Tracking The Groups & Configs Created (And Restore After The Test)
+	var lock sync.Mutex
+	consumerGroups := make(map[string]*kafkatesting.MockConsumerGroup)
+	retentions := make(map[string]time.Duration)
+	failingGroupId := ""
+	newConsumerGroupWrapperPlaceholder := kafkaconsumer.NewConsumerGroupWrapper
+	kafkaconsumer.NewConsumerGroupWrapper = func(brokersArg []string, groupIdArg string, configArg *sarama.Config) (sarama.ConsumerGroup, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		if groupIdArg == failingGroupId {
+			return nil, errors.New("test consumer group error")
+		}
+		consumerGroup := kafkatesting.NewMockConsumerGroup(t)
+		consumerGroups[groupIdArg] = consumerGroup
+		retentions[groupIdArg] = configArg.Consumer.Offsets.Retention
+		return consumerGroup, nil
+	}
+	defer func() {
+		kafkaconsumer.NewConsumerGroupWrapper = newConsumerGroupWrapperPlaceholder
+	}()
+
+	// Create The Dispatcher With Two Subscribers Consuming With The Base Configuration
+	dispatcher := NewDispatcher(DispatcherConfig{Logger: logger, ChannelKey: "test-namespace/test-channel"}).(*DispatcherImpl)
+	dispatcher.SaramaConfig = getSaramaConfigFromYaml(t, TestConfigBase)
+	dispatcher.SaramaConfig.ClientID = "test-client-id"
+	subscriberSpecs := []eventingduck.SubscriberSpec{{UID: uid123}, {UID: uid456}}
+	assert.Empty(t, dispatcher.UpdateSubscriptions(subscriberSpecs, nil))
+	originalGroup123 := consumerGroups["kafka."+id123]
+	originalGroup456 := consumerGroups["kafka."+id456]
+
+	// A Consumer Change Restarts Both ConsumerGroups With The New Configuration, One Of Which Fails
+	failingGroupId = "kafka." + id456
+	configMap := getBaseConfigMap()
+	configMap.Data[commonconfig.SaramaSettingsConfigKey] = TestConfigConsumerChange
+	assert.Equal(t, dispatcher, dispatcher.ConfigChanged(configMap))
+	assert.True(t, originalGroup123.Closed)
+	assert.True(t, originalGroup456.Closed)
+	lock.Lock()
+	assert.NotEqual(t, originalGroup123, consumerGroups["kafka."+id123])
+	assert.Equal(t, 604800000000001*time.Nanosecond, retentions["kafka."+id123])
+	lock.Unlock()
+	assert.Contains(t, dispatcher.subscribers, uid123)
+	assert.NotContains(t, dispatcher.subscribers, uid456)
+	dispatcher.consumerUpdateLock.Lock()
+	assert.Contains(t, dispatcher.restarts, uid456)
+	dispatcher.consumerUpdateLock.Unlock()
+
+	// The Failure Is Reported In The Subscriber Status Until The Restart Succeeds
+	assert.Len(t, dispatcher.UpdateSubscriptions(subscriberSpecs, nil), 1)
+
+	// The ConsumerGroup Is Restarted After A Backoff Once It Can Be Created
+	lock.Lock()
+	failingGroupId = ""
+	lock.Unlock()
+	assert.Eventually(t, func() bool {
+		dispatcher.consumerUpdateLock.Lock()
+		defer dispatcher.consumerUpdateLock.Unlock()
+		_, ok := dispatcher.subscribers[uid456]
+		return ok && len(dispatcher.restarts) == 0
+	}, 5*time.Second, 100*time.Millisecond)
+	assert.Empty(t, dispatcher.UpdateSubscriptions(subscriberSpecs, nil))
+
+	dispatcher.Shutdown()
+}
+
+// Test That ConfigChanged() Keeps The Current Configuration When The New One Is Invalid
+func TestConfigChangedInvalidConfig(t *testing.T) {
+	logger := logtesting.TestLogger(t).Desugar()
+	assert.Nil(t, os.Setenv(system.NamespaceEnvKey, constants.KnativeEventingNamespace))
+
+	consumerGroup := kafkatesting.NewMockConsumerGroup(t)
+	dispatcher := &DispatcherImpl{
+		DispatcherConfig: DispatcherConfig{Logger: logger, SaramaConfig: getSaramaConfigFromYaml(t, TestConfigBase)},
+		subscribers: map[types.UID]*SubscriberWrapper{
+			uid123: NewSubscriberWrapper(eventingduck.SubscriberSpec{UID: uid123}, kafkav1beta1.DefaultSubscriberDelivery(), "kafka."+id123, consumerGroup),
+		},
+	}
+	dispatcher.SaramaConfig.ClientID = "test-client-id"
+	originalConfig := dispatcher.SaramaConfig
+
+	configMap := getBaseConfigMap()
+	configMap.Data[commonconfig.SaramaSettingsConfigKey] = TestConfigNet + TestConfigMeta + `
+Consumer:
+  Fetch:
+    Min: -1
+`
+	assert.Nil(t, dispatcher.ConfigChanged(configMap))
+	assert.Equal(t, originalConfig, dispatcher.SaramaConfig)
+	assert.False(t, consumerGroup.Closed)
+	assert.Contains(t, dispatcher.subscribers, uid123)
+}
+
+// Test The Backoff Between The Attempts To Restart A ConsumerGroup
+func TestRestartBackoff(t *testing.T) {
+	assert.Equal(t, restartInitialBackoff, restartBackoff(0))
+	assert.Equal(t, 2*restartInitialBackoff, restartBackoff(1))
+	assert.Equal(t, 8*restartInitialBackoff, restartBackoff(3))
+	assert.Equal(t, restartMaxBackoff, restartBackoff(100))
+}
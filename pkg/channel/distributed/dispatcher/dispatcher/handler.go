@@ -21,11 +21,18 @@ import (
 	"errors"
 	"net/http"
 	"net/url"
+	"sync/atomic"
+	"time"
 
 	"github.com/Shopify/sarama"
 	kafkasaramaprotocol "github.com/cloudevents/sdk-go/protocol/kafka_sarama/v2"
 	"github.com/cloudevents/sdk-go/v2/binding"
 	"go.uber.org/zap"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	commonconsumer "knative.dev/eventing-kafka/pkg/common/consumer"
+	"knative.dev/eventing-kafka/pkg/common/deadletter"
+	dispatchmetrics "knative.dev/eventing-kafka/pkg/common/metrics"
+	"knative.dev/eventing-kafka/pkg/common/retrytopic"
 	"knative.dev/eventing-kafka/pkg/common/tracing"
 	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
 	"knative.dev/eventing/pkg/channel"
@@ -38,88 +45,131 @@ var _ sarama.ConsumerGroupHandler = &Handler{}
 // Define A Sarama ConsumerGroupHandler Implementation
 type Handler struct {
 	Logger            *zap.Logger
-	Subscriber        *eventingduck.SubscriberSpec
+	Delivery          kafkav1beta1.SubscriberDelivery
 	MessageDispatcher channel.MessageDispatcher
+	Metrics           *dispatchmetrics.SubscriptionReporter // Optional
+	subscription      atomic.Value                          // The Current *HandlerSubscription (Replaced As A Whole By UpdateSubscription)
 }
 
+// The Part Of A Handler Which Can Be Updated Without Restarting Its ConsumerGroup
+type HandlerSubscription struct {
+	Subscriber     *eventingduck.SubscriberSpec
+	Producer       sarama.SyncProducer // Only Used For Retry Topics & Dead Letter Sinks Which Are Kafka Topics
+	RetryTopics    retrytopic.Topics
+	destinationURL *url.URL
+	replyURL       *url.URL
+	deadLetterURL  *url.URL
+	retryConfig    kncloudevents.RetryConfig
+}
+
+// Error Returned For Messages Which Can Never Be Dispatched
+var errUnknownEncoding = errors.New("received a message with unknown encoding - skipping")
+
 // Create A New Handler
-func NewHandler(logger *zap.Logger, subscriber *eventingduck.SubscriberSpec) *Handler {
-	return &Handler{
+func NewHandler(logger *zap.Logger, subscriber *eventingduck.SubscriberSpec, delivery kafkav1beta1.SubscriberDelivery, producer sarama.SyncProducer, retryTopics retrytopic.Topics, reporter *dispatchmetrics.SubscriptionReporter) *Handler {
+	handler := &Handler{
 		Logger:            logger,
-		Subscriber:        subscriber,
+		Delivery:          delivery,
 		MessageDispatcher: newMessageDispatcherWrapper(logger),
+		Metrics:           reporter,
 	}
+	handler.UpdateSubscription(subscriber, producer, retryTopics)
+	return handler
 }
 
-// Wrapper Function To Facilitate Testing With A Mock Knative MessageDispatcher
-var newMessageDispatcherWrapper = func(logger *zap.Logger) channel.MessageDispatcher {
-	return channel.NewMessageDispatcher(logger)
+// Get The Handler's Current Subscription
+func (h *Handler) Subscription() *HandlerSubscription {
+	return h.subscription.Load().(*HandlerSubscription)
 }
 
-// ConsumerGroupHandler Lifecycle Method (Runs before any ConsumeClaims)
-func (h *Handler) Setup(_ sarama.ConsumerGroupSession) error {
-	return nil // Nothing To Do As Of Yet
-}
+// Atomically Replace The Handler's Subscription (Messages Being Dispatched Complete With The Previous One)
+func (h *Handler) UpdateSubscription(subscriber *eventingduck.SubscriberSpec, producer sarama.SyncProducer, retryTopics retrytopic.Topics) {
 
-// ConsumerGroupHandler Lifecycle Method (Runs after all ConsumeClaims stop but before final offset commit)
-func (h *Handler) Cleanup(_ sarama.ConsumerGroupSession) error {
-	return nil // Nothing To Do As Of Yet
-}
-
-// ConsumerGroupHandler Lifecycle Method (Main processing loop, must finish when claim.Messages() channel closes.)
-func (h *Handler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	subscription := &HandlerSubscription{
+		Subscriber:  subscriber,
+		Producer:    producer,
+		RetryTopics: retryTopics,
+		retryConfig: kncloudevents.NoRetries(),
+	}
 
 	// Extract The Destination URL From The Subscriber
-	var destinationURL *url.URL
-	if !h.Subscriber.SubscriberURI.IsEmpty() {
-		destinationURL = h.Subscriber.SubscriberURI.URL()
+	if !subscriber.SubscriberURI.IsEmpty() {
+		subscription.destinationURL = subscriber.SubscriberURI.URL()
 	}
 
 	// Extract The Reply URL From The Subscriber
-	var replyURL *url.URL
-	if !h.Subscriber.ReplyURI.IsEmpty() {
-		replyURL = h.Subscriber.ReplyURI.URL()
+	if !subscriber.ReplyURI.IsEmpty() {
+		subscription.replyURL = subscriber.ReplyURI.URL()
 	}
 
 	// Validate The Subscriber's Delivery (Optional)
-	var deadLetterURL *url.URL
-	retryConfig := kncloudevents.NoRetries()
-	if h.Subscriber.Delivery != nil {
+	if subscriber.Delivery != nil {
 
 		// Extract The DeadLetterSink From The Subscriber.Delivery
-		if h.Subscriber.Delivery.DeadLetterSink != nil &&
-			h.Subscriber.Delivery.DeadLetterSink.URI != nil &&
-			!h.Subscriber.Delivery.DeadLetterSink.URI.IsEmpty() {
-			deadLetterURL = h.Subscriber.Delivery.DeadLetterSink.URI.URL()
+		if subscriber.Delivery.DeadLetterSink != nil &&
+			subscriber.Delivery.DeadLetterSink.URI != nil &&
+			!subscriber.Delivery.DeadLetterSink.URI.IsEmpty() {
+			subscription.deadLetterURL = subscriber.Delivery.DeadLetterSink.URI.URL()
 		}
 
 		// Extract The RetryConfig From The Subscriber.Delivery (Defaults To NoRetries)
-		var err error
-		retryConfig, err = kncloudevents.RetryConfigFromDeliverySpec(*h.Subscriber.Delivery)
+		retryConfig, err := kncloudevents.RetryConfigFromDeliverySpec(*subscriber.Delivery)
 		if err != nil {
 			h.Logger.Error("Failed To Parse RetryConfig From DeliverySpec - No Retries Will Occur", zap.Error(err))
 		} else {
 			h.Logger.Info("Successfully Parsed RetryConfig From DeliverySpec", zap.Int("RetryMax", retryConfig.RetryMax))
 			retryConfig.CheckRetry = h.checkRetry // Specify Custom CheckRetry Function
+			subscription.retryConfig = retryConfig
 		}
 	}
 
-	// Pull Any Available Messages From The ConsumerGroupClaim (Until The Channel Closes)
-	for message := range claim.Messages() {
+	h.subscription.Store(subscription)
+}
 
-		// Consume The Message (Ignore Errors - Will have already been retried and we're moving on so as not to block further Topic processing.)
-		_ = h.consumeMessage(session.Context(), message, destinationURL, replyURL, deadLetterURL, &retryConfig)
+// Wrapper Function To Facilitate Testing With A Mock Knative MessageDispatcher
+var newMessageDispatcherWrapper = func(logger *zap.Logger) channel.MessageDispatcher {
+	return channel.NewMessageDispatcher(logger)
+}
 
-		// Mark The Message As Having Been Consumed (Does Not Imply Successful Delivery - Only Full Retry Attempts Made)
-		session.MarkMessage(message, "")
+// ConsumerGroupHandler Lifecycle Method (Runs before any ConsumeClaims)
+func (h *Handler) Setup(_ sarama.ConsumerGroupSession) error {
+	return nil // Nothing To Do As Of Yet
+}
+
+// ConsumerGroupHandler Lifecycle Method (Runs after all ConsumeClaims stop but before final offset commit)
+func (h *Handler) Cleanup(_ sarama.ConsumerGroupSession) error {
+	return nil // Nothing To Do As Of Yet
+}
+
+// ConsumerGroupHandler Lifecycle Method (Main processing loop, must finish when claim.Messages() channel closes.)
+func (h *Handler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+
+	// Consume The Message With The Current Subscription (Delivered When Successful Or When It Can Never Be Dispatched)
+	handle := func(ctx context.Context, message *sarama.ConsumerMessage) (bool, error) {
+		err := h.consumeMessage(ctx, message, h.Subscription())
+		return err == nil || err == errUnknownEncoding, err
+	}
+
+	// Report The Lag Of The Partition As Its Messages Are Consumed
+	if h.Metrics != nil {
+		handle = commonconsumer.WithLag(claim, h.reportLag, handle)
+	}
+
+	// Errors Will Have Already Been Retried - Whether The Partition Moves On Depends On The Delivery Ordering
+	onError := func(message *sarama.ConsumerMessage, err error) {
+		h.Logger.Warn("Failed To Dispatch Message", zap.Int32("Partition", message.Partition), zap.Int64("Offset", message.Offset), zap.Error(err))
 	}
 
+	// Pull Any Available Messages From The ConsumerGroupClaim (Until The Channel Closes) & Mark Them Per The Delivery Ordering
+	// Don't Use The Session Context Since It Is Closed Before The In-Flight Messages Are Drained
+	commonconsumer.ConsumeWithDelivery(context.Background(), session, claim, h.Delivery, handle, onError)
+
 	// Return Success
 	return nil
 }
 
 // Consume A Single Message
-func (h *Handler) consumeMessage(context context.Context, consumerMessage *sarama.ConsumerMessage, destinationURL *url.URL, replyURL *url.URL, deadLetterURL *url.URL, retryConfig *kncloudevents.RetryConfig) error {
+func (h *Handler) consumeMessage(context context.Context, consumerMessage *sarama.ConsumerMessage, subscription *HandlerSubscription) error {
 
 	// Debug Log Kafka ConsumerMessage
 	if h.Logger.Core().Enabled(zap.DebugLevel) {
@@ -137,17 +187,43 @@ func (h *Handler) consumeMessage(context context.Context, consumerMessage *saram
 	message := kafkasaramaprotocol.NewMessageFromConsumerMessage(consumerMessage)
 	if message.ReadEncoding() == binding.EncodingUnknown {
 		h.Logger.Warn("Received A Message With Unknown Encoding - Skipping")
-		return errors.New("received a message with unknown encoding - skipping")
+		return errUnknownEncoding
 	}
 
 	ctx, span := tracing.StartTraceFromMessage(h.Logger.Sugar(), context, message, consumerMessage.Topic)
 	defer span.End()
 
-	// Dispatch The Message With Configured Retries & Return Any Errors
-	_, dispatchError := h.MessageDispatcher.DispatchMessageWithRetries(ctx, message, nil, destinationURL, replyURL, deadLetterURL, retryConfig)
+	// Dispatch The Message With Configured Retries (Via Retry Topics / Dead Lettering To Kafka If So Configured) & Return Any Errors
+	start := time.Now()
+	result, dispatchError := retrytopic.DispatchMessage(ctx, subscription.RetryTopics, h.MessageDispatcher, subscription.Producer, message, consumerMessage, subscription.destinationURL, subscription.replyURL, subscription.deadLetterURL, &subscription.retryConfig)
+	h.reportDispatch(result, time.Since(start))
 	return dispatchError
 }
 
+// Report The Metrics Of A Dispatched Message
+func (h *Handler) reportDispatch(result *deadletter.Result, latency time.Duration) {
+	if h.Metrics == nil || result == nil {
+		return
+	}
+	err := h.Metrics.ReportDispatch(dispatchmetrics.DispatchResult{
+		ResponseCode: result.ResponseCode(),
+		Latency:      latency,
+		Retries:      result.Retries,
+		DeadLettered: result.DeadLettered,
+		Error:        result.Error,
+	})
+	if err != nil {
+		h.Logger.Warn("Failed To Report Dispatch Metrics", zap.Error(err))
+	}
+}
+
+// Report The Lag Of A Partition
+func (h *Handler) reportLag(topic string, partition int32, lag int64) {
+	if err := h.Metrics.ReportLag(topic, partition, lag); err != nil {
+		h.Logger.Warn("Failed To Report ConsumerGroup Lag", zap.Error(err))
+	}
+}
+
 //
 // Custom Implementation Of RetryConfig.CheckRetry To Determine Whether To Retry Based On Response
 //
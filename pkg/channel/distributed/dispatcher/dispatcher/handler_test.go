@@ -30,7 +30,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 	"k8s.io/apimachinery/pkg/types"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
 	dispatchertesting "knative.dev/eventing-kafka/pkg/channel/distributed/dispatcher/testing"
+	"knative.dev/eventing-kafka/pkg/common/retrytopic"
 	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
 	"knative.dev/eventing/pkg/channel"
 	"knative.dev/eventing/pkg/kncloudevents"
@@ -73,6 +75,26 @@ func TestNewHandler(t *testing.T) {
 	assert.NotNil(t, createTestHandler)
 }
 
+// Test The Handler's UpdateSubscription() Functionality
+func TestHandlerUpdateSubscription(t *testing.T) {
+	handler := createTestHandler(t, testSubscriberURI, testReplyURI, nil)
+	previous := handler.Subscription()
+	assert.Equal(t, testSubscriberURI.URL(), previous.destinationURL)
+	assert.Equal(t, 0, previous.retryConfig.RetryMax)
+
+	// The New Subscription Replaces The Previous One As A Whole
+	deliverySpec := createDeliverySpec(testDeadLetterURI, true)
+	handler.UpdateSubscription(&eventingduck.SubscriberSpec{UID: testSubscriberUID, SubscriberURI: testReplyURI, Delivery: &deliverySpec}, nil, retrytopic.Topics{})
+	current := handler.Subscription()
+	assert.NotSame(t, previous, current)
+	assert.Equal(t, testReplyURI.URL(), current.destinationURL)
+	assert.Nil(t, current.replyURL)
+	assert.Equal(t, testDeadLetterURI.URL(), current.deadLetterURL)
+	assert.Equal(t, int(testRetryCount), current.retryConfig.RetryMax)
+	assert.NotNil(t, current.retryConfig.CheckRetry)
+	assert.Equal(t, testSubscriberURI.URL(), previous.destinationURL)
+}
+
 // Test The Handler's Setup() Functionality
 func TestHandlerSetup(t *testing.T) {
 	handler := createTestHandler(t, testSubscriberURI, testReplyURI, nil)
@@ -172,12 +194,6 @@ func performHandlerConsumeClaimTest(t *testing.T, destinationUri, replyUri, dead
 		replyUrl = replyUri.URL()
 	}
 
-	// Initialize DeadLetter As Specified
-	var deadLetterUrl *url.URL
-	if deadLetterUri != nil {
-		deadLetterUrl = deadLetterUri.URL()
-	}
-
 	// Create The Specified DeliverySpec
 	deliverySpec := createDeliverySpec(deadLetterUri, retry)
 
@@ -193,7 +209,8 @@ func performHandlerConsumeClaimTest(t *testing.T, destinationUri, replyUri, dead
 	// Create Mocks For Testing
 	mockConsumerGroupSession := dispatchertesting.NewMockConsumerGroupSession(t)
 	mockConsumerGroupClaim := dispatchertesting.NewMockConsumerGroupClaim(t)
-	mockMessageDispatcher := dispatchertesting.NewMockMessageDispatcher(t, nil, destinationUrl, replyUrl, deadLetterUrl, &retryConfig, nil)
+	// (The DeadLetter Is Only Dispatched To Separately, Once The Subscriber Has Failed - See deadletter.DispatchMessage)
+	mockMessageDispatcher := dispatchertesting.NewMockMessageDispatcher(t, nil, destinationUrl, replyUrl, nil, &retryConfig, nil)
 
 	// Mock The newMessageDispatcherWrapper Function (And Restore Post-Test)
 	newMessageDispatcherWrapperPlaceholder := newMessageDispatcherWrapper
@@ -227,6 +244,67 @@ func performHandlerConsumeClaimTest(t *testing.T, destinationUri, replyUri, dead
 	verifyDispatchedMessage(t, mockMessageDispatcher.Message())
 }
 
+// Test The Handler's ConsumeClaim() Keeps Dispatching After The Session Context Is Closed
+func TestHandlerConsumeClaimSessionContextClosed(t *testing.T) {
+
+	// Create Mocks For Testing (The Session's Context Is Closed As When A Rebalance Begins)
+	mockConsumerGroupSession := &closedContextConsumerGroupSession{MockConsumerGroupSession: dispatchertesting.NewMockConsumerGroupSession(t)}
+	mockConsumerGroupClaim := dispatchertesting.NewMockConsumerGroupClaim(t)
+	retryConfig := kncloudevents.RetryConfig{}
+	mockMessageDispatcher := &contextRecordingMessageDispatcher{
+		MockMessageDispatcher: dispatchertesting.NewMockMessageDispatcher(t, nil, testSubscriberURI.URL(), testReplyURI.URL(), nil, &retryConfig, nil),
+	}
+
+	// Mock The newMessageDispatcherWrapper Function (And Restore Post-Test)
+	newMessageDispatcherWrapperPlaceholder := newMessageDispatcherWrapper
+	newMessageDispatcherWrapper = func(logger *zap.Logger) channel.MessageDispatcher {
+		return mockMessageDispatcher
+	}
+	defer func() { newMessageDispatcherWrapper = newMessageDispatcherWrapperPlaceholder }()
+
+	// Create The Handler To Test
+	deliverySpec := createDeliverySpec(nil, false)
+	handler := createTestHandler(t, testSubscriberURI, testReplyURI, &deliverySpec)
+
+	// Background Start Consuming Claims
+	go func() {
+		err := handler.ConsumeClaim(mockConsumerGroupSession, mockConsumerGroupClaim)
+		assert.Nil(t, err)
+	}()
+
+	// Perform The Test
+	consumerMessage := createConsumerMessage(t)
+	mockConsumerGroupClaim.MessageChan <- consumerMessage
+	markedMessage := <-mockConsumerGroupSession.MarkMessageChan
+	close(mockConsumerGroupClaim.MessageChan)
+
+	// Verify The Message Was Dispatched With An Open Context & Marked
+	assert.Equal(t, consumerMessage, markedMessage)
+	assert.Nil(t, mockMessageDispatcher.contextErr)
+}
+
+// Mock ConsumerGroupSession Whose Context Is Already Closed
+type closedContextConsumerGroupSession struct {
+	dispatchertesting.MockConsumerGroupSession
+}
+
+func (s *closedContextConsumerGroupSession) Context() context.Context {
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	return ctx
+}
+
+// Mock MessageDispatcher Recording The State Of The Context It Dispatched With
+type contextRecordingMessageDispatcher struct {
+	*dispatchertesting.MockMessageDispatcher
+	contextErr error
+}
+
+func (d *contextRecordingMessageDispatcher) DispatchMessageWithRetries(ctx context.Context, message binding.Message, headers http.Header, destinationUrl *url.URL, replyUrl *url.URL, deadLetterUrl *url.URL, retryConfig *kncloudevents.RetryConfig) (*channel.DispatchExecutionInfo, error) {
+	d.contextErr = ctx.Err()
+	return d.MockMessageDispatcher.DispatchMessageWithRetries(ctx, message, headers, destinationUrl, replyUrl, deadLetterUrl, retryConfig)
+}
+
 // Test The Custom CheckRetry() Implementation
 func TestCheckRetry(t *testing.T) {
 
@@ -413,12 +491,12 @@ func createTestHandler(t *testing.T, subscriberURL *apis.URL, replyUrl *apis.URL
 	}
 
 	// Perform The Test Create The Test Handler
-	handler := NewHandler(logger, testSubscriber)
+	handler := NewHandler(logger, testSubscriber, kafkav1beta1.DefaultSubscriberDelivery(), nil, retrytopic.Topics{}, nil)
 
 	// Verify The Results
 	assert.NotNil(t, handler)
 	assert.Equal(t, logger, handler.Logger)
-	assert.Equal(t, testSubscriber, handler.Subscriber)
+	assert.Equal(t, testSubscriber, handler.Subscription().Subscriber)
 	assert.NotNil(t, handler.MessageDispatcher)
 
 	// Return The Handler
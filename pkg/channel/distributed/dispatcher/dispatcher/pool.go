@@ -0,0 +1,151 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"go.uber.org/zap"
+	v1 "k8s.io/api/core/v1"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	commonk8s "knative.dev/eventing-kafka/pkg/channel/distributed/common/k8s"
+	kafkaconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+	kafkautil "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/util"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/util"
+)
+
+// A Member Of A Pool Of Shared Dispatchers, With A Dispatcher For Each KafkaChannel Assigned To It
+type Pool struct {
+	config      DispatcherConfig // The Config Of New Dispatchers (Without A ChannelKey Or Topic)
+	member      int
+	size        int
+	secret      string                // The Kafka Secret Of The KafkaChannels (All KafkaChannels If Empty)
+	dispatchers map[string]Dispatcher // Keyed By ChannelKey
+	lock        sync.Mutex
+}
+
+// Pool Constructor
+func NewPool(config DispatcherConfig, member int, size int, secret string) *Pool {
+	return &Pool{
+		config:      config,
+		member:      member,
+		size:        size,
+		secret:      secret,
+		dispatchers: make(map[string]Dispatcher),
+	}
+}
+
+// Determine Whether The Specified KafkaChannel Is Assigned To This Pool Member (Dedicated Dispatchers Are Not Shared,
+// And Each Kafka Secret Has Its Own Pool)
+func (p *Pool) Serves(channel *kafkav1beta1.KafkaChannel) bool {
+	if dedicated, err := channel.DedicatedDispatcher(); err != nil || dedicated {
+		return false
+	}
+	if len(p.secret) > 0 && channel.Labels[kafkaconstants.KafkaChannelSecretLabel] != commonk8s.TruncateLabelValue(p.secret) {
+		return false
+	}
+	return util.DispatcherPoolMember(poolChannelKey(channel), p.size) == p.member
+}
+
+// Get The Dispatcher Of The Specified KafkaChannel, Creating It If Necessary (Nil If The Channel Is Not Served)
+func (p *Pool) Dispatcher(channel *kafkav1beta1.KafkaChannel) Dispatcher {
+	channelKey := poolChannelKey(channel)
+	if !p.Serves(channel) {
+		p.Remove(channelKey)
+		return nil
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if dispatcher, ok := p.dispatchers[channelKey]; ok {
+		return dispatcher
+	}
+
+	// Create A Dispatcher For The KafkaChannel's Topic (With Its Own Copy Of The Sarama Config)
+	config := p.config
+	config.Logger = p.config.Logger.With(zap.String("ChannelKey", channelKey))
+	config.ChannelKey = channelKey
+	config.Topic = kafkautil.TopicName(channel.Namespace, channel.Name)
+	if p.config.SaramaConfig != nil {
+		saramaConfig := *p.config.SaramaConfig
+		config.SaramaConfig = &saramaConfig
+	}
+	dispatcher := NewDispatcher(config)
+	p.dispatchers[channelKey] = dispatcher
+	p.config.Logger.Info("Dispatching KafkaChannel", zap.String("ChannelKey", channelKey))
+	return dispatcher
+}
+
+// Shutdown The Dispatcher Of A KafkaChannel Which Is No Longer Served (Or No Longer Exists)
+func (p *Pool) Remove(channelKey string) {
+	p.lock.Lock()
+	dispatcher, ok := p.dispatchers[channelKey]
+	delete(p.dispatchers, channelKey)
+	p.lock.Unlock()
+	if ok {
+		p.config.Logger.Info("No Longer Dispatching KafkaChannel", zap.String("ChannelKey", channelKey))
+		dispatcher.Shutdown()
+	}
+}
+
+// Get The Keys Of The KafkaChannels Being Dispatched (Sorted)
+func (p *Pool) ChannelKeys() []string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	channelKeys := make([]string, 0, len(p.dispatchers))
+	for channelKey := range p.dispatchers {
+		channelKeys = append(channelKeys, channelKey)
+	}
+	sort.Strings(channelKeys)
+	return channelKeys
+}
+
+// Apply A Change Of The ConfigMap To The Dispatchers Of All The KafkaChannels, And To Those Created Subsequently
+func (p *Pool) ConfigChanged(configMap *v1.ConfigMap) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	// The Config Of New Dispatchers Is Updated Like That Of A Dispatcher Without Subscribers
+	template := NewDispatcher(p.config)
+	if template.ConfigChanged(configMap) != nil {
+		p.config = template.(*DispatcherImpl).DispatcherConfig
+	}
+
+	// Each Dispatcher Restarts Its Own ConsumerGroups In Place
+	for channelKey, dispatcher := range p.dispatchers {
+		if newDispatcher := dispatcher.ConfigChanged(configMap); newDispatcher != nil {
+			p.dispatchers[channelKey] = newDispatcher
+		}
+	}
+}
+
+// Shutdown The Dispatchers Of All The KafkaChannels
+func (p *Pool) Shutdown() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for channelKey, dispatcher := range p.dispatchers {
+		dispatcher.Shutdown()
+		delete(p.dispatchers, channelKey)
+	}
+}
+
+// Get The Key Of A KafkaChannel (As Used By The Controller & Dispatch Metrics)
+func poolChannelKey(channel *kafkav1beta1.KafkaChannel) string {
+	return fmt.Sprintf("%s/%s", channel.Namespace, channel.Name)
+}
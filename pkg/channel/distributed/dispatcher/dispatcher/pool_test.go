@@ -0,0 +1,132 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dispatcher
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	commonconfig "knative.dev/eventing-kafka/pkg/channel/distributed/common/config"
+	kafkaconstants "knative.dev/eventing-kafka/pkg/channel/distributed/common/kafka/constants"
+	"knative.dev/eventing-kafka/pkg/channel/distributed/common/util"
+	"knative.dev/eventing-kafka/pkg/common/constants"
+	logtesting "knative.dev/pkg/logging/testing"
+	"knative.dev/pkg/system"
+)
+
+// Test Data
+const poolSize = 2
+
+// Create A KafkaChannel With The Specified Namespace & Name
+func newPoolChannel(namespace string, name string) *kafkav1beta1.KafkaChannel {
+	return &kafkav1beta1.KafkaChannel{ObjectMeta: v1.ObjectMeta{Namespace: namespace, Name: name}}
+}
+
+// Find A KafkaChannel Assigned To The Specified Pool Member
+func poolMemberChannel(t *testing.T, member int) *kafkav1beta1.KafkaChannel {
+	for i := 0; i < 100; i++ {
+		channel := newPoolChannel("pool-namespace", fmt.Sprintf("channel-%d", i))
+		if util.DispatcherPoolMember(channel.Namespace+"/"+channel.Name, poolSize) == member {
+			return channel
+		}
+	}
+	t.Fatalf("No KafkaChannel Assigned To Pool Member %d", member)
+	return nil
+}
+
+// Test The Assignment Of KafkaChannels To The Pool Members
+func TestPoolServes(t *testing.T) {
+	pool := NewPool(DispatcherConfig{Logger: logtesting.TestLogger(t).Desugar()}, 0, poolSize, "")
+
+	// Only The KafkaChannels Assigned To The Pool Member Are Served
+	assert.True(t, pool.Serves(poolMemberChannel(t, 0)))
+	assert.False(t, pool.Serves(poolMemberChannel(t, 1)))
+
+	// KafkaChannels With A Dedicated Dispatcher Are Not Served By The Pool
+	dedicated := poolMemberChannel(t, 0)
+	dedicated.Annotations = map[string]string{kafkav1beta1.DedicatedDispatcherAnnotation: "true"}
+	assert.False(t, pool.Serves(dedicated))
+	dedicated.Annotations[kafkav1beta1.DedicatedDispatcherAnnotation] = "false"
+	assert.True(t, pool.Serves(dedicated))
+	dedicated.Annotations[kafkav1beta1.DedicatedDispatcherAnnotation] = "invalid"
+	assert.False(t, pool.Serves(dedicated))
+
+	// The Pool Of A Kafka Secret Only Serves The KafkaChannels Of That Kafka Secret
+	secretPool := NewPool(DispatcherConfig{Logger: logtesting.TestLogger(t).Desugar()}, 0, poolSize, "kafka-secret")
+	channel := poolMemberChannel(t, 0)
+	assert.False(t, secretPool.Serves(channel))
+	channel.Labels = map[string]string{kafkaconstants.KafkaChannelSecretLabel: "other-kafka-secret"}
+	assert.False(t, secretPool.Serves(channel))
+	channel.Labels[kafkaconstants.KafkaChannelSecretLabel] = "kafka-secret"
+	assert.True(t, secretPool.Serves(channel))
+}
+
+// Test The Creation & Removal Of The Dispatchers Of The KafkaChannels Served By The Pool Member
+func TestPoolDispatcher(t *testing.T) {
+	pool := NewPool(DispatcherConfig{Logger: logtesting.TestLogger(t).Desugar(), ClientId: "test-client-id"}, 0, poolSize, "")
+	channel := poolMemberChannel(t, 0)
+	channelKey := channel.Namespace + "/" + channel.Name
+
+	// The Dispatcher Is Created For The KafkaChannel's Topic & Then Reused
+	dispatcher := pool.Dispatcher(channel)
+	assert.NotNil(t, dispatcher)
+	assert.Equal(t, channelKey, dispatcher.(*DispatcherImpl).ChannelKey)
+	assert.Equal(t, "pool-namespace."+channel.Name, dispatcher.(*DispatcherImpl).Topic)
+	assert.Equal(t, "test-client-id", dispatcher.(*DispatcherImpl).ClientId)
+	assert.Same(t, dispatcher, pool.Dispatcher(channel))
+	assert.Equal(t, []string{channelKey}, pool.ChannelKeys())
+
+	// KafkaChannels Assigned To Another Pool Member Have No Dispatcher
+	assert.Nil(t, pool.Dispatcher(poolMemberChannel(t, 1)))
+
+	// The Dispatcher Is Removed Once The KafkaChannel Gets A Dedicated Dispatcher
+	channel.Annotations = map[string]string{kafkav1beta1.DedicatedDispatcherAnnotation: "true"}
+	assert.Nil(t, pool.Dispatcher(channel))
+	assert.Empty(t, pool.ChannelKeys())
+
+	// Or Once The KafkaChannel Is Removed
+	channel.Annotations = nil
+	assert.NotNil(t, pool.Dispatcher(channel))
+	pool.Remove(channelKey)
+	assert.Empty(t, pool.ChannelKeys())
+
+	// Shutdown Removes All The Dispatchers
+	assert.NotNil(t, pool.Dispatcher(channel))
+	pool.Shutdown()
+	assert.Empty(t, pool.ChannelKeys())
+}
+
+// Test That A ConfigMap Change Applies To Existing & New Dispatchers
+func TestPoolConfigChanged(t *testing.T) {
+	assert.Nil(t, os.Setenv(system.NamespaceEnvKey, constants.KnativeEventingNamespace))
+	saramaConfig := getSaramaConfigFromYaml(t, TestConfigBase)
+	saramaConfig.ClientID = "test-client-id"
+	pool := NewPool(DispatcherConfig{Logger: logtesting.TestLogger(t).Desugar(), SaramaConfig: saramaConfig}, 0, poolSize, "")
+	existing := pool.Dispatcher(poolMemberChannel(t, 0))
+
+	configMap := getBaseConfigMap()
+	configMap.Data[commonconfig.EventingKafkaSettingsConfigKey] = TestEventingKafkaConcurrencyChange
+	pool.ConfigChanged(configMap)
+	assert.Equal(t, 10, existing.(*DispatcherImpl).Concurrency)
+
+	pool.Remove(pool.ChannelKeys()[0])
+	assert.Equal(t, 10, pool.Dispatcher(poolMemberChannel(t, 0)).(*DispatcherImpl).Concurrency)
+}
@@ -41,11 +41,16 @@ type Environment struct {
 
 	// Kafka Configuration
 	KafkaBrokers string        // Required
-	KafkaTopic   string        // Required
-	ChannelKey   string        // Required
+	KafkaTopic   string        // Required (Unless Shared)
+	ChannelKey   string        // Required (Unless Shared)
 	ServiceName  string        // Required
 	ResyncPeriod time.Duration // Optional
 
+	// Shared Dispatcher Configuration (A Pool Member Dispatches The KafkaChannels Assigned To It)
+	PoolMember int    // Optional
+	PoolSize   int    // Optional (Zero For The Dispatcher Of A Single KafkaChannel)
+	PoolSecret string // Optional (The Kafka Secret Of The KafkaChannels Dispatched By The Pool)
+
 	// Kafka Authorization
 	KafkaUsername string // Optional
 	KafkaPassword string // Optional
@@ -96,18 +101,35 @@ func GetEnvironment(logger *zap.Logger) (*Environment, error) {
 		return nil, err
 	}
 
-	// Get The Required K8S KafkaTopic Config Value
-	environment.KafkaTopic, err = env.GetRequiredConfigValue(logger, env.KafkaTopicEnvVarKey)
+	// Get The Optional Pool Size & Member Config Values Of A Shared Dispatcher & Convert To Int
+	environment.PoolSize, err = env.GetOptionalConfigInt(logger, env.DispatcherPoolSizeEnvVarKey, "0", "PoolSize")
 	if err != nil {
 		return nil, err
 	}
-
-	// Get The Required K8S ChannelKey Config Value
-	environment.ChannelKey, err = env.GetRequiredConfigValue(logger, env.ChannelKeyEnvVarKey)
+	environment.PoolMember, err = env.GetOptionalConfigInt(logger, env.DispatcherPoolMemberEnvVarKey, "0", "PoolMember")
 	if err != nil {
 		return nil, err
 	}
 
+	// Get The Optional Kafka Secret Config Value Of A Shared Dispatcher
+	environment.PoolSecret = env.GetOptionalConfigValue(logger, env.DispatcherPoolSecretEnvVarKey, "")
+
+	// The KafkaTopic & ChannelKey Are Only Required By The Dispatcher Of A Single KafkaChannel
+	if environment.PoolSize <= 0 {
+
+		// Get The Required K8S KafkaTopic Config Value
+		environment.KafkaTopic, err = env.GetRequiredConfigValue(logger, env.KafkaTopicEnvVarKey)
+		if err != nil {
+			return nil, err
+		}
+
+		// Get The Required K8S ChannelKey Config Value
+		environment.ChannelKey, err = env.GetRequiredConfigValue(logger, env.ChannelKeyEnvVarKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Get The Required K8S ServiceName Config Value
 	environment.ServiceName, err = env.GetRequiredConfigValue(logger, env.ServiceNameEnvVarKey)
 	if err != nil {
@@ -60,6 +60,9 @@ type TestCase struct {
 	kafkaPassword        string
 	podName              string
 	containerName        string
+	poolSize             string
+	poolMember           string
+	poolSecret           string
 	expectedError        error
 	expectedResyncPeriod string
 }
@@ -115,6 +118,19 @@ func TestGetEnvironment(t *testing.T) {
 	testCase.expectedError = getMissingRequiredEnvironmentVariableError(commonenv.ChannelKeyEnvVarKey)
 	testCases = append(testCases, testCase)
 
+	testCase = getValidTestCase("Valid Shared Config - Missing KafkaTopic & ChannelKey")
+	testCase.kafkaTopic = ""
+	testCase.channelKey = ""
+	testCase.poolSize = "3"
+	testCase.poolMember = "1"
+	testCase.poolSecret = "kafka-secret"
+	testCases = append(testCases, testCase)
+
+	testCase = getValidTestCase("Invalid Config - PoolSize")
+	testCase.poolSize = "NAN"
+	testCase.expectedError = getInvalidIntEnvironmentVariableError(testCase.poolSize, commonenv.DispatcherPoolSizeEnvVarKey)
+	testCases = append(testCases, testCase)
+
 	testCase = getValidTestCase("Missing Required Config - ServiceName")
 	testCase.serviceName = ""
 	testCase.expectedError = getMissingRequiredEnvironmentVariableError(commonenv.ServiceNameEnvVarKey)
@@ -158,6 +174,9 @@ func TestGetEnvironment(t *testing.T) {
 			assertSetenv(t, commonenv.KafkaPasswordEnvVarKey, testCase.kafkaPassword)
 			assertSetenv(t, commonenv.PodNameEnvVarKey, testCase.podName)
 			assertSetenv(t, commonenv.ContainerNameEnvVarKey, testCase.containerName)
+			assertSetenvNonempty(t, commonenv.DispatcherPoolSizeEnvVarKey, testCase.poolSize)
+			assertSetenvNonempty(t, commonenv.DispatcherPoolMemberEnvVarKey, testCase.poolMember)
+			assertSetenv(t, commonenv.DispatcherPoolSecretEnvVarKey, testCase.poolSecret)
 
 			// Perform The Test
 			environment, err := GetEnvironment(logger)
@@ -177,6 +196,11 @@ func TestGetEnvironment(t *testing.T) {
 				assert.Equal(t, testCase.kafkaPassword, environment.KafkaPassword)
 				assert.Equal(t, testCase.podName, environment.PodName)
 				assert.Equal(t, testCase.containerName, environment.ContainerName)
+				if testCase.poolSize != "" {
+					assert.Equal(t, testCase.poolSize, strconv.Itoa(environment.PoolSize))
+					assert.Equal(t, testCase.poolMember, strconv.Itoa(environment.PoolMember))
+					assert.Equal(t, testCase.poolSecret, environment.PoolSecret)
+				}
 				assert.Equal(t, testCase.expectedResyncPeriod, strconv.Itoa(int(environment.ResyncPeriod/time.Minute)))
 
 			} else {
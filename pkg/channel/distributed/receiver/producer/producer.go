@@ -19,6 +19,7 @@ package producer
 import (
 	"context"
 	"errors"
+	"sync"
 	"time"
 
 	"knative.dev/eventing-kafka/pkg/common/tracing"
@@ -51,8 +52,13 @@ type Producer struct {
 	metricsStoppedChan chan struct{}
 	configuration      *sarama.Config
 	brokers            []string
+	closeLock          sync.RWMutex // Held For Reading By The Messages Being Produced & For Writing When Closing
+	closed             bool
 }
 
+// Error Returned For Messages Produced After The Producer Was Closed (E.g. Replaced Due To A Configuration Change)
+var ErrProducerClosed = errors.New("kafka producer closed - unable to produce message")
+
 // Initialize The Producer
 func NewProducer(logger *zap.Logger,
 	config *sarama.Config,
@@ -107,6 +113,13 @@ func (p *Producer) ProduceKafkaMessage(ctx context.Context, channelReference eve
 		return errors.New("uninitialized kafka producer - unable to produce message")
 	}
 
+	// Hold Off Closing The Producer Until The Message Has Been Produced (Unless It Is Already Closed)
+	p.closeLock.RLock()
+	defer p.closeLock.RUnlock()
+	if p.closed {
+		return ErrProducerClosed
+	}
+
 	// Get The Topic Name From The ChannelReference
 	topicName := util.TopicName(channelReference)
 	logger := p.logger.With(zap.String("Topic", topicName))
@@ -172,6 +185,28 @@ func (p *Producer) Close() {
 	// Mark The Producer As No Longer Ready
 	p.healthServer.SetProducerReady(false)
 
+	// Close The Producer Once Any Messages Being Produced Are Done
+	p.closeKafkaProducer()
+}
+
+// Drain The Producer After It Has Been Replaced By A New One, Closing It Once The Messages Being Produced Are Done
+// (Unlike Close() The Readiness Is Left As Is, Since The New Producer Is Ready)
+func (p *Producer) Drain() {
+	p.logger.Info("Draining Kafka Producer")
+	p.closeKafkaProducer()
+}
+
+// Close The Kafka Producer & Stop Observing Its Metrics, Waiting For The Messages Being Produced
+func (p *Producer) closeKafkaProducer() {
+
+	// Wait For The Messages Being Produced (Subsequent Messages Fail With ErrProducerClosed)
+	p.closeLock.Lock()
+	defer p.closeLock.Unlock()
+	if p.closed {
+		return
+	}
+	p.closed = true
+
 	// Stop Observing Metrics
 	close(p.metricsStopChan)
 	<-p.metricsStoppedChan
@@ -193,6 +228,9 @@ func (p *Producer) Close() {
 // are needed in the future, the environment will also need to be re-parsed here.
 // If there aren't any producer-specific differences between the current config and the new one,
 // then just log that and move on; do not restart the Producer unnecessarily.
+// Otherwise a new Producer is created and returned while this one keeps producing, so that the caller can
+// switch to the new Producer before draining this one.  If the new Producer can not be created this one is
+// kept, so that the receiver stays ready throughout.
 func (p *Producer) ConfigChanged(configMap *v1.ConfigMap) *Producer {
 
 	// Create A New Sarama Config (Carrying Forward The Kafka Secret Auth)
@@ -227,15 +265,14 @@ func (p *Producer) ConfigChanged(configMap *v1.ConfigMap) *Producer {
 	}
 
 	// Create A New Producer With The New Configuration (Reusing All Other Existing Config)
-	p.logger.Info("Producer Changes Detected In New Configuration - Closing & Recreating Producer")
-	p.Close()
+	p.logger.Info("Producer Changes Detected In New Configuration - Creating New Producer")
 	reconfiguredKafkaProducer, err := NewProducer(p.logger, newConfig, p.brokers, p.statsReporter, p.healthServer)
 	if err != nil {
-		p.logger.Fatal("Failed To Create Kafka Producer With New Configuration", zap.Error(err))
+		p.logger.Error("Failed To Create Kafka Producer With New Configuration - Keeping The Current Producer", zap.Error(err))
 		return nil
 	}
 
-	// Successfully Created New Producer - Close Old One And Return New One
+	// Successfully Created New Producer - Return It For The Caller To Switch To Before Draining This One
 	p.logger.Info("Successfully Created New Producer")
 	return reconfiguredKafkaProducer
 }
@@ -19,7 +19,9 @@ package producer
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"os"
+	"time"
 
 	"github.com/Shopify/sarama"
 	cloudevents "github.com/cloudevents/sdk-go/v2"
@@ -215,6 +217,7 @@ func runConfigChangedTest(t *testing.T, originalProducer *Producer, base *corev1
 	newProducer := originalProducer.ConfigChanged(base)
 	if newProducer != nil {
 		// Simulate what happens in main() when the producer changes
+		originalProducer.Drain()
 		originalProducer = newProducer
 	}
 
@@ -231,6 +234,8 @@ func runConfigChangedTest(t *testing.T, originalProducer *Producer, base *corev1
 
 	// Return either the new or original producer for use by the rest of the TestConfigChanged test
 	if expectedNewProducer {
+		originalProducer.Drain()
+		assert.True(t, newProducer.healthServer.ProducerReady())
 		return newProducer
 	}
 	return originalProducer
@@ -253,6 +258,60 @@ func TestClose(t *testing.T) {
 	assert.True(t, mockSyncProducer.Closed())
 }
 
+// Test That A Failure To Create The New Producer Keeps The Current One
+func TestConfigChangedProducerError(t *testing.T) {
+	assert.Nil(t, os.Setenv(system.NamespaceEnvKey, commonconstants.KnativeEventingNamespace))
+	mockSyncProducer := receivertesting.NewMockSyncProducer()
+	producer := createTestProducer(t, mockSyncProducer)
+
+	// Stub The Kafka Producer Creation Wrapper With A Failing Version
+	createSyncProducerWrapperPlaceholder := createSyncProducerWrapper
+	createSyncProducerWrapper = func(config *sarama.Config, brokers []string) (sarama.SyncProducer, gometrics.Registry, error) {
+		return nil, nil, errors.New("test producer error")
+	}
+	defer func() { createSyncProducerWrapper = createSyncProducerWrapperPlaceholder }()
+
+	configMap := getBaseConfigMap()
+	configMap.Data[commonconfig.SaramaSettingsConfigKey] = TestConfigProducerChange
+	assert.Nil(t, producer.ConfigChanged(configMap))
+	assert.True(t, producer.healthServer.ProducerReady())
+	assert.False(t, mockSyncProducer.Closed())
+}
+
+// Test The Producer's Drain() Functionality
+func TestDrain(t *testing.T) {
+
+	// Create A Test Producer Whose SyncProducer Blocks On The Second Message Until The First One Is Read
+	mockSyncProducer := receivertesting.NewMockSyncProducer()
+	producer := createTestProducer(t, mockSyncProducer)
+	channelReference := receivertesting.CreateChannelReference(receivertesting.ChannelName, receivertesting.ChannelNamespace)
+	assert.Nil(t, producer.ProduceKafkaMessage(context.Background(), channelReference, receivertesting.CreateBindingMessage(cloudevents.VersionV1)))
+
+	// Produce A Message Which Is In Flight While The Producer Is Drained
+	produced := make(chan error)
+	go func() {
+		produced <- producer.ProduceKafkaMessage(context.Background(), channelReference, receivertesting.CreateBindingMessage(cloudevents.VersionV1))
+	}()
+	time.Sleep(100 * time.Millisecond)
+	drained := make(chan struct{})
+	go func() {
+		producer.Drain()
+		close(drained)
+	}()
+
+	// The Producer Is Only Closed Once The Message In Flight Has Been Produced
+	time.Sleep(100 * time.Millisecond)
+	assert.False(t, mockSyncProducer.Closed())
+	mockSyncProducer.GetMessage()
+	assert.Nil(t, <-produced)
+	<-drained
+	assert.True(t, mockSyncProducer.Closed())
+
+	// The Readiness Is Unchanged & Subsequent Messages Are Rejected
+	assert.True(t, producer.healthServer.ProducerReady())
+	assert.Equal(t, ErrProducerClosed, producer.ProduceKafkaMessage(context.Background(), channelReference, receivertesting.CreateBindingMessage(cloudevents.VersionV1)))
+}
+
 func getSaramaConfigFromYaml(t *testing.T, saramaYaml string) *sarama.Config {
 	var config *sarama.Config
 	jsonSettings, err := yaml.YAMLToJSON([]byte(saramaYaml))
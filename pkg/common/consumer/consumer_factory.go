@@ -54,7 +54,13 @@ func (c *customConsumerGroup) Close() error {
 var _ sarama.ConsumerGroup = (*customConsumerGroup)(nil)
 
 func (c kafkaConsumerGroupFactoryImpl) StartConsumerGroup(groupID string, topics []string, logger *zap.SugaredLogger, handler KafkaConsumerHandler) (sarama.ConsumerGroup, error) {
-	consumerGroup, err := newConsumerGroup(c.addrs, groupID, c.config)
+	config := c.config
+	if deliveryHandler, ok := handler.(KafkaConsumerDeliveryHandler); ok {
+		// A New Group Starts At The Subscriber's Start Offset Rather Than The Default One (If It Specifies One)
+		config = ConsumerConfig(config, deliveryHandler.SubscriberDelivery())
+	}
+
+	consumerGroup, err := newConsumerGroup(c.addrs, groupID, config)
 	if err != nil {
 		return nil, err
 	}
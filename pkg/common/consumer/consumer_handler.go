@@ -72,7 +72,25 @@ func (consumer *SaramaConsumerHandler) ConsumeClaim(session sarama.ConsumerGroup
 	// Do not move the code below to a goroutine.
 	// The `ConsumeClaim` itself is called within a goroutine, see:
 	// https://github.com/Shopify/sarama/blob/master/consumer_group.go#L27-L29
+	if deliveryHandler, ok := consumer.handler.(KafkaConsumerDeliveryHandler); ok {
+		handle := HandleFunc(deliveryHandler.Handle)
+		if lagHandler, ok := consumer.handler.(KafkaConsumerLagHandler); ok {
+			handle = WithLag(claim, lagHandler.ReportLag, handle)
+		}
+		// Don't use the session context since it is closed before messages are drained.
+		ConsumeWithDelivery(context.Background(), session, claim, deliveryHandler.SubscriberDelivery(), handle, func(message *sarama.ConsumerMessage, err error) {
+			consumer.logger.Infow("Failure while handling a message", zap.String("topic", message.Topic), zap.Int32("partition", message.Partition), zap.Int64("offset", message.Offset), zap.Error(err))
+			consumer.errors <- err
+		})
+		consumer.logger.Infof("Stopping partition consumer, topic: %s, partition: %d", claim.Topic(), claim.Partition())
+		return nil
+	}
+
+	lagHandler, reportsLag := consumer.handler.(KafkaConsumerLagHandler)
 	for message := range claim.Messages() {
+		if reportsLag {
+			lagHandler.ReportLag(message.Topic, message.Partition, Lag(claim, message))
+		}
 		if ce := consumer.logger.Desugar().Check(zap.DebugLevel, "debugging"); ce != nil {
 			consumer.logger.Debugw("Message claimed", zap.String("topic", message.Topic), zap.Binary("value", message.Value))
 		}
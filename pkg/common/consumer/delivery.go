@@ -0,0 +1,236 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package consumer
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Shopify/sarama"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+)
+
+// NotBeforeHeader holds the time (in milliseconds since the epoch) before which a message must not be dispatched.  It
+// is set on the messages produced to retry topics (see the retrytopic package).
+const NotBeforeHeader = "kafka-retry-not-before"
+
+// The backoff used while a strictly-ordered partition is blocked on an undelivered message (vars to allow testing).
+var (
+	strictOrderingInitialBackoff = 500 * time.Millisecond
+	strictOrderingMaxBackoff     = 30 * time.Second
+)
+
+// KafkaConsumerDeliveryHandler is an optional extension of KafkaConsumerHandler.  Handlers implementing it have
+// the messages of a claim dispatched according to the returned SubscriberDelivery instead of one at a time.
+type KafkaConsumerDeliveryHandler interface {
+	KafkaConsumerHandler
+	SubscriberDelivery() kafkav1beta1.SubscriberDelivery
+}
+
+// HandleFunc handles a single message and returns true if it was delivered.
+type HandleFunc func(ctx context.Context, message *sarama.ConsumerMessage) (bool, error)
+
+// ErrorFunc is notified of every error returned by a HandleFunc.
+type ErrorFunc func(message *sarama.ConsumerMessage, err error)
+
+// ConsumeWithDelivery consumes the messages of the claim until its channel closes, dispatching them to the handle
+// function according to the specified SubscriberDelivery and marking them in the session once they are done.
+//
+// With the unordered and ordered-by-key orderings a message is done once the handle function returned (i.e. once
+// it has exhausted its retries), whatever the outcome, so that an undeliverable message never holds back the
+// partition's committed offset.  With the strictly-ordered ordering a message is only done once it has been
+// delivered, and the handle function is invoked again (with backoff) until then, or until the session ends.
+//
+// Messages with a NotBeforeHeader (i.e. consumed from a retry topic) are held back until they are due, and messages
+// published before the timestamp the subscriber starts or was rewound from (see StartPosition) are skipped.
+func ConsumeWithDelivery(ctx context.Context, session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim, delivery kafkav1beta1.SubscriberDelivery, handle HandleFunc, onError ErrorFunc) {
+	_, since, _ := StartPosition(delivery)
+	handle = skipBefore(since, handle)
+	switch delivery.Ordering {
+	case kafkav1beta1.DeliveryStrictlyOrdered:
+		consumeStrictlyOrdered(ctx, session, claim, handle, onError)
+	case kafkav1beta1.DeliveryOrderedByKey:
+		consumeConcurrently(ctx, session, claim, delivery.Concurrency, true, handle, onError)
+	default:
+		consumeConcurrently(ctx, session, claim, delivery.Concurrency, false, handle, onError)
+	}
+}
+
+// consumeStrictlyOrdered dispatches one message at a time, blocking on each message until it is delivered.
+func consumeStrictlyOrdered(ctx context.Context, session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim, handle HandleFunc, onError ErrorFunc) {
+	for message := range claim.Messages() {
+		if !awaitNotBefore(session, message) {
+			return
+		}
+		backoff := strictOrderingInitialBackoff
+		for {
+			delivered, err := handle(ctx, message)
+			if err != nil {
+				onError(message, err)
+			}
+			if delivered {
+				session.MarkMessage(message, "")
+				break
+			}
+			select {
+			case <-session.Context().Done():
+				// The session is over - leave the message unmarked so that it is redelivered to the next owner.
+				return
+			case <-time.After(backoff):
+			}
+			if backoff *= 2; backoff > strictOrderingMaxBackoff {
+				backoff = strictOrderingMaxBackoff
+			}
+		}
+	}
+}
+
+// consumeConcurrently dispatches up to concurrency messages at once, serializing the messages which share a key
+// when orderByKey is set.  Messages are marked through an offsetTracker, so that a committed offset never skips a
+// message which is still in flight.
+func consumeConcurrently(ctx context.Context, session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim, concurrency int, orderByKey bool, handle HandleFunc, onError ErrorFunc) {
+	if concurrency < 1 {
+		concurrency = kafkav1beta1.DefaultDeliveryConcurrency
+	}
+
+	tracker := newOffsetTracker(session)
+	slots := make(chan struct{}, concurrency)
+	keys := make(map[string]chan struct{}) // The completion channel of the last message dispatched for each key
+	var waitGroup sync.WaitGroup
+
+	for message := range claim.Messages() {
+
+		// Hold back messages from retry topics until they are due, leaving them unmarked if the session ends first
+		if !awaitNotBefore(session, message) {
+			break
+		}
+
+		// Wait for a free worker slot before accepting the message
+		slots <- struct{}{}
+		tracker.add(message)
+
+		// Chain the message behind the previous message with the same key (if any)
+		var previous <-chan struct{}
+		done := make(chan struct{})
+		if orderByKey && len(message.Key) > 0 {
+			key := string(message.Key)
+			previous = keys[key]
+			keys[key] = done
+		}
+
+		waitGroup.Add(1)
+		go func(message *sarama.ConsumerMessage, previous <-chan struct{}, done chan struct{}) {
+			defer waitGroup.Done()
+			defer func() { <-slots }()
+			defer close(done)
+			if previous != nil {
+				<-previous
+			}
+			if _, err := handle(ctx, message); err != nil {
+				onError(message, err)
+			}
+			tracker.complete(message)
+		}(message, previous, done)
+
+		// Forget keys whose last message has completed to keep the map bounded
+		if len(keys) > concurrency {
+			for key, keyDone := range keys {
+				select {
+				case <-keyDone:
+					delete(keys, key)
+				default:
+				}
+			}
+		}
+	}
+
+	waitGroup.Wait()
+}
+
+// awaitNotBefore waits until a message consumed from a retry topic is due, returning false if the session ended
+// first.  All the messages of a retry topic share the same delay, so waiting for them in order never holds back a
+// message which is already due.
+func awaitNotBefore(session sarama.ConsumerGroupSession, message *sarama.ConsumerMessage) bool {
+	notBefore, ok := NotBefore(message)
+	if !ok {
+		return true
+	}
+	delay := time.Until(notBefore)
+	if delay <= 0 {
+		return true
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-session.Context().Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// NotBefore returns the time held by the NotBeforeHeader of a message, if it has a valid one.
+func NotBefore(message *sarama.ConsumerMessage) (time.Time, bool) {
+	for _, header := range message.Headers {
+		if header != nil && string(header.Key) == NotBeforeHeader {
+			millis, err := strconv.ParseInt(string(header.Value), 10, 64)
+			if err != nil {
+				return time.Time{}, false
+			}
+			return time.Unix(0, millis*int64(time.Millisecond)), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// offsetTracker marks messages in a session once they, and every message received before them, have completed.
+type offsetTracker struct {
+	lock      sync.Mutex
+	session   sarama.ConsumerGroupSession
+	pending   []*sarama.ConsumerMessage // Messages not yet marked, in the order they were received
+	completed map[int64]bool
+}
+
+func newOffsetTracker(session sarama.ConsumerGroupSession) *offsetTracker {
+	return &offsetTracker{session: session, completed: make(map[int64]bool)}
+}
+
+// add registers a message before it is dispatched.  Messages must be added in the order they are received.
+func (t *offsetTracker) add(message *sarama.ConsumerMessage) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.pending = append(t.pending, message)
+}
+
+// complete records a dispatched message and marks the highest contiguous completed message, if it advanced.
+func (t *offsetTracker) complete(message *sarama.ConsumerMessage) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.completed[message.Offset] = true
+
+	var highest *sarama.ConsumerMessage
+	for len(t.pending) > 0 && t.completed[t.pending[0].Offset] {
+		highest = t.pending[0]
+		delete(t.completed, highest.Offset)
+		t.pending = t.pending[1:]
+	}
+	if highest != nil {
+		t.session.MarkMessage(highest, "")
+	}
+}
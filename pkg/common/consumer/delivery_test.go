@@ -0,0 +1,322 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package consumer
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+)
+
+//------ Mocks
+
+type deliverySession struct {
+	mockConsumerGroupSession
+	ctx    context.Context
+	lock   sync.Mutex
+	offset []int64
+}
+
+func (s *deliverySession) MarkMessage(msg *sarama.ConsumerMessage, _ string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.offset = append(s.offset, msg.Offset)
+}
+
+func (s *deliverySession) Context() context.Context {
+	return s.ctx
+}
+
+func (s *deliverySession) markedOffsets() []int64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]int64{}, s.offset...)
+}
+
+type deliveryClaim struct {
+	mockConsumerGroupClaim
+	messages chan *sarama.ConsumerMessage
+}
+
+func (c deliveryClaim) Messages() <-chan *sarama.ConsumerMessage {
+	return c.messages
+}
+
+// newDeliveryClaim returns a claim with the specified keys as messages (offsets 0..n-1) and a closed channel.
+func newDeliveryClaim(keys ...string) deliveryClaim {
+	messages := make(chan *sarama.ConsumerMessage, len(keys))
+	for i, key := range keys {
+		message := &sarama.ConsumerMessage{Offset: int64(i)}
+		if key != "" {
+			message.Key = []byte(key)
+		}
+		messages <- message
+	}
+	close(messages)
+	return deliveryClaim{messages: messages}
+}
+
+//------ Tests
+
+func TestConsumeWithDeliveryUnordered(t *testing.T) {
+	session := &deliverySession{ctx: context.Background()}
+	claim := newDeliveryClaim("", "", "", "", "", "", "", "")
+
+	var lock sync.Mutex
+	inFlight, maxInFlight, handled := 0, 0, 0
+	handle := func(ctx context.Context, message *sarama.ConsumerMessage) (bool, error) {
+		lock.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		lock.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		lock.Lock()
+		inFlight--
+		handled++
+		lock.Unlock()
+		return false, errors.New("failed")
+	}
+
+	var errorCount int
+	onError := func(*sarama.ConsumerMessage, error) {
+		lock.Lock()
+		errorCount++
+		lock.Unlock()
+	}
+
+	delivery := kafkav1beta1.SubscriberDelivery{Ordering: kafkav1beta1.DeliveryUnordered, Concurrency: 4}
+	ConsumeWithDelivery(context.Background(), session, claim, delivery, handle, onError)
+
+	assert.Equal(t, 8, handled)
+	assert.Equal(t, 8, errorCount)
+	assert.True(t, maxInFlight > 1)
+	assert.True(t, maxInFlight <= 4)
+	marked := session.markedOffsets()
+	assert.NotEmpty(t, marked)
+	assert.Equal(t, int64(7), marked[len(marked)-1]) // Failures are still marked
+}
+
+func TestConsumeWithDeliveryOrderedByKey(t *testing.T) {
+	session := &deliverySession{ctx: context.Background()}
+	claim := newDeliveryClaim("a", "b", "a", "b", "a", "b")
+
+	var lock sync.Mutex
+	inFlight := make(map[string]bool)
+	order := make(map[string][]int64)
+	handle := func(ctx context.Context, message *sarama.ConsumerMessage) (bool, error) {
+		key := string(message.Key)
+		lock.Lock()
+		assert.False(t, inFlight[key], "messages with the same key dispatched concurrently")
+		inFlight[key] = true
+		order[key] = append(order[key], message.Offset)
+		lock.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		lock.Lock()
+		inFlight[key] = false
+		lock.Unlock()
+		return true, nil
+	}
+
+	delivery := kafkav1beta1.SubscriberDelivery{Ordering: kafkav1beta1.DeliveryOrderedByKey, Concurrency: 6}
+	ConsumeWithDelivery(context.Background(), session, claim, delivery, handle, func(*sarama.ConsumerMessage, error) {})
+
+	assert.Equal(t, []int64{0, 2, 4}, order["a"])
+	assert.Equal(t, []int64{1, 3, 5}, order["b"])
+	marked := session.markedOffsets()
+	assert.Equal(t, int64(5), marked[len(marked)-1])
+}
+
+func TestConsumeWithDeliveryStrictlyOrdered(t *testing.T) {
+	defer restoreStrictOrderingBackoff(strictOrderingInitialBackoff, strictOrderingMaxBackoff)
+	strictOrderingInitialBackoff = time.Millisecond
+	strictOrderingMaxBackoff = 2 * time.Millisecond
+
+	session := &deliverySession{ctx: context.Background()}
+	claim := newDeliveryClaim("", "", "")
+
+	var attempts []int64
+	handle := func(ctx context.Context, message *sarama.ConsumerMessage) (bool, error) {
+		attempts = append(attempts, message.Offset)
+		if message.Offset == 1 && len(attempts) < 4 {
+			return false, errors.New("failed")
+		}
+		return true, nil
+	}
+
+	errorCount := 0
+	delivery := kafkav1beta1.SubscriberDelivery{Ordering: kafkav1beta1.DeliveryStrictlyOrdered}
+	ConsumeWithDelivery(context.Background(), session, claim, delivery, handle, func(*sarama.ConsumerMessage, error) { errorCount++ })
+
+	assert.Equal(t, []int64{0, 1, 1, 1, 2}, attempts)
+	assert.Equal(t, 2, errorCount)
+	assert.Equal(t, []int64{0, 1, 2}, session.markedOffsets())
+}
+
+func TestConsumeWithDeliveryStrictlyOrderedSessionDone(t *testing.T) {
+	defer restoreStrictOrderingBackoff(strictOrderingInitialBackoff, strictOrderingMaxBackoff)
+	strictOrderingInitialBackoff = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	session := &deliverySession{ctx: ctx}
+	claim := newDeliveryClaim("", "")
+
+	handle := func(ctx context.Context, message *sarama.ConsumerMessage) (bool, error) {
+		cancel()
+		return false, errors.New("failed")
+	}
+
+	delivery := kafkav1beta1.SubscriberDelivery{Ordering: kafkav1beta1.DeliveryStrictlyOrdered}
+	ConsumeWithDelivery(context.Background(), session, claim, delivery, handle, func(*sarama.ConsumerMessage, error) {})
+
+	assert.Empty(t, session.markedOffsets())
+}
+
+func TestOffsetTracker(t *testing.T) {
+	session := &deliverySession{ctx: context.Background()}
+	tracker := newOffsetTracker(session)
+
+	// Offsets need not be contiguous (e.g. compacted topics)
+	messages := []*sarama.ConsumerMessage{{Offset: 3}, {Offset: 4}, {Offset: 7}, {Offset: 8}}
+	for _, message := range messages {
+		tracker.add(message)
+	}
+
+	tracker.complete(messages[1])
+	assert.Empty(t, session.markedOffsets())
+	tracker.complete(messages[3])
+	assert.Empty(t, session.markedOffsets())
+	tracker.complete(messages[0])
+	assert.Equal(t, []int64{4}, session.markedOffsets())
+	tracker.complete(messages[2])
+	assert.Equal(t, []int64{4, 8}, session.markedOffsets())
+}
+
+func TestConsumeWithDeliveryFailedMessageDoesNotHoldBackOffsets(t *testing.T) {
+	session := &deliverySession{ctx: context.Background()}
+	claim := newDeliveryClaim("", "", "", "", "")
+
+	var lock sync.Mutex
+	var handled []int64
+	handle := func(ctx context.Context, message *sarama.ConsumerMessage) (bool, error) {
+		lock.Lock()
+		handled = append(handled, message.Offset)
+		lock.Unlock()
+		if message.Offset == 2 {
+			return false, errors.New("failed")
+		}
+		return true, nil
+	}
+
+	delivery := kafkav1beta1.SubscriberDelivery{Ordering: kafkav1beta1.DeliveryUnordered, Concurrency: 2}
+	ConsumeWithDelivery(context.Background(), session, claim, delivery, handle, func(*sarama.ConsumerMessage, error) {})
+
+	// The failed message has exhausted its retries, so the committed offset moves past it
+	assert.Len(t, handled, 5)
+	marked := session.markedOffsets()
+	assert.NotEmpty(t, marked)
+	assert.Equal(t, int64(4), marked[len(marked)-1])
+}
+
+func TestConsumeWithDeliverySlowMessageHoldsBackOffsets(t *testing.T) {
+	session := &deliverySession{ctx: context.Background()}
+	claim := newDeliveryClaim("", "", "", "")
+
+	release := make(chan struct{})
+	handle := func(ctx context.Context, message *sarama.ConsumerMessage) (bool, error) {
+		if message.Offset == 0 {
+			<-release
+		}
+		return true, nil
+	}
+
+	finished := make(chan struct{})
+	go func() {
+		delivery := kafkav1beta1.SubscriberDelivery{Ordering: kafkav1beta1.DeliveryUnordered, Concurrency: 4}
+		ConsumeWithDelivery(context.Background(), session, claim, delivery, handle, func(*sarama.ConsumerMessage, error) {})
+		close(finished)
+	}()
+
+	// The later messages complete, but nothing may be marked while the first is still in flight
+	time.Sleep(50 * time.Millisecond)
+	assert.Empty(t, session.markedOffsets())
+
+	close(release)
+	<-finished
+	assert.Equal(t, []int64{3}, session.markedOffsets())
+}
+
+func TestConsumeWithDeliveryHoldsBackRetries(t *testing.T) {
+	notBefore := func(delay time.Duration) *sarama.RecordHeader {
+		millis := time.Now().Add(delay).UnixNano() / int64(time.Millisecond)
+		return &sarama.RecordHeader{Key: []byte(NotBeforeHeader), Value: []byte(strconv.FormatInt(millis, 10))}
+	}
+
+	var lock sync.Mutex
+	var handled []time.Time
+	handle := func(ctx context.Context, message *sarama.ConsumerMessage) (bool, error) {
+		lock.Lock()
+		defer lock.Unlock()
+		handled = append(handled, time.Now())
+		return true, nil
+	}
+	delivery := kafkav1beta1.SubscriberDelivery{Ordering: kafkav1beta1.DeliveryUnordered, Concurrency: 2}
+
+	// A Message Is Only Dispatched Once It Is Due
+	start := time.Now()
+	session := &deliverySession{ctx: context.Background()}
+	claim := newDeliveryClaim("", "")
+	ConsumeWithDelivery(context.Background(), session, deliveryClaim{messages: withHeaders(claim.messages, notBefore(-time.Second), notBefore(50*time.Millisecond))}, delivery, handle, func(*sarama.ConsumerMessage, error) {})
+	assert.Len(t, handled, 2)
+	assert.True(t, handled[1].Sub(start) >= 45*time.Millisecond) // NotBefore Has Millisecond Precision
+	assert.Equal(t, int64(1), session.markedOffsets()[len(session.markedOffsets())-1])
+
+	// Messages Which Are Not Yet Due When The Session Ends Are Left Unmarked
+	handled = nil
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	session = &deliverySession{ctx: ctx}
+	claim = newDeliveryClaim("")
+	ConsumeWithDelivery(context.Background(), session, deliveryClaim{messages: withHeaders(claim.messages, notBefore(time.Hour))}, delivery, handle, func(*sarama.ConsumerMessage, error) {})
+	assert.Empty(t, handled)
+	assert.Empty(t, session.markedOffsets())
+}
+
+// withHeaders adds one header to each of the messages of a closed channel, returning a new closed channel.
+func withHeaders(messages chan *sarama.ConsumerMessage, headers ...*sarama.RecordHeader) chan *sarama.ConsumerMessage {
+	result := make(chan *sarama.ConsumerMessage, len(headers))
+	for _, header := range headers {
+		message := <-messages
+		message.Headers = append(message.Headers, header)
+		result <- message
+	}
+	close(result)
+	return result
+}
+
+func restoreStrictOrderingBackoff(initial time.Duration, max time.Duration) {
+	strictOrderingInitialBackoff = initial
+	strictOrderingMaxBackoff = max
+}
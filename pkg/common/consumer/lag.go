@@ -0,0 +1,82 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package consumer
+
+import (
+	"github.com/Shopify/sarama"
+)
+
+// GroupOffsetsLister is the part of sarama.ClusterAdmin which lists the offsets committed by a consumer group.
+type GroupOffsetsLister interface {
+	ListConsumerGroupOffsets(group string, topicPartitions map[string][]int32) (*sarama.OffsetFetchResponse, error)
+}
+
+// OffsetsReader is the part of sarama.Client which reads the partitions of the topics and their offsets.
+type OffsetsReader interface {
+	Partitions(topic string) ([]int32, error)
+	GetOffset(topic string, partitionID int32, time int64) (int64, error)
+}
+
+var _ GroupOffsetsLister = (sarama.ClusterAdmin)(nil)
+var _ OffsetsReader = (sarama.Client)(nil)
+
+// GroupLag returns the number of events of the topics left to consume by the consumer group, which is the sum of the
+// differences between the newest offsets of their partitions and the offsets committed by the group.  The partitions
+// which the group has not committed any offset for count from their oldest offset, and the missing topics are
+// skipped.
+func GroupLag(groupOffsets GroupOffsetsLister, offsets OffsetsReader, groupID string, topics []string) (int64, error) {
+	topicPartitions := make(map[string][]int32, len(topics))
+	for _, topic := range topics {
+		partitions, err := offsets.Partitions(topic)
+		if err == sarama.ErrUnknownTopicOrPartition {
+			continue
+		} else if err != nil {
+			return 0, err
+		}
+		topicPartitions[topic] = partitions
+	}
+
+	committed, err := groupOffsets.ListConsumerGroupOffsets(groupID, topicPartitions)
+	if err != nil {
+		return 0, err
+	}
+	var lag int64
+	for topic, partitions := range topicPartitions {
+		for _, partition := range partitions {
+			newest, err := offsets.GetOffset(topic, partition, sarama.OffsetNewest)
+			if err != nil {
+				return 0, err
+			}
+			offset := int64(-1)
+			if block := committed.GetBlock(topic, partition); block != nil {
+				if block.Err != sarama.ErrNoError {
+					return 0, block.Err
+				}
+				offset = block.Offset
+			}
+			if offset < 0 {
+				if offset, err = offsets.GetOffset(topic, partition, sarama.OffsetOldest); err != nil {
+					return 0, err
+				}
+			}
+			if newest > offset {
+				lag += newest - offset
+			}
+		}
+	}
+	return lag, nil
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package consumer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeGroupOffsets map[string]map[int32]int64
+
+func (f fakeGroupOffsets) ListConsumerGroupOffsets(_ string, topicPartitions map[string][]int32) (*sarama.OffsetFetchResponse, error) {
+	response := &sarama.OffsetFetchResponse{}
+	for topic, partitions := range topicPartitions {
+		for _, partition := range partitions {
+			if offset, ok := f[topic][partition]; ok {
+				response.AddBlock(topic, partition, &sarama.OffsetFetchResponseBlock{Offset: offset})
+			}
+		}
+	}
+	return response, nil
+}
+
+type fakeOffsets struct {
+	oldest map[string][]int64
+	newest map[string][]int64
+	err    error
+}
+
+func (f *fakeOffsets) Partitions(topic string) ([]int32, error) {
+	offsets, ok := f.newest[topic]
+	if !ok {
+		return nil, sarama.ErrUnknownTopicOrPartition
+	}
+	partitions := make([]int32, len(offsets))
+	for i := range offsets {
+		partitions[i] = int32(i)
+	}
+	return partitions, nil
+}
+
+func (f *fakeOffsets) GetOffset(topic string, partitionID int32, time int64) (int64, error) {
+	if f.err != nil {
+		return 0, f.err
+	}
+	if time == sarama.OffsetOldest {
+		return f.oldest[topic][partitionID], nil
+	}
+	return f.newest[topic][partitionID], nil
+}
+
+func TestGroupLag(t *testing.T) {
+	offsets := &fakeOffsets{
+		oldest: map[string][]int64{"topic": {0, 5}, "retry": {2}},
+		newest: map[string][]int64{"topic": {10, 20}, "retry": {4}},
+	}
+
+	// the partitions count from their committed offset, or from their oldest one, and the missing topics are skipped
+	lag, err := GroupLag(fakeGroupOffsets{"topic": {0: 7}}, offsets, "group", []string{"topic", "retry", "missing"})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3+15+2), lag)
+
+	// a group ahead of the newest offset (e.g. after a topic was recreated) has no lag
+	lag, err = GroupLag(fakeGroupOffsets{"topic": {0: 12, 1: 20}}, offsets, "group", []string{"topic"})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), lag)
+
+	// the errors are returned
+	offsets.err = errors.New("offsets failed")
+	_, err = GroupLag(fakeGroupOffsets{}, offsets, "group", []string{"topic"})
+	assert.Equal(t, offsets.err, err)
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package consumer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/Shopify/sarama"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+)
+
+// rewindInfix separates the base consumer group id from the suffix identifying a rewind.
+const rewindInfix = ".rewind-"
+
+// GroupID returns the consumer group id of a subscriber, which is the specified base id unless the subscriber has
+// been rewound.  A rewound subscriber gets a new group per rewind value, so that it starts over from the rewind
+// offset (new groups having no committed offsets) while restarts of the dispatcher keep using the same group.
+func GroupID(base string, delivery kafkav1beta1.SubscriberDelivery) string {
+	if delivery.Rewind == "" {
+		return base
+	}
+	hash := sha256.Sum256([]byte(delivery.Rewind))
+	return base + rewindInfix + hex.EncodeToString(hash[:4])
+}
+
+// BaseGroupID returns the base consumer group id of the specified group id, without the suffix of a rewind (if any).
+func BaseGroupID(groupID string) string {
+	if index := strings.LastIndex(groupID, rewindInfix); index >= 0 {
+		return groupID[:index]
+	}
+	return groupID
+}
+
+// StartPosition returns the initial offset of a new consumer group for the subscriber (sarama.OffsetOldest or
+// sarama.OffsetNewest), along with the time before which messages are skipped (zero when none are).  The returned
+// bool is false if the delivery settings leave the initial offset up to the sarama config.  A rewind takes
+// precedence over the start offset.
+func StartPosition(delivery kafkav1beta1.SubscriberDelivery) (int64, time.Time, bool) {
+	value := delivery.Rewind
+	if value == "" {
+		value = delivery.StartOffset
+	}
+	if value == "" {
+		return 0, time.Time{}, false
+	}
+	offset, err := kafkav1beta1.ParseDeliveryOffset(value)
+	if err != nil {
+		return 0, time.Time{}, false
+	}
+	if offset.Latest {
+		return sarama.OffsetNewest, time.Time{}, true
+	}
+	return sarama.OffsetOldest, offset.Time, true
+}
+
+// ConsumerConfig returns the sarama config for the consumer group of a subscriber, which is a copy of the specified
+// config with the initial offset of the subscriber's StartPosition (or the config itself if it has none).
+func ConsumerConfig(config *sarama.Config, delivery kafkav1beta1.SubscriberDelivery) *sarama.Config {
+	initial, _, ok := StartPosition(delivery)
+	if !ok || config == nil || config.Consumer.Offsets.Initial == initial {
+		return config
+	}
+	configCopy := *config
+	configCopy.Consumer.Offsets.Initial = initial
+	return &configCopy
+}
+
+// RequiresNewSession returns true if the change of a subscriber's delivery settings cannot be applied in place, as
+// the messages of its claims are consumed differently (see ConsumeWithDelivery) or from another consumer group.
+// Every other setting of a subscriber can be updated without restarting its consumer group.
+func RequiresNewSession(previous kafkav1beta1.SubscriberDelivery, delivery kafkav1beta1.SubscriberDelivery) bool {
+	return previous.Ordering != delivery.Ordering ||
+		previous.Concurrency != delivery.Concurrency ||
+		previous.RetryTopics != delivery.RetryTopics ||
+		previous.Rewind != delivery.Rewind
+}
+
+// skipBefore wraps a HandleFunc so that messages with a timestamp before the specified time are done without being
+// handled.  Messages without a timestamp (from brokers before Kafka 0.10) are always handled.
+func skipBefore(since time.Time, handle HandleFunc) HandleFunc {
+	if since.IsZero() {
+		return handle
+	}
+	return func(ctx context.Context, message *sarama.ConsumerMessage) (bool, error) {
+		if !message.Timestamp.IsZero() && message.Timestamp.Before(since) {
+			return true, nil
+		}
+		return handle(ctx, message)
+	}
+}
+
+// KafkaConsumerLagHandler is an optional extension of KafkaConsumerHandler.  Handlers implementing it are notified of
+// the lag of a claim's partition (see Lag) before each of its messages is handled.
+type KafkaConsumerLagHandler interface {
+	KafkaConsumerHandler
+	ReportLag(topic string, partition int32, lag int64)
+}
+
+// LagFunc is notified of the lag of a claim's partition.
+type LagFunc func(topic string, partition int32, lag int64)
+
+// Lag returns the number of messages of the claim's partition after the specified one, i.e. those the consumer group
+// has yet to consume once it has consumed that message.
+func Lag(claim sarama.ConsumerGroupClaim, message *sarama.ConsumerMessage) int64 {
+	lag := claim.HighWaterMarkOffset() - message.Offset - 1
+	if lag < 0 {
+		return 0
+	}
+	return lag
+}
+
+// WithLag wraps a HandleFunc so that the lag of the claim's partition is reported before each message is handled.
+func WithLag(claim sarama.ConsumerGroupClaim, reportLag LagFunc, handle HandleFunc) HandleFunc {
+	return func(ctx context.Context, message *sarama.ConsumerMessage) (bool, error) {
+		reportLag(message.Topic, message.Partition, Lag(claim, message))
+		return handle(ctx, message)
+	}
+}
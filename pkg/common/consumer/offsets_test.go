@@ -0,0 +1,177 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package consumer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+)
+
+func TestGroupID(t *testing.T) {
+	delivery := kafkav1beta1.DefaultSubscriberDelivery()
+	assert.Equal(t, "kafka.uid", GroupID("kafka.uid", delivery))
+
+	delivery.Rewind = "earliest"
+	rewound := GroupID("kafka.uid", delivery)
+	assert.Regexp(t, `^kafka\.uid\.rewind-[0-9a-f]{8}$`, rewound)
+	assert.Equal(t, rewound, GroupID("kafka.uid", delivery)) // Stable Across Restarts
+
+	delivery.Rewind = "2021-01-02T03:04:05Z"
+	assert.NotEqual(t, rewound, GroupID("kafka.uid", delivery)) // Every Rewind Gets A New Group
+}
+
+func TestBaseGroupID(t *testing.T) {
+	delivery := kafkav1beta1.DefaultSubscriberDelivery()
+	assert.Equal(t, "kafka.uid", BaseGroupID(GroupID("kafka.uid", delivery)))
+
+	delivery.Rewind = "earliest"
+	assert.Equal(t, "kafka.uid", BaseGroupID(GroupID("kafka.uid", delivery)))
+}
+
+func TestStartPosition(t *testing.T) {
+	testCases := map[string]struct {
+		startOffset string
+		rewind      string
+		initial     int64
+		since       time.Time
+		ok          bool
+	}{
+		"default":          {},
+		"earliest":         {startOffset: "earliest", initial: sarama.OffsetOldest, ok: true},
+		"latest":           {startOffset: "latest", initial: sarama.OffsetNewest, ok: true},
+		"timestamp":        {startOffset: "2021-01-02T03:04:05Z", initial: sarama.OffsetOldest, since: time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC), ok: true},
+		"rewind overrides": {startOffset: "latest", rewind: "earliest", initial: sarama.OffsetOldest, ok: true},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			delivery := kafkav1beta1.SubscriberDelivery{StartOffset: testCase.startOffset, Rewind: testCase.rewind}
+			initial, since, ok := StartPosition(delivery)
+			assert.Equal(t, testCase.ok, ok)
+			assert.Equal(t, testCase.initial, initial)
+			assert.True(t, testCase.since.Equal(since))
+		})
+	}
+}
+
+func TestConsumerConfig(t *testing.T) {
+	config := sarama.NewConfig()
+	config.Consumer.Offsets.Initial = sarama.OffsetNewest
+
+	assert.Same(t, config, ConsumerConfig(config, kafkav1beta1.DefaultSubscriberDelivery()))
+
+	consumerConfig := ConsumerConfig(config, kafkav1beta1.SubscriberDelivery{StartOffset: "earliest"})
+	assert.NotSame(t, config, consumerConfig)
+	assert.Equal(t, sarama.OffsetOldest, consumerConfig.Consumer.Offsets.Initial)
+	assert.Equal(t, sarama.OffsetNewest, config.Consumer.Offsets.Initial)
+}
+
+func TestRequiresNewSession(t *testing.T) {
+	delivery := kafkav1beta1.DefaultSubscriberDelivery()
+	assert.False(t, RequiresNewSession(delivery, delivery))
+
+	changed := delivery
+	changed.StartOffset = "earliest" // Only Applies To New Consumer Groups
+	assert.False(t, RequiresNewSession(delivery, changed))
+
+	for _, change := range []func(*kafkav1beta1.SubscriberDelivery){
+		func(d *kafkav1beta1.SubscriberDelivery) { d.Ordering = kafkav1beta1.DeliveryStrictlyOrdered },
+		func(d *kafkav1beta1.SubscriberDelivery) { d.Concurrency++ },
+		func(d *kafkav1beta1.SubscriberDelivery) { d.RetryTopics = true },
+		func(d *kafkav1beta1.SubscriberDelivery) { d.Rewind = "earliest" },
+	} {
+		changed := delivery
+		change(&changed)
+		assert.True(t, RequiresNewSession(delivery, changed))
+	}
+}
+
+func TestConsumeWithDeliverySkipsMessagesBeforeTimestamp(t *testing.T) {
+	since := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	claim := newDeliveryClaim("", "", "", "")
+	timestamps := []time.Time{since.Add(-time.Minute), {}, since, since.Add(-time.Second)}
+	messages := make(chan *sarama.ConsumerMessage, len(timestamps))
+	for _, timestamp := range timestamps {
+		message := <-claim.messages
+		message.Timestamp = timestamp
+		messages <- message
+	}
+	close(messages)
+
+	var handled []int64
+	handle := func(ctx context.Context, message *sarama.ConsumerMessage) (bool, error) {
+		handled = append(handled, message.Offset)
+		return true, nil
+	}
+
+	// Skipped Messages Are Marked Without Being Handled (Messages Without A Timestamp Are Always Handled)
+	session := &deliverySession{ctx: context.Background()}
+	delivery := kafkav1beta1.SubscriberDelivery{Ordering: kafkav1beta1.DeliveryStrictlyOrdered, Rewind: "2021-01-02T03:04:05Z"}
+	ConsumeWithDelivery(context.Background(), session, deliveryClaim{messages: messages}, delivery, handle, func(*sarama.ConsumerMessage, error) {})
+	assert.Equal(t, []int64{1, 2}, handled)
+	assert.Equal(t, []int64{0, 1, 2, 3}, session.markedOffsets())
+}
+
+// lagClaim is a deliveryClaim with a high water mark.
+type lagClaim struct {
+	deliveryClaim
+	highWaterMark int64
+}
+
+func (c lagClaim) HighWaterMarkOffset() int64 {
+	return c.highWaterMark
+}
+
+// lagHandler is a KafkaConsumerDeliveryHandler recording the reported lags.
+type lagHandler struct {
+	lags []int64
+}
+
+func (h *lagHandler) Handle(context.Context, *sarama.ConsumerMessage) (bool, error) {
+	return true, nil
+}
+
+func (h *lagHandler) SubscriberDelivery() kafkav1beta1.SubscriberDelivery {
+	return kafkav1beta1.SubscriberDelivery{Ordering: kafkav1beta1.DeliveryStrictlyOrdered}
+}
+
+func (h *lagHandler) ReportLag(topic string, partition int32, lag int64) {
+	h.lags = append(h.lags, lag)
+}
+
+func TestLag(t *testing.T) {
+	claim := lagClaim{highWaterMark: 10}
+	assert.Equal(t, int64(9), Lag(claim, &sarama.ConsumerMessage{Offset: 0}))
+	assert.Equal(t, int64(0), Lag(claim, &sarama.ConsumerMessage{Offset: 9}))
+	assert.Equal(t, int64(0), Lag(claim, &sarama.ConsumerMessage{Offset: 12})) // The High Water Mark May Be Stale
+}
+
+func TestConsumeClaimReportsLag(t *testing.T) {
+	handler := &lagHandler{}
+	consumerHandler := NewConsumerHandler(zap.NewNop().Sugar(), handler)
+	session := &deliverySession{ctx: context.Background()}
+
+	// The Lag Is Reported Before Each Message Is Handled
+	assert.Nil(t, consumerHandler.ConsumeClaim(session, lagClaim{deliveryClaim: newDeliveryClaim("", "", ""), highWaterMark: 5}))
+	assert.Equal(t, []int64{4, 3, 2}, handler.lags)
+	assert.Equal(t, []int64{0, 1, 2}, session.markedOffsets())
+}
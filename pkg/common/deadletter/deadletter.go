@@ -0,0 +1,247 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package deadletter implements dead letter sinks which are Kafka topics rather than HTTP endpoints.  A
+// subscription selects such a sink with a "kafka://<topic>" dead letter URI, and the dispatchers then produce
+// events which could not be delivered directly to that topic, along with headers describing the failure.
+// Dead letter sinks given as a reference (even to a KafkaChannel) are resolved to an HTTP address by the
+// subscription, and so are not recognized here.
+package deadletter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Shopify/sarama"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"knative.dev/eventing/pkg/channel"
+	"knative.dev/eventing/pkg/kncloudevents"
+)
+
+// Scheme is the URI scheme of dead letter sinks which are Kafka topics.
+const Scheme = "kafka"
+
+// The headers added to the Kafka messages produced to a dead letter topic.
+const (
+	StatusCodeHeader   = "kafka-dead-letter-status-code"
+	AttemptsHeader     = "kafka-dead-letter-attempts"
+	SubscriberHeader   = "kafka-dead-letter-subscriber"
+	ErrorHeader        = "kafka-dead-letter-error"
+	ResponseBodyHeader = "kafka-dead-letter-response-body"
+)
+
+// MaxResponseBodyBytes limits how much of the last failed response body is recorded.
+const MaxResponseBodyBytes = 1024
+
+// IsKafkaTopic returns true if the specified dead letter URL refers to a Kafka topic.
+func IsKafkaTopic(deadLetter *url.URL) bool {
+	return deadLetter != nil && strings.EqualFold(deadLetter.Scheme, Scheme)
+}
+
+// Topic returns the name of the Kafka topic referred to by a "kafka://<topic>" dead letter URL.
+func Topic(deadLetter *url.URL) (string, error) {
+	if !IsKafkaTopic(deadLetter) {
+		return "", fmt.Errorf("dead letter sink %q is not a kafka topic", deadLetter)
+	}
+	topic := deadLetter.Host
+	if topic == "" {
+		topic = strings.Trim(deadLetter.Path, "/")
+	}
+	if topic == "" {
+		return "", fmt.Errorf("dead letter sink %q does not specify a topic", deadLetter)
+	}
+	return topic, nil
+}
+
+// Failure describes why an event was dead lettered.
+type Failure struct {
+	StatusCode   int
+	Attempts     int
+	Subscriber   string
+	Error        string
+	ResponseBody []byte
+}
+
+// headers returns the Kafka RecordHeaders describing the failure.
+func (f Failure) headers() []sarama.RecordHeader {
+	headers := []sarama.RecordHeader{
+		{Key: []byte(StatusCodeHeader), Value: []byte(strconv.Itoa(f.StatusCode))},
+		{Key: []byte(AttemptsHeader), Value: []byte(strconv.Itoa(f.Attempts))},
+		{Key: []byte(SubscriberHeader), Value: []byte(f.Subscriber)},
+		{Key: []byte(ErrorHeader), Value: []byte(f.Error)},
+	}
+	if len(f.ResponseBody) > 0 {
+		headers = append(headers, sarama.RecordHeader{Key: []byte(ResponseBodyHeader), Value: f.ResponseBody})
+	}
+	return headers
+}
+
+// Recorder captures the outcome of every attempt made with a RetryConfig, so that a Failure can be reported.
+// A Recorder must only be used for a single dispatch.
+type Recorder struct {
+	lock         sync.Mutex
+	attempts     int
+	statusCode   int
+	responseBody []byte
+}
+
+// NewRecorder returns a copy of the specified RetryConfig which records each attempt in the returned Recorder.
+func NewRecorder(retryConfig *kncloudevents.RetryConfig) (*kncloudevents.RetryConfig, *Recorder) {
+	recorder := &Recorder{}
+	recordingConfig := kncloudevents.NoRetries()
+	if retryConfig != nil {
+		recordingConfig = *retryConfig
+	}
+	checkRetry := recordingConfig.CheckRetry
+	recordingConfig.CheckRetry = func(ctx context.Context, response *http.Response, err error) (bool, error) {
+		recorder.record(response)
+		if checkRetry == nil {
+			return false, nil
+		}
+		return checkRetry(ctx, response, err)
+	}
+	return &recordingConfig, recorder
+}
+
+// record the status code and (the start of) the body of an attempt's response, leaving the response readable.
+func (r *Recorder) record(response *http.Response) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.attempts++
+	r.statusCode = http.StatusInternalServerError
+	r.responseBody = nil
+	if response == nil {
+		return
+	}
+	r.statusCode = response.StatusCode
+	if response.Body != nil && response.Body != http.NoBody {
+		body, _ := ioutil.ReadAll(io.LimitReader(response.Body, MaxResponseBodyBytes))
+		r.responseBody = body
+		response.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(body), response.Body), Closer: response.Body}
+	}
+}
+
+// Failure returns the Failure of the recorded dispatch, which failed with the specified error.
+func (r *Recorder) Failure(subscriber *url.URL, err error) Failure {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	failure := Failure{
+		StatusCode:   r.statusCode,
+		Attempts:     r.attempts,
+		ResponseBody: r.responseBody,
+	}
+	if subscriber != nil {
+		failure.Subscriber = subscriber.String()
+	}
+	if err != nil {
+		failure.Error = err.Error()
+	}
+	return failure
+}
+
+// retries returns the number of recorded attempts beyond the first.
+func (r *Recorder) retries() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.attempts <= 1 {
+		return 0
+	}
+	return r.attempts - 1
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// Produce sends the specified ConsumerMessage, unaltered apart from the failure headers, to the dead letter topic.
+func Produce(producer sarama.SyncProducer, deadLetter *url.URL, message *sarama.ConsumerMessage, failure Failure) error {
+	if producer == nil {
+		return errors.New("no kafka producer available for the dead letter topic")
+	}
+	topic, err := Topic(deadLetter)
+	if err != nil {
+		return err
+	}
+
+	headers := make([]sarama.RecordHeader, 0, len(message.Headers)+5)
+	for _, header := range message.Headers {
+		if header != nil {
+			headers = append(headers, *header)
+		}
+	}
+	headers = append(headers, failure.headers()...)
+
+	producerMessage := &sarama.ProducerMessage{
+		Topic:   topic,
+		Value:   sarama.ByteEncoder(message.Value),
+		Headers: headers,
+	}
+	if message.Key != nil {
+		producerMessage.Key = sarama.ByteEncoder(message.Key)
+	}
+
+	_, _, err = producer.SendMessage(producerMessage)
+	return err
+}
+
+// Result describes the outcome of dispatching a message.
+type Result struct {
+	Info         *channel.DispatchExecutionInfo // The Execution Info Of The Subscriber's Last Request (May Be Nil)
+	Retries      int                            // The Attempts Beyond The First (Including A Retry Scheduled On A Retry Topic)
+	DeadLettered bool                           // Whether The Message Was Sent To The Dead Letter Sink
+	Error        error                          // The Subscriber's Error, Even If The Message Was Then Dead Lettered
+}
+
+// ResponseCode returns the response code of the subscriber's last request, which is negative if there was none.
+func (r *Result) ResponseCode() int {
+	if r == nil || r.Info == nil {
+		return channel.NoResponse
+	}
+	return r.Info.ResponseCode
+}
+
+// DispatchMessage dispatches a message like MessageDispatcher.DispatchMessageWithRetries, except that a dead letter
+// sink referring to a Kafka topic is handled by producing the original consumer message to that topic.  The returned
+// Result describes the attempts made, even when an error is returned.
+func DispatchMessage(ctx context.Context, dispatcher channel.MessageDispatcher, producer sarama.SyncProducer, message binding.Message, consumerMessage *sarama.ConsumerMessage, destination *url.URL, reply *url.URL, deadLetter *url.URL, retryConfig *kncloudevents.RetryConfig) (*Result, error) {
+	recordingConfig, recorder := NewRecorder(retryConfig)
+	info, err := dispatcher.DispatchMessageWithRetries(ctx, message, nil, destination, reply, nil, recordingConfig)
+	result := &Result{Info: info, Retries: recorder.retries(), Error: err}
+	if err == nil || deadLetter == nil {
+		return result, err
+	}
+
+	// The Dead Letter Sink Is Sent The Original Message, Like The MessageDispatcher Would
+	if IsKafkaTopic(deadLetter) {
+		if deadLetterErr := Produce(producer, deadLetter, consumerMessage, recorder.Failure(destination, err)); deadLetterErr != nil {
+			return result, fmt.Errorf("unable to complete request to either %s (%v) or %s (%v)", destination, err, deadLetter, deadLetterErr)
+		}
+	} else if _, deadLetterErr := dispatcher.DispatchMessageWithRetries(ctx, message, nil, deadLetter, nil, nil, retryConfig); deadLetterErr != nil {
+		return result, fmt.Errorf("unable to complete request to either %s (%v) or %s (%v)", destination, err, deadLetter, deadLetterErr)
+	}
+	result.DeadLettered = true
+	return result, nil
+}
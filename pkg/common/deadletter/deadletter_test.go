@@ -0,0 +1,216 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deadletter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	protocolkafka "github.com/cloudevents/sdk-go/protocol/kafka_sarama/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"knative.dev/eventing/pkg/channel"
+	"knative.dev/eventing/pkg/kncloudevents"
+)
+
+//------ Mocks
+
+type mockSyncProducer struct {
+	messages []*sarama.ProducerMessage
+	err      error
+}
+
+func (p *mockSyncProducer) SendMessage(msg *sarama.ProducerMessage) (int32, int64, error) {
+	p.messages = append(p.messages, msg)
+	return 0, 0, p.err
+}
+
+func (p *mockSyncProducer) SendMessages(msgs []*sarama.ProducerMessage) error {
+	panic("implement me")
+}
+
+func (p *mockSyncProducer) Close() error {
+	return nil
+}
+
+var _ sarama.SyncProducer = &mockSyncProducer{}
+
+func newConsumerMessage() *sarama.ConsumerMessage {
+	return &sarama.ConsumerMessage{
+		Headers: []*sarama.RecordHeader{
+			{Key: []byte("content-type"), Value: []byte("application/json")},
+			{Key: []byte("ce_specversion"), Value: []byte("1.0")},
+			{Key: []byte("ce_id"), Value: []byte("id")},
+			{Key: []byte("ce_source"), Value: []byte("source")},
+			{Key: []byte("ce_type"), Value: []byte("type")},
+		},
+		Key:   []byte("key"),
+		Value: []byte(`{"content": "test"}`),
+		Topic: "topic",
+	}
+}
+
+func headerValue(message *sarama.ProducerMessage, key string) string {
+	for _, header := range message.Headers {
+		if string(header.Key) == key {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
+
+//------ Tests
+
+func TestTopic(t *testing.T) {
+	testCases := map[string]struct {
+		url     string
+		topic   string
+		wantErr bool
+	}{
+		"host":         {url: "kafka://dead-letters", topic: "dead-letters"},
+		"path":         {url: "kafka:///dead-letters", topic: "dead-letters"},
+		"upper case":   {url: "KAFKA://dead-letters", topic: "dead-letters"},
+		"no topic":     {url: "kafka://", wantErr: true},
+		"not kafka":    {url: "http://dead-letters", wantErr: true},
+		"not absolute": {url: "dead-letters", wantErr: true},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			deadLetter, err := url.Parse(testCase.url)
+			assert.Nil(t, err)
+			topic, err := Topic(deadLetter)
+			assert.Equal(t, testCase.wantErr, err != nil)
+			assert.Equal(t, testCase.topic, topic)
+		})
+	}
+	assert.False(t, IsKafkaTopic(nil))
+}
+
+func TestDispatchMessageToKafkaTopic(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		attempts++
+		writer.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = writer.Write([]byte("subscriber is down"))
+	}))
+	defer server.Close()
+
+	destination, _ := url.Parse(server.URL)
+	deadLetter, _ := url.Parse("kafka://dead-letters")
+	retryConfig := kncloudevents.RetryConfig{
+		RetryMax: 2,
+		CheckRetry: func(ctx context.Context, response *http.Response, err error) (bool, error) {
+			return response == nil || response.StatusCode >= 500, nil
+		},
+		Backoff: func(attemptNum int, resp *http.Response) time.Duration {
+			return time.Millisecond
+		},
+	}
+	consumerMessage := newConsumerMessage()
+	message := protocolkafka.NewMessageFromConsumerMessage(consumerMessage)
+	dispatcher := channel.NewMessageDispatcher(zap.NewNop())
+
+	// The Dead Letter Topic Receives The Unaltered Message Along With The Failure Headers
+	producer := &mockSyncProducer{}
+	result, err := DispatchMessage(context.Background(), dispatcher, producer, message, consumerMessage, destination, nil, deadLetter, &retryConfig)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, 2, result.Retries)
+	assert.True(t, result.DeadLettered)
+	assert.Equal(t, http.StatusServiceUnavailable, result.ResponseCode())
+	assert.Len(t, producer.messages, 1)
+	produced := producer.messages[0]
+	assert.Equal(t, "dead-letters", produced.Topic)
+	assert.Equal(t, sarama.ByteEncoder(consumerMessage.Key), produced.Key)
+	assert.Equal(t, sarama.ByteEncoder(consumerMessage.Value), produced.Value)
+	assert.Equal(t, "id", headerValue(produced, "ce_id"))
+	assert.Equal(t, "503", headerValue(produced, StatusCodeHeader))
+	assert.Equal(t, "3", headerValue(produced, AttemptsHeader))
+	assert.Equal(t, server.URL, headerValue(produced, SubscriberHeader))
+	assert.Equal(t, "subscriber is down", headerValue(produced, ResponseBodyHeader))
+	assert.NotEmpty(t, headerValue(produced, ErrorHeader))
+
+	// A Failure To Produce To The Dead Letter Topic Is Returned
+	producer = &mockSyncProducer{err: errors.New("kafka is down")}
+	result, err = DispatchMessage(context.Background(), dispatcher, producer, message, consumerMessage, destination, nil, deadLetter, &retryConfig)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "kafka is down")
+	assert.False(t, result.DeadLettered)
+}
+
+func TestDispatchMessageToDeadLetterSink(t *testing.T) {
+	subscriberAttempts, deadLetterAttempts := 0, 0
+	subscriber := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		subscriberAttempts++
+		if subscriberAttempts < 2 {
+			writer.WriteHeader(http.StatusServiceUnavailable)
+		} else {
+			writer.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer subscriber.Close()
+	deadLetterSink := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		deadLetterAttempts++
+		writer.WriteHeader(http.StatusAccepted)
+	}))
+	defer deadLetterSink.Close()
+
+	destination, _ := url.Parse(subscriber.URL)
+	deadLetter, _ := url.Parse(deadLetterSink.URL)
+	retryConfig := kncloudevents.RetryConfig{
+		RetryMax: 2,
+		CheckRetry: func(ctx context.Context, response *http.Response, err error) (bool, error) {
+			return response == nil || response.StatusCode >= 500, nil
+		},
+		Backoff: func(attemptNum int, resp *http.Response) time.Duration {
+			return time.Millisecond
+		},
+	}
+	consumerMessage := newConsumerMessage()
+	message := protocolkafka.NewMessageFromConsumerMessage(consumerMessage)
+	dispatcher := channel.NewMessageDispatcher(zap.NewNop())
+
+	// The Dead Letter Sink Receives The Message Once The Subscriber Has Failed
+	result, err := DispatchMessage(context.Background(), dispatcher, nil, message, consumerMessage, destination, nil, deadLetter, &retryConfig)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, subscriberAttempts)
+	assert.Equal(t, 1, deadLetterAttempts)
+	assert.Equal(t, 1, result.Retries)
+	assert.True(t, result.DeadLettered)
+	assert.Equal(t, http.StatusNotFound, result.ResponseCode())
+	assert.NotNil(t, result.Error)
+
+	// Without A Dead Letter Sink The Failure Is Returned
+	result, err = DispatchMessage(context.Background(), dispatcher, nil, message, consumerMessage, destination, nil, nil, &retryConfig)
+	assert.NotNil(t, err)
+	assert.False(t, result.DeadLettered)
+	assert.Equal(t, 1, deadLetterAttempts)
+
+	// A Delivered Message Is Neither Retried Nor Dead Lettered
+	result, err = DispatchMessage(context.Background(), dispatcher, nil, message, consumerMessage, nil, nil, deadLetter, &retryConfig)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, result.Retries)
+	assert.False(t, result.DeadLettered)
+	assert.Nil(t, result.Error)
+	assert.Equal(t, channel.NoResponse, (*Result)(nil).ResponseCode())
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics implements the per-subscription dispatch metrics of the KafkaChannel dispatchers.  They are
+// recorded via OpenCensus, and exported along with the other metrics of the dispatchers (the METRICS_DOMAIN is
+// prepended to their names).
+package metrics
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/metrics"
+)
+
+// The Labels Of The Dispatch Metrics
+const (
+	LabelNamespaceName     = "namespace_name"
+	LabelChannelName       = "channel_name"
+	LabelSubscription      = "subscription_uid"
+	LabelResponseCode      = "response_code"
+	LabelResponseCodeClass = "response_code_class"
+	LabelTopic             = "topic"
+	LabelPartition         = "partition"
+
+	// NoResponse Is The Response Code Label Of The Dispatches Which Got No Response
+	NoResponse = "none"
+)
+
+var (
+	// Counter For The Number Of Events Dispatched To A Subscriber
+	dispatchCount = stats.Int64(
+		"subscription_dispatch_count",
+		"Number of events dispatched to a subscriber",
+		stats.UnitDimensionless,
+	)
+
+	// Distribution Of The Time Taken To Dispatch An Event, Retries Included
+	dispatchLatency = stats.Float64(
+		"subscription_dispatch_latencies",
+		"The time spent dispatching an event to a subscriber, including retries",
+		stats.UnitMilliseconds,
+	)
+
+	// Counter For The Number Of Retries Of The Dispatched Events
+	retryCount = stats.Int64(
+		"subscription_retry_count",
+		"Number of retries of the events dispatched to a subscriber",
+		stats.UnitDimensionless,
+	)
+
+	// Counter For The Number Of Events Sent To A Dead Letter Sink
+	deadLetterCount = stats.Int64(
+		"subscription_dead_letter_count",
+		"Number of events sent to the dead letter sink of a subscriber",
+		stats.UnitDimensionless,
+	)
+
+	// The Number Of Messages Of A Partition Which The Subscriber's ConsumerGroup Has Yet To Consume
+	consumerLag = stats.Int64(
+		"subscription_consumer_lag",
+		"Number of messages of a partition not consumed yet by the consumer group of a subscriber",
+		stats.UnitDimensionless,
+	)
+
+	namespaceKey         = tag.MustNewKey(LabelNamespaceName)
+	channelKey           = tag.MustNewKey(LabelChannelName)
+	subscriptionKey      = tag.MustNewKey(LabelSubscription)
+	responseCodeKey      = tag.MustNewKey(LabelResponseCode)
+	responseCodeClassKey = tag.MustNewKey(LabelResponseCodeClass)
+	topicKey             = tag.MustNewKey(LabelTopic)
+	partitionKey         = tag.MustNewKey(LabelPartition)
+)
+
+// Register The OpenCensus Views Of The Dispatch Metrics
+func init() {
+	subscriptionKeys := []tag.Key{namespaceKey, channelKey, subscriptionKey}
+	err := view.Register(
+		&view.View{
+			Description: dispatchCount.Description(),
+			Measure:     dispatchCount,
+			Aggregation: view.Count(),
+			TagKeys:     append(subscriptionKeys, responseCodeKey, responseCodeClassKey),
+		},
+		&view.View{
+			Description: dispatchLatency.Description(),
+			Measure:     dispatchLatency,
+			Aggregation: view.Distribution(metrics.Buckets125(1, 100000)...), // 1, 2, 5, 10, ... 100000ms
+			TagKeys:     append(subscriptionKeys, responseCodeKey, responseCodeClassKey),
+		},
+		&view.View{
+			Description: retryCount.Description(),
+			Measure:     retryCount,
+			Aggregation: view.Sum(),
+			TagKeys:     subscriptionKeys,
+		},
+		&view.View{
+			Description: deadLetterCount.Description(),
+			Measure:     deadLetterCount,
+			Aggregation: view.Count(),
+			TagKeys:     subscriptionKeys,
+		},
+		&view.View{
+			Description: consumerLag.Description(),
+			Measure:     consumerLag,
+			Aggregation: view.LastValue(),
+			TagKeys:     append(subscriptionKeys, topicKey, partitionKey),
+		},
+	)
+	if err != nil {
+		log.Printf("failed to register opencensus views, %v", err)
+	}
+}
+
+// SubscriptionReporter Records The Dispatch Metrics Of A Single Subscription, And Tracks Its Health
+type SubscriptionReporter struct {
+	namespace    string
+	channel      string
+	subscription types.UID
+	health       health
+}
+
+// NewSubscriptionReporter Creates A SubscriptionReporter For The Specified Subscription Of A Channel
+func NewSubscriptionReporter(namespace string, channel string, subscription types.UID) *SubscriptionReporter {
+	return &SubscriptionReporter{namespace: namespace, channel: channel, subscription: subscription}
+}
+
+// DispatchResult Describes The Outcome Of Dispatching An Event To The Subscriber
+type DispatchResult struct {
+	ResponseCode int           // The Response Code Of The Last Attempt (Negative If There Was No Response)
+	Latency      time.Duration // The Time Taken By All The Attempts
+	Retries      int           // The Attempts Beyond The First (Including Those Scheduled On A Retry Topic)
+	DeadLettered bool          // Whether The Event Was Sent To The Dead Letter Sink
+	Error        error         // The Subscriber's Error, If It Failed To Accept The Event (Even If Dead Lettered)
+}
+
+// ReportDispatch Records The Metrics Of A Dispatched Event
+func (r *SubscriptionReporter) ReportDispatch(result DispatchResult) error {
+	r.health.recordDispatch(result.Error)
+
+	responseCode, responseCodeClass := NoResponse, NoResponse
+	if result.ResponseCode > 0 {
+		responseCode = strconv.Itoa(result.ResponseCode)
+		responseCodeClass = metrics.ResponseCodeClass(result.ResponseCode)
+	}
+	ctx, err := r.tagged(tag.Insert(responseCodeKey, responseCode), tag.Insert(responseCodeClassKey, responseCodeClass))
+	if err != nil {
+		return err
+	}
+	recordWrapper(ctx, dispatchCount.M(1))
+	recordWrapper(ctx, dispatchLatency.M(float64(result.Latency)/float64(time.Millisecond)))
+
+	if result.Retries > 0 || result.DeadLettered {
+		ctx, err = r.tagged()
+		if err != nil {
+			return err
+		}
+		if result.Retries > 0 {
+			recordWrapper(ctx, retryCount.M(int64(result.Retries)))
+		}
+		if result.DeadLettered {
+			recordWrapper(ctx, deadLetterCount.M(1))
+		}
+	}
+	return nil
+}
+
+// ReportLag Records The Number Of Messages Of A Partition Which Are Yet To Be Consumed
+func (r *SubscriptionReporter) ReportLag(topic string, partition int32, lag int64) error {
+	ctx, err := r.tagged(tag.Insert(topicKey, topic), tag.Insert(partitionKey, strconv.Itoa(int(partition))))
+	if err != nil {
+		return err
+	}
+	recordWrapper(ctx, consumerLag.M(lag))
+	return nil
+}
+
+// Health Returns A Snapshot Of The Health Of The Subscription (Nil Reporters Have A Zero Health)
+func (r *SubscriptionReporter) Health() SubscriptionHealth {
+	if r == nil {
+		return SubscriptionHealth{}
+	}
+	return r.health.snapshot()
+}
+
+// Wrapper Function To Facilitate Testing (Knative Only Records Once The Metrics Exporter Is Configured)
+var recordWrapper = metrics.Record
+
+// Create A Context Tagged With The Subscription & The Specified Tags
+func (r *SubscriptionReporter) tagged(mutators ...tag.Mutator) (context.Context, error) {
+	return tag.New(context.Background(), append([]tag.Mutator{
+		tag.Insert(namespaceKey, r.namespace),
+		tag.Insert(channelKey, r.channel),
+		tag.Insert(subscriptionKey, string(r.subscription)),
+	}, mutators...)...)
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+//------ Mocks
+
+// recording is a measurement recorded through the recordWrapper, along with its tags.
+type recording struct {
+	measure string
+	value   float64
+	tags    map[string]string
+}
+
+// mockRecord replaces the recordWrapper for the duration of the test, returning the recordings it made.
+func mockRecord(t *testing.T) *[]recording {
+	recordings := &[]recording{}
+	original := recordWrapper
+	recordWrapper = func(ctx context.Context, measurement stats.Measurement, _ ...stats.Options) {
+		tags := make(map[string]string)
+		for _, key := range []tag.Key{namespaceKey, channelKey, subscriptionKey, responseCodeKey, responseCodeClassKey, topicKey, partitionKey} {
+			if value, ok := tag.FromContext(ctx).Value(key); ok {
+				tags[key.Name()] = value
+			}
+		}
+		*recordings = append(*recordings, recording{measure: measurement.Measure().Name(), value: measurement.Value(), tags: tags})
+	}
+	t.Cleanup(func() { recordWrapper = original })
+	return recordings
+}
+
+//------ Tests
+
+func TestViewsRegistered(t *testing.T) {
+	for _, name := range []string{"subscription_dispatch_count", "subscription_dispatch_latencies", "subscription_retry_count", "subscription_dead_letter_count", "subscription_consumer_lag"} {
+		assert.NotNil(t, view.Find(name), name)
+	}
+}
+
+func TestReportDispatch(t *testing.T) {
+	recordings := mockRecord(t)
+	reporter := NewSubscriptionReporter("ns", "channel", "uid")
+	subscriptionTags := map[string]string{LabelNamespaceName: "ns", LabelChannelName: "channel", LabelSubscription: "uid"}
+	withTags := func(tags map[string]string) map[string]string {
+		for key, value := range subscriptionTags {
+			tags[key] = value
+		}
+		return tags
+	}
+
+	// A Delivered Event Is Counted With Its Latency
+	assert.Nil(t, reporter.ReportDispatch(DispatchResult{ResponseCode: 202, Latency: 1500 * time.Microsecond}))
+	responseTags := withTags(map[string]string{LabelResponseCode: "202", LabelResponseCodeClass: "2xx"})
+	assert.Equal(t, []recording{
+		{measure: "subscription_dispatch_count", value: 1, tags: responseTags},
+		{measure: "subscription_dispatch_latencies", value: 1.5, tags: responseTags},
+	}, *recordings)
+
+	// Retries & Dead Lettering Are Counted Per Subscription
+	*recordings = nil
+	assert.Nil(t, reporter.ReportDispatch(DispatchResult{ResponseCode: -1, Latency: time.Second, Retries: 3, DeadLettered: true}))
+	noResponseTags := withTags(map[string]string{LabelResponseCode: NoResponse, LabelResponseCodeClass: NoResponse})
+	assert.Equal(t, []recording{
+		{measure: "subscription_dispatch_count", value: 1, tags: noResponseTags},
+		{measure: "subscription_dispatch_latencies", value: 1000, tags: noResponseTags},
+		{measure: "subscription_retry_count", value: 3, tags: subscriptionTags},
+		{measure: "subscription_dead_letter_count", value: 1, tags: subscriptionTags},
+	}, *recordings)
+}
+
+func TestReportLag(t *testing.T) {
+	recordings := mockRecord(t)
+	reporter := NewSubscriptionReporter("ns", "channel", "uid")
+
+	assert.Nil(t, reporter.ReportLag("ns.channel", 3, 42))
+	assert.Equal(t, []recording{{
+		measure: "subscription_consumer_lag",
+		value:   42,
+		tags:    map[string]string{LabelNamespaceName: "ns", LabelChannelName: "channel", LabelSubscription: "uid", LabelTopic: "ns.channel", LabelPartition: "3"},
+	}}, *recordings)
+
+	// Tags Must Be Printable ASCII
+	assert.NotNil(t, NewSubscriptionReporter("ns", "channel\x00", "uid").ReportLag("ns.channel", 3, 42))
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+)
+
+// HealthWindow Is How Long The Dispatches Count Towards The Health Of A Subscription
+const HealthWindow = 5 * time.Minute
+
+// HealthRefreshInterval Is How Often The Dispatchers Merge The Failures Of The Subscribers Into The KafkaChannel
+// Status, And How Often The Controllers Measure Their Lag
+const HealthRefreshInterval = 30 * time.Second
+
+// maxOutcomes Bounds The Number Of Dispatches Retained Within The HealthWindow
+const maxOutcomes = 1000
+
+// SubscriptionHealth Is A Snapshot Of The Delivery Health Of A Subscription
+type SubscriptionHealth struct {
+	Dispatched      int       // The Events Dispatched Within The HealthWindow
+	Failed          int       // The Dispatched Events Which The Subscriber Failed To Accept
+	LastFailureTime time.Time // Zero If The Subscriber Has Not Failed Since The Subscription Started
+	LastFailure     string
+}
+
+// FailurePercentage Returns The Percentage Of The Dispatched Events Which The Subscriber Failed To Accept
+func (h SubscriptionHealth) FailurePercentage() int32 {
+	if h.Dispatched == 0 {
+		return 0
+	}
+	return int32(h.Failed * 100 / h.Dispatched)
+}
+
+// SubscriberHealth Converts The Snapshot To The SubscriberHealth Of The Specified Subscriber's KafkaChannelStatus (The
+// Lag Being Measured By The Controller Instead)
+func (h SubscriptionHealth) SubscriberHealth(uid types.UID) kafkav1beta1.SubscriberHealth {
+	subscriberHealth := kafkav1beta1.SubscriberHealth{
+		UID:               uid,
+		FailurePercentage: h.FailurePercentage(),
+		LastFailure:       h.LastFailure,
+	}
+	if !h.LastFailureTime.IsZero() {
+		lastFailureTime := metav1.NewTime(h.LastFailureTime)
+		subscriberHealth.LastFailureTime = &lastFailureTime
+	}
+	return subscriberHealth
+}
+
+// The Outcome Of A Single Dispatch
+type outcome struct {
+	time   time.Time
+	failed bool
+}
+
+// The Health Tracked By A SubscriptionReporter
+type health struct {
+	lock            sync.Mutex
+	outcomes        []outcome
+	lastFailureTime time.Time
+	lastFailure     string
+}
+
+// Record The Outcome Of A Dispatch
+func (h *health) recordDispatch(err error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	now := nowWrapper()
+	h.outcomes = append(h.expiredOutcomesRemoved(now), outcome{time: now, failed: err != nil})
+	if len(h.outcomes) > maxOutcomes {
+		h.outcomes = h.outcomes[len(h.outcomes)-maxOutcomes:]
+	}
+	if err != nil {
+		h.lastFailureTime = now
+		h.lastFailure = err.Error()
+	}
+}
+
+// Take A Snapshot Of The Health
+func (h *health) snapshot() SubscriptionHealth {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	now := nowWrapper()
+	h.outcomes = h.expiredOutcomesRemoved(now)
+	subscriptionHealth := SubscriptionHealth{
+		Dispatched:      len(h.outcomes),
+		LastFailureTime: h.lastFailureTime,
+		LastFailure:     h.lastFailure,
+	}
+	for _, outcome := range h.outcomes {
+		if outcome.failed {
+			subscriptionHealth.Failed++
+		}
+	}
+	return subscriptionHealth
+}
+
+// Return The Outcomes Without Those Older Than The HealthWindow (Must Be Called With The Lock Held)
+func (h *health) expiredOutcomesRemoved(now time.Time) []outcome {
+	expired := 0
+	for expired < len(h.outcomes) && now.Sub(h.outcomes[expired].time) > HealthWindow {
+		expired++
+	}
+	return h.outcomes[expired:]
+}
+
+// Wrapper Function To Facilitate Testing
+var nowWrapper = time.Now
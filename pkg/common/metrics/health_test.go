@@ -0,0 +1,83 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+)
+
+// mockNow replaces the nowWrapper for the duration of the test, returning a pointer to the mocked time.
+func mockNow(t *testing.T) *time.Time {
+	now := time.Date(2020, 12, 1, 12, 0, 0, 0, time.UTC)
+	original := nowWrapper
+	nowWrapper = func() time.Time { return now }
+	t.Cleanup(func() { nowWrapper = original })
+	return &now
+}
+
+func TestHealth(t *testing.T) {
+	mockRecord(t)
+	now := mockNow(t)
+	reporter := NewSubscriptionReporter("ns", "channel", "uid")
+	assert.Equal(t, SubscriptionHealth{}, reporter.Health())
+	assert.Equal(t, SubscriptionHealth{}, (*SubscriptionReporter)(nil).Health())
+
+	// The Failures Are Tracked
+	failureTime := *now
+	assert.Nil(t, reporter.ReportDispatch(DispatchResult{ResponseCode: 500, Error: errors.New("subscriber failed")}))
+	*now = now.Add(time.Minute)
+	assert.Nil(t, reporter.ReportDispatch(DispatchResult{ResponseCode: 202}))
+	health := reporter.Health()
+	assert.Equal(t, SubscriptionHealth{Dispatched: 2, Failed: 1, LastFailureTime: failureTime, LastFailure: "subscriber failed"}, health)
+	assert.Equal(t, int32(50), health.FailurePercentage())
+
+	// Dispatches Fall Out Of The HealthWindow, But The Last Failure Is Kept
+	*now = now.Add(HealthWindow - time.Second)
+	health = reporter.Health()
+	assert.Equal(t, SubscriptionHealth{Dispatched: 1, LastFailureTime: failureTime, LastFailure: "subscriber failed"}, health)
+	assert.Equal(t, int32(0), health.FailurePercentage())
+}
+
+func TestHealthOutcomesBounded(t *testing.T) {
+	mockRecord(t)
+	mockNow(t)
+	reporter := NewSubscriptionReporter("ns", "channel", "uid")
+	for i := 0; i < maxOutcomes+10; i++ {
+		assert.Nil(t, reporter.ReportDispatch(DispatchResult{ResponseCode: 202}))
+	}
+	assert.Equal(t, maxOutcomes, reporter.Health().Dispatched)
+}
+
+func TestSubscriberHealth(t *testing.T) {
+	failureTime := time.Date(2020, 12, 1, 12, 0, 0, 0, time.UTC)
+	lastFailureTime := metav1.NewTime(failureTime)
+	health := SubscriptionHealth{Dispatched: 3, Failed: 1, LastFailureTime: failureTime, LastFailure: "subscriber failed"}
+	assert.Equal(t, kafkav1beta1.SubscriberHealth{
+		UID:               "uid",
+		FailurePercentage: 33,
+		LastFailureTime:   &lastFailureTime,
+		LastFailure:       "subscriber failed",
+	}, health.SubscriberHealth("uid"))
+	assert.Equal(t, kafkav1beta1.SubscriberHealth{UID: "uid"}, SubscriptionHealth{}.SubscriberHealth("uid"))
+}
@@ -0,0 +1,294 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package orphans implements the garbage collection of the Kafka topics and consumer groups left behind by deleted
+// KafkaChannels and subscribers, e.g. when a finalizer failed or a namespace was force-deleted.  The controllers
+// periodically compare the topics and consumer groups of their Kafka clusters against the existing KafkaChannels,
+// and delete (or only report, in dry-run mode) those which no longer belong to any of them.  Only the topics of the
+// KafkaChannels which the controller has seen (and the topics derived from them, e.g. retry topics) are ever
+// collected, and never the topics which have been used as a dead letter sink, so that the topics of other
+// applications sharing the Kafka cluster are left alone even though they may be named like channel topics.
+package orphans
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/types"
+
+	"knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/common/consumer"
+	"knative.dev/eventing-kafka/pkg/common/deadletter"
+)
+
+// GroupPrefix is the prefix of the consumer groups of the subscribers of the KafkaChannels, whose ids end with the
+// UID of their subscriber (before the suffix of a rewind, if any).
+const GroupPrefix = "kafka."
+
+// DisabledRecheckInterval is how often a disabled garbage collection checks whether it has been enabled.
+const DisabledRecheckInterval = time.Minute
+
+// TopicAdmin is the part of sarama.ClusterAdmin which lists and deletes topics.
+type TopicAdmin interface {
+	ListTopics() (map[string]sarama.TopicDetail, error)
+	DeleteTopic(topic string) error
+}
+
+// GroupAdmin is the part of sarama.ClusterAdmin which lists and deletes consumer groups.
+type GroupAdmin interface {
+	ListConsumerGroups() (map[string]string, error)
+	DeleteConsumerGroup(group string) error
+}
+
+var _ TopicAdmin = (sarama.ClusterAdmin)(nil)
+var _ GroupAdmin = (sarama.ClusterAdmin)(nil)
+
+// Owners are the topics and the subscribers of the existing KafkaChannels.
+type Owners struct {
+	topics      map[string]bool
+	deadLetters map[string]bool
+	subscribers map[types.UID]bool
+}
+
+// NewOwners returns empty Owners, to which the existing KafkaChannels are added.
+func NewOwners() *Owners {
+	return &Owners{topics: make(map[string]bool), deadLetters: make(map[string]bool), subscribers: make(map[types.UID]bool)}
+}
+
+// AddChannel adds the specified topics of a KafkaChannel, along with its subscribers and their Kafka dead letter
+// topics.  The topics prefixing other topics (e.g. retry or migration topics) own them as well.
+func (o *Owners) AddChannel(kc *v1beta1.KafkaChannel, topics ...string) {
+	for _, topic := range topics {
+		o.topics[topic] = true
+	}
+	for _, subscriber := range kc.Spec.Subscribers {
+		o.subscribers[subscriber.UID] = true
+		if subscriber.Delivery != nil && subscriber.Delivery.DeadLetterSink != nil && subscriber.Delivery.DeadLetterSink.URI != nil {
+			deadLetter := url.URL(*subscriber.Delivery.DeadLetterSink.URI)
+			if deadletter.IsKafkaTopic(&deadLetter) {
+				if topic, err := deadletter.Topic(&deadLetter); err == nil {
+					o.deadLetters[topic] = true
+				}
+			}
+		}
+	}
+}
+
+// OwnsTopic returns true if the specified topic is, or is prefixed by, a topic of an existing KafkaChannel, or is
+// the dead letter topic of one of its subscribers.
+func (o *Owners) OwnsTopic(name string) bool {
+	return o.deadLetters[name] || hasTopicPrefix(o.topics, name)
+}
+
+// hasTopicPrefix returns true if the specified topic is, or is prefixed by, one of the topics.
+func hasTopicPrefix(topics map[string]bool, name string) bool {
+	for topic := range topics {
+		if name == topic || strings.HasPrefix(name, topic+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// Seen remembers the topics of all the KafkaChannels, and all the dead letter topics, which the collections have
+// seen since the controller started.  Only the topics of seen KafkaChannels are candidates for the collection, so
+// that a topic which merely looks like a channel topic is never deleted, at the cost of the topics of KafkaChannels
+// deleted while the controller was down not being collected.
+type Seen struct {
+	lock        sync.Mutex
+	topics      map[string]bool
+	deadLetters map[string]bool
+}
+
+// NewSeen returns an empty Seen, which should live as long as the controller.
+func NewSeen() *Seen {
+	return &Seen{topics: make(map[string]bool), deadLetters: make(map[string]bool)}
+}
+
+// Add remembers the topics of the specified owners (Collect adds the owners it loads).
+func (s *Seen) Add(owners *Owners) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for topic := range owners.topics {
+		s.topics[topic] = true
+	}
+	for topic := range owners.deadLetters {
+		s.deadLetters[topic] = true
+	}
+}
+
+// collectable returns true if the specified topic is, or is prefixed by, the topic of a seen KafkaChannel, and has
+// never been used as a dead letter topic.
+func (s *Seen) collectable(name string) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return !s.deadLetters[name] && hasTopicPrefix(s.topics, name)
+}
+
+// OwnsGroup returns true if the specified consumer group is that of a subscriber of an existing KafkaChannel.
+func (o *Owners) OwnsGroup(uid types.UID) bool {
+	return o.subscribers[uid]
+}
+
+// GroupSubscriber returns the UID of the subscriber of the specified consumer group, and false if the group is not
+// named like the consumer group of a subscriber.
+func GroupSubscriber(group string) (types.UID, bool) {
+	if !strings.HasPrefix(group, GroupPrefix) {
+		return "", false
+	}
+	base := consumer.BaseGroupID(group)
+	uid := base[strings.LastIndex(base, ".")+1:]
+	if _, err := uuid.Parse(uid); err != nil {
+		return "", false
+	}
+	return types.UID(uid), true
+}
+
+// Result is the orphaned topics and consumer groups found by a garbage collection, which were deleted unless it
+// was a dry run.
+type Result struct {
+	Topics []string
+	Groups []string
+}
+
+// Collector garbage collects the orphaned topics and consumer groups of a Kafka cluster.
+type Collector struct {
+	// IsChannelTopic returns true if the specified topic is named like a topic of a KafkaChannel, all other topics
+	// being left alone.
+	IsChannelTopic func(topic string) bool
+	// Seen are the topics seen by the earlier collections, to which the owners loaded by this one are added.  Only
+	// the topics of seen KafkaChannels are collected, and none while it is nil.
+	Seen *Seen
+	// DryRun only reports the orphans, without deleting them.
+	DryRun bool
+	Logger *zap.SugaredLogger
+}
+
+// OwnersFunc returns the owners of the existing KafkaChannels, as currently known to the controller.
+type OwnersFunc func() (*Owners, error)
+
+// Collect finds the orphaned topics and consumer groups of a Kafka cluster, and deletes them unless it is a dry run.
+// A nil admin skips the topics or the consumer groups.  The owners are only loaded once the topics and consumer
+// groups have been listed, and loaded again just before deleting the orphans, so that the topic of a KafkaChannel
+// created in the meantime (which the controller knows of before creating its topic) is never deleted.  The deletion
+// failures are logged, and the consumer groups which still have members are never deleted (by Kafka).
+func (c *Collector) Collect(topicAdmin TopicAdmin, groupAdmin GroupAdmin, owners OwnersFunc) (Result, error) {
+	result := Result{}
+
+	var topics, groups []string
+	if topicAdmin != nil {
+		details, err := topicAdmin.ListTopics()
+		if err != nil {
+			return result, err
+		}
+		for topic := range details {
+			topics = append(topics, topic)
+		}
+	}
+	if groupAdmin != nil {
+		protocols, err := groupAdmin.ListConsumerGroups()
+		if err != nil {
+			return result, err
+		}
+		for group := range protocols {
+			groups = append(groups, group)
+		}
+	}
+
+	current, err := owners()
+	if err != nil {
+		return result, err
+	}
+	if c.Seen != nil {
+		c.Seen.Add(current)
+	}
+	result.Topics, result.Groups = c.orphans(topics, groups, current)
+
+	// a KafkaChannel or subscriber may have been created while the orphans were found
+	if !c.DryRun && (len(result.Topics) > 0 || len(result.Groups) > 0) {
+		if current, err = owners(); err != nil {
+			return Result{}, err
+		}
+		result.Topics, result.Groups = c.orphans(result.Topics, result.Groups, current)
+	}
+
+	for _, topic := range result.Topics {
+		if c.DryRun {
+			c.Logger.Infow("Found orphaned topic (dry run)", zap.String("topic", topic))
+		} else if err := topicAdmin.DeleteTopic(topic); err != nil && err != sarama.ErrUnknownTopicOrPartition {
+			c.Logger.Warnw("Failed to delete orphaned topic", zap.String("topic", topic), zap.Error(err))
+		} else {
+			c.Logger.Infow("Deleted orphaned topic", zap.String("topic", topic))
+		}
+	}
+	for _, group := range result.Groups {
+		if c.DryRun {
+			c.Logger.Infow("Found orphaned consumer group (dry run)", zap.String("group", group))
+		} else if err := groupAdmin.DeleteConsumerGroup(group); err != nil {
+			c.Logger.Warnw("Failed to delete orphaned consumer group", zap.String("group", group), zap.Error(err))
+		} else {
+			c.Logger.Infow("Deleted orphaned consumer group", zap.String("group", group))
+		}
+	}
+
+	return result, nil
+}
+
+// orphans returns the sorted topics and consumer groups of the specified ones which are not owned, leaving out the
+// topics which were never seen as the topic of a KafkaChannel.
+func (c *Collector) orphans(topics []string, groups []string, owners *Owners) ([]string, []string) {
+	var orphanedTopics, orphanedGroups []string
+	for _, topic := range topics {
+		if c.IsChannelTopic(topic) && c.Seen != nil && c.Seen.collectable(topic) && !owners.OwnsTopic(topic) {
+			orphanedTopics = append(orphanedTopics, topic)
+		}
+	}
+	for _, group := range groups {
+		if uid, ok := GroupSubscriber(group); ok && !owners.OwnsGroup(uid) {
+			orphanedGroups = append(orphanedGroups, group)
+		}
+	}
+	sort.Strings(orphanedTopics)
+	sort.Strings(orphanedGroups)
+	return orphanedTopics, orphanedGroups
+}
+
+// Run calls collect at the interval returned by the specified function (re-evaluated after each call, so that it
+// follows the configuration), or not at all while the interval is not positive, until the context is done.  The
+// first collection happens after a full interval, once the informers of the KafkaChannels have synced.
+func Run(ctx context.Context, interval func() time.Duration, collect func()) {
+	for {
+		wait := interval()
+		if wait <= 0 {
+			wait = DisabledRecheckInterval
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+			if interval() > 0 {
+				collect()
+			}
+		}
+	}
+}
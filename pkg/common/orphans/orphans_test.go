@@ -0,0 +1,266 @@
+/*
+Copyright 2021 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orphans
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/pkg/apis"
+	duckv1 "knative.dev/pkg/apis/duck/v1"
+	logtesting "knative.dev/pkg/logging/testing"
+
+	"knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/common/consumer"
+)
+
+const (
+	ownedUID    = types.UID("6f8a4c7e-7b1a-4b59-9a54-8a3e2f7c1d20")
+	orphanedUID = types.UID("0c9b0e1c-3d55-4c36-8f44-5b8f2d0a7e11")
+)
+
+// fakeAdmin is an in-memory TopicAdmin and GroupAdmin, whose groups with members can not be deleted.
+type fakeAdmin struct {
+	topics        map[string]sarama.TopicDetail
+	groups        map[string]string
+	groupsMembers map[string]bool
+}
+
+func (a *fakeAdmin) ListTopics() (map[string]sarama.TopicDetail, error) {
+	return a.topics, nil
+}
+
+func (a *fakeAdmin) DeleteTopic(topic string) error {
+	if _, ok := a.topics[topic]; !ok {
+		return sarama.ErrUnknownTopicOrPartition
+	}
+	delete(a.topics, topic)
+	return nil
+}
+
+func (a *fakeAdmin) ListConsumerGroups() (map[string]string, error) {
+	return a.groups, nil
+}
+
+func (a *fakeAdmin) DeleteConsumerGroup(group string) error {
+	if a.groupsMembers[group] {
+		return sarama.ErrNonEmptyGroup
+	}
+	delete(a.groups, group)
+	return nil
+}
+
+func newFakeAdmin(topics []string, groups []string) *fakeAdmin {
+	admin := &fakeAdmin{topics: make(map[string]sarama.TopicDetail), groups: make(map[string]string), groupsMembers: make(map[string]bool)}
+	for _, topic := range topics {
+		admin.topics[topic] = sarama.TopicDetail{NumPartitions: 1}
+	}
+	for _, group := range groups {
+		admin.groups[group] = "consumer"
+	}
+	return admin
+}
+
+func newOwners() *Owners {
+	deadLetter, _ := apis.ParseURL("kafka://knative-messaging-kafka.ns.dead-letters")
+	kc := &v1beta1.KafkaChannel{Spec: v1beta1.KafkaChannelSpec{}}
+	kc.Spec.Subscribers = []eventingduck.SubscriberSpec{{
+		UID:      ownedUID,
+		Delivery: &eventingduck.DeliverySpec{DeadLetterSink: &duckv1.Destination{URI: deadLetter}},
+	}}
+	owners := NewOwners()
+	owners.AddChannel(kc, "knative-messaging-kafka.ns.owned")
+	return owners
+}
+
+func loadOwners() (*Owners, error) {
+	return newOwners(), nil
+}
+
+// newSeen returns the topics seen while the orphaned channel still existed, along with the owned one.
+func newSeen() *Seen {
+	owners := newOwners()
+	owners.AddChannel(&v1beta1.KafkaChannel{}, "knative-messaging-kafka.ns.orphaned")
+	seen := NewSeen()
+	seen.Add(owners)
+	return seen
+}
+
+func isChannelTopic(topic string) bool {
+	return strings.HasPrefix(topic, "knative-messaging-kafka.")
+}
+
+func TestOwners(t *testing.T) {
+	owners := newOwners()
+	assert.True(t, owners.OwnsTopic("knative-messaging-kafka.ns.owned"))
+	assert.True(t, owners.OwnsTopic("knative-messaging-kafka.ns.owned.G2"))
+	assert.True(t, owners.OwnsTopic("knative-messaging-kafka.ns.dead-letters"))
+	assert.False(t, owners.OwnsTopic("knative-messaging-kafka.ns.owned-not"))
+	assert.False(t, owners.OwnsTopic("knative-messaging-kafka.ns.orphaned"))
+	assert.True(t, owners.OwnsGroup(ownedUID))
+	assert.False(t, owners.OwnsGroup(orphanedUID))
+}
+
+func TestGroupSubscriber(t *testing.T) {
+	rewind := v1beta1.DefaultSubscriberDelivery()
+	rewind.Rewind = "earliest"
+
+	testCases := map[string]struct {
+		group string
+		uid   types.UID
+		ok    bool
+	}{
+		"distributed":  {group: "kafka." + string(ownedUID), uid: ownedUID, ok: true},
+		"consolidated": {group: "kafka.ns.name." + string(ownedUID), uid: ownedUID, ok: true},
+		"rewound":      {group: consumer.GroupID("kafka.ns.name."+string(ownedUID), rewind), uid: ownedUID, ok: true},
+		"no uid":       {group: "kafka.ns.name"},
+		"other prefix": {group: "source." + string(ownedUID)},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			uid, ok := GroupSubscriber(tc.group)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.uid, uid)
+		})
+	}
+}
+
+func TestCollect(t *testing.T) {
+	topics := []string{
+		"knative-messaging-kafka.ns.owned",
+		"knative-messaging-kafka.ns.owned.retry-" + string(ownedUID) + ".1",
+		"knative-messaging-kafka.ns.dead-letters",
+		"knative-messaging-kafka.ns.orphaned",
+		"knative-messaging-kafka.ns.orphaned.retry-" + string(orphanedUID) + ".1",
+		"knative-messaging-kafka.ns.never-seen",
+		"other-application",
+	}
+	groups := []string{
+		"kafka.ns.owned." + string(ownedUID),
+		"kafka.ns.orphaned." + string(orphanedUID),
+		"kafka." + string(orphanedUID),
+		"other-application",
+	}
+	wantResult := Result{
+		Topics: []string{"knative-messaging-kafka.ns.orphaned", "knative-messaging-kafka.ns.orphaned.retry-" + string(orphanedUID) + ".1"},
+		Groups: []string{"kafka." + string(orphanedUID), "kafka.ns.orphaned." + string(orphanedUID)},
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		admin := newFakeAdmin(topics, groups)
+		collector := &Collector{IsChannelTopic: isChannelTopic, Seen: newSeen(), DryRun: true, Logger: logtesting.TestLogger(t)}
+		result, err := collector.Collect(admin, admin, loadOwners)
+		assert.Nil(t, err)
+		assert.Equal(t, wantResult, result)
+		assert.Len(t, admin.topics, len(topics))
+		assert.Len(t, admin.groups, len(groups))
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		admin := newFakeAdmin(topics, groups)
+		admin.groupsMembers["kafka."+string(orphanedUID)] = true // still consuming, so it is kept
+		collector := &Collector{IsChannelTopic: isChannelTopic, Seen: newSeen(), Logger: logtesting.TestLogger(t)}
+		result, err := collector.Collect(admin, admin, loadOwners)
+		assert.Nil(t, err)
+		assert.Equal(t, wantResult, result)
+		assert.Len(t, admin.topics, len(topics)-2)
+		assert.NotContains(t, admin.topics, "knative-messaging-kafka.ns.orphaned")
+		assert.Len(t, admin.groups, len(groups)-1)
+		assert.Contains(t, admin.groups, "kafka."+string(orphanedUID))
+	})
+
+	t.Run("groups only", func(t *testing.T) {
+		admin := newFakeAdmin(topics, groups)
+		collector := &Collector{IsChannelTopic: isChannelTopic, Seen: newSeen(), Logger: logtesting.TestLogger(t)}
+		result, err := collector.Collect(nil, admin, loadOwners)
+		assert.Nil(t, err)
+		assert.Empty(t, result.Topics)
+		assert.Len(t, admin.topics, len(topics))
+	})
+
+	t.Run("unreferenced dead letters", func(t *testing.T) {
+		admin := newFakeAdmin(topics, groups)
+		collector := &Collector{IsChannelTopic: isChannelTopic, Seen: newSeen(), Logger: logtesting.TestLogger(t)}
+		result, err := collector.Collect(admin, admin, func() (*Owners, error) {
+			owners := NewOwners() // the subscriber referencing the dead letter topic was removed
+			owners.AddChannel(&v1beta1.KafkaChannel{}, "knative-messaging-kafka.ns.owned")
+			return owners, nil
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, wantResult.Topics, result.Topics)
+		assert.Contains(t, admin.topics, "knative-messaging-kafka.ns.dead-letters")
+	})
+
+	t.Run("nothing seen", func(t *testing.T) {
+		admin := newFakeAdmin(topics, groups)
+		collector := &Collector{IsChannelTopic: isChannelTopic, Logger: logtesting.TestLogger(t)}
+		result, err := collector.Collect(admin, admin, loadOwners)
+		assert.Nil(t, err)
+		assert.Empty(t, result.Topics)
+		assert.Equal(t, wantResult.Groups, result.Groups)
+		assert.Len(t, admin.topics, len(topics))
+	})
+
+	t.Run("created meanwhile", func(t *testing.T) {
+		admin := newFakeAdmin(topics, groups)
+		collector := &Collector{IsChannelTopic: isChannelTopic, Seen: newSeen(), Logger: logtesting.TestLogger(t)}
+		loads := 0
+		result, err := collector.Collect(admin, admin, func() (*Owners, error) {
+			loads++
+			owners := newOwners()
+			if loads > 1 { // the orphaned channel and its subscriber were created after the first load
+				kc := &v1beta1.KafkaChannel{}
+				kc.Spec.Subscribers = []eventingduck.SubscriberSpec{{UID: orphanedUID}}
+				owners.AddChannel(kc, "knative-messaging-kafka.ns.orphaned")
+			}
+			return owners, nil
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, 2, loads)
+		assert.Empty(t, result.Topics)
+		assert.Empty(t, result.Groups)
+		assert.Len(t, admin.topics, len(topics))
+		assert.Len(t, admin.groups, len(groups))
+	})
+}
+
+func TestRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var collections int32
+	done := make(chan struct{})
+	go func() {
+		Run(ctx, func() time.Duration { return time.Millisecond }, func() {
+			if atomic.AddInt32(&collections, 1) == 3 {
+				cancel()
+			}
+		})
+		close(done)
+	}()
+	select {
+	case <-done:
+		assert.Equal(t, int32(3), atomic.LoadInt32(&collections))
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the collections")
+	}
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package producer configures the Kafka producer of the KafkaChannel receivers.  A sarama.SyncProducer sends through
+// an AsyncProducer, so the events sent concurrently while a produce request is in flight already share the next
+// request, each send still waiting for the delivery report of its own message (at-least-once delivery).
+package producer
+
+import (
+	"time"
+
+	"github.com/Shopify/sarama"
+)
+
+// SetBatching configures the producer to wait up to linger for more messages (and to send once batchSize messages
+// are waiting) before sending a batch to a broker.  Zero values keep the Sarama defaults, which send a batch as soon
+// as the previous request to the broker completes.  BenchmarkSyncProducer shows the concurrent sends are then already
+// batched by the round-trips (e.g. 32 messages per request for 64 senders and a 1ms round-trip), a linger about the
+// round-trip halving the requests at the same throughput, while a longer one only adds to the latency of each send.
+func SetBatching(config *sarama.Config, linger time.Duration, batchSize int) {
+	if linger > 0 {
+		config.Producer.Flush.Frequency = linger
+	}
+	if batchSize > 0 {
+		config.Producer.Flush.Messages = batchSize
+	}
+}
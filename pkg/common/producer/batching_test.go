@@ -0,0 +1,84 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package producer
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetBatching(t *testing.T) {
+	config := sarama.NewConfig()
+	SetBatching(config, 0, 0)
+	assert.Equal(t, time.Duration(0), config.Producer.Flush.Frequency)
+	assert.Equal(t, 0, config.Producer.Flush.Messages)
+
+	SetBatching(config, 5*time.Millisecond, 100)
+	assert.Equal(t, 5*time.Millisecond, config.Producer.Flush.Frequency)
+	assert.Equal(t, 100, config.Producer.Flush.Messages)
+}
+
+// BenchmarkSyncProducer sends concurrently through a sarama.SyncProducer to a broker with a 1ms round-trip, reporting
+// the produce requests per message for the default batching and for lingers (see SetBatching).
+//
+//	go test ./pkg/common/producer -run none -bench SyncProducer -cpu 64
+func BenchmarkSyncProducer(b *testing.B) {
+	for _, linger := range []time.Duration{0, time.Millisecond, 5 * time.Millisecond} {
+		b.Run(fmt.Sprintf("linger=%v", linger), func(b *testing.B) {
+			broker := sarama.NewMockBroker(b, 1)
+			defer broker.Close()
+			broker.SetLatency(time.Millisecond)
+			broker.SetHandlerByMap(map[string]sarama.MockResponse{
+				"MetadataRequest": sarama.NewMockMetadataResponse(b).
+					SetBroker(broker.Addr(), broker.BrokerID()).
+					SetLeader("topic", 0, broker.BrokerID()),
+				"ProduceRequest": sarama.NewMockProduceResponse(b).SetVersion(3),
+			})
+
+			config := sarama.NewConfig()
+			config.Producer.Return.Successes = true
+			SetBatching(config, linger, 0)
+			producer, err := sarama.NewSyncProducer([]string{broker.Addr()}, config)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if _, _, err := producer.SendMessage(&sarama.ProducerMessage{Topic: "topic", Value: sarama.StringEncoder("event")}); err != nil {
+						b.Error(err)
+					}
+				}
+			})
+			b.StopTimer()
+			_ = producer.Close()
+
+			requests := 0
+			for _, exchange := range broker.History() {
+				if _, ok := exchange.Request.(*sarama.ProduceRequest); ok {
+					requests++
+				}
+			}
+			b.ReportMetric(float64(requests)/float64(b.N), "requests/msg")
+		})
+	}
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package retrytopic implements non-blocking retries for KafkaChannel subscriptions.  Instead of retrying a failed
+// event in place (holding up the rest of its partition during the backoff), the dispatcher produces it to the delay
+// topic "<topic>.retry.<subscriber-uid>.<n>" of the next retry level, with a header stating when it may be retried.
+// The subscriber's consumer group also consumes its delay topics, waiting for each event's time before dispatching
+// it again.  Events which fail their last retry go to the subscriber's dead letter sink as usual.
+package retrytopic
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/google/uuid"
+	"k8s.io/apimachinery/pkg/types"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/common/consumer"
+	"knative.dev/eventing-kafka/pkg/common/deadletter"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/eventing/pkg/channel"
+	"knative.dev/eventing/pkg/kncloudevents"
+)
+
+// The headers added to the Kafka messages produced to a retry topic.  Consumers hold back messages with a
+// NotBeforeHeader until they are due (see consumer.ConsumeWithDelivery).
+const (
+	// NotBeforeHeader holds the time (in milliseconds since the epoch) before which the message is not retried.
+	NotBeforeHeader = consumer.NotBeforeHeader
+	// AttemptHeader holds the number of the retry attempt the message is waiting for.
+	AttemptHeader = "kafka-retry-attempt"
+)
+
+// topicInfix separates the channel topic from the subscriber specific part of the retry topic names.
+const topicInfix = ".retry."
+
+// Topics describes the retry topics of a single subscriber of a channel topic.
+type Topics struct {
+	Topic      string
+	Subscriber types.UID
+	// Delays holds the delay before each retry, Delays[n-1] being the delay before the event is dispatched from the
+	// retry topic of level n.  There are no retry topics when it is empty.
+	Delays []time.Duration
+}
+
+// NewTopics returns the retry topics of a subscriber with the specified delivery settings, the number of levels and
+// their delays being those of the retry config.  There are none unless the delivery settings use retry topics.
+func NewTopics(topic string, subscriber types.UID, delivery kafkav1beta1.SubscriberDelivery, retryConfig *kncloudevents.RetryConfig) Topics {
+	topics := Topics{Topic: topic, Subscriber: subscriber}
+	if !delivery.UsesRetryTopics() || retryConfig == nil {
+		return topics
+	}
+	for level := 1; level <= retryConfig.RetryMax; level++ {
+		var delay time.Duration
+		if retryConfig.Backoff != nil {
+			delay = retryConfig.Backoff(level, nil)
+		}
+		topics.Delays = append(topics.Delays, delay)
+	}
+	return topics
+}
+
+// SubscriberTopics returns the retry topics of the specified subscriber, based on its DeliverySpec.
+func SubscriberTopics(topic string, subscriber eventingduck.SubscriberSpec, delivery kafkav1beta1.SubscriberDelivery) (Topics, error) {
+	if subscriber.Delivery == nil {
+		return Topics{Topic: topic, Subscriber: subscriber.UID}, nil
+	}
+	retryConfig, err := kncloudevents.RetryConfigFromDeliverySpec(*subscriber.Delivery)
+	if err != nil {
+		return Topics{Topic: topic, Subscriber: subscriber.UID}, err
+	}
+	return NewTopics(topic, subscriber.UID, delivery, &retryConfig), nil
+}
+
+// Prefix returns the prefix shared by the names of all the retry topics of the specified channel topic.
+func Prefix(topic string) string {
+	return topic + topicInfix
+}
+
+// Name returns the name of the retry topic of the specified level.
+func Name(topic string, subscriber types.UID, level int) string {
+	return fmt.Sprintf("%s%s.%d", Prefix(topic), subscriber, level)
+}
+
+// IsName returns true if the specified topic name is that of a retry topic of the channel topic.  Channel names may
+// contain dots, so the subscriber part must be a UID and the level a positive number for the topic to be a match.
+func IsName(topic string, name string) bool {
+	if !strings.HasPrefix(name, Prefix(topic)) {
+		return false
+	}
+	parts := strings.Split(strings.TrimPrefix(name, Prefix(topic)), ".")
+	if len(parts) != 2 {
+		return false
+	}
+	if _, err := uuid.Parse(parts[0]); err != nil {
+		return false
+	}
+	level, err := strconv.Atoi(parts[1])
+	return err == nil && level > 0
+}
+
+// Enabled returns true if the subscriber has retry topics.
+func (t Topics) Enabled() bool {
+	return len(t.Delays) > 0
+}
+
+// Names returns the names of the retry topics, in level order.
+func (t Topics) Names() []string {
+	names := make([]string, 0, len(t.Delays))
+	for level := 1; level <= len(t.Delays); level++ {
+		names = append(names, Name(t.Topic, t.Subscriber, level))
+	}
+	return names
+}
+
+// Level returns the retry level of the specified topic, which is zero for the channel topic (or any other topic).
+func (t Topics) Level(topic string) int {
+	if !strings.HasPrefix(topic, Prefix(t.Topic)) {
+		return 0
+	}
+	for level := 1; level <= len(t.Delays); level++ {
+		if topic == Name(t.Topic, t.Subscriber, level) {
+			return level
+		}
+	}
+	return 0
+}
+
+// Produce sends the specified ConsumerMessage to the retry topic of the specified level, to be retried after the
+// specified time.  The key, value and headers of the message are preserved, apart from the retry headers.
+func Produce(producer sarama.SyncProducer, topic string, attempt int, message *sarama.ConsumerMessage, notBefore time.Time) error {
+	if producer == nil {
+		return fmt.Errorf("no kafka producer available for the retry topic %s", topic)
+	}
+
+	headers := make([]sarama.RecordHeader, 0, len(message.Headers)+2)
+	for _, header := range message.Headers {
+		if header != nil && string(header.Key) != NotBeforeHeader && string(header.Key) != AttemptHeader {
+			headers = append(headers, *header)
+		}
+	}
+	headers = append(headers,
+		sarama.RecordHeader{Key: []byte(NotBeforeHeader), Value: []byte(strconv.FormatInt(notBefore.UnixNano()/int64(time.Millisecond), 10))},
+		sarama.RecordHeader{Key: []byte(AttemptHeader), Value: []byte(strconv.Itoa(attempt))},
+	)
+
+	producerMessage := &sarama.ProducerMessage{
+		Topic:   topic,
+		Value:   sarama.ByteEncoder(message.Value),
+		Headers: headers,
+	}
+	if message.Key != nil {
+		producerMessage.Key = sarama.ByteEncoder(message.Key)
+	}
+
+	_, _, err := producer.SendMessage(producerMessage)
+	return err
+}
+
+// DispatchMessage dispatches a message like deadletter.DispatchMessage when the subscriber has no retry topics.
+// Otherwise the message is dispatched once, and a failed message is produced to the retry topic of the next level,
+// or to the dead letter sink when it was consumed from the last level.  Messages from a retry topic are expected
+// to have been held back until their NotBefore time by the consumer.
+func DispatchMessage(ctx context.Context, topics Topics, dispatcher channel.MessageDispatcher, producer sarama.SyncProducer, message binding.Message, consumerMessage *sarama.ConsumerMessage, destination *url.URL, reply *url.URL, deadLetter *url.URL, retryConfig *kncloudevents.RetryConfig) (*deadletter.Result, error) {
+	if !topics.Enabled() {
+		return deadletter.DispatchMessage(ctx, dispatcher, producer, message, consumerMessage, destination, reply, deadLetter, retryConfig)
+	}
+
+	// A Single Attempt Is Made Per Level, Judged By The Subscriber's Own CheckRetry (If Any)
+	singleAttempt := kncloudevents.NoRetries()
+	if retryConfig != nil {
+		singleAttempt = *retryConfig
+		singleAttempt.RetryMax = 0
+	}
+
+	level := topics.Level(consumerMessage.Topic)
+	if level >= len(topics.Delays) {
+		return deadletter.DispatchMessage(ctx, dispatcher, producer, message, consumerMessage, destination, reply, deadLetter, &singleAttempt)
+	}
+
+	info, err := dispatcher.DispatchMessageWithRetries(ctx, message, nil, destination, reply, nil, &singleAttempt)
+	result := &deadletter.Result{Info: info, Error: err}
+	if err == nil {
+		return result, nil
+	}
+
+	retryTopic := Name(topics.Topic, topics.Subscriber, level+1)
+	if retryErr := Produce(producer, retryTopic, level+1, consumerMessage, time.Now().Add(topics.Delays[level])); retryErr != nil {
+		return result, fmt.Errorf("unable to complete request to %s (%v) or to schedule a retry on %s (%v)", destination, err, retryTopic, retryErr)
+	}
+	result.Retries = 1
+	return result, nil
+}
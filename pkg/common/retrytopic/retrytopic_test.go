@@ -0,0 +1,225 @@
+/*
+Copyright 2020 The Knative Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package retrytopic
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	protocolkafka "github.com/cloudevents/sdk-go/protocol/kafka_sarama/v2"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	kafkav1beta1 "knative.dev/eventing-kafka/pkg/apis/messaging/v1beta1"
+	"knative.dev/eventing-kafka/pkg/common/consumer"
+	eventingduck "knative.dev/eventing/pkg/apis/duck/v1"
+	"knative.dev/eventing/pkg/channel"
+	"knative.dev/eventing/pkg/kncloudevents"
+	"knative.dev/pkg/ptr"
+)
+
+//------ Mocks
+
+type mockSyncProducer struct {
+	messages []*sarama.ProducerMessage
+}
+
+func (p *mockSyncProducer) SendMessage(msg *sarama.ProducerMessage) (int32, int64, error) {
+	p.messages = append(p.messages, msg)
+	return 0, 0, nil
+}
+
+func (p *mockSyncProducer) SendMessages(msgs []*sarama.ProducerMessage) error {
+	panic("implement me")
+}
+
+func (p *mockSyncProducer) Close() error {
+	return nil
+}
+
+var _ sarama.SyncProducer = &mockSyncProducer{}
+
+func newConsumerMessage(topic string) *sarama.ConsumerMessage {
+	return &sarama.ConsumerMessage{
+		Headers: []*sarama.RecordHeader{
+			{Key: []byte("content-type"), Value: []byte("application/json")},
+			{Key: []byte("ce_specversion"), Value: []byte("1.0")},
+			{Key: []byte("ce_id"), Value: []byte("id")},
+			{Key: []byte("ce_source"), Value: []byte("source")},
+			{Key: []byte("ce_type"), Value: []byte("type")},
+		},
+		Key:   []byte("key"),
+		Value: []byte(`{"content": "test"}`),
+		Topic: topic,
+	}
+}
+
+// toConsumerMessage returns the ConsumerMessage a consumer of the retry topic would receive for a produced message.
+func toConsumerMessage(message *sarama.ProducerMessage) *sarama.ConsumerMessage {
+	consumerMessage := &sarama.ConsumerMessage{Topic: message.Topic}
+	consumerMessage.Key, _ = message.Key.Encode()
+	consumerMessage.Value, _ = message.Value.Encode()
+	for i := range message.Headers {
+		consumerMessage.Headers = append(consumerMessage.Headers, &message.Headers[i])
+	}
+	return consumerMessage
+}
+
+func headerValue(message *sarama.ProducerMessage, key string) string {
+	for _, header := range message.Headers {
+		if string(header.Key) == key {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
+
+//------ Tests
+
+func TestNewTopics(t *testing.T) {
+	retryConfig, err := kncloudevents.RetryConfigFromDeliverySpec(eventingduck.DeliverySpec{
+		Retry:         ptr.Int32(3),
+		BackoffPolicy: &[]eventingduck.BackoffPolicyType{eventingduck.BackoffPolicyExponential}[0],
+		BackoffDelay:  ptr.String("PT1S"),
+	})
+	assert.Nil(t, err)
+	delivery := kafkav1beta1.SubscriberDelivery{Ordering: kafkav1beta1.DeliveryUnordered, RetryTopics: true}
+
+	topics := NewTopics("ns.name", "uid", delivery, &retryConfig)
+	assert.True(t, topics.Enabled())
+	assert.Equal(t, []time.Duration{2 * time.Second, 4 * time.Second, 8 * time.Second}, topics.Delays)
+	assert.Equal(t, []string{"ns.name.retry.uid.1", "ns.name.retry.uid.2", "ns.name.retry.uid.3"}, topics.Names())
+	assert.Equal(t, 0, topics.Level("ns.name"))
+	assert.Equal(t, 2, topics.Level("ns.name.retry.uid.2"))
+	assert.Equal(t, 0, topics.Level("ns.name.retry.other-uid.2"))
+	assert.Equal(t, 0, topics.Level("ns.name.retry.uid.4"))
+
+	// Retry Topics Are Not Used Unless Enabled, Nor With Strict Ordering
+	assert.False(t, NewTopics("ns.name", "uid", kafkav1beta1.DefaultSubscriberDelivery(), &retryConfig).Enabled())
+	delivery.Ordering = kafkav1beta1.DeliveryStrictlyOrdered
+	assert.False(t, NewTopics("ns.name", "uid", delivery, &retryConfig).Enabled())
+}
+
+func TestSubscriberTopics(t *testing.T) {
+	delivery := kafkav1beta1.SubscriberDelivery{Ordering: kafkav1beta1.DeliveryUnordered, RetryTopics: true}
+
+	topics, err := SubscriberTopics("ns.name", eventingduck.SubscriberSpec{UID: "uid"}, delivery)
+	assert.Nil(t, err)
+	assert.False(t, topics.Enabled())
+
+	subscriber := eventingduck.SubscriberSpec{UID: "uid", Delivery: &eventingduck.DeliverySpec{Retry: ptr.Int32(2)}}
+	topics, err = SubscriberTopics("ns.name", subscriber, delivery)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"ns.name.retry.uid.1", "ns.name.retry.uid.2"}, topics.Names())
+}
+
+func TestIsName(t *testing.T) {
+	uid := "6f8a4c7e-7b1a-4b59-9a54-8a3e2f7c1d20"
+	assert.True(t, IsName("ns.name", "ns.name.retry."+uid+".1"))
+	assert.False(t, IsName("ns.name", "ns.name"))
+	assert.False(t, IsName("ns.name", "ns.name.retry.not-a-uid.1"))
+	assert.False(t, IsName("ns.name", "ns.name.retry."+uid+".0"))
+	assert.False(t, IsName("ns.name", "ns.name.retry."+uid+".1.x"))
+	assert.False(t, IsName("ns.other", "ns.name.retry."+uid+".1"))
+}
+
+func TestProduceAndNotBefore(t *testing.T) {
+	producer := &mockSyncProducer{}
+	notBefore := time.Unix(1600000000, 123000000)
+
+	// A Message Moving To The Next Level Replaces Its Previous Retry Headers
+	consumerMessage := newConsumerMessage("ns.name.retry.uid.1")
+	consumerMessage.Headers = append(consumerMessage.Headers,
+		&sarama.RecordHeader{Key: []byte(NotBeforeHeader), Value: []byte("1")},
+		&sarama.RecordHeader{Key: []byte(AttemptHeader), Value: []byte("1")},
+	)
+	assert.Nil(t, Produce(producer, "ns.name.retry.uid.2", 2, consumerMessage, notBefore))
+	assert.Len(t, producer.messages, 1)
+	produced := producer.messages[0]
+	assert.Equal(t, "ns.name.retry.uid.2", produced.Topic)
+	assert.Len(t, produced.Headers, 7)
+	assert.Equal(t, "2", headerValue(produced, AttemptHeader))
+
+	when, ok := consumer.NotBefore(toConsumerMessage(produced))
+	assert.True(t, ok)
+	assert.True(t, notBefore.Equal(when))
+
+	_, ok = consumer.NotBefore(newConsumerMessage("ns.name"))
+	assert.False(t, ok)
+
+	assert.NotNil(t, Produce(nil, "ns.name.retry.uid.2", 2, consumerMessage, notBefore))
+}
+
+func TestDispatchMessageWithRetryTopics(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		attempts++
+		writer.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	destination, _ := url.Parse(server.URL)
+	deadLetter, _ := url.Parse("kafka://dead-letters")
+	retryConfig := kncloudevents.RetryConfig{
+		RetryMax: 2,
+		CheckRetry: func(ctx context.Context, response *http.Response, err error) (bool, error) {
+			return response == nil || response.StatusCode >= 500, nil
+		},
+		Backoff: func(attemptNum int, resp *http.Response) time.Duration {
+			return time.Duration(attemptNum) * time.Minute
+		},
+	}
+	delivery := kafkav1beta1.SubscriberDelivery{Ordering: kafkav1beta1.DeliveryUnordered, RetryTopics: true}
+	topics := NewTopics("ns.name", "uid", delivery, &retryConfig)
+	dispatcher := channel.NewMessageDispatcher(zap.NewNop())
+	producer := &mockSyncProducer{}
+
+	// Each Level Makes A Single Attempt Before Moving The Message On To The Next Level...
+	consumerMessage := newConsumerMessage("ns.name")
+	for level := 1; level <= 2; level++ {
+		before := time.Now()
+		message := protocolkafka.NewMessageFromConsumerMessage(consumerMessage)
+		result, err := DispatchMessage(context.Background(), topics, dispatcher, producer, message, consumerMessage, destination, nil, deadLetter, &retryConfig)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, result.Retries)
+		assert.False(t, result.DeadLettered)
+		assert.NotNil(t, result.Error)
+		assert.Equal(t, level, attempts)
+		assert.Len(t, producer.messages, level)
+		produced := producer.messages[level-1]
+		assert.Equal(t, Name("ns.name", "uid", level), produced.Topic)
+		consumerMessage = toConsumerMessage(produced)
+		notBefore, ok := consumer.NotBefore(consumerMessage)
+		assert.True(t, ok)
+		assert.False(t, notBefore.Before(before.Add(time.Duration(level)*time.Minute).Truncate(time.Millisecond)))
+	}
+
+	// ...Until The Last Level Dead Letters It
+	message := protocolkafka.NewMessageFromConsumerMessage(consumerMessage)
+	result, err := DispatchMessage(context.Background(), topics, dispatcher, producer, message, consumerMessage, destination, nil, deadLetter, &retryConfig)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, result.Retries)
+	assert.True(t, result.DeadLettered)
+	assert.Equal(t, 3, attempts)
+	assert.Len(t, producer.messages, 3)
+	assert.Equal(t, "dead-letters", producer.messages[2].Topic)
+	assert.Equal(t, "id", headerValue(producer.messages[2], "ce_id"))
+}
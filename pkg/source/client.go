@@ -131,6 +131,11 @@ func NewTLSConfig(clientCert, clientKey, caCert string) (*tls.Config, error) {
 func MakeAdminClient(clientID string, kafkaAuthCfg *utils.KafkaAuthConfig, bootstrapServers []string) (sarama.ClusterAdmin, error) {
 	saramaConf := sarama.NewConfig()
 	saramaConf.Version = sarama.V2_0_0_0
+	return MakeAdminClientWithConfig(clientID, saramaConf, kafkaAuthCfg, bootstrapServers)
+}
+
+// MakeAdminClientWithConfig is MakeAdminClient starting from the specified sarama config rather than the defaults.
+func MakeAdminClientWithConfig(clientID string, saramaConf *sarama.Config, kafkaAuthCfg *utils.KafkaAuthConfig, bootstrapServers []string) (sarama.ClusterAdmin, error) {
 	saramaConf.ClientID = clientID
 
 	err := UpdateSaramaConfigWithKafkaAuthConfig(saramaConf, kafkaAuthCfg)